@@ -173,7 +173,7 @@ func (d *CommandDiscovery) isLikelyNamespaceWrapper(funcName string) bool {
 
 	// Try to split CamelCase into namespace:method patterns
 	commonNamespaces := []string{
-		"build", "test", "lint", "format", "deps", "git", "release", "docs",
+		"build", "test", "lint", "check", "format", "deps", "git", "release", "docs",
 		"tools", "generate", "mod", "help", "version", "install",
 		"configure", "init", "bench", "vet", "aws",
 	}
@@ -97,15 +97,61 @@ var (
 	ErrMagefileExists = errors.New("magefile.go already exists")
 )
 
+// ErrInvalidGraphFormat is returned when -graph is given an unsupported format
+var ErrInvalidGraphFormat = errors.New("invalid graph format (want text or dot)")
+
+// ErrNoGraphCommand is returned when -graph is used without a command
+var ErrNoGraphCommand = errors.New("no command given to graph")
+
+// Graph output formats for the -graph flag
+const (
+	graphFormatText = "text"
+	graphFormatDOT  = "dot"
+)
+
+// graphFlag is the -graph flag: a boolean flag that optionally takes a format
+// (-graph prints text, -graph=dot prints Graphviz DOT)
+type graphFlag struct {
+	format string
+}
+
+// String returns the selected format, or an empty string when the flag is unset
+func (g *graphFlag) String() string {
+	if g == nil {
+		return ""
+	}
+	return g.format
+}
+
+// Set parses the flag value
+func (g *graphFlag) Set(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case trueValue, graphFormatText:
+		g.format = graphFormatText
+	case graphFormatDOT:
+		g.format = graphFormatDOT
+	case "false", "":
+		g.format = ""
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidGraphFormat, value)
+	}
+	return nil
+}
+
+// IsBoolFlag allows -graph to be used without a value
+func (g *graphFlag) IsBoolFlag() bool { return true }
+
 // Flags holds all command line flags
 type Flags struct {
 	Clean     *bool
 	Compile   *string
 	Debug     *bool
 	Force     *bool
+	Graph     *graphFlag
 	Help      *bool
 	HelpLong  *bool
 	Init      *bool
+	Jobs      *int
 	List      *bool
 	ListLong  *bool
 	Namespace *bool
@@ -117,14 +163,16 @@ type Flags struct {
 
 // initFlags initializes all command line flags
 func initFlags() *Flags {
-	return &Flags{
+	flags := &Flags{
 		Clean:     flag.Bool("clean", false, "clean MAGE-X cache and temporary files"),
 		Compile:   flag.String("compile", "", "compile a magefile for use with mage"),
 		Debug:     flag.Bool("debug", false, "enable debug output"),
 		Force:     flag.Bool("f", false, "force operation"),
+		Graph:     &graphFlag{},
 		Help:      flag.Bool("h", false, "show help"),
 		HelpLong:  flag.Bool("help", false, "show help"),
 		Init:      flag.Bool("init", false, "initialize a new magefile with MAGE-X imports"),
+		Jobs:      flag.Int("j", 1, "maximum number of dependencies to run concurrently"),
 		List:      flag.Bool("l", false, "list available commands"),
 		ListLong:  flag.Bool("list", false, "list available commands (verbose)"),
 		Namespace: flag.Bool("n", false, "show commands organized by namespace"),
@@ -133,6 +181,8 @@ func initFlags() *Flags {
 		Verbose:   flag.Bool("v", false, "verbose output"),
 		Version:   flag.Bool("version", false, "show version"),
 	}
+	flag.Var(flags.Graph, "graph", "print the execution plan of a command (-graph=dot for Graphviz)")
+	return flags
 }
 
 // tryCustomCommand attempts to execute a custom command via delegation.
@@ -170,9 +220,11 @@ func run(ctx context.Context, args []string) int {
 		Compile:   fs.String("compile", "", "compile a magefile for use with mage"),
 		Debug:     fs.Bool("debug", false, "enable debug output"),
 		Force:     fs.Bool("f", false, "force operation"),
+		Graph:     &graphFlag{},
 		Help:      fs.Bool("h", false, "show help"),
 		HelpLong:  fs.Bool("help", false, "show help"),
 		Init:      fs.Bool("init", false, "initialize a new magefile with MAGE-X imports"),
+		Jobs:      fs.Int("j", 1, "maximum number of dependencies to run concurrently"),
 		List:      fs.Bool("l", false, "list available commands"),
 		ListLong:  fs.Bool("list", false, "list available commands (verbose)"),
 		Namespace: fs.Bool("n", false, "show commands organized by namespace"),
@@ -181,6 +233,7 @@ func run(ctx context.Context, args []string) int {
 		Verbose:   fs.Bool("v", false, "verbose output"),
 		Version:   fs.Bool("version", false, "show version"),
 	}
+	fs.Var(flags.Graph, "graph", "print the execution plan of a command (-graph=dot for Graphviz)")
 
 	// Custom usage function
 	fs.Usage = showUsage
@@ -190,6 +243,10 @@ func run(ctx context.Context, args []string) int {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		return 1
 	}
+	if *flags.Jobs < 1 {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: -j must be at least 1, got %d\n", *flags.Jobs)
+		return 1
+	}
 
 	// Load environment variables from .env files (early startup hook)
 	// This loads .github/.env.base and other env files before tool version checks
@@ -314,6 +371,15 @@ func run(ctx context.Context, args []string) int {
 		return 0
 	}
 
+	// Handle execution plan request: magex -graph[=dot] <command>
+	if flags.Graph.String() != "" {
+		if err := showExecutionPlan(reg, cmdArgs, flags.Graph.String()); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
 	// Process command execution (cmdArgs already defined above)
 	if len(cmdArgs) == 0 {
 		// No command specified, show available commands
@@ -365,7 +431,14 @@ func run(ctx context.Context, args []string) int {
 		}
 	}
 
+	reg.SetParallelism(*flags.Jobs)
+	tagAuditCommand(command)
+	if err := mage.PrependToolsBinToPath(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	started := time.Now()
 	if err := reg.Execute(command, commandArgs...); err != nil {
+		notifyCommandFailure(ctx, command, time.Since(started), err)
 		exitCode = handleCommandError(ctx, reg, command, commandArgs, discovery, delegateTimeout, err)
 	}
 
@@ -389,6 +462,29 @@ func run(ctx context.Context, args []string) int {
 	return exitCode
 }
 
+// notifyCommandFailure reports a command that ran and failed to the notification
+// channels in .mage.yaml. Unknown commands and Ctrl+C are not failures.
+func notifyCommandFailure(ctx context.Context, command string, duration time.Duration, err error) {
+	if errors.Is(err, registry.ErrUnknownCommand) || ctx.Err() != nil {
+		return
+	}
+	if notifyErr := mage.NotifyCommandFailure(ctx, command, duration, err); notifyErr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", notifyErr)
+	}
+}
+
+// tagAuditCommand publishes the command being run so audit events recorded by this
+// process, and by any mage process it delegates to, name it. An outer magex
+// invocation keeps its own name when magex runs itself.
+func tagAuditCommand(command string) {
+	if os.Getenv(mage.EnvAuditCommand) != "" {
+		return
+	}
+	if err := os.Setenv(mage.EnvAuditCommand, command); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  failed to set %s: %v\n", mage.EnvAuditCommand, err)
+	}
+}
+
 // handleCommandError handles errors from command execution, including
 // unknown commands which may be namespace names or custom magefile commands.
 func handleCommandError(ctx context.Context, reg *registry.Registry, command string, commandArgs []string, discovery *CommandDiscovery, timeout time.Duration, err error) int {
@@ -412,8 +508,12 @@ func handleCommandError(ctx context.Context, reg *registry.Registry, command str
 		return 1
 	}
 
+	started := time.Now()
 	exitCode, customErr := tryCustomCommand(ctx, command, commandArgs, discovery, timeout)
 	if customErr != nil {
+		if customCommandRan(customErr) {
+			notifyCommandFailure(ctx, command, time.Since(started), customErr)
+		}
 		fmt.Fprintf(os.Stderr, "❌ %v\n", customErr)
 		return exitCode
 	}
@@ -421,6 +521,12 @@ func handleCommandError(ctx context.Context, reg *registry.Registry, command str
 	return 0
 }
 
+// customCommandRan reports whether a delegated magefile command started and then
+// failed or timed out, rather than never running at all
+func customCommandRan(err error) bool {
+	return errors.Is(err, ErrCommandFailed) || errors.Is(err, ErrCommandTimeout)
+}
+
 // Signal-classification constants for cancelledBy. Translated to standard
 // shell exit codes (128 + signal number) when main() returns.
 const (
@@ -627,6 +733,27 @@ func showOptionsSection() {
 	fmt.Printf("  -init            Create a magefile with MAGE-X imports\n")
 	fmt.Printf("  -clean           Clean MAGE-X cache and temporary files\n")
 	fmt.Printf("  -debug           Enable debug output\n")
+	fmt.Printf("  -j <n>           Run up to n independent dependencies concurrently\n")
+	fmt.Printf("  -graph <cmd>     Print a command's execution plan (-graph=dot for Graphviz)\n")
+}
+
+// showExecutionPlan prints the resolved dependency plan of a command
+func showExecutionPlan(reg *registry.Registry, cmdArgs []string, format string) error {
+	if len(cmdArgs) == 0 {
+		return ErrNoGraphCommand
+	}
+
+	plan, err := reg.Plan(normalizeCommandName(cmdArgs[0]))
+	if err != nil {
+		return err
+	}
+
+	if format == graphFormatDOT {
+		fmt.Print(plan.DOT())
+	} else {
+		fmt.Print(plan.Text())
+	}
+	return nil
 }
 
 // showCategorizedCommands displays all commands organized by category
@@ -5,6 +5,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mrz1836/mage-x/pkg/mage"
 	"github.com/mrz1836/mage-x/pkg/mage/registry"
 	"github.com/mrz1836/mage-x/pkg/testhelpers"
 	"github.com/mrz1836/mage-x/pkg/utils"
@@ -1884,9 +1886,11 @@ func TestInitFlags(t *testing.T) {
 	require.NotNil(t, flags.Compile)
 	require.NotNil(t, flags.Debug)
 	require.NotNil(t, flags.Force)
+	require.NotNil(t, flags.Graph)
 	require.NotNil(t, flags.Help)
 	require.NotNil(t, flags.HelpLong)
 	require.NotNil(t, flags.Init)
+	require.NotNil(t, flags.Jobs)
 	require.NotNil(t, flags.List)
 	require.NotNil(t, flags.ListLong)
 	require.NotNil(t, flags.Namespace)
@@ -1903,6 +1907,7 @@ func TestInitFlags(t *testing.T) {
 	assert.False(t, *flags.Help)
 	assert.False(t, *flags.HelpLong)
 	assert.False(t, *flags.Init)
+	assert.Equal(t, 1, *flags.Jobs)
 	assert.False(t, *flags.List)
 	assert.False(t, *flags.ListLong)
 	assert.False(t, *flags.Namespace)
@@ -1910,6 +1915,82 @@ func TestInitFlags(t *testing.T) {
 	assert.Empty(t, *flags.Timeout)
 	assert.False(t, *flags.Verbose)
 	assert.False(t, *flags.Version)
+	assert.Empty(t, flags.Graph.String())
+}
+
+// TestGraphFlag tests parsing of the -graph flag
+func TestGraphFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{args: []string{"-graph", "build"}, want: graphFormatText},
+		{args: []string{"-graph=text", "build"}, want: graphFormatText},
+		{args: []string{"-graph=DOT", "build"}, want: graphFormatDOT},
+		{args: []string{"-graph=false", "build"}, want: ""},
+		{args: []string{"build"}, want: ""},
+		{args: []string{"-graph=svg", "build"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("magex", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			graph := &graphFlag{}
+			fs.Var(graph, "graph", "")
+
+			err := fs.Parse(tt.args)
+			if tt.wantErr {
+				require.ErrorContains(t, err, ErrInvalidGraphFormat.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, graph.String())
+			assert.Equal(t, []string{"build"}, fs.Args(), "-graph must not consume the command name")
+		})
+	}
+}
+
+// TestShowExecutionPlan tests printing a command's dependency plan
+func TestShowExecutionPlan(t *testing.T) {
+	reg := registry.NewRegistry()
+	noop := func() error { return nil }
+	reg.MustRegister(&registry.Command{Name: "generate", Description: "generate", Func: noop})
+	reg.MustRegister(&registry.Command{Name: "build", Description: "build", Dependencies: []string{"generate"}, Func: noop})
+
+	require.ErrorIs(t, showExecutionPlan(reg, nil, graphFormatText), ErrNoGraphCommand)
+	require.ErrorIs(t, showExecutionPlan(reg, []string{"missing"}, graphFormatText), registry.ErrUnknownCommand)
+
+	capture := func(format string) string {
+		oldStdout := os.Stdout
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		os.Stdout = w
+
+		planErr := showExecutionPlan(reg, []string{"build"}, format)
+
+		require.NoError(t, w.Close())
+		os.Stdout = oldStdout
+		require.NoError(t, planErr)
+
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(r)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	text := capture(graphFormatText)
+	assert.Contains(t, text, "Execution plan for build (2 steps):")
+	assert.Contains(t, text, "- build (after generate)")
+
+	dot := capture(graphFormatDOT)
+	assert.Contains(t, dot, `digraph "build" {`)
+	assert.Contains(t, dot, `"generate" -> "build";`)
 }
 
 // TestShowNamespaceHelp tests namespace help display
@@ -3599,3 +3680,23 @@ func TestUpdateCommandsNoStaleBanner(t *testing.T) {
 		})
 	}
 }
+
+// TestTagAuditCommand verifies the running command is published for audit events
+func TestTagAuditCommand(t *testing.T) {
+	t.Setenv(mage.EnvAuditCommand, "")
+	require.NoError(t, os.Unsetenv(mage.EnvAuditCommand))
+
+	tagAuditCommand("release")
+	assert.Equal(t, "release", os.Getenv(mage.EnvAuditCommand))
+
+	tagAuditCommand("build")
+	assert.Equal(t, "release", os.Getenv(mage.EnvAuditCommand), "the outer command is kept")
+}
+
+// TestCustomCommandRan verifies only delegated commands that ran are reported as failures
+func TestCustomCommandRan(t *testing.T) {
+	assert.True(t, customCommandRan(fmt.Errorf("%w 'deploy': exit status 1", ErrCommandFailed)))
+	assert.True(t, customCommandRan(fmt.Errorf("%w: 'deploy' after 1s", ErrCommandTimeout)))
+	assert.False(t, customCommandRan(fmt.Errorf("%w: deploy", ErrCommandNotFound)))
+	assert.False(t, customCommandRan(ErrGoCommandNotFound))
+}
@@ -29,6 +29,8 @@ type (
 	Bench     mg.Namespace
 	Bmad      mg.Namespace
 	Build     mg.Namespace
+	Certs     mg.Namespace
+	Check     mg.Namespace
 	Configure mg.Namespace
 	Deps      mg.Namespace
 	Docs      mg.Namespace
@@ -156,6 +158,18 @@ func Uninstall() error {
 	return i.Uninstall()
 }
 
+// Watch re-runs commands (or the affected tests) whenever Go sources change
+func Watch() error {
+	var r mage.Run
+	return r.WatchWithArgs(getMageArgs()...)
+}
+
+// Audit queries the command audit log
+func Audit() error {
+	var a mage.Audit
+	return a.Query(getMageArgs()...)
+}
+
 // ReleaseDefault creates a new release (default)
 func ReleaseDefault() error {
 	var r mage.Release
@@ -856,6 +870,12 @@ func (t Test) Coverage() error {
 	return impl.Coverage(getMageArgs()...)
 }
 
+// Flaky lists flaky tests detected by CI mode reruns
+func (t Test) Flaky() error {
+	var impl mage.Test
+	return impl.Flaky(getMageArgs()...)
+}
+
 // Lint namespace methods
 func (l Lint) Default() error {
 	var impl mage.Lint
@@ -952,6 +972,32 @@ func (l Lint) Verbose() error {
 	return impl.Verbose()
 }
 
+// Check namespace methods
+
+// All runs every check concurrently and prints a consolidated report
+func (c Check) All() error {
+	var impl mage.Check
+	return impl.AllWithArgs(getMageArgs()...)
+}
+
+// License checks license headers (fix=true inserts or updates them)
+func (c Check) License() error {
+	var impl mage.Check
+	return impl.LicenseWithArgs(getMageArgs()...)
+}
+
+// Tidy fails with a diff when go mod tidy would change go.mod or go.sum
+func (c Check) Tidy() error {
+	var impl mage.Check
+	return impl.Tidy()
+}
+
+// Generate fails with a diff when go generate would change any file
+func (c Check) Generate() error {
+	var impl mage.Check
+	return impl.Generate()
+}
+
 // Deps namespace methods
 func (d Deps) Default() error {
 	var impl mage.Deps
@@ -1011,7 +1057,7 @@ func (d Deps) Vendor() error {
 
 func (d Deps) Licenses() error {
 	var impl mage.Deps
-	return impl.Licenses()
+	return impl.LicensesWithArgs(getMageArgs()...)
 }
 
 func (d Deps) Check() error {
@@ -1189,7 +1235,7 @@ func (d Docs) Build() error {
 
 func (d Docs) Lint() error {
 	var impl mage.Docs
-	return impl.Lint()
+	return impl.LintWithArgs(getMageArgs()...)
 }
 
 func (d Docs) Spell() error {
@@ -1199,7 +1245,7 @@ func (d Docs) Spell() error {
 
 func (d Docs) Links() error {
 	var impl mage.Docs
-	return impl.Links()
+	return impl.LinksWithArgs(getMageArgs()...)
 }
 
 func (d Docs) API() error {
@@ -1379,6 +1425,11 @@ func (r Release) Clean() error {
 	return impl.Clean()
 }
 
+func (r Release) Package() error {
+	var impl mage.Release
+	return impl.Package(getMageArgs()...)
+}
+
 // Metrics namespace methods
 func (m Metrics) LOC() error {
 	var impl mage.Metrics
@@ -1486,6 +1537,12 @@ func (i Install) All() error {
 	return impl.All()
 }
 
+// Certs namespace methods
+func (c Certs) Status() error {
+	var impl mage.Certs
+	return impl.Status()
+}
+
 // Configure namespace methods
 func (c Configure) Init() error {
 	var impl mage.Configure
@@ -1738,6 +1795,11 @@ func (b Bench) Save() error {
 	return impl.Save()
 }
 
+func (b Bench) History() error {
+	var impl mage.Bench
+	return impl.HistoryWithArgs(getMageArgs()...)
+}
+
 func (b Bench) CPU() error {
 	var impl mage.Bench
 	return impl.CPU()
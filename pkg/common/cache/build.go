@@ -2,13 +2,16 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/mrz1836/mage-x/pkg/common/fileops"
@@ -22,6 +25,10 @@ type BuildCache struct {
 	enabled  bool
 	maxSize  int64 // in bytes
 	ttl      time.Duration
+	storage  Storage // local entries
+	remote   Storage // optional shared cache consulted on local misses
+	hits     atomic.Int64
+	misses   atomic.Int64
 }
 
 // BuildResult represents a cached build result
@@ -121,6 +128,7 @@ func NewBuildCache(cacheDir string) *BuildCache {
 	return &BuildCache{
 		cacheDir: cacheDir,
 		fileOps:  fileops.New(),
+		storage:  NewLocalStorage(cacheDir),
 		enabled:  true,
 		maxSize:  5 * 1024 * 1024 * 1024, // 5GB default
 		ttl:      7 * 24 * time.Hour,     // 7 days default
@@ -134,6 +142,13 @@ func (c *BuildCache) SetOptions(enabled bool, maxSize int64, ttl time.Duration)
 	c.ttl = ttl
 }
 
+// SetRemote attaches a shared cache. Local misses are looked up remotely and copied
+// into the local cache; new entries are uploaded unless the remote is read-only.
+// Remote failures never fail a cache operation, they only turn into misses.
+func (c *BuildCache) SetRemote(remote Storage) {
+	c.remote = remote
+}
+
 // Init initializes the cache directory structure
 func (c *BuildCache) Init() error {
 	if !c.enabled {
@@ -165,14 +180,25 @@ func (c *BuildCache) getCacheResult(hash, subdir, entryType string, result any)
 		return nil, false
 	}
 
-	path := filepath.Join(c.cacheDir, subdir, hash+".json")
-	if !c.fileOps.File.Exists(path) {
-		return nil, false
+	cached, found := c.readCacheResult(hash, subdir, entryType, result)
+	if found {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
 	}
+	return cached, found
+}
 
-	data, err := c.fileOps.File.ReadFile(path)
+// readCacheResult loads and validates a cache entry, falling back to the remote cache
+func (c *BuildCache) readCacheResult(hash, subdir, entryType string, result any) (any, bool) {
+	key := cacheKey(subdir, hash)
+	data, err := c.storage.Get(context.Background(), key)
+	fromRemote := false
 	if err != nil {
-		return nil, false
+		if data, err = c.fetchRemote(key); err != nil {
+			return nil, false
+		}
+		fromRemote = true
 	}
 
 	if err := json.Unmarshal(data, result); err != nil {
@@ -193,15 +219,36 @@ func (c *BuildCache) getCacheResult(hash, subdir, entryType string, result any)
 	}
 
 	if time.Since(timestamp) > c.ttl {
+		if fromRemote {
+			return nil, false
+		}
+		path := filepath.Join(c.cacheDir, subdir, hash+".json")
 		if err := c.removeCacheEntry(path); err != nil {
 			log.Warn("Failed to remove expired %s cache entry %s: %v", entryType, path, err)
 		}
 		return nil, false
 	}
 
+	if fromRemote {
+		if err := c.storage.Put(context.Background(), key, data); err != nil {
+			log.Debug("failed to copy remote cache entry %s locally: %v", key, err)
+		}
+	}
 	return result, true
 }
 
+// fetchRemote downloads an entry from the remote cache, if one is configured
+func (c *BuildCache) fetchRemote(key string) ([]byte, error) {
+	if c.remote == nil {
+		return nil, ErrEntryNotFound
+	}
+	data, err := c.remote.Get(context.Background(), key)
+	if err != nil && !errors.Is(err, ErrEntryNotFound) {
+		log.Warn("Remote cache lookup for %s failed: %v", key, err)
+	}
+	return data, err
+}
+
 // GetBuildResult retrieves a cached build result
 func (c *BuildCache) GetBuildResult(hash string) (*BuildResult, bool) {
 	var result BuildResult
@@ -250,8 +297,17 @@ func (c *BuildCache) storeCacheResult(hash, subdir string, result any) error {
 		return err
 	}
 
-	path := filepath.Join(c.cacheDir, subdir, hash+".json")
-	return c.fileOps.File.WriteFile(path, data, fileops.PermFile)
+	key := cacheKey(subdir, hash)
+	if err := c.storage.Put(context.Background(), key, data); err != nil {
+		return err
+	}
+
+	if c.remote != nil {
+		if err := c.remote.Put(context.Background(), key, data); err != nil && !errors.Is(err, ErrReadOnlyStorage) {
+			log.Warn("Failed to upload %s to the remote cache: %v", key, err)
+		}
+	}
+	return nil
 }
 
 // StoreBuildResult stores a build result in cache
@@ -349,6 +405,42 @@ func (c *BuildCache) GenerateFileHash(filePaths []string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil))[:16], nil
 }
 
+// GenerateContentHash creates a hash from file paths and contents only, ignoring
+// modification times, so identical checkouts (e.g. separate worktrees) hash the same.
+// Paths are hashed as given; pass paths relative to a shared root to make keys portable.
+func (c *BuildCache) GenerateContentHash(filePaths []string) (string, error) {
+	return c.GenerateContentHashIn("", filePaths)
+}
+
+// GenerateContentHashIn is GenerateContentHash with relative paths read from root
+// instead of the working directory. The paths are still hashed as given.
+func (c *BuildCache) GenerateContentHashIn(root string, filePaths []string) (string, error) {
+	h := sha256.New()
+
+	for _, path := range filePaths {
+		fullPath := path
+		if root != "" && !filepath.IsAbs(path) {
+			fullPath = filepath.Join(root, path)
+		}
+		if !c.fileOps.File.Exists(fullPath) {
+			continue
+		}
+
+		content, err := c.fileOps.File.ReadFile(fullPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		// Length-prefix each part so adjacent paths and contents cannot collide
+		if _, err := fmt.Fprintf(h, "%d:%s%d:", len(path), filepath.ToSlash(path), len(content)); err != nil {
+			return "", err
+		}
+		h.Write(content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
 // GetStats returns cache statistics
 func (c *BuildCache) GetStats() (*Stats, error) {
 	if !c.enabled {
@@ -356,6 +448,8 @@ func (c *BuildCache) GetStats() (*Stats, error) {
 	}
 
 	stats := &Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
 		LastCleanup: time.Now(),
 	}
 
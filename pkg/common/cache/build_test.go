@@ -416,6 +416,73 @@ func TestBuildCache_GenerateFileHash(t *testing.T) {
 	})
 }
 
+func TestBuildCache_GenerateContentHash(t *testing.T) {
+	cache := NewBuildCache(t.TempDir())
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	require.NoError(t, os.WriteFile(file, []byte("package a"), 0o600))
+
+	hash1, err := cache.GenerateContentHash([]string{file})
+	require.NoError(t, err)
+
+	// Touching the file without changing its content keeps the hash
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(file, later, later))
+	hash2, err := cache.GenerateContentHash([]string{file})
+	require.NoError(t, err)
+	assert.Equal(t, hash1, hash2)
+
+	require.NoError(t, os.WriteFile(file, []byte("package a // changed"), 0o600))
+	hash3, err := cache.GenerateContentHash([]string{file})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash1, hash3)
+
+	// Missing files are skipped
+	hash4, err := cache.GenerateContentHash([]string{file, filepath.Join(dir, "missing.go")})
+	require.NoError(t, err)
+	assert.Equal(t, hash3, hash4)
+}
+
+func TestBuildCache_GenerateContentHashIn(t *testing.T) {
+	cache := NewBuildCache(t.TempDir())
+
+	// The same relative file in two roots hashes the same
+	roots := []string{t.TempDir(), t.TempDir()}
+	hashes := make([]string, len(roots))
+	for i, root := range roots {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "a.go"), []byte("package a"), 0o600))
+		hash, err := cache.GenerateContentHashIn(root, []string{"a.go"})
+		require.NoError(t, err)
+		hashes[i] = hash
+	}
+	assert.Equal(t, hashes[0], hashes[1])
+
+	empty, err := cache.GenerateContentHashIn(roots[0], nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, empty, hashes[0], "relative files are read from the root")
+}
+
+func TestBuildCache_HitMissStats(t *testing.T) {
+	cache := NewBuildCache(t.TempDir())
+	require.NoError(t, cache.Init())
+
+	require.NoError(t, cache.StoreTestResult("hit", &TestResult{Package: "example.com/a", Success: true}))
+
+	_, found := cache.GetTestResult("hit")
+	assert.True(t, found)
+	_, found = cache.GetTestResult("miss")
+	assert.False(t, found)
+	_, found = cache.GetTestResult("hit")
+	assert.True(t, found)
+
+	stats, err := cache.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.InDelta(t, 2.0/3.0, stats.HitRate, 0.001)
+}
+
 func TestBuildCache_GetStats(t *testing.T) {
 	tempDir := t.TempDir()
 	cache := NewBuildCache(tempDir)
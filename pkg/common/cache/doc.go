@@ -32,6 +32,20 @@
 // Cache behavior is controlled via environment variables:
 //
 //   - MAGE_X_CACHE_DISABLED=true: Disable caching entirely
+//   - MAGE_X_CACHE_REMOTE_URL, MAGE_X_CACHE_REMOTE_TOKEN, MAGE_X_CACHE_REMOTE_SIGNING_KEY,
+//     MAGE_X_CACHE_REMOTE_READ_ONLY: Share entries through a remote cache (magex also reads
+//     the cache.remote section of .mage.yaml)
+//
+// # Storage Backends
+//
+// Entries are stored through the Storage interface. LocalStorage keeps them as
+// JSON files in the cache directory; HTTPStorage shares them with other machines
+// via plain GET and PUT requests, verifying a SHA-256 digest on every download.
+// The digest only detects corruption; set RemoteConfig.SigningKey to also reject
+// entries not signed with that HMAC key:
+//
+//	remote, err := cache.NewHTTPStorage(&cache.RemoteConfig{URL: url, ReadOnly: true})
+//	buildCache.SetRemote(remote)
 //
 // # Thread Safety
 //
@@ -30,6 +30,7 @@ type Config struct {
 	TTL         time.Duration `yaml:"ttl"`
 	Compression bool          `yaml:"compression"`
 	Strategies  *Strategies   `yaml:"strategies"`
+	Remote      *RemoteConfig `yaml:"remote"` // Optional shared cache (nil or empty URL: local only)
 }
 
 // Strategies defines caching strategies for different operations
@@ -97,6 +98,15 @@ func (m *Manager) Init() error {
 		return fmt.Errorf("failed to initialize build cache: %w", err)
 	}
 
+	// Attach the shared remote cache
+	if m.config.Remote != nil && m.config.Remote.URL != "" {
+		remote, err := NewHTTPStorage(m.config.Remote)
+		if err != nil {
+			return fmt.Errorf("failed to configure remote cache: %w", err)
+		}
+		m.buildCache.SetRemote(remote)
+	}
+
 	return nil
 }
 
@@ -123,6 +133,22 @@ func (m *Manager) GenerateBuildHash(platform, ldflags string, sourceFiles, confi
 
 // GenerateTestHash creates a hash for test operations
 func (m *Manager) GenerateTestHash(pkg string, testFiles, buildFlags []string) (string, error) {
+	return m.GenerateTestHashWithOptions(pkg, testFiles, buildFlags, TestHashOptions{})
+}
+
+// TestHashOptions changes how GenerateTestHashWithOptions hashes test files
+type TestHashOptions struct {
+	// Root resolves relative test files instead of the working directory.
+	// With ContentOnly the paths are still hashed as given.
+	Root string
+
+	// ContentOnly hashes file paths and contents but not modification times,
+	// so identical checkouts (e.g. separate worktrees) produce the same key
+	ContentOnly bool
+}
+
+// GenerateTestHashWithOptions is GenerateTestHash with the file hashing controlled by opts
+func (m *Manager) GenerateTestHashWithOptions(pkg string, testFiles, buildFlags []string, opts TestHashOptions) (string, error) {
 	allFiles := make([]string, 0, len(testFiles)+2)
 	allFiles = append(allFiles, testFiles...)
 	allFiles = append(allFiles, "go.mod", "go.sum")
@@ -130,19 +156,33 @@ func (m *Manager) GenerateTestHash(pkg string, testFiles, buildFlags []string) (
 	// Add build flags to hash
 	flagsStr := strings.Join(buildFlags, "|")
 
+	var fileHash string
+	if opts.ContentOnly {
+		hash, err := m.buildCache.GenerateContentHashIn(opts.Root, allFiles)
+		if err != nil {
+			return "", err
+		}
+		fileHash = hash
+	} else {
+		if opts.Root != "" {
+			for i, file := range allFiles {
+				if !filepath.IsAbs(file) {
+					allFiles[i] = filepath.Join(opts.Root, file)
+				}
+			}
+		}
+		if hash, err := m.buildCache.GenerateFileHash(allFiles); err == nil {
+			// On error the file hash is left empty - it affects the cache key but won't fail
+			fileHash = hash
+		}
+	}
+
 	return m.buildCache.GenerateHash(
 		pkg,
 		flagsStr,
 		runtime.GOOS,
 		runtime.GOARCH,
-		func() string {
-			hash, err := m.buildCache.GenerateFileHash(allFiles)
-			if err != nil {
-				// Return empty string on error - will affect cache key but won't fail
-				return ""
-			}
-			return hash
-		}(),
+		fileHash,
 	), nil
 }
 
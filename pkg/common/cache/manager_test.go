@@ -307,6 +307,31 @@ func TestManager_GenerateTestHash(t *testing.T) {
 	assert.NotEqual(t, hash1, hash3)
 }
 
+func TestManager_GenerateTestHashContentOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "a_test.go")
+	require.NoError(t, os.WriteFile(testFile, []byte("package a"), 0o600))
+
+	manager := NewManager(&Config{Enabled: true, Directory: tempDir, Strategies: DefaultConfig().Strategies})
+	contentOnly := TestHashOptions{ContentOnly: true}
+
+	defaultHash1, err := manager.GenerateTestHash("example.com/a", []string{testFile}, nil)
+	require.NoError(t, err)
+	contentHash1, err := manager.GenerateTestHashWithOptions("example.com/a", []string{testFile}, nil, contentOnly)
+	require.NoError(t, err)
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(testFile, later, later))
+
+	defaultHash2, err := manager.GenerateTestHash("example.com/a", []string{testFile}, nil)
+	require.NoError(t, err)
+	contentHash2, err := manager.GenerateTestHashWithOptions("example.com/a", []string{testFile}, nil, contentOnly)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, defaultHash1, defaultHash2, "the default hash includes modification times")
+	assert.Equal(t, contentHash1, contentHash2, "ContentOnly must not depend on modification times")
+}
+
 func TestManager_GenerateLintHash(t *testing.T) {
 	tempDir := t.TempDir()
 
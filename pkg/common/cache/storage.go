@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/log"
+)
+
+// Storage errors
+var (
+	ErrEntryNotFound     = errors.New("cache entry not found")
+	ErrInvalidKey        = errors.New("invalid cache key")
+	ErrReadOnlyStorage   = errors.New("cache storage is read-only")
+	ErrIntegrityMismatch = errors.New("cache entry failed integrity check")
+	ErrEntryTooLarge     = errors.New("cache entry exceeds size limit")
+	ErrRemoteStatus      = errors.New("unexpected remote cache response")
+	ErrInvalidRemoteURL  = errors.New("invalid remote cache URL")
+)
+
+const (
+	// DefaultRemoteTimeout bounds each request to a remote cache
+	DefaultRemoteTimeout = 10 * time.Second
+
+	// MaxRemoteEntrySize caps the size of a single downloaded entry
+	MaxRemoteEntrySize = 32 * 1024 * 1024
+
+	// remoteEnvelopeVersion is the version of the envelope format stored remotely
+	remoteEnvelopeVersion = 1
+)
+
+// validKey matches "<kind>/<hash>" cache keys such as "tests/0f3a9c21d4e5b678"
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var validKey = regexp.MustCompile(`^[a-z]+/[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// Storage is a backend holding serialized cache entries by key. Keys have the form
+// "<kind>/<hash>", where kind is builds, tests, lint or deps.
+type Storage interface {
+	// Get returns the entry stored under key, or ErrEntryNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores data under key, replacing any existing entry
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete removes the entry under key; deleting a missing entry is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// RemoteConfig configures a shared remote cache
+type RemoteConfig struct {
+	URL        string        `yaml:"url"`         // Base URL; entries live at <url>/<kind>/<hash>
+	Token      string        `yaml:"token"`       // Optional bearer token
+	SigningKey string        `yaml:"signing_key"` // Optional HMAC key; when set, unsigned or mis-signed entries are rejected
+	ReadOnly   bool          `yaml:"read_only"`   // Download entries but never upload
+	Timeout    time.Duration `yaml:"timeout"`     // Per-request timeout (default: 10s)
+}
+
+// checkKey rejects keys that could escape the storage root or the remote base URL
+func checkKey(key string) error {
+	if !validKey.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// cacheKey builds a storage key from an entry kind and hash
+func cacheKey(kind, hash string) string {
+	return kind + "/" + hash
+}
+
+// LocalStorage stores entries as JSON files under a directory
+type LocalStorage struct {
+	dir     string
+	fileOps *fileops.FileOps
+}
+
+// NewLocalStorage creates a storage backend rooted at dir
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{dir: dir, fileOps: fileops.New()}
+}
+
+// path returns the file holding key
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key)+".json")
+}
+
+// Get reads the entry file for key
+func (s *LocalStorage) Get(_ context.Context, key string) ([]byte, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	path := s.path(key)
+	if !s.fileOps.File.Exists(path) {
+		return nil, ErrEntryNotFound
+	}
+	return s.fileOps.File.ReadFile(path)
+}
+
+// Put writes the entry file for key
+func (s *LocalStorage) Put(_ context.Context, key string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	path := s.path(key)
+	if err := s.fileOps.File.MkdirAll(filepath.Dir(path), fileops.PermDir); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return s.fileOps.File.WriteFile(path, data, fileops.PermFile)
+}
+
+// Delete removes the entry file for key
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	path := s.path(key)
+	if !s.fileOps.File.Exists(path) {
+		return nil
+	}
+	return s.fileOps.File.Remove(path)
+}
+
+// HTTPStorage stores entries on a plain HTTP server with GET and PUT on
+// <base>/<kind>/<hash>. Entries are wrapped in an envelope carrying the key and a
+// SHA-256 digest, which is verified on download, so a server returning corrupted
+// or misplaced data is treated as a miss.
+//
+// The digest is computed by the uploading client, so it detects corruption but not
+// tampering: anyone able to write to the server can store a matching digest. With a
+// signing key the envelope also carries an HMAC-SHA256 of the key and data, and only
+// entries signed with the same key are accepted.
+type HTTPStorage struct {
+	baseURL    string
+	token      string
+	signingKey []byte
+	readOnly   bool
+	client     *http.Client
+}
+
+// remoteEnvelope is the body stored on the remote server
+type remoteEnvelope struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+	SHA256  string `json:"sha256"`
+	HMAC    string `json:"hmac,omitempty"`
+	Data    []byte `json:"data"`
+}
+
+// NewHTTPStorage creates a storage backend for the remote cache in config
+func NewHTTPStorage(config *RemoteConfig) (*HTTPStorage, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRemoteURL, config.URL)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+
+	storage := &HTTPStorage{
+		baseURL:  strings.TrimRight(config.URL, "/"),
+		token:    config.Token,
+		readOnly: config.ReadOnly,
+		client:   &http.Client{Timeout: timeout},
+	}
+	if config.SigningKey != "" {
+		storage.signingKey = []byte(config.SigningKey)
+	}
+	return storage, nil
+}
+
+// ReadOnly reports whether uploads are disabled
+func (s *HTTPStorage) ReadOnly() bool {
+	return s.readOnly
+}
+
+// newRequest builds an authenticated request for key
+func (s *HTTPStorage) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+"/"+key, body)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return req, nil
+}
+
+// Get downloads and verifies the entry for key
+func (s *HTTPStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req) // #nosec G704 -- URL is the configured remote cache
+	if err != nil {
+		return nil, fmt.Errorf("remote cache request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Debug("failed to close remote cache response for %s: %v", key, closeErr)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrEntryNotFound
+	default:
+		return nil, fmt.Errorf("%w: GET %s returned %s", ErrRemoteStatus, key, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxRemoteEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote cache entry: %w", err)
+	}
+	if len(body) > MaxRemoteEntrySize {
+		return nil, fmt.Errorf("%w: %s", ErrEntryTooLarge, key)
+	}
+
+	return s.openEnvelope(key, body)
+}
+
+// Put uploads the entry for key, failing with ErrReadOnlyStorage in read-only mode
+func (s *HTTPStorage) Put(ctx context.Context, key string, data []byte) error {
+	if s.readOnly {
+		return ErrReadOnlyStorage
+	}
+
+	body, err := s.sealEnvelope(key, data)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req) // #nosec G704 -- URL is the configured remote cache
+	if err != nil {
+		return fmt.Errorf("remote cache request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Debug("failed to close remote cache response for %s: %v", key, closeErr)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: PUT %s returned %s", ErrRemoteStatus, key, resp.Status)
+	}
+	return nil
+}
+
+// Delete is a no-op for the remote cache: entries are shared, so clients only add to it
+func (s *HTTPStorage) Delete(_ context.Context, key string) error {
+	return checkKey(key)
+}
+
+// sealEnvelope wraps data with its key, digest and, with a signing key, its HMAC for upload
+func (s *HTTPStorage) sealEnvelope(key string, data []byte) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	envelope := remoteEnvelope{
+		Version: remoteEnvelopeVersion,
+		Key:     key,
+		SHA256:  hex.EncodeToString(sum[:]),
+		Data:    data,
+	}
+	if s.signingKey != nil {
+		envelope.HMAC = hex.EncodeToString(s.envelopeMAC(key, data))
+	}
+	return json.Marshal(envelope)
+}
+
+// openEnvelope verifies a downloaded envelope and returns its data
+func (s *HTTPStorage) openEnvelope(key string, body []byte) ([]byte, error) {
+	var envelope remoteEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %s: malformed envelope", ErrIntegrityMismatch, key)
+	}
+	if envelope.Version != remoteEnvelopeVersion || envelope.Key != key {
+		return nil, fmt.Errorf("%w: %s: envelope is for %q", ErrIntegrityMismatch, key, envelope.Key)
+	}
+	sum := sha256.Sum256(envelope.Data)
+	if hex.EncodeToString(sum[:]) != envelope.SHA256 {
+		return nil, fmt.Errorf("%w: %s: digest mismatch", ErrIntegrityMismatch, key)
+	}
+	if s.signingKey != nil {
+		mac, err := hex.DecodeString(envelope.HMAC)
+		if err != nil || !hmac.Equal(mac, s.envelopeMAC(key, envelope.Data)) {
+			return nil, fmt.Errorf("%w: %s: signature mismatch", ErrIntegrityMismatch, key)
+		}
+	}
+	return envelope.Data, nil
+}
+
+// envelopeMAC signs the key and data of an entry, so a signed entry cannot be
+// moved to another key
+func (s *HTTPStorage) envelopeMAC(key string, data []byte) []byte {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
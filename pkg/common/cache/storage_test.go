@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteCacheServer is a stand-in for a shared cache: a map served over GET and PUT
+type remoteCacheServer struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	token   string
+	puts    int
+}
+
+func newRemoteCacheServer(t *testing.T, token string) (*remoteCacheServer, *httptest.Server) {
+	t.Helper()
+
+	rs := &remoteCacheServer{entries: make(map[string][]byte), token: token}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rs.token != "" && r.Header.Get("Authorization") != "Bearer "+rs.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := rs.entries[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data) //nolint:errcheck // test server
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			rs.entries[key] = data
+			rs.puts++
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return rs, server
+}
+
+func (rs *remoteCacheServer) set(key string, data []byte) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.entries[key] = data
+}
+
+func (rs *remoteCacheServer) get(key string) []byte {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.entries[key]
+}
+
+func newRemoteBuildCache(t *testing.T, config *RemoteConfig) *BuildCache {
+	t.Helper()
+
+	c := NewBuildCache(t.TempDir())
+	require.NoError(t, c.Init())
+	remote, err := NewHTTPStorage(config)
+	require.NoError(t, err)
+	c.SetRemote(remote)
+	return c
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocalStorage(t.TempDir())
+
+	_, err := s.Get(ctx, "tests/abc123")
+	require.ErrorIs(t, err, ErrEntryNotFound)
+
+	require.NoError(t, s.Put(ctx, "tests/abc123", []byte(`{"ok":true}`)))
+	data, err := s.Get(ctx, "tests/abc123")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(data))
+
+	require.NoError(t, s.Delete(ctx, "tests/abc123"))
+	require.NoError(t, s.Delete(ctx, "tests/abc123"), "deleting a missing entry is not an error")
+
+	for _, key := range []string{"tests/../../etc/passwd", "abc123", "tests/.hidden", "Tests/abc", "tests/a/b"} {
+		require.ErrorIs(t, s.Put(ctx, key, nil), ErrInvalidKey, key)
+	}
+}
+
+func TestNewHTTPStorage_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"", "cache.example.com", "ftp://cache.example.com", "http://"} {
+		_, err := NewHTTPStorage(&RemoteConfig{URL: rawURL})
+		require.ErrorIs(t, err, ErrInvalidRemoteURL, rawURL)
+	}
+}
+
+func TestHTTPStorage_SharesEntriesBetweenCaches(t *testing.T) {
+	rs, server := newRemoteCacheServer(t, "s3cret")
+	config := &RemoteConfig{URL: server.URL + "/", Token: "s3cret"}
+
+	ci := newRemoteBuildCache(t, config)
+	require.NoError(t, ci.StoreTestResult("abc123", &TestResult{Package: "example.com/a", Success: true, Output: "ok"}))
+	require.NoError(t, ci.StoreLintResult("def456", &LintResult{Success: true}))
+	assert.Equal(t, 2, rs.puts)
+	assert.NotEmpty(t, rs.get("tests/abc123"))
+
+	dev := newRemoteBuildCache(t, config)
+	result, found := dev.GetTestResult("abc123")
+	require.True(t, found, "a local miss is served from the remote cache")
+	assert.Equal(t, "example.com/a", result.Package)
+
+	_, found = dev.GetLintResult("def456")
+	assert.True(t, found)
+
+	// The downloaded entry is now local, so it survives losing the remote
+	dev.SetRemote(nil)
+	_, found = dev.GetTestResult("abc123")
+	assert.True(t, found)
+
+	_, found = dev.GetTestResult("missing")
+	assert.False(t, found)
+}
+
+func TestHTTPStorage_RejectsTamperedEntries(t *testing.T) {
+	rs, server := newRemoteCacheServer(t, "")
+	config := &RemoteConfig{URL: server.URL}
+
+	ci := newRemoteBuildCache(t, config)
+	require.NoError(t, ci.StoreTestResult("abc123", &TestResult{Package: "example.com/a", Success: true}))
+	require.NoError(t, ci.StoreTestResult("def456", &TestResult{Package: "example.com/b", Success: true}))
+
+	original := rs.get("tests/abc123")
+	rs.set("tests/abc123", []byte(strings.Replace(string(original), `"sha256":"`, `"sha256":"00`, 1)))
+	rs.set("tests/def456", original) // valid envelope, but for another key
+
+	remote, err := NewHTTPStorage(config)
+	require.NoError(t, err)
+	_, err = remote.Get(context.Background(), "tests/abc123")
+	require.ErrorIs(t, err, ErrIntegrityMismatch)
+	_, err = remote.Get(context.Background(), "tests/def456")
+	require.ErrorIs(t, err, ErrIntegrityMismatch)
+
+	rs.set("tests/ghi789", []byte("not an envelope"))
+	_, err = remote.Get(context.Background(), "tests/ghi789")
+	require.ErrorIs(t, err, ErrIntegrityMismatch)
+
+	dev := newRemoteBuildCache(t, config)
+	_, found := dev.GetTestResult("abc123")
+	assert.False(t, found, "entries failing verification are misses")
+	_, found = dev.GetTestResult("def456")
+	assert.False(t, found)
+}
+
+func TestHTTPStorage_ReadOnly(t *testing.T) {
+	rs, server := newRemoteCacheServer(t, "")
+
+	writer := newRemoteBuildCache(t, &RemoteConfig{URL: server.URL})
+	require.NoError(t, writer.StoreTestResult("shared", &TestResult{Success: true}))
+
+	reader := newRemoteBuildCache(t, &RemoteConfig{URL: server.URL, ReadOnly: true})
+	require.NoError(t, reader.StoreTestResult("local", &TestResult{Success: true}),
+		"read-only mode still stores locally")
+	assert.Equal(t, 1, rs.puts, "read-only clients never upload")
+	assert.Nil(t, rs.get("tests/local"))
+
+	_, found := reader.GetTestResult("shared")
+	assert.True(t, found, "read-only clients still download")
+	_, found = reader.GetTestResult("local")
+	assert.True(t, found)
+
+	remote, err := NewHTTPStorage(&RemoteConfig{URL: server.URL, ReadOnly: true})
+	require.NoError(t, err)
+	assert.True(t, remote.ReadOnly())
+	require.ErrorIs(t, remote.Put(context.Background(), "tests/x", nil), ErrReadOnlyStorage)
+}
+
+func TestHTTPStorage_ServerErrors(t *testing.T) {
+	_, server := newRemoteCacheServer(t, "s3cret")
+
+	remote, err := NewHTTPStorage(&RemoteConfig{URL: server.URL, Token: "wrong"})
+	require.NoError(t, err)
+	_, err = remote.Get(context.Background(), "tests/abc123")
+	require.ErrorIs(t, err, ErrRemoteStatus)
+	require.ErrorIs(t, remote.Put(context.Background(), "tests/abc123", []byte("{}")), ErrRemoteStatus)
+
+	// An unreachable remote never fails local cache operations
+	c := newRemoteBuildCache(t, &RemoteConfig{URL: server.URL, Token: "wrong"})
+	require.NoError(t, c.StoreTestResult("abc123", &TestResult{Success: true}))
+	_, found := c.GetTestResult("abc123")
+	assert.True(t, found)
+}
+
+func TestManager_InitRemote(t *testing.T) {
+	_, server := newRemoteCacheServer(t, "")
+
+	config := DefaultConfig()
+	config.Directory = t.TempDir()
+	config.Remote = &RemoteConfig{URL: server.URL}
+	manager := NewManager(config)
+	require.NoError(t, manager.Init())
+	assert.NotNil(t, manager.GetBuildCache().remote)
+
+	config.Remote = &RemoteConfig{URL: "not a url"}
+	require.ErrorIs(t, NewManager(config).Init(), ErrInvalidRemoteURL)
+}
+
+func TestHTTPStorage_SigningKey(t *testing.T) {
+	rs, server := newRemoteCacheServer(t, "")
+	signed := &RemoteConfig{URL: server.URL, SigningKey: "ci-key"}
+
+	ci := newRemoteBuildCache(t, signed)
+	require.NoError(t, ci.StoreTestResult("abc123", &TestResult{Package: "example.com/a", Success: true}))
+
+	// A writer without the key can compute a matching digest but not the signature
+	attacker := newRemoteBuildCache(t, &RemoteConfig{URL: server.URL})
+	require.NoError(t, attacker.StoreTestResult("def456", &TestResult{Package: "example.com/evil", Success: true}))
+
+	remote, err := NewHTTPStorage(signed)
+	require.NoError(t, err)
+	_, err = remote.Get(context.Background(), "tests/abc123")
+	require.NoError(t, err)
+	_, err = remote.Get(context.Background(), "tests/def456")
+	require.ErrorIs(t, err, ErrIntegrityMismatch)
+
+	wrongKey, err := NewHTTPStorage(&RemoteConfig{URL: server.URL, SigningKey: "other"})
+	require.NoError(t, err)
+	_, err = wrongKey.Get(context.Background(), "tests/abc123")
+	require.ErrorIs(t, err, ErrIntegrityMismatch)
+
+	// Signed entries stay readable by clients that do not verify signatures
+	assert.Contains(t, string(rs.get("tests/abc123")), `"hmac":"`)
+	_, found := attacker.GetTestResult("abc123")
+	assert.True(t, found)
+}
@@ -24,6 +24,7 @@
 //
 //   - MAGE_X_BUILD_TAGS: Build tags to pass to go build
 //   - MAGE_X_CACHE_DISABLED: Disable build caching
+//   - MAGE_X_CACHE_REMOTE_URL: Shared remote build cache
 //   - MAGE_X_REQUIRE_CHECKSUMS: Require checksums for script downloads
 //   - DEBUG: Enable debug mode (disables binary stripping)
 //   - VERBOSE: Enable verbose output
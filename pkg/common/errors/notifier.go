@@ -2,12 +2,15 @@
 package errors
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/smtp"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +26,7 @@ var (
 	errCreateWebhookRequest = errors.New("failed to create webhook request")
 	errSendWebhook          = errors.New("failed to send webhook")
 	errWebhookBadStatus     = errors.New("webhook returned error status")
+	errEncodeWebhookPayload = errors.New("failed to encode webhook payload")
 	errMockNotify           = errors.New("mock notify error")
 	errMockNotifyContext    = errors.New("mock notify with context error")
 	errMockAddChannel       = errors.New("mock add channel error")
@@ -318,26 +322,48 @@ Timestamp: {{.Timestamp}}
 Source: {{.Source}}
 `
 
+// WebhookFormat selects the payload a WebhookChannel posts
+type WebhookFormat string
+
+const (
+	// WebhookFormatJSON posts a generic JSON document with the error and its context fields
+	WebhookFormatJSON WebhookFormat = "json"
+	// WebhookFormatSlack posts a Slack incoming-webhook message (also accepted by Mattermost and Rocket.Chat)
+	WebhookFormatSlack WebhookFormat = "slack"
+)
+
 // WebhookChannel implements NotificationChannel for webhook notifications
 type WebhookChannel struct {
 	name    string
 	url     string
 	headers map[string]string
+	format  WebhookFormat
 	enabled bool
 	client  *http.Client
 }
 
-// NewWebhookChannel creates a new webhook notification channel
+// NewWebhookChannel creates a new webhook notification channel that posts the generic JSON format
 func NewWebhookChannel(name, url string, headers map[string]string) *WebhookChannel {
 	return &WebhookChannel{
 		name:    name,
 		url:     url,
 		headers: headers,
+		format:  WebhookFormatJSON,
 		enabled: true,
 		client:  &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
+// SetFormat sets the payload format posted by the webhook channel
+func (w *WebhookChannel) SetFormat(format WebhookFormat) {
+	w.format = format
+}
+
+// Format returns the payload format posted by the webhook channel
+func (w *WebhookChannel) Format() WebhookFormat {
+	return w.format
+}
+
 // Name returns the name of the webhook channel.
 func (w *WebhookChannel) Name() string {
 	return w.name
@@ -349,26 +375,13 @@ func (w *WebhookChannel) Send(ctx context.Context, notification *ErrorNotificati
 		return nil
 	}
 
-	// Create JSON payload
-	payload := fmt.Sprintf(
-		`{
-		"error_code": "%s",
-		"message": "%s",
-		"severity": "%s",
-		"timestamp": "%s",
-		"environment": "%s",
-		"service": "%s"
-	}`,
-		notification.Error.Code(),
-		escapeJSON(notification.Error.Error()),
-		notification.Error.Severity().String(),
-		notification.Timestamp.Format(time.RFC3339),
-		notification.Environment,
-		notification.Service,
-	)
+	payload, err := w.payload(notification)
+	if err != nil {
+		return err
+	}
 
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", w.url, strings.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewReader(payload))
 	if err != nil {
 		return fmt.Errorf("%w: %w", errCreateWebhookRequest, err)
 	}
@@ -398,6 +411,116 @@ func (w *WebhookChannel) Send(ctx context.Context, notification *ErrorNotificati
 	return nil
 }
 
+// webhookPayload is the generic JSON webhook body
+type webhookPayload struct {
+	ErrorCode   string         `json:"error_code"`
+	Message     string         `json:"message"`
+	Severity    string         `json:"severity"`
+	Timestamp   string         `json:"timestamp"`
+	Environment string         `json:"environment"`
+	Service     string         `json:"service"`
+	Hostname    string         `json:"hostname,omitempty"`
+	Operation   string         `json:"operation,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+}
+
+// slackPayload is a Slack incoming-webhook message
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+// slackAttachment carries the error details under the message
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Text   string       `json:"text,omitempty"`
+	Fields []slackField `json:"fields,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+// slackField is one title/value pair of a Slack attachment
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// payload encodes the notification in the channel's format
+func (w *WebhookChannel) payload(notification *ErrorNotification) ([]byte, error) {
+	errCtx := notification.Error.Context()
+
+	var body any
+	switch w.format {
+	case WebhookFormatSlack:
+		body = slackMessage(notification, &errCtx)
+	default:
+		body = webhookPayload{
+			ErrorCode:   string(notification.Error.Code()),
+			Message:     notification.Error.Error(),
+			Severity:    notification.Error.Severity().String(),
+			Timestamp:   notification.Timestamp.Format(time.RFC3339),
+			Environment: notification.Environment,
+			Service:     notification.Service,
+			Hostname:    notification.Hostname,
+			Operation:   errCtx.Operation,
+			Fields:      errCtx.Fields,
+		}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errEncodeWebhookPayload, err)
+	}
+	return payload, nil
+}
+
+// slackMessage renders a notification as a Slack message with one attachment
+func slackMessage(notification *ErrorNotification, errCtx *ErrorContext) slackPayload {
+	severity := notification.Error.Severity()
+	title := fmt.Sprintf("[%s] %s", severity.String(), notification.Error.Code())
+	if errCtx.Operation != "" {
+		title = fmt.Sprintf("[%s] %s failed", severity.String(), errCtx.Operation)
+	}
+
+	color := "#439FE0"
+	switch {
+	case severity >= SeverityError:
+		color = "danger"
+	case severity == SeverityWarning:
+		color = "warning"
+	}
+
+	keys := make([]string, 0, len(errCtx.Fields))
+	for key := range errCtx.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]slackField, 0, len(keys))
+	for _, key := range keys {
+		value := formatSlackValue(errCtx.Fields[key])
+		fields = append(fields, slackField{Title: key, Value: value, Short: len(value) <= 40 && !strings.Contains(value, "\n")})
+	}
+
+	return slackPayload{
+		Text: fmt.Sprintf("*%s* (%s)", title, notification.Service),
+		Attachments: []slackAttachment{{
+			Color:  color,
+			Text:   "```" + notification.Error.Error() + "```",
+			Fields: fields,
+			Ts:     notification.Timestamp.Unix(),
+		}},
+	}
+}
+
+// formatSlackValue renders a context field value as Slack text; lists become one item per line
+func formatSlackValue(value any) string {
+	if items, ok := value.([]string); ok {
+		return strings.Join(items, "\n")
+	}
+	return fmt.Sprint(value)
+}
+
 // IsEnabled returns whether the webhook channel is enabled.
 func (w *WebhookChannel) IsEnabled() bool {
 	return w.enabled
@@ -466,15 +589,6 @@ func formatErrorContext(errCtx *ErrorContext) string {
 	return strings.Join(lines, "\n")
 }
 
-func escapeJSON(s string) string {
-	s = strings.ReplaceAll(s, `\`, `\\`)
-	s = strings.ReplaceAll(s, `"`, `\"`)
-	s = strings.ReplaceAll(s, "\n", `\n`)
-	s = strings.ReplaceAll(s, "\r", `\r`)
-	s = strings.ReplaceAll(s, "\t", `\t`)
-	return s
-}
-
 // MockErrorNotifier implements ErrorNotifier for testing
 type MockErrorNotifier struct {
 	NotifyCalls            []error
@@ -2,6 +2,10 @@ package errors
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -96,26 +100,88 @@ func TestFormatErrorContext(t *testing.T) {
 	})
 }
 
-func TestEscapeJSON(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{"empty", "", ""},
-		{"no escaping", "hello world", "hello world"},
-		{"backslash", `a\b`, `a\\b`},
-		{"quote", `a"b`, `a\"b`},
-		{"newline", "a\nb", `a\nb`},
-		{"carriage return", "a\rb", `a\rb`},
-		{"tab", "a\tb", `a\tb`},
-		{"combined", "a\"\n\\b", `a\"\n\\b`},
+// webhookStandIn records the body and headers of the last request it received
+func webhookStandIn(t *testing.T, status int) (*httptest.Server, func() (map[string]any, http.Header)) {
+	t.Helper()
+	var body []byte
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		headers = r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (map[string]any, http.Header) {
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(body, &decoded))
+		return decoded, headers
 	}
+}
 
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			result := escapeJSON(tc.input)
-			assert.Equal(t, tc.expected, result)
-		})
+func testCommandNotification() *ErrorNotification {
+	err := NewErrorBuilder().
+		WithMessage("magex test:unit failed after 2m").
+		WithCode(ErrCommandFailed).
+		WithOperation("test:unit").
+		WithField("duration", "2m").
+		WithField("error_chain", []string{"tests failed", "exit status 1"}).
+		Build()
+	return &ErrorNotification{
+		Error:       err,
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Environment: "ci",
+		Service:     "magex",
 	}
 }
+
+func TestWebhookChannel_SendJSON(t *testing.T) {
+	server, received := webhookStandIn(t, http.StatusOK)
+	channel := NewWebhookChannel("hook", server.URL, map[string]string{"Authorization": "Bearer token"})
+	assert.Equal(t, WebhookFormatJSON, channel.Format())
+
+	require.NoError(t, channel.Send(context.Background(), testCommandNotification()))
+
+	payload, headers := received()
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	assert.Equal(t, "COMMAND_FAILED", payload["error_code"])
+	assert.Equal(t, "magex test:unit failed after 2m", payload["message"])
+	assert.Equal(t, "ERROR", payload["severity"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", payload["timestamp"])
+	assert.Equal(t, "test:unit", payload["operation"])
+	fields, ok := payload["fields"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{"tests failed", "exit status 1"}, fields["error_chain"])
+}
+
+func TestWebhookChannel_SendSlack(t *testing.T) {
+	server, received := webhookStandIn(t, http.StatusOK)
+	channel := NewWebhookChannel("slack", server.URL, nil)
+	channel.SetFormat(WebhookFormatSlack)
+
+	require.NoError(t, channel.Send(context.Background(), testCommandNotification()))
+
+	payload, _ := received()
+	assert.Equal(t, "*[ERROR] test:unit failed* (magex)", payload["text"])
+	attachments, ok := payload["attachments"].([]any)
+	require.True(t, ok)
+	require.Len(t, attachments, 1)
+	attachment, ok := attachments[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "danger", attachment["color"])
+	assert.Equal(t, "```magex test:unit failed after 2m```", attachment["text"])
+	assert.Equal(t, []any{
+		map[string]any{"title": "duration", "value": "2m", "short": true},
+		map[string]any{"title": "error_chain", "value": "tests failed\nexit status 1", "short": false},
+	}, attachment["fields"])
+}
+
+func TestWebhookChannel_SendErrorStatus(t *testing.T) {
+	server, _ := webhookStandIn(t, http.StatusInternalServerError)
+	channel := NewWebhookChannel("hook", server.URL, nil)
+
+	err := channel.Send(context.Background(), testCommandNotification())
+	require.ErrorIs(t, err, errWebhookBadStatus)
+}
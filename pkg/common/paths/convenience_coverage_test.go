@@ -602,6 +602,7 @@ func TestMockPathCacheFunctions(t *testing.T) {
 func TestGetDefaultWatcher(t *testing.T) {
 	watcher := GetDefaultWatcher()
 	assert.NotNil(t, watcher)
+	assert.IsType(t, &DefaultPathWatcher{}, watcher, "native watching is opt-in")
 }
 
 // TestDefaultOptionsManagement tests the default options functions
@@ -123,6 +123,12 @@ type PathWatcher interface {
 	WatchedPaths() []string
 }
 
+// IgnoringPathWatcher is implemented by PathWatchers that can skip files and
+// directories entirely; check for it with a type assertion
+type IgnoringPathWatcher interface {
+	SetIgnorePatterns(patterns ...string) PathWatcher
+}
+
 // PathCache provides caching for path operations
 type PathCache interface {
 	// Cache operations
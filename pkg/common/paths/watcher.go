@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io/fs"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,7 +15,10 @@ import (
 
 // Error definitions for watcher operations
 var (
-	ErrWatcherMockError = errors.New("mock error")
+	ErrWatcherMockError         = errors.New("mock error")
+	ErrWatcherClosed            = errors.New("path watcher is closed")
+	ErrNativeWatcherUnsupported = errors.New("native file notifications are not supported on this platform")
+	ErrWatchLimitReached        = errors.New("inotify watch limit reached; raise fs.inotify.max_user_watches")
 )
 
 // DefaultPathWatcher implements the PathWatcher interface
@@ -30,6 +34,7 @@ type DefaultPathWatcher struct {
 	cancel       context.CancelFunc
 	running      bool
 	lastSeen     map[string]time.Time
+	ignore       []string
 }
 
 // NewPathWatcher creates a new path watcher with default settings
@@ -49,6 +54,20 @@ func NewPathWatcher() PathWatcher {
 	}
 }
 
+// NewNativePathWatcher creates a watcher driven by operating system file
+// notifications (inotify on Linux), which reports changes as they happen instead
+// of rescanning the tree. Where native notifications are unavailable it falls back
+// to the polling watcher returned by NewPathWatcher. Native watching is opt-in:
+// GetDefaultWatcher and NewPathWatcher always poll.
+func NewNativePathWatcher() PathWatcher {
+	watcher, err := newNativePathWatcher()
+	if err != nil {
+		log.Debug("path watcher: native notifications unavailable, polling instead: %v", err)
+		return NewPathWatcher()
+	}
+	return watcher
+}
+
 // Watch starts watching a path for specific events
 func (w *DefaultPathWatcher) Watch(path string, events EventMask) error {
 	w.mu.Lock()
@@ -60,6 +79,7 @@ func (w *DefaultPathWatcher) Watch(path string, events EventMask) error {
 	}
 
 	w.watchedPaths[absPath] = events
+	w.prime(absPath)
 
 	// Start watching if not already running
 	if !w.running {
@@ -118,12 +138,14 @@ func (w *DefaultPathWatcher) Close() error {
 	return nil
 }
 
-// SetBufferSize sets the buffer size for event channels
+// SetBufferSize sets the capacity of the events channel. The channel is replaced,
+// keeping buffered events, so call it before Events.
 func (w *DefaultPathWatcher) SetBufferSize(size int) PathWatcher {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	w.bufferSize = size
+	w.events = resizeEventChannel(w.events, size)
+	w.bufferSize = cap(w.events)
 	return w
 }
 
@@ -145,6 +167,15 @@ func (w *DefaultPathWatcher) SetDebounce(duration time.Duration) PathWatcher {
 	return w
 }
 
+// SetIgnorePatterns sets the patterns of files and directories that never produce events
+func (w *DefaultPathWatcher) SetIgnorePatterns(patterns ...string) PathWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.ignore = append([]string(nil), patterns...)
+	return w
+}
+
 // IsWatching returns true if the path is being watched
 func (w *DefaultPathWatcher) IsWatching(path string) bool {
 	w.mu.RLock()
@@ -172,6 +203,35 @@ func (w *DefaultPathWatcher) WatchedPaths() []string {
 	return paths
 }
 
+// prime records the modification times of files already present under path so that
+// only changes made after Watch was called are reported. Callers must hold w.mu.
+func (w *DefaultPathWatcher) prime(path string) {
+	err := filepath.Walk(path, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable entries are reported by the watch loop
+		}
+		if ignoredPath(w.ignore, path, p) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !w.recursive && p != path && filepath.Dir(p) != path {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, seen := w.lastSeen[p]; !seen {
+			w.lastSeen[p] = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debug("path watcher: failed to prime %s: %v", path, err)
+	}
+}
+
 // watchLoop is the main watching loop
 func (w *DefaultPathWatcher) watchLoop() {
 	w.mu.RLock()
@@ -199,6 +259,7 @@ func (w *DefaultPathWatcher) checkForChanges() {
 		watchedPaths[path] = events
 	}
 	recursive := w.recursive
+	ignore := w.ignore
 	w.mu.RUnlock()
 
 	for watchPath, eventMask := range watchedPaths {
@@ -209,6 +270,13 @@ func (w *DefaultPathWatcher) checkForChanges() {
 				return nil
 			}
 
+			if ignoredPath(ignore, watchPath, path) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			// Skip if not recursive and not in the root directory
 			if !recursive && filepath.Dir(path) != watchPath {
 				if info.IsDir() {
@@ -263,6 +331,55 @@ func (w *DefaultPathWatcher) checkFileForChanges(path string, info fs.FileInfo,
 	}
 }
 
+// resizeEventChannel returns a channel with the given capacity holding the events
+// still buffered in ch. ch is returned unchanged when size is not positive, the
+// buffered events do not fit or ch is closed. Callers must keep senders out.
+func resizeEventChannel(ch chan *PathEvent, size int) chan *PathEvent {
+	if size <= 0 || size == cap(ch) || len(ch) > size {
+		return ch
+	}
+	resized := make(chan *PathEvent, size)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return ch
+			}
+			resized <- event
+		default:
+			return resized
+		}
+	}
+}
+
+// ignoredPath reports whether path, found under the watched root, matches one of
+// the ignore patterns. A pattern without a separator is matched against the name
+// of the file or directory (e.g. ".git", "*.tmp"); a pattern with one is matched
+// against the slash-separated path relative to root (e.g. "build/cache"). The root
+// itself is never ignored.
+func ignoredPath(patterns []string, root, path string) bool {
+	if len(patterns) == 0 || path == root {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	name := filepath.Base(path)
+	for _, pattern := range patterns {
+		pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if matched, matchErr := filepath.Match(pattern, target); matchErr == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // emitEvent sends an event to the events channel.
 //
 // The read lock is held for the entire send so that Close, which acquires the
@@ -315,6 +432,7 @@ type MockPathWatcher struct {
 	SetBufferSizeCalls []int
 	SetRecursiveCalls  []bool
 	SetDebounceCalls   []time.Duration
+	SetIgnoreCalls     [][]string
 	ShouldError        bool
 	WatchedPathsList   []string
 	MockEvents         chan *PathEvent
@@ -422,6 +540,12 @@ func (m *MockPathWatcher) SetDebounce(duration time.Duration) PathWatcher {
 	return m
 }
 
+// SetIgnorePatterns records the ignore patterns for the mock watcher
+func (m *MockPathWatcher) SetIgnorePatterns(patterns ...string) PathWatcher {
+	m.SetIgnoreCalls = append(m.SetIgnoreCalls, patterns)
+	return m
+}
+
 // IsWatching checks if a path is being watched by the mock watcher
 func (m *MockPathWatcher) IsWatching(path string) bool {
 	m.IsWatchingCalls = append(m.IsWatchingCalls, path)
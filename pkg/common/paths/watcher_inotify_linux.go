@@ -0,0 +1,534 @@
+package paths
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrEventOverflow is reported by the inotify watcher when the kernel dropped events
+var ErrEventOverflow = errors.New("inotify event queue overflowed; some changes were missed")
+
+const (
+	// inotifyMask is the set of inotify events subscribed to for every watch
+	inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_DELETE |
+		syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+	// inotifyReadBuffer holds many events per read; each is a header plus a NUL-padded name
+	inotifyReadBuffer = 64 * (syscall.SizeofInotifyEvent + syscall.NAME_MAX + 1)
+
+	// inotifySource is the PathEvent.Source of events from the inotify watcher
+	inotifySource = "inotify"
+)
+
+// inotifyWatch is one kernel watch: a directory (or a watched file) and the root it belongs to
+type inotifyWatch struct {
+	path string
+	root string
+}
+
+// InotifyPathWatcher implements PathWatcher with Linux inotify. Each directory
+// under a watched root gets its own kernel watch; directories created or moved in
+// after Watch are added as they appear, and those removed or moved out are dropped.
+type InotifyPathWatcher struct {
+	mu           sync.RWMutex
+	fd           int
+	file         *os.File
+	watchedPaths map[string]EventMask
+	watches      map[int]*inotifyWatch
+	dirs         map[string]int
+	events       chan *PathEvent
+	errors       chan error
+	bufferSize   int
+	recursive    bool
+	debounce     time.Duration
+	ignore       []string
+	pending      map[string]bool
+	running      bool
+	loopDone     chan struct{}
+}
+
+// newNativePathWatcher creates the inotify watcher
+func newNativePathWatcher() (PathWatcher, error) {
+	return NewInotifyPathWatcher()
+}
+
+// NewInotifyPathWatcher creates a watcher backed by an inotify instance
+func NewInotifyPathWatcher() (*InotifyPathWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify_init1: %w", err)
+	}
+
+	// A non-blocking descriptor is registered with the runtime poller, so reads
+	// park the goroutine and Close unblocks them
+	w := &InotifyPathWatcher{
+		fd:           fd,
+		file:         os.NewFile(uintptr(fd), "inotify"),
+		watchedPaths: make(map[string]EventMask),
+		watches:      make(map[int]*inotifyWatch),
+		dirs:         make(map[string]int),
+		events:       make(chan *PathEvent, 1000),
+		errors:       make(chan error, 100),
+		bufferSize:   1000,
+		recursive:    true,
+		debounce:     100 * time.Millisecond,
+		pending:      make(map[string]bool),
+		running:      true,
+		loopDone:     make(chan struct{}),
+	}
+	go w.readLoop()
+	return w, nil
+}
+
+// Watch starts watching a path for specific events. Directories are watched
+// recursively unless SetRecursive(false) was called.
+func (w *InotifyPathWatcher) Watch(path string, events EventMask) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", absPath, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		return ErrWatcherClosed
+	}
+	if !info.IsDir() {
+		if err := w.addWatch(absPath, absPath); err != nil {
+			return err
+		}
+	} else if _, err := w.addTree(absPath, absPath); err != nil {
+		w.removeRoot(absPath)
+		return err
+	}
+	w.watchedPaths[absPath] = events
+	return nil
+}
+
+// WatchPath starts watching a PathBuilder for specific events
+func (w *InotifyPathWatcher) WatchPath(path PathBuilder, events EventMask) error {
+	return w.Watch(path.String(), events)
+}
+
+// Unwatch stops watching a specific path
+func (w *InotifyPathWatcher) Unwatch(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.removeRoot(absPath)
+	delete(w.watchedPaths, absPath)
+	return nil
+}
+
+// UnwatchPath stops watching a specific PathBuilder
+func (w *InotifyPathWatcher) UnwatchPath(path PathBuilder) error {
+	return w.Unwatch(path.String())
+}
+
+// Events returns the channel for receiving path events
+func (w *InotifyPathWatcher) Events() <-chan *PathEvent {
+	return w.events
+}
+
+// Errors returns the channel for receiving error events
+func (w *InotifyPathWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close releases the inotify instance and closes all channels
+func (w *InotifyPathWatcher) Close() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = false
+	w.mu.Unlock()
+
+	// Closing the file wakes the read loop, which drops the kernel watches with it
+	err := w.file.Close()
+	<-w.loopDone
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	close(w.events)
+	close(w.errors)
+	if err != nil {
+		return fmt.Errorf("failed to close inotify instance: %w", err)
+	}
+	return nil
+}
+
+// SetBufferSize sets the capacity of the events channel. The channel is replaced,
+// keeping buffered events, so call it before Events.
+func (w *InotifyPathWatcher) SetBufferSize(size int) PathWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = resizeEventChannel(w.events, size)
+	w.bufferSize = cap(w.events)
+	return w
+}
+
+// SetRecursive sets whether directories under a watched path are watched too.
+// It applies to paths watched afterwards.
+func (w *InotifyPathWatcher) SetRecursive(recursive bool) PathWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.recursive = recursive
+	return w
+}
+
+// SetDebounce sets the window over which repeats of the same event for the same
+// path are folded into one, which collapses the burst of writes an editor save
+// produces. The first event is reported at once and any repeats within the window
+// are reported once when it ends, so the last change is never lost.
+func (w *InotifyPathWatcher) SetDebounce(duration time.Duration) PathWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.debounce = duration
+	return w
+}
+
+// SetIgnorePatterns sets the patterns of files and directories that never produce
+// events; ignored directories are not watched at all
+func (w *InotifyPathWatcher) SetIgnorePatterns(patterns ...string) PathWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.ignore = append([]string(nil), patterns...)
+	return w
+}
+
+// IsWatching returns true if the path is being watched
+func (w *InotifyPathWatcher) IsWatching(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, exists := w.watchedPaths[absPath]
+	return exists
+}
+
+// WatchedPaths returns all currently watched paths
+func (w *InotifyPathWatcher) WatchedPaths() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	paths := make([]string, 0, len(w.watchedPaths))
+	for path := range w.watchedPaths {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// addWatch adds a kernel watch for path. Adding an inode that is already watched
+// returns its existing descriptor, which is re-pointed at the new path (a move
+// within the tree). Callers must hold w.mu.
+func (w *InotifyPathWatcher) addWatch(path, root string) error {
+	wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+	if err != nil {
+		if errors.Is(err, syscall.ENOSPC) {
+			return fmt.Errorf("%w (%s)", ErrWatchLimitReached, path)
+		}
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+	if old, ok := w.watches[wd]; ok {
+		delete(w.dirs, old.path)
+	}
+	w.watches[wd] = &inotifyWatch{path: path, root: root}
+	w.dirs[path] = wd
+	return nil
+}
+
+// addTree watches dir and, when recursive, every directory below it that is not
+// ignored. It returns the files found in directories below dir, so changes made
+// before their watch existed can be reported. Callers must hold w.mu.
+func (w *InotifyPathWatcher) addTree(dir, root string) ([]string, error) {
+	if !w.recursive {
+		if dir != root {
+			return nil, nil
+		}
+		return nil, w.addWatch(dir, root)
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil //nolint:nilerr // entries that vanish or cannot be read are skipped
+		}
+		if ignoredPath(w.ignore, root, path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+		if err := w.addWatch(path, root); err != nil {
+			if path == dir || errors.Is(err, ErrWatchLimitReached) {
+				return err
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return files, err
+}
+
+// removeTree drops the watches of dir and every directory below it. Callers must hold w.mu.
+func (w *InotifyPathWatcher) removeTree(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path, wd := range w.dirs {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			_, _ = syscall.InotifyRmWatch(w.fd, uint32(wd)) //nolint:errcheck,gosec // the kernel may already have dropped it
+			delete(w.dirs, path)
+			delete(w.watches, wd)
+		}
+	}
+}
+
+// removeRoot drops every watch that belongs to root. Callers must hold w.mu.
+func (w *InotifyPathWatcher) removeRoot(root string) {
+	for wd, watch := range w.watches {
+		if watch.root == root {
+			_, _ = syscall.InotifyRmWatch(w.fd, uint32(wd)) //nolint:errcheck,gosec // the kernel may already have dropped it
+			delete(w.dirs, watch.path)
+			delete(w.watches, wd)
+		}
+	}
+}
+
+// readLoop reads and dispatches inotify events until the file is closed
+func (w *InotifyPathWatcher) readLoop() {
+	defer close(w.loopDone)
+
+	buf := make([]byte, inotifyReadBuffer)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				w.emitError(fmt.Errorf("failed to read inotify events: %w", err))
+			}
+			return
+		}
+		for _, raw := range parseInotifyEvents(buf[:n]) {
+			w.handle(raw)
+		}
+	}
+}
+
+// rawInotifyEvent is one decoded inotify_event
+type rawInotifyEvent struct {
+	wd   int
+	mask uint32
+	name string
+}
+
+// parseInotifyEvents decodes the inotify_event records in buf
+func parseInotifyEvents(buf []byte) []rawInotifyEvent {
+	var events []rawInotifyEvent
+	for len(buf) >= syscall.SizeofInotifyEvent {
+		wd := int32(binary.NativeEndian.Uint32(buf[0:4])) //nolint:gosec // wd is a C int
+		mask := binary.NativeEndian.Uint32(buf[4:8])
+		nameLen := int(binary.NativeEndian.Uint32(buf[12:16]))
+		end := syscall.SizeofInotifyEvent + nameLen
+		if end > len(buf) {
+			break
+		}
+		name := strings.TrimRight(string(buf[syscall.SizeofInotifyEvent:end]), "\x00")
+		events = append(events, rawInotifyEvent{wd: int(wd), mask: mask, name: name})
+		buf = buf[end:]
+	}
+	return events
+}
+
+// inotifyOp maps an inotify mask to the PathWatcher event it reports. A move into
+// a watched directory is a create and a move out of one is a rename, so a rename
+// within the tree reports the old path as renamed and the new one as created.
+func inotifyOp(mask uint32) EventMask {
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		return EventCreate
+	case mask&syscall.IN_MODIFY != 0:
+		return EventWrite
+	case mask&(syscall.IN_DELETE|syscall.IN_DELETE_SELF) != 0:
+		return EventRemove
+	case mask&(syscall.IN_MOVED_FROM|syscall.IN_MOVE_SELF) != 0:
+		return EventRename
+	case mask&syscall.IN_ATTRIB != 0:
+		return EventChmod
+	default:
+		return 0
+	}
+}
+
+// handle updates the watch set for one kernel event and reports it
+func (w *InotifyPathWatcher) handle(raw rawInotifyEvent) {
+	if raw.mask&syscall.IN_Q_OVERFLOW != 0 {
+		w.emitError(ErrEventOverflow)
+		return
+	}
+
+	w.mu.Lock()
+	watch, ok := w.watches[raw.wd]
+	if !ok {
+		w.mu.Unlock()
+		return
+	}
+	if raw.mask&syscall.IN_IGNORED != 0 {
+		// The kernel dropped the watch: its directory was deleted or unmounted
+		delete(w.dirs, watch.path)
+		delete(w.watches, raw.wd)
+		w.mu.Unlock()
+		return
+	}
+
+	path := watch.path
+	if raw.name != "" {
+		path = filepath.Join(watch.path, raw.name)
+	}
+	root := watch.root
+	mask, watched := w.watchedPaths[root]
+	if !watched || ignoredPath(w.ignore, root, path) {
+		w.mu.Unlock()
+		return
+	}
+	// The watched root itself is reported by its parent's watch, if any, so its
+	// own delete/move notifications only drop the watches below it
+	if raw.name == "" && raw.mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF) != 0 && path != root {
+		w.mu.Unlock()
+		return
+	}
+
+	isDir := raw.mask&syscall.IN_ISDIR != 0
+	var created []string
+	var addErr error
+	switch {
+	case isDir && raw.mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		created, addErr = w.addTree(path, root)
+	case isDir && raw.mask&(syscall.IN_MOVED_FROM|syscall.IN_DELETE) != 0:
+		w.removeTree(path)
+	case path == root && raw.mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF) != 0:
+		w.removeRoot(root)
+	}
+	w.mu.Unlock()
+
+	if addErr != nil {
+		w.emitError(addErr)
+	}
+	op := inotifyOp(raw.mask)
+	if op&mask != 0 {
+		w.emitEvent(path, op)
+	}
+	// Files that appeared in a new directory before its watch was added
+	if mask&EventCreate != 0 {
+		for _, file := range created {
+			w.emitEvent(file, EventCreate)
+		}
+	}
+}
+
+// emitEvent reports path, folding repeats of the same event within the debounce
+// window into one trailing event sent when the window ends
+func (w *InotifyPathWatcher) emitEvent(path string, op EventMask) {
+	key := fmt.Sprintf("%d:%s", op, path)
+
+	w.mu.Lock()
+	debounce := w.debounce
+	if debounce > 0 {
+		if _, open := w.pending[key]; open {
+			w.pending[key] = true
+			w.mu.Unlock()
+			return
+		}
+		w.pending[key] = false
+		time.AfterFunc(debounce, func() { w.flushDebounced(key, path, op) })
+	}
+	w.mu.Unlock()
+
+	w.sendEvent(path, op)
+}
+
+// flushDebounced ends the debounce window of key, reporting the event again when
+// it repeated within the window
+func (w *InotifyPathWatcher) flushDebounced(key, path string, op EventMask) {
+	w.mu.Lock()
+	repeated := w.pending[key]
+	delete(w.pending, key)
+	w.mu.Unlock()
+
+	if repeated {
+		w.emitEvent(path, op)
+	}
+}
+
+// sendEvent sends an event for path without blocking. The read lock is held for
+// the send so Close cannot close the channel under it.
+func (w *InotifyPathWatcher) sendEvent(path string, op EventMask) {
+	event := &PathEvent{Path: path, Op: op, Time: time.Now(), Source: inotifySource}
+	if op != EventRemove && op != EventRename {
+		if info, err := os.Lstat(path); err == nil {
+			event.Info = info
+		}
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if !w.running {
+		return
+	}
+	select {
+	case w.events <- event:
+	default:
+		// Events channel is full, drop the event
+	}
+}
+
+// emitError sends an error to the errors channel without blocking
+func (w *InotifyPathWatcher) emitError(err error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if !w.running {
+		return
+	}
+	select {
+	case w.errors <- err:
+	default:
+		// Errors channel is full, drop the error
+	}
+}
+
+// Ensure InotifyPathWatcher implements PathWatcher
+var _ PathWatcher = (*InotifyPathWatcher)(nil)
@@ -0,0 +1,245 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInotifyWatcher returns an inotify watcher watching a fresh temp dir
+func newTestInotifyWatcher(t *testing.T, events EventMask, ignore ...string) (*InotifyPathWatcher, string) {
+	t.Helper()
+
+	watcher, err := NewInotifyPathWatcher()
+	require.NoError(t, err)
+	watcher.SetDebounce(10 * time.Millisecond)
+	watcher.SetIgnorePatterns(ignore...)
+	t.Cleanup(func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	})
+
+	tempDir := t.TempDir()
+	require.NoError(t, watcher.Watch(tempDir, events))
+	return watcher, tempDir
+}
+
+// waitForEvent reads events until one for path with op arrives, collecting the
+// paths of every event seen on the way
+func waitForEvent(t *testing.T, watcher PathWatcher, path string, op EventMask) []string {
+	t.Helper()
+
+	var seen []string
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-watcher.Events():
+			seen = append(seen, event.Path)
+			if event.Path == path && event.Op == op {
+				assert.Equal(t, inotifySource, event.Source)
+				return seen
+			}
+		case err := <-watcher.Errors():
+			t.Fatalf("unexpected watcher error: %v", err)
+		case <-timeout:
+			t.Fatalf("expected event %d for %s, saw %v", op, path, seen)
+		}
+	}
+}
+
+func TestInotifyPathWatcher_CreateAndWrite(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventCreate|EventWrite)
+
+	filePath := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(filePath, []byte("package main"), 0o600))
+	waitForEvent(t, watcher, filePath, EventCreate)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n"), 0o600))
+	waitForEvent(t, watcher, filePath, EventWrite)
+}
+
+func TestInotifyPathWatcher_DebounceReportsTrailingWrite(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventWrite)
+	watcher.SetDebounce(200 * time.Millisecond)
+
+	filePath := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(filePath, nil, 0o600))
+	require.NoError(t, os.WriteFile(filePath, []byte("package main"), 0o600))
+	waitForEvent(t, watcher, filePath, EventWrite)
+
+	// Repeats within the window are folded into one event sent when it ends
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n\n"), 0o600))
+	waitForEvent(t, watcher, filePath, EventWrite)
+
+	select {
+	case event := <-watcher.Events():
+		t.Fatalf("unexpected event after the window: %+v", event)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestInotifyPathWatcher_RemoveAndChmod(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventRemove|EventChmod)
+
+	filePath := filepath.Join(tempDir, "doomed.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("x"), 0o600))
+	require.NoError(t, os.Chmod(filePath, 0o400)) //nolint:gosec // test file
+	waitForEvent(t, watcher, filePath, EventChmod)
+
+	require.NoError(t, os.Remove(filePath))
+	waitForEvent(t, watcher, filePath, EventRemove)
+}
+
+func TestInotifyPathWatcher_NewDirectories(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventCreate)
+
+	nested := filepath.Join(tempDir, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o750))
+	waitForEvent(t, watcher, filepath.Join(tempDir, "a"), EventCreate)
+
+	// The new directories are watched in turn
+	filePath := filepath.Join(nested, "deep.go")
+	require.NoError(t, os.WriteFile(filePath, []byte("package b"), 0o600))
+	waitForEvent(t, watcher, filePath, EventCreate)
+}
+
+func TestInotifyPathWatcher_DirectoryMovedIn(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventCreate)
+
+	// A tree built outside the watched root and moved in
+	outside := filepath.Join(t.TempDir(), "pkg")
+	require.NoError(t, os.MkdirAll(filepath.Join(outside, "sub"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "sub", "old.go"), []byte("package sub"), 0o600))
+
+	moved := filepath.Join(tempDir, "pkg")
+	require.NoError(t, os.Rename(outside, moved))
+	waitForEvent(t, watcher, moved, EventCreate)
+	waitForEvent(t, watcher, filepath.Join(moved, "sub", "old.go"), EventCreate)
+
+	filePath := filepath.Join(moved, "sub", "new.go")
+	require.NoError(t, os.WriteFile(filePath, []byte("package sub"), 0o600))
+	waitForEvent(t, watcher, filePath, EventCreate)
+}
+
+func TestInotifyPathWatcher_Rename(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventCreate|EventRename)
+
+	oldDir := filepath.Join(tempDir, "old")
+	require.NoError(t, os.Mkdir(oldDir, 0o750))
+	waitForEvent(t, watcher, oldDir, EventCreate)
+
+	newDir := filepath.Join(tempDir, "new")
+	require.NoError(t, os.Rename(oldDir, newDir))
+	waitForEvent(t, watcher, oldDir, EventRename)
+	waitForEvent(t, watcher, newDir, EventCreate)
+
+	// Events below the renamed directory carry its new path
+	filePath := filepath.Join(newDir, "file.go")
+	require.NoError(t, os.WriteFile(filePath, []byte("package new"), 0o600))
+	waitForEvent(t, watcher, filePath, EventCreate)
+
+	// Moving it out of the tree drops its watch
+	require.NoError(t, os.Rename(newDir, filepath.Join(t.TempDir(), "gone")))
+	waitForEvent(t, watcher, newDir, EventRename)
+	watcher.mu.RLock()
+	_, watched := watcher.dirs[newDir]
+	watcher.mu.RUnlock()
+	assert.False(t, watched)
+}
+
+func TestInotifyPathWatcher_IgnorePatterns(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, ".git"), 0o750))
+
+	watcher, err := NewInotifyPathWatcher()
+	require.NoError(t, err)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+	watcher.SetIgnorePatterns(".git", "vendor", "*.swp")
+	require.NoError(t, watcher.Watch(tempDir, EventCreate))
+
+	watcher.mu.RLock()
+	_, gitWatched := watcher.dirs[filepath.Join(tempDir, ".git")]
+	watcher.mu.RUnlock()
+	assert.False(t, gitWatched, "ignored directories get no watch")
+
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, "vendor"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "vendor", "dep.go"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".main.go.swp"), []byte("x"), 0o600))
+	kept := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(kept, []byte("package main"), 0o600))
+
+	seen := waitForEvent(t, watcher, kept, EventCreate)
+	assert.Equal(t, []string{kept}, seen, "ignored paths must not be reported")
+}
+
+func TestInotifyPathWatcher_NonRecursive(t *testing.T) {
+	watcher, err := NewInotifyPathWatcher()
+	require.NoError(t, err)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+	watcher.SetRecursive(false)
+
+	tempDir := t.TempDir()
+	subDir := filepath.Join(tempDir, "sub")
+	require.NoError(t, os.Mkdir(subDir, 0o750))
+	require.NoError(t, watcher.Watch(tempDir, EventCreate))
+
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "skip.go"), []byte("x"), 0o600))
+	kept := filepath.Join(tempDir, "top.go")
+	require.NoError(t, os.WriteFile(kept, []byte("x"), 0o600))
+
+	seen := waitForEvent(t, watcher, kept, EventCreate)
+	assert.Equal(t, []string{kept}, seen)
+}
+
+func TestInotifyPathWatcher_UnwatchAndClose(t *testing.T) {
+	watcher, tempDir := newTestInotifyWatcher(t, EventAll)
+
+	assert.True(t, watcher.IsWatching(tempDir))
+	assert.Equal(t, []string{tempDir}, watcher.WatchedPaths())
+
+	require.NoError(t, watcher.Unwatch(tempDir))
+	assert.False(t, watcher.IsWatching(tempDir))
+	watcher.mu.RLock()
+	assert.Empty(t, watcher.watches)
+	watcher.mu.RUnlock()
+
+	require.NoError(t, watcher.Close())
+	require.NoError(t, watcher.Close(), "Close is idempotent")
+	require.ErrorIs(t, watcher.Watch(tempDir, EventAll), ErrWatcherClosed)
+
+	_, open := <-watcher.Events()
+	assert.False(t, open, "events channel is closed")
+}
+
+func TestInotifyPathWatcher_WatchMissingPath(t *testing.T) {
+	watcher, err := NewInotifyPathWatcher()
+	require.NoError(t, err)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+
+	require.Error(t, watcher.Watch(filepath.Join(t.TempDir(), "missing"), EventAll))
+	assert.Empty(t, watcher.WatchedPaths())
+}
+
+func TestParseInotifyEvents(t *testing.T) {
+	assert.Empty(t, parseInotifyEvents(nil))
+	assert.Empty(t, parseInotifyEvents(make([]byte, 4)), "a short record is ignored")
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package paths
+
+// newNativePathWatcher reports that no native watcher exists on this platform,
+// so NewNativePathWatcher falls back to polling
+func newNativePathWatcher() (PathWatcher, error) {
+	return nil, ErrNativeWatcherUnsupported
+}
@@ -3,6 +3,7 @@ package paths
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -135,6 +136,7 @@ func TestDefaultPathWatcher_Configuration(t *testing.T) {
 	// Test SetBufferSize
 	result := watcher.SetBufferSize(500)
 	assert.Equal(t, watcher, result) // Should return self for chaining
+	assert.Equal(t, 500, cap(watcher.Events()))
 
 	// Test SetRecursive
 	result = watcher.SetRecursive(false)
@@ -145,6 +147,28 @@ func TestDefaultPathWatcher_Configuration(t *testing.T) {
 	assert.Equal(t, watcher, result)
 }
 
+func TestResizeEventChannel(t *testing.T) {
+	ch := make(chan *PathEvent, 4)
+	ch <- &PathEvent{Path: "a"}
+	ch <- &PathEvent{Path: "b"}
+
+	resized := resizeEventChannel(ch, 8)
+	assert.Equal(t, 8, cap(resized))
+	require.Len(t, resized, 2)
+	assert.Equal(t, "a", (<-resized).Path)
+	assert.Equal(t, "b", (<-resized).Path)
+
+	// Sizes that cannot hold the buffered events, or are not positive, keep the channel
+	ch <- &PathEvent{Path: "c"}
+	ch <- &PathEvent{Path: "d"}
+	assert.Equal(t, ch, resizeEventChannel(ch, 1))
+	assert.Equal(t, ch, resizeEventChannel(ch, 0))
+
+	closed := make(chan *PathEvent, 1)
+	close(closed)
+	assert.Equal(t, closed, resizeEventChannel(closed, 2), "a closed channel is kept")
+}
+
 func TestDefaultPathWatcher_Channels(t *testing.T) {
 	watcher := NewPathWatcher()
 	defer func() {
@@ -275,6 +299,33 @@ func TestDefaultPathWatcher_ModifyEventGeneration(t *testing.T) {
 	}
 }
 
+func TestDefaultPathWatcher_ExistingFilesNotReportedAsCreated(t *testing.T) {
+	watcher, ok := NewPathWatcher().(*DefaultPathWatcher)
+	require.True(t, ok, "Expected *DefaultPathWatcher")
+	watcher.SetDebounce(20 * time.Millisecond)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "existing.txt"), []byte("old"), 0o600))
+
+	require.NoError(t, watcher.Watch(tempDir, EventCreate))
+
+	newFile := filepath.Join(tempDir, "new.txt")
+	require.NoError(t, os.WriteFile(newFile, []byte("new"), 0o600))
+
+	select {
+	case event := <-watcher.Events():
+		assert.Equal(t, newFile, event.Path, "files present before Watch must not be reported")
+		assert.Equal(t, EventCreate, event.Op)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a create event for the new file")
+	}
+}
+
 func TestDefaultPathWatcher_NonRecursive(t *testing.T) {
 	watcher, ok := NewPathWatcher().(*DefaultPathWatcher)
 	require.True(t, ok, "Expected *DefaultPathWatcher")
@@ -310,6 +361,70 @@ func TestDefaultPathWatcher_NonRecursive(t *testing.T) {
 	assert.True(t, watcher.IsWatching(tempDir.String()))
 }
 
+func TestDefaultPathWatcher_IgnorePatterns(t *testing.T) {
+	watcher, ok := NewPathWatcher().(*DefaultPathWatcher)
+	require.True(t, ok, "Expected *DefaultPathWatcher")
+	watcher.SetDebounce(20 * time.Millisecond)
+	watcher.SetIgnorePatterns("node_modules", "*.tmp")
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+
+	tempDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, "node_modules"), 0o750))
+	require.NoError(t, watcher.Watch(tempDir, EventCreate))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "node_modules", "dep.js"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "scratch.tmp"), []byte("x"), 0o600))
+	kept := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(kept, []byte("package main"), 0o600))
+
+	select {
+	case event := <-watcher.Events():
+		assert.Equal(t, kept, event.Path, "ignored paths must not be reported")
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a create event for main.go")
+	}
+}
+
+func TestIgnoredPath(t *testing.T) {
+	root := filepath.Join("/", "repo")
+	patterns := []string{".git", "*.tmp", "build/cache"}
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{root, false},
+		{filepath.Join(root, ".git"), true},
+		{filepath.Join(root, "pkg", ".git"), true},
+		{filepath.Join(root, "pkg", "file.tmp"), true},
+		{filepath.Join(root, "build", "cache"), true},
+		{filepath.Join(root, "pkg", "build", "cache"), false},
+		{filepath.Join(root, "main.go"), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.ignored, ignoredPath(patterns, root, tt.path), tt.path)
+	}
+	assert.False(t, ignoredPath(nil, root, filepath.Join(root, ".git")))
+}
+
+func TestNewNativePathWatcher(t *testing.T) {
+	watcher := NewNativePathWatcher()
+	require.NotNil(t, watcher)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			t.Logf("Failed to close watcher: %v", err)
+		}
+	}()
+
+	tempDir := t.TempDir()
+	require.NoError(t, watcher.Watch(tempDir, EventAll))
+	assert.True(t, watcher.IsWatching(tempDir))
+}
+
 func TestDefaultPathWatcher_ErrorChannel(t *testing.T) {
 	watcher := NewPathWatcher()
 	defer func() {
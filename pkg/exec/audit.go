@@ -18,6 +18,7 @@ import (
 type AuditEvent struct {
 	Timestamp   time.Time
 	User        string
+	Hostname    string
 	Command     string
 	Args        []string
 	WorkingDir  string
@@ -162,6 +163,12 @@ func (a *AuditingExecutor) logAuditEvent(command string, args []string, startTim
 		currentUser = usr.Username
 	}
 
+	// Get the machine the command ran on
+	hostname, hostErr := os.Hostname()
+	if hostErr != nil {
+		hostname = "unknown"
+	}
+
 	// Get working directory
 	workingDir := a.workingDir
 	if workingDir == "" {
@@ -192,6 +199,7 @@ func (a *AuditingExecutor) logAuditEvent(command string, args []string, startTim
 	event := AuditEvent{
 		Timestamp:   startTime,
 		User:        currentUser,
+		Hostname:    hostname,
 		Command:     command,
 		Args:        args,
 		WorkingDir:  workingDir,
@@ -217,7 +225,7 @@ func (d *DefaultAuditLogger) LogEvent(event AuditEvent) error {
 		os.Stderr, "[AUDIT] %s: %s %s (duration=%s, exit=%d, success=%v)\n",
 		event.Timestamp.Format(time.RFC3339),
 		event.Command,
-		strings.Join(event.Args, " "),
+		strings.Join(redactArgs(event.Args), " "),
 		event.Duration,
 		event.ExitCode,
 		event.Success,
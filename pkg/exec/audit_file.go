@@ -0,0 +1,388 @@
+package exec
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+)
+
+// Static errors for the file audit logger
+var (
+	errAuditPathEmpty = errors.New("audit log path is empty")
+)
+
+const (
+	// DefaultAuditMaxSize is the size at which the audit log is rotated
+	DefaultAuditMaxSize int64 = 10 * 1024 * 1024
+
+	// DefaultAuditMaxFiles is the number of rotated audit logs kept
+	DefaultAuditMaxFiles = 5
+
+	// maxAuditLineSize bounds a single JSON line when reading the log
+	maxAuditLineSize = 1024 * 1024
+
+	// redactedValue replaces the value of a sensitive argument
+	redactedValue = "[REDACTED]"
+)
+
+// auditRecord is the JSON line written for each AuditEvent
+type auditRecord struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	User        string            `json:"user"`
+	Hostname    string            `json:"hostname,omitempty"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args,omitempty"`
+	WorkingDir  string            `json:"working_dir,omitempty"`
+	DurationMS  int64             `json:"duration_ms"`
+	ExitCode    int               `json:"exit_code"`
+	Success     bool              `json:"success"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encodes the event as it is stored in the audit log
+func (e AuditEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(auditRecord{
+		Timestamp:   e.Timestamp.UTC(),
+		User:        e.User,
+		Hostname:    e.Hostname,
+		Command:     e.Command,
+		Args:        redactArgs(e.Args),
+		WorkingDir:  e.WorkingDir,
+		DurationMS:  e.Duration.Milliseconds(),
+		ExitCode:    e.ExitCode,
+		Success:     e.Success,
+		Environment: e.Environment,
+		Metadata:    e.Metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON decodes an event written by MarshalJSON
+func (e *AuditEvent) UnmarshalJSON(data []byte) error {
+	var record auditRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("failed to decode audit event: %w", err)
+	}
+	*e = AuditEvent{
+		Timestamp:   record.Timestamp,
+		User:        record.User,
+		Hostname:    record.Hostname,
+		Command:     record.Command,
+		Args:        record.Args,
+		WorkingDir:  record.WorkingDir,
+		Duration:    time.Duration(record.DurationMS) * time.Millisecond,
+		ExitCode:    record.ExitCode,
+		Success:     record.Success,
+		Environment: record.Environment,
+		Metadata:    record.Metadata,
+	}
+	return nil
+}
+
+// redactArgs returns a copy of args with the values of sensitive flags and
+// NAME=value assignments replaced. A name is sensitive when, upper-cased with
+// dashes turned into underscores, it matches DefaultSensitivePrefixes the way
+// the environment filter does: "--api-key=x", "--github-token x" and
+// "SECRET_KEY=x" are all redacted.
+func redactArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i := 0; i < len(redacted); i++ {
+		arg := redacted[i]
+		name, _, hasValue := strings.Cut(arg, "=")
+		if !isSensitiveArgName(name) {
+			continue
+		}
+		switch {
+		case hasValue:
+			redacted[i] = name + "=" + redactedValue
+		case strings.HasPrefix(name, "-") && i+1 < len(redacted) && !strings.HasPrefix(redacted[i+1], "-"):
+			i++
+			redacted[i] = redactedValue
+		}
+	}
+	return redacted
+}
+
+// isSensitiveArgName reports whether a flag or assignment name names a secret
+func isSensitiveArgName(name string) bool {
+	name = strings.TrimLeft(name, "-")
+	if name == "" {
+		return false
+	}
+	return isSensitiveName(strings.ToUpper(strings.ReplaceAll(name, "-", "_")), DefaultSensitivePrefixes)
+}
+
+// FileAuditLogger appends audit events to a file as JSON lines. The file is
+// rotated to <path>.1, <path>.2, ... once it grows past the maximum size, and
+// rotated files beyond the maximum count or older than the maximum age are removed
+// on rotation and before the logger's first event.
+type FileAuditLogger struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	maxAge   time.Duration
+
+	mu     sync.Mutex
+	pruned bool // Rotated logs were checked since the logger was created
+}
+
+// FileAuditOption configures a FileAuditLogger
+type FileAuditOption func(*FileAuditLogger)
+
+// WithAuditMaxSize sets the size in bytes at which the log is rotated (0 disables rotation)
+func WithAuditMaxSize(size int64) FileAuditOption {
+	return func(f *FileAuditLogger) {
+		f.maxSize = size
+	}
+}
+
+// WithAuditMaxFiles sets how many rotated logs are kept
+func WithAuditMaxFiles(count int) FileAuditOption {
+	return func(f *FileAuditLogger) {
+		f.maxFiles = count
+	}
+}
+
+// WithAuditMaxAge removes rotated logs last written longer ago than age (0 keeps them)
+func WithAuditMaxAge(age time.Duration) FileAuditOption {
+	return func(f *FileAuditLogger) {
+		f.maxAge = age
+	}
+}
+
+// NewFileAuditLogger creates a logger that appends to path
+func NewFileAuditLogger(path string, opts ...FileAuditOption) *FileAuditLogger {
+	f := &FileAuditLogger{
+		path:     path,
+		maxSize:  DefaultAuditMaxSize,
+		maxFiles: DefaultAuditMaxFiles,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Path returns the file the logger appends to
+func (f *FileAuditLogger) Path() string {
+	return f.path
+}
+
+// LogEvent appends the event as one JSON line, rotating the file first when needed
+func (f *FileAuditLogger) LogEvent(event AuditEvent) error {
+	if f.path == "" {
+		return errAuditPathEmpty
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err //nolint:wrapcheck // MarshalJSON already describes the failure
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.path), fileops.PermDirSensitive); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	// A log that rarely reaches maxSize would otherwise keep expired files forever
+	if !f.pruned {
+		f.pruned = true
+		if err := f.prune(); err != nil {
+			return err
+		}
+	}
+	if err := f.rotateIfNeeded(int64(len(line))); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileops.PermFileSensitive)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close() //nolint:errcheck // the write error is the one worth reporting
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
+
+// rotateIfNeeded shifts the log to <path>.1 when appending size bytes would exceed maxSize
+func (f *FileAuditLogger) rotateIfNeeded(size int64) error {
+	if f.maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	if info.Size() == 0 || info.Size()+size <= f.maxSize {
+		return nil
+	}
+
+	if f.maxFiles <= 0 {
+		if err := os.Remove(f.path); err != nil {
+			return fmt.Errorf("failed to remove audit log: %w", err)
+		}
+		return nil
+	}
+	for i := f.maxFiles - 1; i >= 1; i-- {
+		from := rotatedAuditPath(f.path, i)
+		if err := os.Rename(from, rotatedAuditPath(f.path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(f.path, rotatedAuditPath(f.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return f.prune()
+}
+
+// prune removes rotated logs past maxFiles or older than maxAge
+func (f *FileAuditLogger) prune() error {
+	rotated, err := rotatedAuditFiles(f.path)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-f.maxAge)
+	var errs []error
+	for _, file := range rotated {
+		expired := f.maxAge > 0 && file.modTime.Before(cutoff)
+		if file.index > f.maxFiles || expired {
+			if err := os.Remove(file.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to prune audit logs: %w", err)
+	}
+	return nil
+}
+
+// ReadAuditLog returns every event in the log at path, oldest first, including
+// the rotated files. Lines that cannot be decoded (for example a write cut short
+// by a crash) are skipped. A missing log yields no events.
+func ReadAuditLog(path string) ([]AuditEvent, error) {
+	rotated, err := rotatedAuditFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(rotated)+1)
+	for i := len(rotated) - 1; i >= 0; i-- {
+		files = append(files, rotated[i].path)
+	}
+	files = append(files, path)
+
+	var events []AuditEvent
+	for _, file := range files {
+		fileEvents, err := readAuditFile(file)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, nil
+}
+
+// readAuditFile decodes the JSON lines of one log file
+func readAuditFile(path string) ([]AuditEvent, error) {
+	file, err := os.Open(path) // #nosec G304 -- path is the configured audit log
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }() //nolint:errcheck // read-only file
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLineSize)
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return events, nil
+}
+
+// rotatedAuditFile is one <path>.N file
+type rotatedAuditFile struct {
+	path    string
+	index   int
+	modTime time.Time
+}
+
+// rotatedAuditFiles lists the rotated logs of path, newest (lowest index) first
+func rotatedAuditFiles(path string) ([]rotatedAuditFile, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	prefix := filepath.Base(path) + "."
+	var files []rotatedAuditFile
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < 1 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, rotatedAuditFile{
+			path:    filepath.Join(filepath.Dir(path), entry.Name()),
+			index:   index,
+			modTime: info.ModTime(),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].index < files[j].index })
+	return files, nil
+}
+
+// rotatedAuditPath returns the name of the index-th rotated log
+func rotatedAuditPath(path string, index int) string {
+	return path + "." + strconv.Itoa(index)
+}
+
+// Ensure FileAuditLogger implements AuditLogger
+var _ AuditLogger = (*FileAuditLogger)(nil)
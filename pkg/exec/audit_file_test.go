@@ -0,0 +1,195 @@
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditEvent(command string, exitCode int) AuditEvent {
+	return AuditEvent{
+		Timestamp: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		User:      "alice",
+		Hostname:  "build-01",
+		Command:   command,
+		Args:      []string{"release", "--clean"},
+		Duration:  1500 * time.Millisecond,
+		ExitCode:  exitCode,
+		Success:   exitCode == 0,
+		Metadata:  map[string]string{"magex_command": "release"},
+	}
+}
+
+func TestFileAuditLogger_AppendsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	logger := NewFileAuditLogger(path)
+
+	require.NoError(t, logger.LogEvent(testAuditEvent("goreleaser", 0)))
+	require.NoError(t, logger.LogEvent(testAuditEvent("git", 128)))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"command":"goreleaser"`)
+	assert.Contains(t, lines[0], `"duration_ms":1500`)
+	assert.Contains(t, lines[1], `"exit_code":128`)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	events, err := ReadAuditLog(path)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, testAuditEvent("goreleaser", 0), events[0])
+	assert.Equal(t, 128, events[1].ExitCode)
+	assert.False(t, events[1].Success)
+}
+
+func TestFileAuditLogger_RedactsSensitiveArgs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	event := testAuditEvent("deploy", 0)
+	event.Args = []string{"--api-key=abc123", "--github-token", "ghp_secret", "SECRET_KEY=s3cr3t", "--verbose", "target"}
+	require.NoError(t, NewFileAuditLogger(path).LogEvent(event))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "abc123")
+	assert.NotContains(t, string(data), "ghp_secret")
+	assert.NotContains(t, string(data), "s3cr3t")
+
+	events, err := ReadAuditLog(path)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, []string{
+		"--api-key=" + redactedValue, "--github-token", redactedValue, "SECRET_KEY=" + redactedValue, "--verbose", "target",
+	}, events[0].Args)
+	assert.Equal(t, "ghp_secret", event.Args[2], "the event itself is not modified")
+}
+
+func TestRedactArgs(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, redactArgs(nil))
+	assert.Equal(t, []string{"--secret"}, redactArgs([]string{"--secret"}), "a trailing flag has no value")
+	assert.Equal(t, []string{"--private-key", "--force"}, redactArgs([]string{"--private-key", "--force"}))
+	assert.Equal(t, []string{"--secretive=x", "--token=y", "-api-key=" + redactedValue},
+		redactArgs([]string{"--secretive=x", "--token=y", "-api-key=z"}), "prefixes match whole name parts only")
+}
+
+func TestFileAuditLogger_Rotation(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewFileAuditLogger(path, WithAuditMaxSize(300), WithAuditMaxFiles(2))
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, logger.LogEvent(testAuditEvent("cmd"+string(rune('a'+i)), 0)))
+	}
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3", "only max_files rotated logs are kept")
+
+	events, err := ReadAuditLog(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, "cmdf", events[len(events)-1].Command, "the newest event is last")
+	for i := 1; i < len(events); i++ {
+		assert.Greater(t, events[i].Command, events[i-1].Command, "events are read oldest first")
+	}
+}
+
+func TestFileAuditLogger_Retention(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewFileAuditLogger(path, WithAuditMaxSize(1), WithAuditMaxAge(24*time.Hour))
+
+	require.NoError(t, os.WriteFile(path+".1", []byte("{}\n"), 0o600))
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(path+".1", old, old))
+	require.NoError(t, logger.LogEvent(testAuditEvent("first", 0)))
+	require.NoError(t, logger.LogEvent(testAuditEvent("second", 0)))
+
+	assert.FileExists(t, path+".1", "the freshly rotated log is kept")
+	assert.NoFileExists(t, path+".2", "the expired log is removed")
+}
+
+func TestFileAuditLogger_RetentionWithoutRotation(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewFileAuditLogger(path, WithAuditMaxFiles(2), WithAuditMaxAge(24*time.Hour))
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{path + ".1", path + ".3"} {
+		require.NoError(t, os.WriteFile(name, []byte("{}\n"), 0o600))
+	}
+	require.NoError(t, os.Chtimes(path+".1", old, old))
+	require.NoError(t, os.WriteFile(path+".2", []byte("{}\n"), 0o600))
+	require.NoError(t, logger.LogEvent(testAuditEvent("first", 0)))
+
+	assert.NoFileExists(t, path+".1", "an expired log is removed even though the log never rotates")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3", "logs past max_files are removed")
+}
+
+func TestReadAuditLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing log", func(t *testing.T) {
+		t.Parallel()
+		events, err := ReadAuditLog(filepath.Join(t.TempDir(), "none", "audit.jsonl"))
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("skips lines it cannot decode", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		require.NoError(t, NewFileAuditLogger(path).LogEvent(testAuditEvent("go", 0)))
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // test file
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"command":"trunc`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		events, err := ReadAuditLog(path)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "go", events[0].Command)
+	})
+}
+
+func TestFileAuditLogger_WithAuditingExecutor(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	executor := NewBuilder().
+		WithDryRun(true).
+		WithAuditLogging(NewFileAuditLogger(path)).
+		WithAuditMetadata("magex_command", "release").
+		Build()
+
+	require.NoError(t, executor.Execute(context.Background(), "echo", "hello"))
+
+	events, err := ReadAuditLog(path)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "echo", events[0].Command)
+	assert.Equal(t, []string{"hello"}, events[0].Args)
+	assert.NotEmpty(t, events[0].Hostname)
+	assert.Equal(t, "release", events[0].Metadata["magex_command"])
+}
@@ -198,6 +198,7 @@ func TestAuditEvent_Fields(t *testing.T) {
 		require.Len(t, events, 1)
 		// User should be set (either actual username or "unknown")
 		assert.NotEmpty(t, events[0].User)
+		assert.NotEmpty(t, events[0].Hostname)
 	})
 
 	t.Run("captures duration", func(t *testing.T) {
@@ -172,27 +172,27 @@ func (e *EnvFilteringExecutor) FilterEnvironment(env []string, commandName strin
 
 // shouldKeepEnvVar determines if an environment variable should be kept based on sensitivity and whitelist rules
 func (e *EnvFilteringExecutor) shouldKeepEnvVar(varName, commandName string) bool {
-	// Check if this environment variable contains sensitive data
-	for _, prefix := range e.SensitivePrefixes {
-		if !strings.HasPrefix(varName, prefix) {
-			continue
-		}
-		// Only match if it's an exact match or followed by underscore
-		if len(varName) != len(prefix) && varName[len(prefix)] != '_' {
+	// Not a sensitive variable - keep it
+	if !isSensitiveName(varName, e.SensitivePrefixes) {
+		return true
+	}
+
+	// Sensitive variables are only kept when whitelisted for this command
+	return e.isWhitelisted(varName, commandName)
+}
+
+// isSensitiveName reports whether an upper-case name starts with one of the prefixes,
+// matching only an exact name or a prefix followed by an underscore
+func isSensitiveName(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, prefix) {
 			continue
 		}
-
-		// This is a sensitive variable - check if it's whitelisted for this command
-		if e.isWhitelisted(varName, commandName) {
+		if len(name) == len(prefix) || name[len(prefix)] == '_' {
 			return true
 		}
-
-		// Sensitive and not whitelisted - filter it out
-		return false
 	}
-
-	// Not a sensitive variable - keep it
-	return true
+	return false
 }
 
 // isWhitelisted checks if a variable is whitelisted for a specific command
@@ -0,0 +1,295 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/exec"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for the command audit log
+var (
+	errInvalidAuditRetention = errors.New("invalid audit.retention")
+	errInvalidAuditParam     = errors.New("invalid audit parameter")
+	errUnknownAuditFormat    = errors.New("unknown audit format")
+)
+
+// auditMetadataCommand is the metadata key holding the magex command behind an event
+const auditMetadataCommand = "magex_command"
+
+// Audit queries the persistent command audit log
+type Audit struct{}
+
+// commandAuditLogger tags each event with the magex command (from EnvAuditCommand)
+// that caused it before handing it to the wrapped logger
+type commandAuditLogger struct {
+	logger exec.AuditLogger
+}
+
+// LogEvent records the event with the current magex command attached
+func (c commandAuditLogger) LogEvent(event exec.AuditEvent) error {
+	if command := os.Getenv(EnvAuditCommand); command != "" {
+		if event.Metadata == nil {
+			event.Metadata = make(map[string]string)
+		}
+		event.Metadata[auditMetadataCommand] = command
+	}
+	return c.logger.LogEvent(event)
+}
+
+// newAuditLogger returns the logger configured under audit, or nil when auditing is off
+func newAuditLogger(config *Config) (exec.AuditLogger, error) {
+	if !config.Audit.Enabled {
+		return nil, nil //nolint:nilnil // a nil logger means auditing is disabled
+	}
+
+	path, err := auditLogPath(config)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := auditRetention(config.Audit.Retention)
+	if err != nil {
+		return nil, err
+	}
+	maxSizeMB := config.Audit.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = DefaultAuditMaxSizeMB
+	}
+	maxFiles := config.Audit.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = exec.DefaultAuditMaxFiles
+	}
+
+	return commandAuditLogger{logger: exec.NewFileAuditLogger(path,
+		exec.WithAuditMaxSize(int64(maxSizeMB)*1024*1024),
+		exec.WithAuditMaxFiles(maxFiles),
+		exec.WithAuditMaxAge(retention),
+	)}, nil
+}
+
+// auditLogPath returns the absolute audit log path, so commands run from other
+// directories still append to the same log
+func auditLogPath(config *Config) (string, error) {
+	path := config.Audit.Path
+	if path == "" {
+		path = DefaultAuditLog
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve audit log path: %w", err)
+	}
+	return absPath, nil
+}
+
+// auditRetention parses audit.retention; empty means the default and "0" keeps rotated logs
+func auditRetention(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultAuditRetention, nil
+	}
+	if value == "0" {
+		return 0, nil
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidAuditRetention, value)
+	}
+	return retention, nil
+}
+
+// auditFilter selects events from the audit log
+type auditFilter struct {
+	command  string
+	user     string
+	since    time.Time
+	until    time.Time
+	exitCode *int
+}
+
+// match reports whether the event passes every filter that is set. A command
+// matches the executed program or the magex command, where a namespace such as
+// "release" also matches "release:snapshot".
+func (f auditFilter) match(event exec.AuditEvent) bool {
+	if f.command != "" {
+		magexCommand := event.Metadata[auditMetadataCommand]
+		if event.Command != f.command && magexCommand != f.command && !strings.HasPrefix(magexCommand, f.command+":") {
+			return false
+		}
+	}
+	if f.user != "" && event.User != f.user {
+		return false
+	}
+	if !f.since.IsZero() && event.Timestamp.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && event.Timestamp.After(f.until) {
+		return false
+	}
+	if f.exitCode != nil && event.ExitCode != *f.exitCode {
+		return false
+	}
+	return true
+}
+
+// parseAuditTime accepts RFC 3339 timestamps, dates (2006-01-02) and durations
+// counted back from now (e.g. 24h)
+func parseAuditTime(name, value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %s=%s (use RFC 3339, YYYY-MM-DD or a duration such as 24h)", errInvalidAuditParam, name, value)
+}
+
+// parseAuditFilter builds the filter from the magex audit parameters
+func parseAuditFilter(params map[string]string, now time.Time) (auditFilter, error) {
+	filter := auditFilter{
+		command: utils.GetParam(params, "command", ""),
+		user:    utils.GetParam(params, "user", ""),
+	}
+	var err error
+	if v := utils.GetParam(params, "since", ""); v != "" {
+		if filter.since, err = parseAuditTime("since", v, now); err != nil {
+			return filter, err
+		}
+	}
+	if v := utils.GetParam(params, "until", ""); v != "" {
+		if filter.until, err = parseAuditTime("until", v, now); err != nil {
+			return filter, err
+		}
+		// A bare date includes the whole day
+		if _, dateErr := time.ParseInLocation(time.DateOnly, v, time.Local); dateErr == nil {
+			filter.until = filter.until.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	if v := utils.GetParam(params, "exit_code", ""); v != "" {
+		code, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			return filter, fmt.Errorf("%w: exit_code=%s", errInvalidAuditParam, v)
+		}
+		filter.exitCode = &code
+	}
+	return filter, nil
+}
+
+// Default shows the most recent audit log entries
+func (Audit) Default() error {
+	return Audit{}.Query()
+}
+
+// Query shows audit log entries, newest last. Parameters: command=<name>, user=<name>,
+// since=<time>, until=<time>, exit_code=<n>, last=<n> (default 50, 0 for all) and
+// format=table|json.
+func (Audit) Query(argsList ...string) error {
+	params := utils.ParseParams(argsList)
+
+	filter, err := parseAuditFilter(params, time.Now())
+	if err != nil {
+		return err
+	}
+	last := DefaultAuditQueryLast
+	if v := utils.GetParam(params, "last", ""); v != "" {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil || n < 0 {
+			return fmt.Errorf("%w: last=%s", errInvalidAuditParam, v)
+		}
+		last = n
+	}
+	format := utils.GetParam(params, "format", "table")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("%w: %s (use table or json)", errUnknownAuditFormat, format)
+	}
+	if format == "table" {
+		// JSON output stays machine-readable
+		utils.Header("Command Audit Log")
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	path, err := auditLogPath(config)
+	if err != nil {
+		return err
+	}
+	matched, total, err := queryAuditLog(path, filter, last)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return writeAuditJSON(os.Stdout, matched)
+	}
+
+	if len(matched) == 0 {
+		if !config.Audit.Enabled && total == 0 {
+			utils.Info("No audit events in %s; enable recording with audit.enabled: true", path)
+		} else {
+			utils.Info("No audit events in %s match the filters", path)
+		}
+		return nil
+	}
+	utils.Info("%d of %d event(s) from %s", len(matched), total, path)
+	printAuditEvents(matched)
+	return nil
+}
+
+// queryAuditLog returns the last matching events of the log at path (all when last
+// is 0) and the number of events in the log
+func queryAuditLog(path string, filter auditFilter, last int) ([]exec.AuditEvent, int, error) {
+	events, err := exec.ReadAuditLog(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	matched := make([]exec.AuditEvent, 0, len(events))
+	for _, event := range events {
+		if filter.match(event) {
+			matched = append(matched, event)
+		}
+	}
+	if last > 0 && len(matched) > last {
+		matched = matched[len(matched)-last:]
+	}
+	return matched, len(events), nil
+}
+
+// writeAuditJSON writes the events as JSON lines, in the same format as the log
+func writeAuditJSON(w io.Writer, events []exec.AuditEvent) error {
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to write audit event: %w", err)
+		}
+	}
+	return nil
+}
+
+// printAuditEvents prints one line per event
+func printAuditEvents(events []exec.AuditEvent) {
+	utils.Print("%-20s %-28s %-20s %5s %9s  %s\n", "TIME", "USER@HOST", "MAGEX", "EXIT", "DURATION", "COMMAND")
+	for _, event := range events {
+		who := event.User
+		if event.Hostname != "" {
+			who += "@" + event.Hostname
+		}
+		magexCommand := event.Metadata[auditMetadataCommand]
+		if magexCommand == "" {
+			magexCommand = "-"
+		}
+		utils.Print("%-20s %-28s %-20s %5d %9s  %s\n",
+			event.Timestamp.Local().Format(time.DateTime), who, magexCommand, event.ExitCode,
+			utils.FormatDuration(event.Duration), strings.TrimSpace(event.Command+" "+strings.Join(event.Args, " ")))
+	}
+}
@@ -0,0 +1,191 @@
+package mage
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/exec"
+)
+
+// auditAt is a fixed point in time the audit test events are placed around
+//
+//nolint:gochecknoglobals // read-only test fixture
+var auditAt = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func auditTestEvent(user, command, magexCommand string, exitCode int, age time.Duration) exec.AuditEvent {
+	return exec.AuditEvent{
+		Timestamp: auditAt.Add(-age),
+		User:      user,
+		Hostname:  "build-01",
+		Command:   command,
+		ExitCode:  exitCode,
+		Success:   exitCode == 0,
+		Duration:  time.Second,
+		Metadata:  map[string]string{auditMetadataCommand: magexCommand},
+	}
+}
+
+func TestNewAuditLogger(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		logger, err := newAuditLogger(defaultConfig())
+		require.NoError(t, err)
+		assert.Nil(t, logger)
+	})
+
+	t.Run("records the magex command", func(t *testing.T) {
+		t.Setenv(EnvAuditCommand, "release:snapshot")
+		config := defaultConfig()
+		config.Audit.Enabled = true
+		config.Audit.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+
+		logger, err := newAuditLogger(config)
+		require.NoError(t, err)
+		require.NotNil(t, logger)
+		require.NoError(t, logger.LogEvent(exec.AuditEvent{Timestamp: auditAt, User: "alice", Command: "goreleaser"}))
+
+		events, err := exec.ReadAuditLog(config.Audit.Path)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "release:snapshot", events[0].Metadata[auditMetadataCommand])
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		config := defaultConfig()
+		config.Audit.Enabled = true
+		config.Audit.Retention = "forever"
+		_, err := newAuditLogger(config)
+		require.ErrorIs(t, err, errInvalidAuditRetention)
+	})
+}
+
+func TestAuditRetention(t *testing.T) {
+	t.Parallel()
+
+	retention, err := auditRetention("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAuditRetention, retention)
+
+	retention, err = auditRetention("0")
+	require.NoError(t, err)
+	assert.Zero(t, retention)
+
+	retention, err = auditRetention("720h")
+	require.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, retention)
+
+	_, err = auditRetention("-1h")
+	require.ErrorIs(t, err, errInvalidAuditRetention)
+}
+
+func TestParseAuditFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := parseAuditFilter(map[string]string{"since": "48h", "until": "2026-10-16", "exit_code": "2", "user": "bob"}, auditAt)
+	require.NoError(t, err)
+	assert.Equal(t, auditAt.Add(-48*time.Hour), filter.since)
+	assert.Equal(t, 2026, filter.until.Year())
+	assert.Equal(t, 23, filter.until.Hour(), "a bare until date covers the whole day")
+	require.NotNil(t, filter.exitCode)
+	assert.Equal(t, 2, *filter.exitCode)
+	assert.Equal(t, "bob", filter.user)
+
+	filter, err = parseAuditFilter(map[string]string{"since": "2026-10-15T08:00:00Z"}, auditAt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC), filter.since)
+
+	_, err = parseAuditFilter(map[string]string{"since": "last tuesday"}, auditAt)
+	require.ErrorIs(t, err, errInvalidAuditParam)
+	_, err = parseAuditFilter(map[string]string{"exit_code": "x"}, auditAt)
+	require.ErrorIs(t, err, errInvalidAuditParam)
+}
+
+func TestAuditFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	release := auditTestEvent("alice", "goreleaser", "release:snapshot", 0, time.Hour)
+	failedLint := auditTestEvent("bob", "golangci-lint", "lint", 1, 72*time.Hour)
+	exitZero, exitOne := 0, 1
+
+	tests := []struct {
+		name    string
+		filter  auditFilter
+		matches []bool // release, failedLint
+	}{
+		{name: "no filters", matches: []bool{true, true}},
+		{name: "magex namespace", filter: auditFilter{command: "release"}, matches: []bool{true, false}},
+		{name: "magex command", filter: auditFilter{command: "lint"}, matches: []bool{false, true}},
+		{name: "executed program", filter: auditFilter{command: "goreleaser"}, matches: []bool{true, false}},
+		{name: "namespace prefix only", filter: auditFilter{command: "rel"}, matches: []bool{false, false}},
+		{name: "user", filter: auditFilter{user: "bob"}, matches: []bool{false, true}},
+		{name: "since", filter: auditFilter{since: auditAt.Add(-24 * time.Hour)}, matches: []bool{true, false}},
+		{name: "until", filter: auditFilter{until: auditAt.Add(-24 * time.Hour)}, matches: []bool{false, true}},
+		{name: "exit code zero", filter: auditFilter{exitCode: &exitZero}, matches: []bool{true, false}},
+		{name: "exit code one", filter: auditFilter{exitCode: &exitOne}, matches: []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.matches[0], tt.filter.match(release))
+			assert.Equal(t, tt.matches[1], tt.filter.match(failedLint))
+		})
+	}
+}
+
+func TestQueryAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := exec.NewFileAuditLogger(path)
+	for i, user := range []string{"alice", "bob", "alice", "alice"} {
+		require.NoError(t, logger.LogEvent(auditTestEvent(user, "go", "build", 0, time.Duration(4-i)*time.Hour)))
+	}
+
+	matched, total, err := queryAuditLog(path, auditFilter{user: "alice"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	require.Len(t, matched, 2, "only the last matches are kept")
+	assert.Equal(t, auditAt.Add(-time.Hour), matched[1].Timestamp)
+
+	var out bytes.Buffer
+	require.NoError(t, writeAuditJSON(&out, matched))
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(lines[1], &decoded))
+	assert.Equal(t, "alice", decoded["user"])
+	assert.Equal(t, "build-01", decoded["hostname"])
+
+	config := defaultConfig()
+	config.Audit.Path = path
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+	require.NoError(t, Audit{}.Query("command=build", "last=0"))
+	require.ErrorIs(t, Audit{}.Query("format=xml"), errUnknownAuditFormat)
+	require.ErrorIs(t, Audit{}.Query("last=-1"), errInvalidAuditParam)
+}
+
+func TestSecureCommandRunner_AuditLog(t *testing.T) {
+	t.Setenv(EnvAuditCommand, "check:all")
+	config := defaultConfig()
+	config.Audit.Enabled = true
+	config.Audit.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+
+	runner := NewSecureCommandRunner()
+	_, err := runner.RunCmdOutput("go", "version")
+	require.NoError(t, err)
+
+	events, err := exec.ReadAuditLog(config.Audit.Path)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "go", events[0].Command)
+	assert.Equal(t, []string{"version"}, events[0].Args)
+	assert.Equal(t, "check:all", events[0].Metadata[auditMetadataCommand])
+	assert.True(t, events[0].Success)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +22,8 @@ import (
 var (
 	ErrOldBenchFileNotFound = errors.New("old benchmark file not found")
 	ErrNewBenchFileNotFound = errors.New("new benchmark file not found")
+	ErrBenchRegression      = errors.New("benchmark regression beyond threshold")
+	ErrBenchTooFewSamples   = errors.New("too few benchmark samples for the regression gate")
 )
 
 // Benchmark default constants
@@ -275,6 +278,13 @@ func (Bench) SaveWithArgs(argsList ...string) error {
 	}
 
 	utils.Success("Benchmark results saved to: %s", output)
+
+	// Record the run for bench:history unless disabled
+	if !utils.IsParamFalse(params, "history") {
+		if err := recordBenchHistory(combinedOutput); err != nil {
+			utils.Warn("Failed to record benchmark history: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -499,6 +509,15 @@ func (Bench) RegressionWithArgs(argsList ...string) error {
 	// Parse command-line parameters
 	params := utils.ParseParams(argsList)
 
+	// The U test cannot reach significance with fewer samples, so the gate
+	// would never fire
+	count := utils.GetParam(params, "count", "")
+	if count == "" {
+		argsList = append(argsList, fmt.Sprintf("count=%d", MinBenchRegressionSamples))
+	} else if n, err := strconv.Atoi(count); err != nil || n < MinBenchRegressionSamples {
+		return fmt.Errorf("%w: count=%s, use count=%d or more", ErrBenchTooFewSamples, count, MinBenchRegressionSamples)
+	}
+
 	// Save current results
 	currentFile := defaultCurrentFile
 	if err := os.Setenv("MAGE_X_BENCH_FILE", currentFile); err != nil {
@@ -532,11 +551,34 @@ func (Bench) RegressionWithArgs(argsList ...string) error {
 	}
 
 	utils.Info("Comparing with baseline...")
-	compareArgs := []string{"old=" + baseline, "new=" + currentFile}
-	if err := b.CompareWithArgs(compareArgs...); err != nil {
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	regressionCfg := config.Test.BenchRegression
+	report, err := compareBenchFiles(baseline, currentFile, regressionCfg)
+	if err != nil {
 		return fmt.Errorf("failed to compare benchmark results: %w", err)
 	}
 
+	utils.Info("%s", report.markdown())
+	if err := report.writeStepSummary(); err != nil {
+		utils.Warn("Failed to write step summary: %v", err)
+	}
+	reportPath := utils.GetParam(params, "json", regressionCfg.Report)
+	if reportPath == "" {
+		reportPath = DefaultBenchRegressionReport
+	}
+	if err := report.writeJSON(reportPath); err != nil {
+		return fmt.Errorf("failed to write benchmark report: %w", err)
+	}
+	utils.Info("Comparison written to %s", reportPath)
+
+	// A regression fails the run and keeps the baseline
+	if report.Regressions > 0 {
+		return fmt.Errorf("%w: %d metric(s), see %s", ErrBenchRegression, report.Regressions, reportPath)
+	}
+
 	// Ask about updating baseline
 	updateBaseline := utils.GetParam(params, "update-baseline", "")
 	if updateBaseline == "" {
@@ -0,0 +1,493 @@
+package mage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for benchmark history
+var (
+	errInvalidBenchHistoryParam  = errors.New("invalid bench:history parameter")
+	errUnknownBenchHistoryFormat = errors.New("unknown bench:history format")
+)
+
+// Benchmark history default values
+const (
+	defaultBenchHistoryCommits   = 20
+	defaultBenchHistoryThreshold = 10.0
+	benchStepMinSamples          = 4 // Fewer samples per side can never reach significance
+)
+
+// sparkBlocks renders trend lines in the terminal, lowest to highest
+//
+//nolint:gochecknoglobals // read-only glyph table
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// benchCPUPattern matches the cpu line go test prints before benchmark results
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var benchCPUPattern = regexp.MustCompile(`(?m)^cpu:\s*(.+)$`)
+
+// benchMachine identifies where a benchmark run happened. Results are only
+// comparable between runs with the same fingerprint.
+type benchMachine struct {
+	Fingerprint string `json:"fingerprint"`
+	Hostname    string `json:"hostname"`
+	OS          string `json:"os"`
+	Arch        string `json:"arch"`
+	NumCPU      int    `json:"num_cpu"`
+	CPU         string `json:"cpu,omitempty"`
+}
+
+// benchHistoryRecord is one saved run, keyed by commit, branch, Go version and machine
+type benchHistoryRecord struct {
+	Commit     string              `json:"commit"`
+	Branch     string              `json:"branch"`
+	GoVersion  string              `json:"go_version"`
+	Machine    benchMachine        `json:"machine"`
+	Timestamp  time.Time           `json:"timestamp"`
+	Benchmarks []benchHistoryEntry `json:"benchmarks"`
+}
+
+// benchHistoryEntry holds the samples of one benchmark in a record
+type benchHistoryEntry struct {
+	Package string               `json:"package,omitempty"`
+	Name    string               `json:"name"`
+	Samples map[string][]float64 `json:"samples"`
+}
+
+// benchPoint is one record's value for a benchmark metric
+type benchPoint struct {
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch"`
+	GoVersion string    `json:"go_version"`
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Samples   int       `json:"samples"`
+	raw       []float64
+}
+
+// benchStep is a change between two consecutive points beyond the threshold
+type benchStep struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Delta  float64 `json:"delta_percent"`
+	PValue float64 `json:"p_value"`
+}
+
+// benchSeries is the trend of one benchmark metric across the records of one machine
+type benchSeries struct {
+	Package string       `json:"package,omitempty"`
+	Name    string       `json:"name"`
+	Machine string       `json:"machine"` // Fingerprint of the machine the points come from
+	Metric  string       `json:"metric"`
+	Points  []benchPoint `json:"points"`
+	Steps   []benchStep  `json:"steps"`
+}
+
+// benchHistoryDir returns the history store location
+func benchHistoryDir() string {
+	if dir := GetMageXEnv("BENCH_HISTORY_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultBenchHistoryDir
+}
+
+// currentBenchMachine describes this machine; cpu is the model reported by go test, if any
+func currentBenchMachine(cpu string) benchMachine {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = statusUnknown
+	}
+	m := benchMachine{Hostname: hostname, OS: runtime.GOOS, Arch: runtime.GOARCH, NumCPU: runtime.NumCPU(), CPU: cpu}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", m.Hostname, m.OS, m.Arch, m.NumCPU)))
+	m.Fingerprint = hex.EncodeToString(sum[:])[:12]
+	return m
+}
+
+// newBenchHistoryRecord builds a record from go test -bench output and the current checkout
+func newBenchHistoryRecord(output string, now time.Time) *benchHistoryRecord {
+	cpu := ""
+	if match := benchCPUPattern.FindStringSubmatch(output); match != nil {
+		cpu = strings.TrimSpace(match[1])
+	}
+
+	record := &benchHistoryRecord{
+		Commit:    statusUnknown,
+		Branch:    statusUnknown,
+		GoVersion: runtime.Version(),
+		Machine:   currentBenchMachine(cpu),
+		Timestamp: now.UTC(),
+	}
+	if commit, err := GetRunner().RunCmdOutput("git", "rev-parse", "HEAD"); err == nil && strings.TrimSpace(commit) != "" {
+		record.Commit = strings.TrimSpace(commit)
+	}
+	if branch, err := getCurrentBranch(); err == nil && branch != "" {
+		record.Branch = branch
+	}
+	if version, err := GetRunner().RunCmdOutput("go", "env", "GOVERSION"); err == nil && strings.HasPrefix(strings.TrimSpace(version), "go") {
+		record.GoVersion = strings.TrimSpace(version)
+	}
+
+	samples := parseBenchOutput(output)
+	keys := make([]string, 0, len(samples))
+	for key := range samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := samples[key]
+		record.Benchmarks = append(record.Benchmarks, benchHistoryEntry{Package: s.pkg, Name: s.name, Samples: s.metrics})
+	}
+	return record
+}
+
+// fileName returns the record's file name; saving the same key again replaces it
+func (r *benchHistoryRecord) fileName() string {
+	commit := r.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	sum := sha256.Sum256([]byte(r.Branch + "|" + r.GoVersion + "|" + r.Machine.Fingerprint))
+	return fmt.Sprintf("%s-%s.json", commit, hex.EncodeToString(sum[:])[:12])
+}
+
+// saveBenchHistoryRecord writes record to the history store in dir
+func saveBenchHistoryRecord(dir string, record *benchHistoryRecord) (string, error) {
+	fileOps := fileops.New()
+	if err := fileOps.File.MkdirAll(dir, fileops.PermDir); err != nil {
+		return "", fmt.Errorf("failed to create benchmark history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode benchmark history record: %w", err)
+	}
+	path := filepath.Join(dir, record.fileName())
+	if err := fileOps.File.WriteFile(path, data, fileops.PermFile); err != nil {
+		return "", fmt.Errorf("failed to write benchmark history record: %w", err)
+	}
+	return path, nil
+}
+
+// recordBenchHistory stores the results of a bench:save run
+func recordBenchHistory(output string) error {
+	record := newBenchHistoryRecord(output, time.Now())
+	if len(record.Benchmarks) == 0 {
+		return nil
+	}
+	path, err := saveBenchHistoryRecord(benchHistoryDir(), record)
+	if err != nil {
+		return err
+	}
+	utils.Info("Benchmark history updated: %s", path)
+	return nil
+}
+
+// loadBenchHistory reads every record in dir, oldest first
+func loadBenchHistory(dir string) ([]*benchHistoryRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark history: %w", err)
+	}
+
+	fileOps := fileops.New()
+	var records []*benchHistoryRecord
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := fileOps.File.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read benchmark history record: %w", err)
+		}
+		var record benchHistoryRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			utils.Warn("Skipping unreadable benchmark history record %s: %v", entry.Name(), err)
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records, nil
+}
+
+// benchHistoryFilter selects the records a trend report covers
+type benchHistoryFilter struct {
+	commits int
+	branch  string
+	machine string // Fingerprint, or empty for every machine
+	pattern *regexp.Regexp
+}
+
+// apply keeps matching records from the last commits distinct commits, oldest first
+func (f benchHistoryFilter) apply(records []*benchHistoryRecord) []*benchHistoryRecord {
+	seen := make(map[string]bool)
+	var kept []*benchHistoryRecord
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if (f.branch != "" && r.Branch != f.branch) || (f.machine != "" && r.Machine.Fingerprint != f.machine) {
+			continue
+		}
+		if !seen[r.Commit] {
+			if len(seen) == f.commits {
+				break
+			}
+			seen[r.Commit] = true
+		}
+		kept = append(kept, r)
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// buildBenchSeries turns records into per-benchmark, per-machine, per-metric trends
+// and flags step changes beyond threshold percent. Machines get separate series so
+// a switch of machine is never reported as a step. With enough samples on both
+// sides a step must also be significant at alpha.
+func buildBenchSeries(records []*benchHistoryRecord, pattern *regexp.Regexp, threshold, alpha float64) []*benchSeries {
+	byKey := make(map[string]*benchSeries)
+	for _, r := range records {
+		for _, b := range r.Benchmarks {
+			if pattern != nil && !pattern.MatchString(b.Name) {
+				continue
+			}
+			for _, metric := range benchMetrics {
+				values := b.Samples[metric]
+				if len(values) == 0 {
+					continue
+				}
+				machine := r.Machine.Fingerprint
+				key := b.Package + " " + b.Name + " " + machine + " " + metric
+				series, ok := byKey[key]
+				if !ok {
+					series = &benchSeries{Package: b.Package, Name: b.Name, Machine: machine, Metric: metric, Steps: []benchStep{}}
+					byKey[key] = series
+				}
+				series.Points = append(series.Points, benchPoint{
+					Commit:    r.Commit,
+					Branch:    r.Branch,
+					GoVersion: r.GoVersion,
+					Timestamp: r.Timestamp,
+					Value:     benchMedian(values),
+					Samples:   len(values),
+					raw:       values,
+				})
+			}
+		}
+	}
+
+	all := make([]*benchSeries, 0, len(byKey))
+	for _, series := range byKey {
+		series.detectSteps(threshold, alpha)
+		all = append(all, series)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Package != all[j].Package {
+			return all[i].Package < all[j].Package
+		}
+		if all[i].Name != all[j].Name {
+			return all[i].Name < all[j].Name
+		}
+		if all[i].Machine != all[j].Machine {
+			return all[i].Machine < all[j].Machine
+		}
+		return metricIndex(all[i].Metric) < metricIndex(all[j].Metric)
+	})
+	return all
+}
+
+// metricIndex orders metrics as benchMetrics lists them
+func metricIndex(metric string) int {
+	for i, m := range benchMetrics {
+		if m == metric {
+			return i
+		}
+	}
+	return len(benchMetrics)
+}
+
+// detectSteps flags consecutive points whose values differ beyond threshold percent
+func (s *benchSeries) detectSteps(threshold, alpha float64) {
+	for i := 1; i < len(s.Points); i++ {
+		prev, cur := s.Points[i-1], s.Points[i]
+		if prev.Value == 0 {
+			continue
+		}
+		delta := (cur.Value - prev.Value) / prev.Value * 100
+		if math.Abs(delta) < threshold {
+			continue
+		}
+		pValue := mannWhitneyU(prev.raw, cur.raw)
+		if len(prev.raw) >= benchStepMinSamples && len(cur.raw) >= benchStepMinSamples && pValue >= alpha {
+			continue
+		}
+		s.Steps = append(s.Steps, benchStep{From: prev.Commit, To: cur.Commit, Delta: delta, PValue: pValue})
+	}
+}
+
+// sparkline renders the series values with block glyphs
+func (s *benchSeries) sparkline() string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range s.Points {
+		lo, hi = math.Min(lo, p.Value), math.Max(hi, p.Value)
+	}
+	var sb strings.Builder
+	for _, p := range s.Points {
+		idx := 0
+		if hi > lo {
+			idx = int((p.Value - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
+
+// shortCommit abbreviates a commit SHA for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// printBenchTrends prints one line per series with its trend and step changes. The
+// machine column only appears when the series come from more than one machine.
+func printBenchTrends(series []*benchSeries) {
+	machines := make(map[string]bool)
+	for _, s := range series {
+		machines[s.Machine] = true
+	}
+	machineColumn := func(machine string) string {
+		if len(machines) < 2 {
+			return ""
+		}
+		return fmt.Sprintf(" %-12s", machine)
+	}
+
+	utils.Print("%-32s %-40s%s %-10s %6s %12s %12s %9s  %-20s %s\n", "PACKAGE", "BENCHMARK", machineColumn("MACHINE"),
+		"METRIC", "POINTS", "FIRST", "LAST", "CHANGE", "TREND", "STEPS")
+	for _, s := range series {
+		first, last := s.Points[0].Value, s.Points[len(s.Points)-1].Value
+		change := "n/a"
+		if first != 0 {
+			change = fmt.Sprintf("%+.1f%%", (last-first)/first*100)
+		}
+		steps := make([]string, 0, len(s.Steps))
+		for _, step := range s.Steps {
+			steps = append(steps, fmt.Sprintf("%+.1f%% at %s", step.Delta, shortCommit(step.To)))
+		}
+		// Pad by runes: the block glyphs are multi-byte
+		trend := s.sparkline()
+		trend += strings.Repeat(" ", max(0, 20-utf8.RuneCountInString(trend)))
+		utils.Print("%-32s %-40s%s %-10s %6d %12s %12s %9s  %s %s\n", s.Package, s.Name, machineColumn(s.Machine),
+			s.Metric, len(s.Points), formatBenchValue(first), formatBenchValue(last), change, trend, strings.Join(steps, ", "))
+	}
+}
+
+// History shows benchmark trends from the history store
+func (Bench) History() error {
+	return Bench{}.HistoryWithArgs()
+}
+
+// HistoryWithArgs shows benchmark trends over the last commits and flags step changes.
+// Parameters: n=<commits>, bench=<regex>, branch=<name>, machine=all, threshold=<percent>,
+// format=table|csv|json|html and output=<file>.
+func (Bench) HistoryWithArgs(argsList ...string) error {
+	utils.Header("Benchmark History")
+	params := utils.ParseParams(argsList)
+
+	filter := benchHistoryFilter{
+		commits: defaultBenchHistoryCommits,
+		branch:  utils.GetParam(params, "branch", ""),
+		machine: currentBenchMachine("").Fingerprint,
+	}
+	if v := utils.GetParam(params, "n", ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("%w: n=%s", errInvalidBenchHistoryParam, v)
+		}
+		filter.commits = n
+	}
+	if utils.GetParam(params, "machine", "") == "all" {
+		filter.machine = ""
+	}
+	if v := utils.GetParam(params, "bench", ""); v != "" {
+		pattern, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("%w: bench=%s: %w", errInvalidBenchHistoryParam, v, err)
+		}
+		filter.pattern = pattern
+	}
+	threshold := defaultBenchHistoryThreshold
+	if v := utils.GetParam(params, "threshold", ""); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 {
+			return fmt.Errorf("%w: threshold=%s", errInvalidBenchHistoryParam, v)
+		}
+		threshold = t
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	alpha := config.Test.BenchRegression.Alpha
+	if alpha <= 0 || alpha >= 1 {
+		alpha = DefaultBenchRegressionAlpha
+	}
+
+	dir := benchHistoryDir()
+	records, err := loadBenchHistory(dir)
+	if err != nil {
+		return err
+	}
+	records = filter.apply(records)
+	if len(records) == 0 {
+		utils.Info("No benchmark history in %s for this machine", dir)
+		utils.Info("Record runs with 'magex bench:save', or use machine=all to include other machines")
+		return nil
+	}
+
+	series := buildBenchSeries(records, filter.pattern, threshold, alpha)
+	format := utils.GetParam(params, "format", "table")
+	output := utils.GetParam(params, "output", "")
+	switch format {
+	case "table":
+		utils.Info("%d record(s) from %s, step threshold %g%%", len(records), dir, threshold)
+		printBenchTrends(series)
+		return nil
+	case "csv":
+		return writeBenchHistoryExport(output, func(f *os.File) error { return writeBenchHistoryCSV(f, series) })
+	case "json":
+		return writeBenchHistoryExport(output, func(f *os.File) error { return writeBenchHistoryJSON(f, series) })
+	case "html":
+		if output == "" {
+			output = defaultBenchHistoryHTML
+		}
+		return writeBenchHistoryExport(output, func(f *os.File) error { return writeBenchHistoryHTML(f, series) })
+	default:
+		return fmt.Errorf("%w: %s (use table, csv, json or html)", errUnknownBenchHistoryFormat, format)
+	}
+}
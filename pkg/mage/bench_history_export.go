@@ -0,0 +1,202 @@
+package mage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// defaultBenchHistoryHTML is where format=html writes without output=
+const defaultBenchHistoryHTML = "bench-history.html"
+
+// Chart geometry for the HTML export, in SVG units
+const (
+	benchChartWidth   = 640.0
+	benchChartHeight  = 160.0
+	benchChartPadding = 12.0
+)
+
+// writeBenchHistoryExport runs write against output, or stdout when output is empty
+func writeBenchHistoryExport(output string, write func(f *os.File) error) error {
+	if output == "" {
+		return write(os.Stdout)
+	}
+
+	if dir := filepath.Dir(output); dir != "." && dir != "" {
+		if err := fileops.New().File.MkdirAll(dir, fileops.PermDir); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileops.PermFile) // #nosec G304 -- output path chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close() //nolint:errcheck // already returning the write error
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", output, err)
+	}
+	utils.Success("Benchmark history written to %s", output)
+	return nil
+}
+
+// writeBenchHistoryCSV writes one row per point
+func writeBenchHistoryCSV(w io.Writer, series []*benchSeries) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"timestamp", "commit", "branch", "go_version", "package", "benchmark", "metric", "value", "samples", "machine"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, s := range series {
+		for _, p := range s.Points {
+			row := []string{
+				p.Timestamp.Format(time.RFC3339), p.Commit, p.Branch, p.GoVersion,
+				s.Package, s.Name, s.Metric, formatBenchValue(p.Value), strconv.Itoa(p.Samples), s.Machine,
+			}
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV: %w", err)
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeBenchHistoryJSON writes the series with their points and steps
+func writeBenchHistoryJSON(w io.Writer, series []*benchSeries) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(series); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// benchChart is a series laid out for the HTML template
+type benchChart struct {
+	Title    string
+	Package  string
+	Machine  string
+	Summary  string
+	Polyline string
+	Dots     []benchChartDot
+	Min, Max string
+}
+
+// benchChartDot is one point of a chart
+type benchChartDot struct {
+	X, Y  float64
+	Label string
+	Step  bool
+}
+
+// newBenchChart scales a series into the chart area
+func newBenchChart(s *benchSeries) benchChart {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range s.Points {
+		lo, hi = math.Min(lo, p.Value), math.Max(hi, p.Value)
+	}
+	steps := make(map[string]bool, len(s.Steps))
+	for _, step := range s.Steps {
+		steps[step.To] = true
+	}
+
+	chart := benchChart{
+		Title:   s.Name + " (" + s.Metric + ")",
+		Package: s.Package,
+		Machine: s.Machine,
+		Summary: fmt.Sprintf("%d point(s), %d step change(s)", len(s.Points), len(s.Steps)),
+		Min:     formatBenchValue(lo),
+		Max:     formatBenchValue(hi),
+	}
+	coords := make([]string, 0, len(s.Points))
+	for i, p := range s.Points {
+		x := benchChartPadding
+		if len(s.Points) > 1 {
+			x += float64(i) / float64(len(s.Points)-1) * (benchChartWidth - 2*benchChartPadding)
+		}
+		y := benchChartHeight / 2
+		if hi > lo {
+			y = benchChartHeight - benchChartPadding - (p.Value-lo)/(hi-lo)*(benchChartHeight-2*benchChartPadding)
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+		chart.Dots = append(chart.Dots, benchChartDot{
+			X:     x,
+			Y:     y,
+			Label: fmt.Sprintf("%s %s: %s %s", shortCommit(p.Commit), p.Timestamp.Format(time.DateOnly), formatBenchValue(p.Value), s.Metric),
+			Step:  steps[p.Commit] && i > 0,
+		})
+	}
+	chart.Polyline = strings.Join(coords, " ")
+	return chart
+}
+
+// benchHistoryPage renders every chart as inline SVG with no external assets
+//
+//nolint:gochecknoglobals // parsed once, read-only
+var benchHistoryPage = template.Must(template.New("bench-history").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Benchmark History</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #1f2328; }
+h1 { font-size: 1.4rem; }
+.chart { margin-bottom: 2rem; }
+.chart h2 { font-size: 1rem; margin: 0; font-family: monospace; }
+.meta { color: #59636e; font-size: 0.85rem; }
+svg { background: #f6f8fa; border: 1px solid #d1d9e0; border-radius: 6px; }
+polyline { fill: none; stroke: #0969da; stroke-width: 2; }
+circle { fill: #0969da; }
+circle.step { fill: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Benchmark History</h1>
+<p class="meta">Generated {{.Generated}}. Red points mark step changes.</p>
+{{range .Charts}}<div class="chart">
+<h2>{{.Title}}</h2>
+<p class="meta">{{if .Package}}{{.Package}} · {{end}}{{if .Machine}}machine {{.Machine}} · {{end}}{{.Summary}} · min {{.Min}} · max {{.Max}}</p>
+<svg width="{{$.Width}}" height="{{$.Height}}" viewBox="0 0 {{$.Width}} {{$.Height}}" role="img">
+<polyline points="{{.Polyline}}"/>
+{{range .Dots}}<circle cx="{{.X}}" cy="{{.Y}}" r="4"{{if .Step}} class="step"{{end}}><title>{{.Label}}</title></circle>
+{{end}}</svg>
+</div>
+{{else}}<p>No benchmarks recorded.</p>
+{{end}}</body>
+</html>
+`))
+
+// writeBenchHistoryHTML writes a self-contained page with one chart per series
+func writeBenchHistoryHTML(w io.Writer, series []*benchSeries) error {
+	charts := make([]benchChart, 0, len(series))
+	for _, s := range series {
+		charts = append(charts, newBenchChart(s))
+	}
+	data := struct {
+		Generated     string
+		Width, Height float64
+		Charts        []benchChart
+	}{
+		Generated: time.Now().Format(time.RFC1123),
+		Width:     benchChartWidth,
+		Height:    benchChartHeight,
+		Charts:    charts,
+	}
+	if err := benchHistoryPage.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
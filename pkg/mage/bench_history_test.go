@@ -0,0 +1,253 @@
+package mage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// benchHistoryRunner answers the git and go queries made when recording a run
+type benchHistoryRunner struct {
+	commit string
+	branch string
+}
+
+func (m *benchHistoryRunner) RunCmd(_ string, _ ...string) error {
+	return nil
+}
+
+func (m *benchHistoryRunner) RunCmdOutput(name string, args ...string) (string, error) {
+	switch name + " " + strings.Join(args, " ") {
+	case "git rev-parse HEAD":
+		return m.commit + "\n", nil
+	case "git branch --show-current":
+		return m.branch + "\n", nil
+	case "go env GOVERSION":
+		return "go1.25.0\n", nil
+	default:
+		return "", nil
+	}
+}
+
+// withBenchHistoryRunner swaps in a runner reporting the given checkout
+func withBenchHistoryRunner(t *testing.T, commit, branch string) {
+	t.Helper()
+	original := GetRunner()
+	require.NoError(t, SetRunner(&benchHistoryRunner{commit: commit, branch: branch}))
+	t.Cleanup(func() {
+		require.NoError(t, SetRunner(original))
+	})
+}
+
+// benchHistoryFixture saves one record per commit with the given ns/op samples
+func benchHistoryFixture(t *testing.T, dir string, runs map[string][]int) {
+	t.Helper()
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, commit := range []string{"aaaaaaa1", "bbbbbbb2", "ccccccc3", "ddddddd4"} {
+		samples, ok := runs[commit]
+		if !ok {
+			continue
+		}
+		withBenchHistoryRunner(t, commit, "main")
+		output := "cpu: Test CPU @ 3.00GHz\n" + benchRun("example.com/a", "BenchmarkParse", 2, samples...)
+		_, err := saveBenchHistoryRecord(dir, newBenchHistoryRecord(output, start.Add(time.Duration(i)*time.Hour)))
+		require.NoError(t, err)
+	}
+}
+
+func TestNewBenchHistoryRecord(t *testing.T) {
+	withBenchHistoryRunner(t, "0123456789abcdef0123", "feature/x")
+
+	output := "goos: linux\ncpu: Test CPU @ 3.00GHz\n" + benchRun("example.com/a", "BenchmarkParse", 2, 100, 110)
+	record := newBenchHistoryRecord(output, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "0123456789abcdef0123", record.Commit)
+	assert.Equal(t, "feature/x", record.Branch)
+	assert.Equal(t, "go1.25.0", record.GoVersion)
+	assert.Equal(t, "Test CPU @ 3.00GHz", record.Machine.CPU)
+	assert.Equal(t, currentBenchMachine("").Fingerprint, record.Machine.Fingerprint, "the CPU model is not part of the fingerprint")
+	require.Len(t, record.Benchmarks, 1)
+	assert.Equal(t, []float64{100, 110}, record.Benchmarks[0].Samples["ns/op"])
+
+	name := record.fileName()
+	assert.True(t, strings.HasPrefix(name, "0123456789ab-"), name)
+	record.GoVersion = "go1.26.0"
+	assert.NotEqual(t, name, record.fileName(), "another Go version is another record")
+}
+
+func TestBenchHistoryStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+
+	records, err := loadBenchHistory(dir)
+	require.NoError(t, err)
+	assert.Empty(t, records, "a missing store is empty")
+
+	benchHistoryFixture(t, dir, map[string][]int{"aaaaaaa1": {100}, "bbbbbbb2": {100}})
+	benchHistoryFixture(t, dir, map[string][]int{"bbbbbbb2": {90}})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600))
+
+	records, err = loadBenchHistory(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 2, "saving the same commit again replaces its record")
+	assert.Equal(t, "aaaaaaa1", records[0].Commit)
+	assert.Equal(t, []float64{90}, records[1].Benchmarks[0].Samples["ns/op"])
+}
+
+func TestBenchHistoryFilter(t *testing.T) {
+	t.Parallel()
+
+	at := func(h int) time.Time { return time.Date(2026, 10, 1, h, 0, 0, 0, time.UTC) }
+	records := []*benchHistoryRecord{
+		{Commit: "a", Branch: "main", Machine: benchMachine{Fingerprint: "m1"}, Timestamp: at(1)},
+		{Commit: "b", Branch: "main", Machine: benchMachine{Fingerprint: "m1"}, Timestamp: at(2)},
+		{Commit: "b", Branch: "main", Machine: benchMachine{Fingerprint: "m1"}, GoVersion: "go1.26", Timestamp: at(3)},
+		{Commit: "c", Branch: "dev", Machine: benchMachine{Fingerprint: "m1"}, Timestamp: at(4)},
+		{Commit: "d", Branch: "main", Machine: benchMachine{Fingerprint: "m2"}, Timestamp: at(5)},
+	}
+
+	commits := func(rs []*benchHistoryRecord) string {
+		var sb strings.Builder
+		for _, r := range rs {
+			sb.WriteString(r.Commit)
+		}
+		return sb.String()
+	}
+	assert.Equal(t, "bbc", commits(benchHistoryFilter{commits: 2, machine: "m1"}.apply(records)))
+	assert.Equal(t, "abb", commits(benchHistoryFilter{commits: 5, machine: "m1", branch: "main"}.apply(records)))
+	assert.Equal(t, "abbcd", commits(benchHistoryFilter{commits: 10}.apply(records)))
+}
+
+func TestBuildBenchSeries(t *testing.T) {
+	dir := t.TempDir()
+	benchHistoryFixture(t, dir, map[string][]int{
+		"aaaaaaa1": {100, 101, 99, 100, 102},
+		"bbbbbbb2": {104, 105, 103, 104, 106}, // within threshold
+		"ccccccc3": {130, 131, 129, 130, 132}, // significant step up
+		"ddddddd4": {90},                      // too few samples to test, so the threshold decides
+	})
+	records, err := loadBenchHistory(dir)
+	require.NoError(t, err)
+
+	series := buildBenchSeries(records, nil, 10, DefaultBenchRegressionAlpha)
+	require.Len(t, series, 3)
+	ns := series[0]
+	assert.Equal(t, "BenchmarkParse", ns.Name)
+	assert.Equal(t, "ns/op", ns.Metric)
+	require.Len(t, ns.Points, 4)
+	assert.InDelta(t, 130.0, ns.Points[2].Value, 0)
+
+	require.Len(t, ns.Steps, 2)
+	assert.Equal(t, "bbbbbbb2", ns.Steps[0].From)
+	assert.Equal(t, "ccccccc3", ns.Steps[0].To)
+	assert.InDelta(t, 25.0, ns.Steps[0].Delta, 1e-9)
+	assert.Equal(t, "ddddddd4", ns.Steps[1].To)
+	assert.Empty(t, series[1].Steps, "B/op never changed")
+	assert.Equal(t, "▂▃█▁", ns.sparkline())
+
+	assert.Empty(t, buildBenchSeries(records, regexp.MustCompile(`^BenchmarkOther$`), 10, DefaultBenchRegressionAlpha))
+}
+
+func TestBuildBenchSeries_SeparatesMachines(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 10, 1, hour, 0, 0, 0, time.UTC) }
+	run := func(commit, machine string, hour int, value float64) *benchHistoryRecord {
+		return &benchHistoryRecord{
+			Commit:    commit,
+			Machine:   benchMachine{Fingerprint: machine},
+			Timestamp: at(hour),
+			Benchmarks: []benchHistoryEntry{
+				{Package: "example.com/a", Name: "BenchmarkParse", Samples: map[string][]float64{"ns/op": {value}}},
+			},
+		}
+	}
+	// The laptop is twice as slow as the CI runner; neither machine changes
+	records := []*benchHistoryRecord{
+		run("a", "ci", 1, 100), run("b", "laptop", 2, 200), run("c", "ci", 3, 101), run("d", "laptop", 4, 201),
+	}
+
+	series := buildBenchSeries(records, nil, 10, DefaultBenchRegressionAlpha)
+	require.Len(t, series, 2)
+	for _, s := range series {
+		assert.Len(t, s.Points, 2, s.Machine)
+		assert.Empty(t, s.Steps, "switching machines is not a step change on %s", s.Machine)
+	}
+	assert.Equal(t, "ci", series[0].Machine)
+	assert.Equal(t, "laptop", series[1].Machine)
+
+	// Capture stdout to verify the table names the package and machine
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	printBenchTrends(series)
+	os.Stdout = old
+	require.NoError(t, w.Close())
+	output, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "PACKAGE")
+	assert.Contains(t, string(output), "MACHINE")
+	assert.Contains(t, string(output), "example.com/a")
+}
+
+func TestBenchHistoryExports(t *testing.T) {
+	dir := t.TempDir()
+	benchHistoryFixture(t, dir, map[string][]int{"aaaaaaa1": {100}, "bbbbbbb2": {150}})
+	records, err := loadBenchHistory(dir)
+	require.NoError(t, err)
+	series := buildBenchSeries(records, nil, 10, DefaultBenchRegressionAlpha)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeBenchHistoryCSV(&buf, series))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 7, "header plus two points for each of three metrics")
+	assert.Equal(t, []string{"2026-10-01T13:00:00Z", "bbbbbbb2", "main", "go1.25.0", "example.com/a", "BenchmarkParse", "ns/op", "150", "1", series[0].Machine}, rows[2])
+
+	buf.Reset()
+	require.NoError(t, writeBenchHistoryJSON(&buf, series))
+	var decoded []benchSeries
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 3)
+	require.Len(t, decoded[0].Steps, 1)
+	assert.InDelta(t, 50.0, decoded[0].Steps[0].Delta, 1e-9)
+
+	buf.Reset()
+	require.NoError(t, writeBenchHistoryHTML(&buf, series))
+	page := buf.String()
+	assert.Contains(t, page, "<svg")
+	assert.Contains(t, page, "BenchmarkParse (ns/op)")
+	assert.Contains(t, page, `class="step"`)
+	assert.NotContains(t, page, "<script", "the page is self-contained")
+}
+
+func TestBenchHistoryWithArgs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	t.Setenv("MAGE_X_BENCH_HISTORY_DIR", dir)
+	benchHistoryFixture(t, dir, map[string][]int{"aaaaaaa1": {100}, "bbbbbbb2": {150}})
+
+	bench := Bench{}
+	require.NoError(t, bench.HistoryWithArgs())
+
+	output := filepath.Join(t.TempDir(), "out", "history.csv")
+	require.NoError(t, bench.HistoryWithArgs("format=csv", "output="+output, "n=1"))
+	data, err := os.ReadFile(output) //nolint:gosec // test file in temp dir
+	require.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(string(data), "\n"), "only the latest commit")
+
+	require.ErrorIs(t, bench.HistoryWithArgs("n=0"), errInvalidBenchHistoryParam)
+	require.ErrorIs(t, bench.HistoryWithArgs("bench=("), errInvalidBenchHistoryParam)
+	require.ErrorIs(t, bench.HistoryWithArgs("format=xml"), errUnknownBenchHistoryFormat)
+
+	t.Setenv("MAGE_X_BENCH_HISTORY_DIR", filepath.Join(t.TempDir(), "empty"))
+	require.NoError(t, bench.HistoryWithArgs(), "an empty store is not an error")
+}
@@ -0,0 +1,423 @@
+package mage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+)
+
+// Benchmark metrics compared by the regression gate
+const (
+	benchMetricNsPerOp     = "ns/op"
+	benchMetricBytesPerOp  = "B/op"
+	benchMetricAllocsPerOp = "allocs/op"
+)
+
+// Comparison outcomes
+const (
+	benchStatusRegressed = "regressed"
+	benchStatusImproved  = "improved"
+	benchStatusUnchanged = "unchanged"
+)
+
+// maxExactUTestSize bounds the sample product for which the exact U distribution is computed
+const maxExactUTestSize = 400
+
+//nolint:gochecknoglobals // read-only metric order
+var benchMetrics = []string{benchMetricNsPerOp, benchMetricBytesPerOp, benchMetricAllocsPerOp}
+
+// benchLinePattern matches a result line: name, iterations, then value/unit pairs
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var benchLinePattern = regexp.MustCompile(`^(Benchmark\S*)\s+\d+\s+(.+)$`)
+
+// benchProcsSuffix matches the -GOMAXPROCS suffix go test appends to benchmark names
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var benchProcsSuffix = regexp.MustCompile(`-\d+$`)
+
+// benchSamples holds every measurement of one benchmark, per metric
+type benchSamples struct {
+	pkg     string
+	name    string
+	metrics map[string][]float64
+}
+
+// benchComparison is the result of comparing one metric of one benchmark
+type benchComparison struct {
+	Package    string   `json:"package,omitempty"`
+	Name       string   `json:"name"`
+	Metric     string   `json:"metric"`
+	Old        float64  `json:"old"`
+	New        float64  `json:"new"`
+	OldSamples int      `json:"old_samples"`
+	NewSamples int      `json:"new_samples"`
+	Delta      *float64 `json:"delta_percent,omitempty"` // Unset when the baseline is zero
+	PValue     float64  `json:"p_value"`
+	Threshold  float64  `json:"threshold_percent"` // Negative when the metric is not checked
+	Status     string   `json:"status"`
+}
+
+// benchReport is the full comparison between a baseline and a current run
+type benchReport struct {
+	Baseline    string            `json:"baseline"`
+	Current     string            `json:"current"`
+	Alpha       float64           `json:"alpha"`
+	Regressions int               `json:"regressions"`
+	Comparisons []benchComparison `json:"comparisons"`
+}
+
+// parseBenchOutput collects benchmark samples from go test -bench output. Names
+// lose their -GOMAXPROCS suffix so runs on machines with different CPU counts compare.
+func parseBenchOutput(output string) map[string]*benchSamples {
+	results := make(map[string]*benchSamples)
+	pkg := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "pkg:"); ok {
+			pkg = strings.TrimSpace(rest)
+			continue
+		}
+
+		match := benchLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name := benchProcsSuffix.ReplaceAllString(match[1], "")
+		key := pkg + " " + name
+		samples, ok := results[key]
+		if !ok {
+			samples = &benchSamples{pkg: pkg, name: name, metrics: make(map[string][]float64)}
+			results[key] = samples
+		}
+
+		fields := strings.Fields(match[2])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			samples.metrics[fields[i+1]] = append(samples.metrics[fields[i+1]], value)
+		}
+	}
+	return results
+}
+
+// compareBenchmarks compares every metric present in both runs
+func compareBenchmarks(baseline, current map[string]*benchSamples, cfg BenchRegressionConfig) *benchReport {
+	alpha := cfg.Alpha
+	if alpha <= 0 || alpha >= 1 {
+		alpha = DefaultBenchRegressionAlpha
+	}
+	report := &benchReport{Alpha: alpha, Comparisons: []benchComparison{}}
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		if _, ok := baseline[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldRun, newRun := baseline[key], current[key]
+		for _, metric := range benchMetrics {
+			oldValues, newValues := oldRun.metrics[metric], newRun.metrics[metric]
+			if len(oldValues) == 0 || len(newValues) == 0 {
+				continue
+			}
+
+			c := benchComparison{
+				Package:    newRun.pkg,
+				Name:       newRun.name,
+				Metric:     metric,
+				Old:        benchMedian(oldValues),
+				New:        benchMedian(newValues),
+				OldSamples: len(oldValues),
+				NewSamples: len(newValues),
+				PValue:     mannWhitneyU(oldValues, newValues),
+				Threshold:  benchThreshold(cfg, newRun.pkg, newRun.name, metric),
+				Status:     benchStatusUnchanged,
+			}
+			if c.Old != 0 {
+				delta := (c.New - c.Old) / c.Old * 100
+				c.Delta = &delta
+			}
+
+			significant := c.PValue < alpha && c.New != c.Old
+			switch {
+			case significant && c.New > c.Old && c.Threshold >= 0 && (c.Delta == nil || *c.Delta > c.Threshold):
+				c.Status = benchStatusRegressed
+				report.Regressions++
+			case significant && c.New < c.Old:
+				c.Status = benchStatusImproved
+			}
+			report.Comparisons = append(report.Comparisons, c)
+		}
+	}
+	return report
+}
+
+// benchThreshold resolves the allowed increase for a metric: an exact benchmark
+// entry, then the longest matching glob, then the global thresholds, then the default
+func benchThreshold(cfg BenchRegressionConfig, pkg, name, metric string) float64 {
+	var exact, globs []string
+	for pattern := range cfg.Benchmarks {
+		if pattern == name || pattern == pkg+"."+name {
+			exact = append(exact, pattern)
+		} else if ok, err := path.Match(pattern, name); err == nil && ok {
+			globs = append(globs, pattern)
+		}
+	}
+	sort.Strings(exact)
+	sort.Slice(globs, func(i, j int) bool {
+		if len(globs[i]) != len(globs[j]) {
+			return len(globs[i]) > len(globs[j])
+		}
+		return globs[i] < globs[j]
+	})
+
+	for _, pattern := range append(exact, globs...) {
+		if value := cfg.Benchmarks[pattern].get(metric); value != nil {
+			return *value
+		}
+	}
+	if value := cfg.Thresholds.get(metric); value != nil {
+		return *value
+	}
+	return DefaultBenchRegressionThreshold
+}
+
+// get returns the threshold configured for metric, or nil when unset
+func (t BenchThresholds) get(metric string) *float64 {
+	switch metric {
+	case benchMetricNsPerOp:
+		return t.NsPerOp
+	case benchMetricBytesPerOp:
+		return t.BytesPerOp
+	case benchMetricAllocsPerOp:
+		return t.AllocsPerOp
+	default:
+		return nil
+	}
+}
+
+// benchMedian returns the median of values
+func benchMedian(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mannWhitneyU returns the two-sided p-value of the Mann-Whitney U test for
+// samples x and y. Small samples without ties use the exact distribution of U;
+// otherwise the normal approximation with tie and continuity correction is used.
+func mannWhitneyU(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	type ranked struct {
+		value float64
+		first bool
+	}
+	all := make([]ranked, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, ranked{value: v, first: true})
+	}
+	for _, v := range y {
+		all = append(all, ranked{value: v})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Assign average ranks to ties and accumulate the tie correction term
+	rankSum, tieTerm, ties := 0.0, 0.0, false
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				rankSum += avgRank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+
+	u := rankSum - float64(n1*(n1+1))/2
+	if !ties && n1*n2 <= maxExactUTestSize {
+		return exactUTestP(n1, n2, int(math.Round(u)))
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactUTestP returns the exact two-sided p-value for U with sample sizes n1 and n2
+func exactUTestP(n1, n2, u int) float64 {
+	// counts[i][j][k] is the number of orderings of i and j values with U = k,
+	// built with the recurrence f(i,j,k) = f(i-1,j,k-j) + f(i,j-1,k)
+	maxU := n1 * n2
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = make([]float64, maxU+1)
+		prev[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = make([]float64, maxU+1)
+		cur[0][0] = 1
+		for j := 1; j <= n2; j++ {
+			cur[j] = make([]float64, maxU+1)
+			for k := 0; k <= i*j; k++ {
+				if k >= j {
+					cur[j][k] += prev[j][k-j]
+				}
+				cur[j][k] += cur[j-1][k]
+			}
+		}
+		prev = cur
+	}
+
+	dist := prev[n2]
+	total, lower, upper := 0.0, 0.0, 0.0
+	for k, count := range dist {
+		total += count
+		if k <= u {
+			lower += count
+		}
+		if k >= u {
+			upper += count
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// markdown renders the comparison as a Markdown table
+func (r *benchReport) markdown() string {
+	var sb strings.Builder
+	sb.WriteString("## Benchmark Comparison\n\n")
+	if len(r.Comparisons) == 0 {
+		sb.WriteString("No benchmarks in common between the baseline and the current run.\n")
+		return sb.String()
+	}
+
+	if r.Regressions > 0 {
+		fmt.Fprintf(&sb, "❌ **%d regression(s)** beyond threshold (α = %g)\n\n", r.Regressions, r.Alpha)
+	} else {
+		fmt.Fprintf(&sb, "✅ No regressions beyond threshold (α = %g)\n\n", r.Alpha)
+	}
+
+	sb.WriteString("| Benchmark | Metric | Baseline | Current | Delta | p | Threshold | Result |\n")
+	sb.WriteString("|-----------|--------|----------|---------|-------|---|-----------|--------|\n")
+	for _, c := range r.Comparisons {
+		delta := "n/a"
+		if c.Delta != nil {
+			delta = fmt.Sprintf("%+.2f%%", *c.Delta)
+		}
+		threshold := "off"
+		if c.Threshold >= 0 {
+			threshold = fmt.Sprintf("%g%%", c.Threshold)
+		}
+		result := "~"
+		switch c.Status {
+		case benchStatusRegressed:
+			result = "❌ regressed"
+		case benchStatusImproved:
+			result = "✅ improved"
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %s | %s | %.3f | %s | %s |\n",
+			c.Name, c.Metric, formatBenchValue(c.Old), formatBenchValue(c.New), delta, c.PValue, threshold, result)
+	}
+	return sb.String()
+}
+
+// formatBenchValue prints a measurement without trailing zeros
+func formatBenchValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// writeJSON writes the report to path, creating its directory
+func (r *benchReport) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode benchmark report: %w", err)
+	}
+	fileOps := fileops.New()
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := fileOps.File.MkdirAll(dir, fileops.PermDir); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	return fileOps.File.WriteFile(path, data, fileops.PermFile)
+}
+
+// writeStepSummary appends the Markdown table to the GitHub step summary, if any
+func (r *benchReport) writeStepSummary() error {
+	summaryFile := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryFile == "" {
+		return nil
+	}
+	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileops.PermFileSensitive) // #nosec G304 -- path set by GitHub Actions
+	if err != nil {
+		return fmt.Errorf("failed to open step summary file: %w", err)
+	}
+	if _, err := f.WriteString(r.markdown() + "\n"); err != nil {
+		_ = f.Close() //nolint:errcheck // already returning the write error
+		return fmt.Errorf("failed to write step summary: %w", err)
+	}
+	return f.Close()
+}
+
+// compareBenchFiles parses and compares a baseline and a current results file
+func compareBenchFiles(baselineFile, currentFile string, cfg BenchRegressionConfig) (*benchReport, error) {
+	fileOps := fileops.New()
+	oldData, err := fileOps.File.ReadFile(baselineFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	newData, err := fileOps.File.ReadFile(currentFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current results: %w", err)
+	}
+
+	report := compareBenchmarks(parseBenchOutput(string(oldData)), parseBenchOutput(string(newData)), cfg)
+	for _, c := range report.Comparisons {
+		if c.OldSamples < MinBenchRegressionSamples || c.NewSamples < MinBenchRegressionSamples {
+			return nil, fmt.Errorf("%w: %s has %d baseline and %d current samples, record both with count=%d or more",
+				ErrBenchTooFewSamples, c.Name, c.OldSamples, c.NewSamples, MinBenchRegressionSamples)
+		}
+	}
+	report.Baseline = baselineFile
+	report.Current = currentFile
+	return report, nil
+}
@@ -0,0 +1,195 @@
+package mage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// benchRun renders go test -bench output with one line per ns/op sample
+func benchRun(pkg, name string, allocs int, nsPerOp ...int) string {
+	var sb strings.Builder
+	sb.WriteString("goos: linux\ngoarch: amd64\npkg: " + pkg + "\n")
+	for _, ns := range nsPerOp {
+		sb.WriteString(name + "-8   \t 1000000\t" + strconv.Itoa(ns) + " ns/op\t 64 B/op\t " + strconv.Itoa(allocs) + " allocs/op\n")
+	}
+	sb.WriteString("PASS\nok  \t" + pkg + "\t1.234s\n")
+	return sb.String()
+}
+
+func thresholdPtr(v float64) *float64 {
+	return &v
+}
+
+func TestParseBenchOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "# Module: main\n" + benchRun("example.com/a", "BenchmarkParse/small", 2, 100, 110) +
+		"BenchmarkCustom-16 \t 50\t 12.5 ns/op\t 3.0 widgets/op\n" +
+		"BenchmarkBroken-8 \t FAIL\n"
+
+	results := parseBenchOutput(output)
+	require.Len(t, results, 2)
+
+	parse := results["example.com/a BenchmarkParse/small"]
+	require.NotNil(t, parse)
+	assert.Equal(t, []float64{100, 110}, parse.metrics["ns/op"])
+	assert.Equal(t, []float64{64, 64}, parse.metrics["B/op"])
+	assert.Equal(t, []float64{2, 2}, parse.metrics["allocs/op"])
+
+	custom := results["example.com/a BenchmarkCustom"]
+	require.NotNil(t, custom, "the GOMAXPROCS suffix is dropped")
+	assert.Equal(t, []float64{3}, custom.metrics["widgets/op"])
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	t.Parallel()
+
+	// Fully separated samples of five: the smallest exact two-sided p-value is 2/252
+	assert.InDelta(t, 2.0/252, mannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}), 1e-12)
+	assert.InDelta(t, 2.0/252, mannWhitneyU([]float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}), 1e-12)
+	assert.InDelta(t, 1.0, mannWhitneyU([]float64{1, 4, 5, 8}, []float64{2, 3, 6, 7}), 1e-12)
+	assert.InDelta(t, 1.0, mannWhitneyU([]float64{100}, []float64{200}), 1e-12, "single samples are never significant")
+
+	// Ties switch to the normal approximation
+	assert.InDelta(t, 1.0, mannWhitneyU([]float64{5, 5, 5}, []float64{5, 5, 5}), 1e-12)
+	assert.Less(t, mannWhitneyU([]float64{3, 3, 3, 3, 3}, []float64{4, 4, 4, 4, 4}), 0.05)
+}
+
+func TestBenchThreshold(t *testing.T) {
+	t.Parallel()
+
+	cfg := BenchRegressionConfig{
+		Thresholds: BenchThresholds{NsPerOp: thresholdPtr(5)},
+		Benchmarks: map[string]BenchThresholds{
+			"BenchmarkParse*":                   {NsPerOp: thresholdPtr(20)},
+			"*/*":                               {AllocsPerOp: thresholdPtr(-1)},
+			"BenchmarkParse/*":                  {NsPerOp: thresholdPtr(30)},
+			"example.com/a.BenchmarkParse/fast": {NsPerOp: thresholdPtr(1)},
+		},
+	}
+
+	assert.InDelta(t, 5.0, benchThreshold(cfg, "example.com/a", "BenchmarkOther", "ns/op"), 0)
+	assert.InDelta(t, DefaultBenchRegressionThreshold, benchThreshold(cfg, "example.com/a", "BenchmarkOther", "B/op"), 0)
+	assert.InDelta(t, 20.0, benchThreshold(cfg, "example.com/a", "BenchmarkParseJSON", "ns/op"), 0)
+	assert.InDelta(t, 30.0, benchThreshold(cfg, "example.com/a", "BenchmarkParse/slow", "ns/op"), 0, "the longest glob wins")
+	assert.InDelta(t, 1.0, benchThreshold(cfg, "example.com/a", "BenchmarkParse/fast", "ns/op"), 0, "exact names win")
+	assert.InDelta(t, -1.0, benchThreshold(cfg, "example.com/a", "BenchmarkParse/slow", "allocs/op"), 0,
+		"unset metrics fall through to the next matching entry")
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	t.Parallel()
+
+	baseline := parseBenchOutput(
+		benchRun("example.com/a", "BenchmarkSlower", 2, 100, 101, 99, 100, 102) +
+			benchRun("example.com/a", "BenchmarkFaster", 2, 200, 201, 199, 200, 202) +
+			benchRun("example.com/a", "BenchmarkNoisy", 2, 100, 101, 99, 100, 102) +
+			benchRun("example.com/a", "BenchmarkRemoved", 2, 100))
+	current := parseBenchOutput(
+		benchRun("example.com/a", "BenchmarkSlower", 3, 130, 131, 129, 130, 132) +
+			benchRun("example.com/a", "BenchmarkFaster", 2, 100, 101, 99, 100, 102) +
+			benchRun("example.com/a", "BenchmarkNoisy", 2, 105, 106, 104, 105, 107) +
+			benchRun("example.com/a", "BenchmarkAdded", 2, 100))
+
+	report := compareBenchmarks(baseline, current, BenchRegressionConfig{})
+	assert.InDelta(t, DefaultBenchRegressionAlpha, report.Alpha, 0)
+	require.Len(t, report.Comparisons, 9, "three metrics for each benchmark in both runs")
+
+	byKey := make(map[string]benchComparison)
+	for _, c := range report.Comparisons {
+		byKey[c.Name+" "+c.Metric] = c
+	}
+	slower := byKey["BenchmarkSlower ns/op"]
+	assert.Equal(t, benchStatusRegressed, slower.Status)
+	assert.InDelta(t, 30.0, *slower.Delta, 1e-9)
+	assert.Less(t, slower.PValue, 0.05)
+	assert.Equal(t, benchStatusRegressed, byKey["BenchmarkSlower allocs/op"].Status)
+	assert.Equal(t, benchStatusUnchanged, byKey["BenchmarkSlower B/op"].Status)
+	assert.Equal(t, benchStatusImproved, byKey["BenchmarkFaster ns/op"].Status)
+	assert.Equal(t, benchStatusUnchanged, byKey["BenchmarkNoisy ns/op"].Status, "significant but within threshold")
+	assert.Equal(t, 2, report.Regressions)
+
+	// Raising the thresholds accepts the same numbers
+	relaxed := compareBenchmarks(baseline, current, BenchRegressionConfig{
+		Benchmarks: map[string]BenchThresholds{"BenchmarkSlower": {NsPerOp: thresholdPtr(50), AllocsPerOp: thresholdPtr(-1)}},
+	})
+	assert.Zero(t, relaxed.Regressions)
+}
+
+func TestCompareBenchmarks_ZeroBaseline(t *testing.T) {
+	t.Parallel()
+
+	baseline := parseBenchOutput(benchRun("example.com/a", "BenchmarkAlloc", 0, 10, 10, 10, 10, 10))
+	current := parseBenchOutput(benchRun("example.com/a", "BenchmarkAlloc", 1, 10, 10, 10, 10, 10))
+
+	report := compareBenchmarks(baseline, current, BenchRegressionConfig{})
+	require.Equal(t, 1, report.Regressions)
+	for _, c := range report.Comparisons {
+		if c.Metric == "allocs/op" {
+			assert.Nil(t, c.Delta)
+			assert.Equal(t, benchStatusRegressed, c.Status)
+		}
+	}
+}
+
+func TestBenchReport_Outputs(t *testing.T) {
+	dir := t.TempDir()
+	baselineFile := filepath.Join(dir, "bench-baseline.txt")
+	currentFile := filepath.Join(dir, "bench-current.txt")
+	require.NoError(t, os.WriteFile(baselineFile, []byte(benchRun("example.com/a", "BenchmarkSlower", 2, 100, 101, 99, 100, 102)), 0o600))
+	require.NoError(t, os.WriteFile(currentFile, []byte(benchRun("example.com/a", "BenchmarkSlower", 2, 130, 131, 129, 130, 132)), 0o600))
+
+	report, err := compareBenchFiles(baselineFile, currentFile, BenchRegressionConfig{})
+	require.NoError(t, err)
+
+	markdown := report.markdown()
+	assert.Contains(t, markdown, "**1 regression(s)**")
+	assert.Contains(t, markdown, "| `BenchmarkSlower` | ns/op | 100 | 130 | +30.00% | 0.012 | 10% | ❌ regressed |")
+
+	summary := filepath.Join(dir, "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+	require.NoError(t, report.writeStepSummary())
+	data, err := os.ReadFile(summary) //nolint:gosec // test file in temp dir
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "## Benchmark Comparison")
+
+	jsonPath := filepath.Join(dir, ".mage-x", "bench-regression.json")
+	require.NoError(t, report.writeJSON(jsonPath))
+	data, err = os.ReadFile(jsonPath) //nolint:gosec // test file in temp dir
+	require.NoError(t, err)
+	var decoded benchReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 1, decoded.Regressions)
+	assert.Equal(t, baselineFile, decoded.Baseline)
+	require.Len(t, decoded.Comparisons, 3)
+	assert.Equal(t, "example.com/a", decoded.Comparisons[0].Package)
+
+	_, err = compareBenchFiles(filepath.Join(dir, "missing.txt"), currentFile, BenchRegressionConfig{})
+	require.Error(t, err)
+}
+
+func TestCompareBenchFiles_TooFewSamples(t *testing.T) {
+	dir := t.TempDir()
+	baselineFile := filepath.Join(dir, "bench-baseline.txt")
+	currentFile := filepath.Join(dir, "bench-current.txt")
+	require.NoError(t, os.WriteFile(baselineFile, []byte(benchRun("example.com/a", "BenchmarkSlower", 2, 100)), 0o600))
+	require.NoError(t, os.WriteFile(currentFile, []byte(benchRun("example.com/a", "BenchmarkSlower", 2, 300, 301, 299, 300, 302)), 0o600))
+
+	_, err := compareBenchFiles(baselineFile, currentFile, BenchRegressionConfig{})
+	require.ErrorIs(t, err, ErrBenchTooFewSamples)
+	assert.Contains(t, err.Error(), "BenchmarkSlower has 1 baseline and 5 current samples")
+}
+
+func TestBenchRegression_RequiresCount(t *testing.T) {
+	for _, count := range []string{"1", "4", "many"} {
+		err := Bench{}.RegressionWithArgs("count=" + count)
+		require.ErrorIs(t, err, ErrBenchTooFewSamples, "count=%s", count)
+	}
+}
@@ -73,13 +73,16 @@ type DefaultCacheManagerProvider struct {
 func NewDefaultCacheManagerProvider() *DefaultCacheManagerProvider {
 	factory := func() *cache.Manager {
 		config := cache.DefaultConfig()
-		// Cache configuration uses default settings for now.
-		// Future enhancement: integrate with main Config struct for customization.
+		// Only the remote cache is configurable (cache.remote in .mage.yaml);
+		// everything else uses the default settings.
 
 		// Check if cache is disabled via environment
 		if os.Getenv("MAGE_X_CACHE_DISABLED") == trueValue {
 			config.Enabled = false
 		}
+		if mageConfig, err := GetConfig(); err == nil {
+			applyRemoteCacheConfig(config, mageConfig.Cache.Remote)
+		}
 
 		manager := cache.NewManager(config)
 		if manager != nil {
@@ -98,6 +101,28 @@ func NewDefaultCacheManagerProvider() *DefaultCacheManagerProvider {
 	}
 }
 
+// applyRemoteCacheConfig attaches the shared remote cache selected in the cache.remote
+// section of .mage.yaml. Read-only mode lets untrusted clients (e.g. forks or laptops)
+// download entries without being able to publish them.
+func applyRemoteCacheConfig(cacheConfig *cache.Config, remote RemoteCacheConfig) {
+	if remote.URL == "" {
+		return
+	}
+	cacheConfig.Remote = &cache.RemoteConfig{
+		URL:        remote.URL,
+		Token:      env.MustGet("MAGE_X_CACHE_REMOTE_TOKEN"),
+		SigningKey: env.MustGet("MAGE_X_CACHE_REMOTE_SIGNING_KEY"),
+		ReadOnly:   remote.ReadOnly,
+	}
+	if remote.Timeout != "" {
+		if d, err := time.ParseDuration(remote.Timeout); err == nil && d > 0 {
+			cacheConfig.Remote.Timeout = d
+		} else {
+			utils.Warn("Ignoring invalid remote cache timeout %q", remote.Timeout)
+		}
+	}
+}
+
 // GetCacheManager returns a cache manager instance using thread-safe singleton pattern
 func (p *DefaultCacheManagerProvider) GetCacheManager() *cache.Manager {
 	return p.Get()
@@ -2,6 +2,7 @@ package mage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -158,6 +159,34 @@ func (s *BuildCacheTestSuite) TestBuildContextWithCacheManager() {
 	s.Same(result, result2, "Multiple calls should return identical instances")
 }
 
+// TestApplyRemoteCacheConfig tests that the remote cache is configured from .mage.yaml and the environment
+func (s *BuildCacheTestSuite) TestApplyRemoteCacheConfig() {
+	config := cache.DefaultConfig()
+	applyRemoteCacheConfig(config, RemoteCacheConfig{})
+	s.Nil(config.Remote, "no remote cache without a URL")
+
+	s.T().Setenv("MAGE_X_CACHE_REMOTE_TOKEN", "s3cret")
+	s.T().Setenv("MAGE_X_CACHE_REMOTE_SIGNING_KEY", "sign")
+	applyRemoteCacheConfig(config, RemoteCacheConfig{URL: "https://cache.example.com", ReadOnly: true, Timeout: "3s"})
+	s.Require().NotNil(config.Remote)
+	s.Equal("https://cache.example.com", config.Remote.URL)
+	s.Equal("s3cret", config.Remote.Token)
+	s.Equal("sign", config.Remote.SigningKey)
+	s.True(config.Remote.ReadOnly)
+	s.Equal(3*time.Second, config.Remote.Timeout)
+}
+
+// TestRemoteCacheEnvOverrides tests that the environment overrides the cache.remote section
+func (s *BuildCacheTestSuite) TestRemoteCacheEnvOverrides() {
+	s.T().Setenv("MAGE_X_CACHE_REMOTE_URL", "https://cache.example.com")
+	s.T().Setenv("MAGE_X_CACHE_REMOTE_READ_ONLY", "true")
+	s.T().Setenv("MAGE_X_CACHE_REMOTE_TIMEOUT", "3s")
+
+	config := &Config{Cache: CacheConfig{Remote: RemoteCacheConfig{URL: "https://other.example.com"}}}
+	applyEnvOverrides(config)
+	s.Equal(RemoteCacheConfig{URL: "https://cache.example.com", ReadOnly: true, Timeout: "3s"}, config.Cache.Remote)
+}
+
 // TestBuildNamespace runs the BuildCacheTestSuite
 func TestBuildCache(t *testing.T) {
 	suite.Run(t, new(BuildCacheTestSuite))
@@ -0,0 +1,697 @@
+package mage
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/magefile/mage/mg"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for development certificate operations
+var (
+	errUnsupportedCertKeyType = errors.New("unsupported certificate key type")
+	errInvalidCertDuration    = errors.New("invalid certificate duration")
+	errInvalidCertName        = errors.New("invalid certificate name")
+	errNoPEMBlock             = errors.New("no PEM block found")
+	errUnsupportedPrivateKey  = errors.New("private key cannot sign certificates")
+	errCertKeyMismatch        = errors.New("private key does not match the certificate")
+	errInvalidCertRSABits     = errors.New("invalid RSA key size")
+	errDevCertNotManaged      = errors.New("existing certificate is not managed by the development CA")
+	errDevCertBackupExists    = errors.New("certificate backup already exists")
+)
+
+const (
+	// defaultCertsDir is where install:certs writes the CA and certificates
+	defaultCertsDir = "certs"
+
+	// defaultCertName is the certificate issued when none are configured
+	defaultCertName = "server"
+
+	// certsCAName is the file name (without extension) of the development CA
+	certsCAName = "ca"
+
+	// certKeyECDSA selects P-256 ECDSA keys
+	certKeyECDSA = "ecdsa"
+
+	// certKeyRSA selects RSA keys
+	certKeyRSA = "rsa"
+
+	// defaultCertRSABits is the RSA key size when rsa_bits is not set
+	defaultCertRSABits = 2048
+
+	// minCertRSABits and maxCertRSABits bound rsa_bits
+	minCertRSABits = 2048
+	maxCertRSABits = 8192
+
+	// devCAOrganization is the subject organization of the development CA, which
+	// identifies certificates it issued across CA renewals
+	devCAOrganization = "mage-x development CA"
+
+	// certBackupSuffix is appended to certificates and keys replaced with force=true
+	certBackupSuffix = ".bak"
+
+	// certBackupTimeFormat dates a backup when an earlier one already has the plain suffix
+	certBackupTimeFormat = "20060102-150405"
+
+	// defaultCertValidity is the lifetime of issued certificates
+	defaultCertValidity = 365 * 24 * time.Hour
+
+	// defaultCAValidity is the lifetime of the development CA
+	defaultCAValidity = 10 * 365 * 24 * time.Hour
+
+	// defaultCertRenewBefore reissues certificates that expire within this window
+	defaultCertRenewBefore = 30 * 24 * time.Hour
+
+	// certClockSkew backdates certificates so machines with a slow clock accept them
+	certClockSkew = time.Hour
+)
+
+// Certs namespace for development certificate tasks
+type Certs mg.Namespace
+
+// devCertSettings is the certs config with defaults applied and durations parsed
+type devCertSettings struct {
+	dir          string
+	keyType      string
+	rsaBits      int
+	validity     time.Duration
+	caValidity   time.Duration
+	renewBefore  time.Duration
+	certificates []DevCertConfig
+}
+
+// devCertPaths returns the certificate and key paths of a named certificate
+func (s *devCertSettings) devCertPaths(name string) (certPath, keyPath string) {
+	return filepath.Join(s.dir, name+".crt"), filepath.Join(s.dir, name+".key")
+}
+
+// Certs creates a local development CA and the certificates configured under certs
+// in .mage.yaml, signed by it, using crypto/x509 only. Certificates are reissued
+// when they are missing, near expiry, no longer signed by the CA, or lack a
+// configured host or key type; valid ones are left alone.
+func (Install) Certs() error {
+	return Install{}.CertsWithArgs()
+}
+
+// CertsWithArgs creates the development CA and certificates. A certificate or key
+// that cannot be read or was not issued by the development CA, and a CA that cannot
+// be read, does not match its key or is not a CA, is never replaced unless
+// force=true is passed; it is then kept with a .bak suffix.
+func (Install) CertsWithArgs(argsList ...string) error {
+	utils.Header("Installing Development Certificates")
+
+	params := utils.ParseParams(argsList)
+	force := utils.IsParamTrue(params, "force")
+
+	settings, err := loadDevCertSettings()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(settings.dir, fileops.PermDirSensitive); err != nil {
+		return fmt.Errorf("failed to create certs directory: %w", err)
+	}
+
+	ca, caKey, caIssued, err := ensureDevCA(settings, force)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, cert := range settings.certificates {
+		certPath, keyPath := settings.devCertPaths(cert.Name)
+		reason := "the CA was reissued"
+		if !caIssued {
+			reason = devCertRenewReason(settings, cert, ca, now)
+		}
+		if reason == "" {
+			utils.Info("%s is up to date", certPath)
+			continue
+		}
+		if conflict := devCertConflict(certPath, keyPath); conflict != "" {
+			if !force {
+				return fmt.Errorf("%w: %s (%s); run with force=true to replace it, keeping a %s copy",
+					errDevCertNotManaged, certPath, conflict, certBackupSuffix)
+			}
+			if err := backupDevCert(certPath, keyPath); err != nil {
+				return err
+			}
+		}
+
+		utils.Info("Issuing %s: %s", certPath, reason)
+		if err := issueDevCert(settings, cert, ca, caKey, now); err != nil {
+			return err
+		}
+		utils.Info("Certificate: %s (%s)", certPath, strings.Join(devCertHosts(cert), ", "))
+		utils.Info("Private key: %s", keyPath)
+	}
+
+	caPath, _ := settings.devCertPaths(certsCAName)
+	utils.Success("Development certificates ready")
+	if caIssued {
+		utils.Info("Trust %s in your browser or OS certificate store to accept them", caPath)
+	}
+	return nil
+}
+
+// Status lists the development CA and every certificate in the certs directory
+// with its hosts, expiry and whether install:certs would reissue it
+func (Certs) Status() error {
+	utils.Header("Development Certificates")
+
+	settings, err := loadDevCertSettings()
+	if err != nil {
+		return err
+	}
+
+	caPath, _ := settings.devCertPaths(certsCAName)
+	ca, err := readCertificate(caPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		utils.Info("No development CA in %s, run: magex install:certs", settings.dir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	names, err := devCertNames(settings)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CERTIFICATE\tHOSTS\tEXPIRES\tSTATUS") //nolint:errcheck // writes to strings.Builder never fail
+	caStatus := devCertExpiryStatus(ca, settings.renewBefore, now)
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", caPath, ca.Subject.CommonName, ca.NotAfter.Format(time.DateOnly), caStatus) //nolint:errcheck // writes to strings.Builder never fail
+	for _, name := range names {
+		certPath, _ := settings.devCertPaths(name)
+		cert, readErr := readCertificate(certPath)
+		if readErr != nil {
+			_, _ = fmt.Fprintf(w, "%s\t-\t-\t%s\n", certPath, devCertReadStatus(readErr)) //nolint:errcheck // writes to strings.Builder never fail
+			continue
+		}
+		status := devCertExpiryStatus(cert, settings.renewBefore, now)
+		if cert.CheckSignatureFrom(ca) != nil {
+			status = "not signed by the CA"
+		}
+		hosts := strings.Join(certificateHosts(cert), ", ")
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", certPath, hosts, cert.NotAfter.Format(time.DateOnly), status) //nolint:errcheck // writes to strings.Builder never fail
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to format status: %w", err)
+	}
+	utils.Print("%s", sb.String())
+	return nil
+}
+
+// loadDevCertSettings reads the certs config and applies the defaults
+func loadDevCertSettings() (*devCertSettings, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration: %w", err)
+	}
+	certs := config.Certs
+
+	settings := &devCertSettings{
+		dir:          certs.Dir,
+		keyType:      strings.ToLower(certs.KeyType),
+		rsaBits:      certs.RSABits,
+		certificates: certs.Certificates,
+	}
+	if settings.dir == "" {
+		settings.dir = defaultCertsDir
+	}
+	if settings.keyType == "" {
+		settings.keyType = certKeyECDSA
+	}
+	if settings.keyType != certKeyECDSA && settings.keyType != certKeyRSA {
+		return nil, fmt.Errorf("%w: %q (use %q or %q)", errUnsupportedCertKeyType, certs.KeyType, certKeyECDSA, certKeyRSA)
+	}
+	if settings.rsaBits == 0 {
+		settings.rsaBits = defaultCertRSABits
+	}
+	if settings.rsaBits < minCertRSABits || settings.rsaBits > maxCertRSABits {
+		return nil, fmt.Errorf("%w: certs.rsa_bits %d (use %d to %d)", errInvalidCertRSABits, certs.RSABits, minCertRSABits, maxCertRSABits)
+	}
+	if len(settings.certificates) == 0 {
+		settings.certificates = []DevCertConfig{{Name: defaultCertName}}
+	}
+	for _, cert := range settings.certificates {
+		if cert.Name == "" || cert.Name == certsCAName || strings.ContainsAny(cert.Name, `/\`) {
+			return nil, fmt.Errorf("%w: %q", errInvalidCertName, cert.Name)
+		}
+	}
+
+	durations := []struct {
+		value    string
+		fallback time.Duration
+		target   *time.Duration
+		field    string
+	}{
+		{certs.Validity, defaultCertValidity, &settings.validity, "validity"},
+		{certs.CAValidity, defaultCAValidity, &settings.caValidity, "ca_validity"},
+		{certs.RenewBefore, defaultCertRenewBefore, &settings.renewBefore, "renew_before"},
+	}
+	for _, d := range durations {
+		*d.target = d.fallback
+		if d.value == "" {
+			continue
+		}
+		parsed, parseErr := time.ParseDuration(d.value)
+		if parseErr != nil || parsed < 0 {
+			return nil, fmt.Errorf("%w: certs.%s %q", errInvalidCertDuration, d.field, d.value)
+		}
+		*d.target = parsed
+	}
+	return settings, nil
+}
+
+// ensureDevCA loads the development CA, creating it when it is missing or near expiry.
+// A CA that cannot be read, does not match its key or is not a CA is only replaced
+// with force, keeping the original with the backup suffix; issued reports whether
+// a new CA was created.
+func ensureDevCA(settings *devCertSettings, force bool) (ca *x509.Certificate, key crypto.Signer, issued bool, err error) {
+	certPath, keyPath := settings.devCertPaths(certsCAName)
+	ca, key, loadErr := loadCertificatePair(certPath, keyPath)
+	if loadErr == nil && ca.IsCA && time.Until(ca.NotAfter) > settings.renewBefore {
+		return ca, key, false, nil
+	}
+
+	conflict := ""
+	switch {
+	case errors.Is(loadErr, fs.ErrNotExist) && !utils.FileExists(certPath) && !utils.FileExists(keyPath):
+		utils.Info("Creating development CA %s", certPath)
+	case loadErr != nil:
+		conflict = loadErr.Error()
+	case !ca.IsCA:
+		conflict = "not a CA certificate"
+	default:
+		utils.Info("Renewing development CA %s, it expires %s", certPath, ca.NotAfter.Format(time.DateOnly))
+	}
+	if conflict != "" {
+		if !force {
+			return nil, nil, false, fmt.Errorf("%w: %s (%s); run with force=true to replace it, keeping a %s copy",
+				errDevCertNotManaged, certPath, conflict, certBackupSuffix)
+		}
+		if err := backupDevCert(certPath, keyPath); err != nil {
+			return nil, nil, false, err
+		}
+		utils.Warn("Replacing development CA %s: %s", certPath, conflict)
+	}
+
+	key, err = generateCertKey(settings.keyType, settings.rsaBits)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{devCAOrganization},
+			CommonName:   devCAOrganization + " " + certOwner(),
+		},
+		NotBefore:             now.Add(-certClockSkew),
+		NotAfter:              now.Add(settings.caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	ca, err = createCertificate(template, template, key, key, certPath, keyPath)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return ca, key, true, nil
+}
+
+// devCertRenewReason explains why a certificate has to be issued again, or
+// returns "" when it is still good
+func devCertRenewReason(settings *devCertSettings, config DevCertConfig, ca *x509.Certificate, now time.Time) string {
+	certPath, keyPath := settings.devCertPaths(config.Name)
+	cert, _, err := loadCertificatePair(certPath, keyPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "missing"
+	case err != nil:
+		return err.Error()
+	case cert.CheckSignatureFrom(ca) != nil:
+		return "not signed by the current CA"
+	case cert.NotAfter.Sub(now) <= settings.renewBefore:
+		return "expires " + cert.NotAfter.Format(time.DateOnly)
+	case certKeyTypeOf(cert) != settings.keyType:
+		return "key type changed to " + settings.keyType
+	}
+
+	have := certificateHosts(cert)
+	for _, host := range devCertHosts(config) {
+		if !slices.Contains(have, host) {
+			return "host " + host + " added"
+		}
+	}
+	return ""
+}
+
+// devCertConflict explains why an existing certificate or key must not be replaced
+// without force=true: it cannot be read or a CA other than the development CA
+// issued it. It returns "" when there is nothing to keep.
+func devCertConflict(certPath, keyPath string) string {
+	if !utils.FileExists(certPath) && !utils.FileExists(keyPath) {
+		return ""
+	}
+	cert, err := readCertificate(certPath)
+	if err == nil && utils.FileExists(keyPath) {
+		cert, _, err = loadCertificatePair(certPath, keyPath)
+	}
+	switch {
+	case err != nil:
+		return err.Error()
+	case !slices.Contains(cert.Issuer.Organization, devCAOrganization):
+		return "issued by " + cert.Issuer.String()
+	}
+	return ""
+}
+
+// backupDevCert moves an existing certificate and key aside with the backup suffix.
+// When an earlier backup is in the way the new one is dated instead, so forced runs
+// never destroy a backup.
+func backupDevCert(certPath, keyPath string) error {
+	suffix := certBackupSuffix
+	if utils.FileExists(certPath+suffix) || utils.FileExists(keyPath+suffix) {
+		suffix += "." + time.Now().Format(certBackupTimeFormat)
+	}
+	for _, path := range []string{certPath, keyPath} {
+		if utils.FileExists(path + suffix) {
+			return fmt.Errorf("%w: %s", errDevCertBackupExists, path+suffix)
+		}
+	}
+	for _, path := range []string{certPath, keyPath} {
+		if !utils.FileExists(path) {
+			continue
+		}
+		if err := os.Rename(path, path+suffix); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		utils.Warn("Kept the existing %s as %s", path, filepath.Base(path)+suffix)
+	}
+	return nil
+}
+
+// issueDevCert writes a new key and certificate for config, signed by the CA
+func issueDevCert(settings *devCertSettings, config DevCertConfig, ca *x509.Certificate, caKey crypto.Signer, now time.Time) error {
+	key, err := generateCertKey(settings.keyType, settings.rsaBits)
+	if err != nil {
+		return err
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return err
+	}
+
+	hosts := devCertHosts(config)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"mage-x development certificate"},
+			CommonName:   hosts[0],
+		},
+		NotBefore:   now.Add(-certClockSkew),
+		NotAfter:    now.Add(settings.validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if settings.keyType == certKeyRSA {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	if template.NotAfter.After(ca.NotAfter) {
+		template.NotAfter = ca.NotAfter
+	}
+
+	certPath, keyPath := settings.devCertPaths(config.Name)
+	_, err = createCertificate(template, ca, key, caKey, certPath, keyPath)
+	return err
+}
+
+// createCertificate signs template for key with signer and writes the certificate
+// and key as PEM; the key file is private to the user
+func createCertificate(template, parent *x509.Certificate, key, signer crypto.Signer, certPath, keyPath string) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate %s: %w", certPath, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", certPath, err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key %s: %w", keyPath, err)
+	}
+	if err := writeSensitivePEM(keyPath, "PRIVATE KEY", keyDER); err != nil {
+		return nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certPath, certPEM, fileops.PermFile); err != nil {
+		return nil, fmt.Errorf("failed to write certificate %s: %w", certPath, err)
+	}
+	return cert, nil
+}
+
+// writeSensitivePEM writes a PEM block readable only by the user, tightening the
+// mode of an existing file
+func writeSensitivePEM(path, blockType string, der []byte) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, fileops.PermFileSensitive); err != nil {
+		return fmt.Errorf("failed to write key %s: %w", path, err)
+	}
+	if err := os.Chmod(path, fileops.PermFileSensitive); err != nil {
+		return fmt.Errorf("failed to restrict key %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadCertificatePair reads a certificate and its key and checks they belong together
+func loadCertificatePair(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	cert, err := readCertificate(certPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := readPrivateKey(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type comparablePublicKey interface {
+		Equal(x crypto.PublicKey) bool
+	}
+	pub, ok := key.Public().(comparablePublicKey)
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return nil, nil, fmt.Errorf("%w: %s, %s", errCertKeyMismatch, keyPath, certPath)
+	}
+	return cert, key, nil
+}
+
+// readCertificate parses the first PEM certificate in path
+func readCertificate(path string) (*x509.Certificate, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+	}
+	return cert, nil
+}
+
+// readPrivateKey parses a PKCS#8, PKCS#1 or EC private key from path
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedPrivateKey, path)
+	}
+	return signer, nil
+}
+
+// readPEMBlock returns the first PEM block of a file
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is inside the configured certs directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	block, _ := pem.Decode(bytes.TrimSpace(data))
+	if block == nil {
+		return nil, fmt.Errorf("%w in %s", errNoPEMBlock, path)
+	}
+	return block, nil
+}
+
+// generateCertKey creates a P-256 ECDSA or RSA key
+func generateCertKey(keyType string, rsaBits int) (crypto.Signer, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch keyType {
+	case certKeyECDSA:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case certKeyRSA:
+		key, err = rsa.GenerateKey(rand.Reader, rsaBits)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedCertKeyType, keyType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", keyType, err)
+	}
+	return key, nil
+}
+
+// randomSerialNumber returns a random 128-bit certificate serial number
+func randomSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// devCertHosts returns localhost, the loopback addresses and the configured hosts
+// of a certificate, without duplicates
+func devCertHosts(config DevCertConfig) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	for _, host := range config.Hosts {
+		if host = strings.TrimSpace(host); host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// certificateHosts returns the DNS names and IP addresses a certificate covers
+func certificateHosts(cert *x509.Certificate) []string {
+	hosts := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	return hosts
+}
+
+// certKeyTypeOf returns the certs.key_type value matching a certificate's key
+func certKeyTypeOf(cert *x509.Certificate) string {
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		return certKeyECDSA
+	case x509.RSA:
+		return certKeyRSA
+	default:
+		return strings.ToLower(cert.PublicKeyAlgorithm.String())
+	}
+}
+
+// devCertNames returns the configured certificates followed by any other
+// certificate found in the certs directory
+func devCertNames(settings *devCertSettings) ([]string, error) {
+	names := make([]string, 0, len(settings.certificates))
+	for _, cert := range settings.certificates {
+		names = append(names, cert.Name)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(settings.dir, "*.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+	slices.Sort(matches)
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".crt")
+		if name != certsCAName && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// devCertExpiryStatus describes how long a certificate remains valid
+func devCertExpiryStatus(cert *x509.Certificate, renewBefore time.Duration, now time.Time) string {
+	remaining := cert.NotAfter.Sub(now)
+	days := int(remaining.Hours() / 24)
+	switch {
+	case remaining <= 0:
+		return "expired"
+	case remaining <= renewBefore:
+		return fmt.Sprintf("renewal due (%d days left)", days)
+	default:
+		return fmt.Sprintf("valid (%d days left)", days)
+	}
+}
+
+// devCertReadStatus describes a certificate that could not be read
+func devCertReadStatus(err error) string {
+	if errors.Is(err, fs.ErrNotExist) {
+		return "missing"
+	}
+	return "unreadable"
+}
+
+// certOwner identifies who created the CA, as user@host
+func certOwner() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		name += "@" + host
+	}
+	return name
+}
@@ -0,0 +1,302 @@
+package mage
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useCertsConfig runs the test in a temp dir with the given certs config
+func useCertsConfig(t *testing.T, certs CertsConfig) *devCertSettings {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	config := defaultConfig()
+	config.Certs = certs
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+
+	settings, err := loadDevCertSettings()
+	require.NoError(t, err)
+	return settings
+}
+
+// readTestCert reads a named certificate from the certs directory
+func readTestCert(t *testing.T, settings *devCertSettings, name string) *x509.Certificate {
+	t.Helper()
+	certPath, _ := settings.devCertPaths(name)
+	cert, err := readCertificate(certPath)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestInstallCerts_IssuesFromDevCA(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{
+		Dir: "tls",
+		Certificates: []DevCertConfig{
+			{Name: "api", Hosts: []string{"api.local", "10.0.0.5", "localhost"}},
+			{Name: "web"},
+		},
+	})
+
+	require.NoError(t, Install{}.Certs())
+
+	ca := readTestCert(t, settings, certsCAName)
+	assert.True(t, ca.IsCA)
+	api := readTestCert(t, settings, "api")
+	assert.Equal(t, []string{"localhost", "api.local"}, api.DNSNames)
+	assert.True(t, api.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")))
+	assert.True(t, api.IPAddresses[2].Equal(net.ParseIP("10.0.0.5")))
+	assert.Equal(t, x509.ECDSA, api.PublicKeyAlgorithm)
+
+	// The leaf verifies against the CA for every host
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, host := range []string{"localhost", "api.local", "10.0.0.5", "::1"} {
+		_, err := api.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		require.NoError(t, err, host)
+	}
+
+	info, err := os.Stat(filepath.Join("tls", "api.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.FileExists(t, filepath.Join("tls", "web.crt"))
+}
+
+func TestInstallCerts_KeepsValidAndRenews(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	require.NoError(t, Install{}.Certs())
+	first := readTestCert(t, settings, defaultCertName)
+
+	// A valid certificate is kept
+	require.NoError(t, Install{}.Certs())
+	assert.Equal(t, first.SerialNumber, readTestCert(t, settings, defaultCertName).SerialNumber)
+
+	// A certificate inside the renewal window is reissued by the same CA
+	config := defaultConfig()
+	config.Certs.RenewBefore = "9000h"
+	TestSetConfig(config)
+	ca := readTestCert(t, settings, certsCAName)
+	require.NoError(t, Install{}.Certs())
+	assert.NotEqual(t, first.SerialNumber, readTestCert(t, settings, defaultCertName).SerialNumber)
+	assert.Equal(t, ca.SerialNumber, readTestCert(t, settings, certsCAName).SerialNumber, "the CA is still valid")
+}
+
+func TestDevCertRenewReason(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	require.NoError(t, Install{}.Certs())
+	ca := readTestCert(t, settings, certsCAName)
+	now := time.Now()
+
+	assert.Empty(t, devCertRenewReason(settings, DevCertConfig{Name: defaultCertName}, ca, now))
+	assert.Equal(t, "missing", devCertRenewReason(settings, DevCertConfig{Name: "other"}, ca, now))
+	assert.Equal(t, "host dev.example.com added", devCertRenewReason(settings, DevCertConfig{Name: defaultCertName, Hosts: []string{"dev.example.com"}}, ca, now))
+
+	settings.keyType = certKeyRSA
+	assert.Equal(t, "key type changed to rsa", devCertRenewReason(settings, DevCertConfig{Name: defaultCertName}, ca, now))
+	settings.keyType = certKeyECDSA
+
+	// A certificate signed by another CA is reissued
+	caPath, caKeyPath := settings.devCertPaths(certsCAName)
+	require.NoError(t, os.Remove(caPath))
+	require.NoError(t, os.Remove(caKeyPath))
+	newCA, _, issued, err := ensureDevCA(settings, false)
+	require.NoError(t, err)
+	assert.True(t, issued)
+	assert.Equal(t, "not signed by the current CA", devCertRenewReason(settings, DevCertConfig{Name: defaultCertName}, newCA, now))
+}
+
+func TestInstallCerts_KeepsForeignCertificates(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	require.NoError(t, os.MkdirAll(settings.dir, 0o750))
+	certPath, keyPath := settings.devCertPaths(defaultCertName)
+
+	// A certificate from another CA is not replaced without force=true
+	key, err := generateCertKey(certKeyECDSA, 0)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{"Example Inc"}, CommonName: "localhost"},
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	_, err = createCertificate(template, template, key, key, certPath, keyPath)
+	require.NoError(t, err)
+	foreign, err := os.ReadFile(certPath) //nolint:gosec // test file path
+	require.NoError(t, err)
+
+	require.ErrorIs(t, Install{}.Certs(), errDevCertNotManaged)
+	assert.NoFileExists(t, certPath+certBackupSuffix)
+
+	// force=true replaces it and keeps the original with a .bak suffix
+	require.NoError(t, Install{}.CertsWithArgs("force=true"))
+	backup, err := os.ReadFile(certPath + certBackupSuffix) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Equal(t, foreign, backup)
+	assert.FileExists(t, keyPath+certBackupSuffix)
+	require.NoError(t, readTestCert(t, settings, defaultCertName).CheckSignatureFrom(readTestCert(t, settings, certsCAName)))
+
+	// A second forced replacement keeps the first backup and dates the new one
+	_, err = createCertificate(template, template, key, key, certPath, keyPath)
+	require.NoError(t, err)
+	require.NoError(t, Install{}.CertsWithArgs("force=true"))
+	backup, err = os.ReadFile(certPath + certBackupSuffix) //nolint:gosec // test file path
+	require.NoError(t, err)
+	assert.Equal(t, foreign, backup, "the first backup is kept")
+	dated, err := filepath.Glob(certPath + certBackupSuffix + ".*")
+	require.NoError(t, err)
+	require.Len(t, dated, 1)
+	keyDated, err := filepath.Glob(keyPath + certBackupSuffix + ".*")
+	require.NoError(t, err)
+	assert.Len(t, keyDated, 1, "the key backup is dated the same way")
+
+	// Certificates from an earlier development CA are replaced freely
+	caPath, caKeyPath := settings.devCertPaths(certsCAName)
+	require.NoError(t, os.Remove(caPath))
+	require.NoError(t, os.Remove(caKeyPath))
+	require.NoError(t, Install{}.Certs())
+
+	// An unreadable certificate is kept too
+	require.NoError(t, os.WriteFile(certPath, []byte("not a certificate"), 0o600))
+	config := defaultConfig()
+	config.Certs.RenewBefore = "9000h"
+	TestSetConfig(config)
+	require.ErrorIs(t, Install{}.Certs(), errDevCertNotManaged)
+}
+
+func TestInstallCerts_KeepsBrokenCA(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	require.NoError(t, Install{}.Certs())
+	caPath, caKeyPath := settings.devCertPaths(certsCAName)
+	leaf, leafKey := settings.devCertPaths(defaultCertName)
+	otherKey, err := generateCertKey(certKeyECDSA, 0)
+	require.NoError(t, err)
+	otherKeyDER, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	require.NoError(t, err)
+	notCA := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{Organization: []string{devCAOrganization}, CommonName: "leaf"},
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{"unreadable", func(t *testing.T) {
+			require.NoError(t, os.WriteFile(caPath, []byte("not a certificate"), 0o600))
+		}},
+		{"key does not match", func(t *testing.T) {
+			require.NoError(t, writeSensitivePEM(caKeyPath, "PRIVATE KEY", otherKeyDER))
+		}},
+		{"key without certificate", func(t *testing.T) {
+			require.NoError(t, os.Remove(caPath))
+		}},
+		{"not a CA", func(t *testing.T) {
+			_, err := createCertificate(notCA, notCA, otherKey, otherKey, caPath, caKeyPath)
+			require.NoError(t, err)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+			readOriginal := func(path string) []byte {
+				data, readErr := os.ReadFile(path) //nolint:gosec // test file path
+				if readErr != nil {
+					return nil
+				}
+				return data
+			}
+			certBefore, keyBefore := readOriginal(caPath), readOriginal(caKeyPath)
+
+			// The CA is not replaced without force=true
+			require.ErrorIs(t, Install{}.Certs(), errDevCertNotManaged)
+			assert.Equal(t, certBefore, readOriginal(caPath))
+			assert.Equal(t, keyBefore, readOriginal(caKeyPath))
+			assert.NoFileExists(t, caKeyPath+certBackupSuffix)
+
+			// force=true replaces it and keeps the original with a .bak suffix
+			require.NoError(t, Install{}.CertsWithArgs("force=true"))
+			assert.Equal(t, keyBefore, readOriginal(caKeyPath+certBackupSuffix))
+			assert.Equal(t, certBefore, readOriginal(caPath+certBackupSuffix))
+			ca := readTestCert(t, settings, certsCAName)
+			assert.True(t, ca.IsCA)
+			require.NoError(t, readTestCert(t, settings, defaultCertName).CheckSignatureFrom(ca))
+
+			for _, path := range []string{caPath, caKeyPath, leaf, leafKey} {
+				require.NoError(t, os.RemoveAll(path+certBackupSuffix))
+			}
+		})
+	}
+}
+
+func TestInstallCerts_RSA(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{KeyType: "RSA", Validity: "48h"})
+
+	require.NoError(t, Install{}.Certs())
+
+	cert := readTestCert(t, settings, defaultCertName)
+	assert.Equal(t, x509.RSA, cert.PublicKeyAlgorithm)
+	assert.NotZero(t, cert.KeyUsage&x509.KeyUsageKeyEncipherment)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), cert.NotAfter, 2*time.Hour)
+}
+
+func TestLoadDevCertSettings(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	assert.Equal(t, defaultCertsDir, settings.dir)
+	assert.Equal(t, certKeyECDSA, settings.keyType)
+	assert.Equal(t, defaultCertValidity, settings.validity)
+	assert.Equal(t, []DevCertConfig{{Name: defaultCertName}}, settings.certificates)
+
+	tests := []struct {
+		name  string
+		certs CertsConfig
+		err   error
+	}{
+		{"key type", CertsConfig{KeyType: "ed25519"}, errUnsupportedCertKeyType},
+		{"validity", CertsConfig{Validity: "1y"}, errInvalidCertDuration},
+		{"renew before", CertsConfig{RenewBefore: "-1h"}, errInvalidCertDuration},
+		{"ca name", CertsConfig{Certificates: []DevCertConfig{{Name: certsCAName}}}, errInvalidCertName},
+		{"path name", CertsConfig{Certificates: []DevCertConfig{{Name: "../server"}}}, errInvalidCertName},
+		{"rsa bits too small", CertsConfig{KeyType: certKeyRSA, RSABits: 1024}, errInvalidCertRSABits},
+		{"rsa bits too large", CertsConfig{KeyType: certKeyRSA, RSABits: 16384}, errInvalidCertRSABits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultConfig()
+			config.Certs = tt.certs
+			TestSetConfig(config)
+			_, err := loadDevCertSettings()
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCertsStatus(t *testing.T) {
+	settings := useCertsConfig(t, CertsConfig{})
+	require.NoError(t, Certs{}.Status(), "no CA yet")
+
+	require.NoError(t, Install{}.Certs())
+	require.NoError(t, os.WriteFile(filepath.Join(settings.dir, "stale.crt"), []byte("not a certificate"), 0o600))
+	require.NoError(t, Certs{}.Status())
+
+	names, err := devCertNames(settings)
+	require.NoError(t, err)
+	assert.Equal(t, []string{defaultCertName, "stale"}, names)
+}
+
+func TestDevCertExpiryStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	cert := &x509.Certificate{NotAfter: now.Add(10 * 24 * time.Hour)}
+	assert.Equal(t, "valid (9 days left)", devCertExpiryStatus(cert, time.Hour, now.Add(time.Minute)))
+	assert.Equal(t, "renewal due (9 days left)", devCertExpiryStatus(cert, 30*24*time.Hour, now.Add(time.Minute)))
+	assert.Equal(t, "expired", devCertExpiryStatus(cert, time.Hour, now.Add(11*24*time.Hour)))
+}
@@ -0,0 +1,533 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Changelog generation constants
+const (
+	// changelogFile is the changelog written by version:changelog write=true
+	changelogFile = "CHANGELOG.md"
+
+	// changelogUnreleased heads changes that are not tagged yet
+	changelogUnreleased = "Unreleased"
+
+	// Changelog sections in the order they are written
+	changelogSectionBreaking = "Breaking Changes"
+	changelogSectionFeatures = "Features"
+	changelogSectionFixes    = "Fixes"
+	changelogSectionOther    = "Other"
+
+	// changelogLogFormat separates commit fields with \x1f and commits with \x1e
+	changelogLogFormat = "--format=%H%x1f%h%x1f%cs%x1f%an%x1f%B%x1e"
+
+	// changelogHeader starts a new CHANGELOG.md
+	changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+)
+
+//nolint:gochecknoglobals // Immutable parsing patterns and section order
+var (
+	// conventionalHeaderRegex matches "type(scope)!: description"
+	conventionalHeaderRegex = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()\r\n]*)\))?(!)?:\s+(.+)$`)
+
+	// breakingFooterRegex matches a BREAKING CHANGE footer
+	breakingFooterRegex = regexp.MustCompile(`^BREAKING[ -]CHANGE:\s*(.*)$`)
+
+	// commitFooterRegex matches any git trailer style footer
+	commitFooterRegex = regexp.MustCompile(`^(?:[\w-]+|BREAKING CHANGE)(?::\s|\s#)`)
+
+	// issueRefRegex matches issue references such as #123
+	issueRefRegex = regexp.MustCompile(`(?:^|[^\w/&])(#\d+)\b`)
+
+	// changelogSections lists the sections in the order they are written
+	changelogSections = []string{changelogSectionBreaking, changelogSectionFeatures, changelogSectionFixes, changelogSectionOther}
+)
+
+// changelogTarget is a module that gets its own changelog
+type changelogTarget struct {
+	name     string   // Module name, empty for the root module
+	dir      string   // Module directory relative to the repository root
+	excludes []string // Sub-module directories left out of the root changelog
+}
+
+// tagPattern returns the git tag pattern of the module's releases
+func (target changelogTarget) tagPattern() string {
+	if target.name == "" {
+		return "v*"
+	}
+	return target.name + "/v*"
+}
+
+// tagRef turns a bare version such as v1.2.0 into the module's tag
+func (target changelogTarget) tagRef(ref string) string {
+	if target.name == "" || ref == "" || strings.Contains(ref, "/") || !strings.HasPrefix(ref, "v") {
+		return ref
+	}
+	return target.name + "/" + ref
+}
+
+// pathspec returns the paths the module's commits are limited to
+func (target changelogTarget) pathspec() []string {
+	if target.name != "" {
+		return []string{target.dir}
+	}
+	if len(target.excludes) == 0 {
+		return nil
+	}
+	paths := []string{"."}
+	for _, exclude := range target.excludes {
+		paths = append(paths, ":(exclude)"+exclude)
+	}
+	return paths
+}
+
+// Changelog generates a Keep a Changelog section from Conventional Commits,
+// grouped into breaking changes, features, fixes and other changes, and prints
+// it or prepends it to CHANGELOG.md.
+// Supports:
+//   - from: Tag to start after (default: the release before to)
+//   - to: Tag or ref to end at (default: HEAD)
+//   - version: Section heading (default: the tag at to, or Unreleased)
+//   - write: Prepend the section to the changelog file (default: false)
+//   - file: Changelog file name (default: CHANGELOG.md)
+//   - module: Sub-module name, or "all" for the root and every sub-module
+func (Version) Changelog(args ...string) error {
+	utils.Header("Generating Changelog")
+	return runChangelog(utils.ParseParams(args))
+}
+
+// runChangelog generates the changelog of every target selected by params
+func runChangelog(params map[string]string) error {
+	targets, err := changelogTargets(utils.GetParam(params, "module", ""))
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := generateChangelog(target, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changelogTargets returns the modules selected by the module parameter
+func changelogTargets(module string) ([]changelogTarget, error) {
+	if module == "" {
+		return []changelogTarget{{dir: "."}}, nil
+	}
+
+	modules, err := discoverModules()
+	if err != nil {
+		return nil, err
+	}
+	if module == "all" || module == "*" {
+		root := changelogTarget{dir: "."}
+		targets := make([]changelogTarget, 0, len(modules)+1)
+		for _, m := range modules {
+			root.excludes = append(root.excludes, m.Path)
+			targets = append(targets, changelogTarget{name: m.Name, dir: m.Path})
+		}
+		return append([]changelogTarget{root}, targets...), nil
+	}
+	for _, m := range modules {
+		if m.Name == module || m.Path == module {
+			return []changelogTarget{{name: m.Name, dir: m.Path}}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errSubmoduleNotFound, module)
+}
+
+// generateChangelog prints or writes the changelog section of one module
+func generateChangelog(target changelogTarget, params map[string]string) error {
+	from, to, version := resolveChangelogRange(target, params)
+	label := "root module"
+	if target.name != "" {
+		label = "module " + target.name
+	}
+	if from != "" {
+		utils.Info("Collecting %s changes from %s to %s", label, from, to)
+	} else {
+		utils.Info("Collecting all %s changes up to %s", label, to)
+	}
+
+	entries, date, err := collectChangeEntries(from, to, target.pathspec())
+	if err != nil {
+		return err
+	}
+	changelog := buildChangelog(version, date, entries)
+	section := renderChangelogSection(changelog)
+
+	if !utils.IsParamTrue(params, "write") {
+		utils.Print("\n%s", section)
+		utils.Info("%d commits", len(entries))
+		return nil
+	}
+
+	path := filepath.Join(target.dir, filepath.Base(utils.GetParam(params, "file", changelogFile)))
+	if err := updateChangelogFile(path, changelog.Version, section); err != nil {
+		return err
+	}
+	utils.Success("Updated %s with %d commits", path, len(entries))
+	return nil
+}
+
+// resolveChangelogRange returns the refs to compare and the section heading.
+// Without from, the range starts at the release before to: the tag preceding
+// to when to is tagged, otherwise the newest tag.
+func resolveChangelogRange(target changelogTarget, params map[string]string) (from, to, version string) {
+	to = target.tagRef(utils.GetParam(params, "to", "HEAD"))
+	from = target.tagRef(utils.GetParam(params, "from", ""))
+	version = utils.GetParam(params, "version", "")
+
+	pattern := target.tagPattern()
+	tags := listVersionTags(pattern)
+	if len(tags) == 0 && target.name == "" {
+		// Releases not tagged v* are found through the tags reachable from to
+		pattern = "*"
+		tags = describedTags(to)
+	}
+	toTag := ""
+	if slices.Contains(tags, to) {
+		toTag = to
+	} else if to == "HEAD" {
+		if onHead := listVersionTags(pattern, "--points-at", "HEAD"); len(onHead) > 0 {
+			toTag = onHead[0]
+		}
+	}
+
+	if from == "" {
+		next := 0
+		if toTag != "" {
+			next = slices.Index(tags, toTag) + 1
+		}
+		if next < len(tags) {
+			from = tags[next]
+		}
+	}
+
+	if version == "" {
+		version = changelogUnreleased
+		if toTag != "" {
+			version = parseModuleTagVersion(toTag, target.name)
+		}
+	}
+	return from, to, strings.TrimPrefix(version, "v")
+}
+
+// listVersionTags returns the tags matching pattern, highest version first
+func listVersionTags(pattern string, extra ...string) []string {
+	args := append([]string{"tag", "-l", pattern, "--sort=-version:refname"}, extra...)
+	output, err := GetRunner().RunCmdOutput("git", args...)
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(output, "\n") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// describedTags returns the nearest tag reachable from ref and the one before it
+func describedTags(ref string) []string {
+	var tags []string
+	for len(tags) < 2 {
+		output, err := GetRunner().RunCmdOutput("git", "describe", "--tags", "--abbrev=0", ref)
+		tag := strings.TrimSpace(output)
+		if err != nil || tag == "" {
+			break
+		}
+		tags = append(tags, tag)
+		ref = tag + "^"
+	}
+	return tags
+}
+
+// collectChangeEntries parses the commits between from and to, newest first,
+// and returns them with the date of the newest commit
+func collectChangeEntries(from, to string, pathspec []string) ([]ChangeEntry, time.Time, error) {
+	revision := to
+	if from != "" {
+		revision = from + ".." + to
+	}
+	args := []string{"log", "--no-merges", changelogLogFormat, revision}
+	if len(pathspec) > 0 {
+		args = append(append(args, "--"), pathspec...)
+	}
+
+	output, err := GetRunner().RunCmdOutput("git", args...)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	var entries []ChangeEntry
+	var date time.Time
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\r\n"), "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		entry := parseConventionalCommit(fields[4])
+		if entry.Description == "" {
+			continue
+		}
+		entry.CommitHash = fields[1]
+		entry.Author = fields[3]
+		if date.IsZero() {
+			date, _ = time.Parse(time.DateOnly, fields[2]) //nolint:errcheck // a missing date leaves the heading undated
+		}
+		entries = append(entries, entry)
+	}
+	return entries, date, nil
+}
+
+// parseConventionalCommit parses a commit message into a change entry. Messages
+// that do not follow Conventional Commits keep their subject as description.
+func parseConventionalCommit(message string) ChangeEntry {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n")
+	subject := strings.TrimSpace(lines[0])
+
+	entry := ChangeEntry{Description: subject}
+	if match := conventionalHeaderRegex.FindStringSubmatch(subject); match != nil {
+		entry.Type = strings.ToLower(match[1])
+		entry.Scope = strings.TrimSpace(match[2])
+		entry.Breaking = match[3] == "!"
+		entry.Description = strings.TrimSpace(match[4])
+	}
+
+	// Collect a BREAKING CHANGE footer and the lines continuing it
+	var note []string
+	inNote := false
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if match := breakingFooterRegex.FindStringSubmatch(trimmed); match != nil {
+			inNote = true
+			note = append(note, match[1])
+			continue
+		}
+		if inNote && (trimmed == "" || commitFooterRegex.MatchString(trimmed)) {
+			inNote = false
+		}
+		if inNote {
+			note = append(note, trimmed)
+		}
+	}
+	if len(note) > 0 {
+		entry.Breaking = true
+		entry.BreakingNote = strings.TrimSpace(strings.Join(note, " "))
+	}
+
+	for _, match := range issueRefRegex.FindAllStringSubmatch(message, -1) {
+		if !slices.Contains(entry.Issues, match[1]) {
+			entry.Issues = append(entry.Issues, match[1])
+		}
+	}
+	return entry
+}
+
+// changelogSection returns the section a change type is listed under
+func changelogSection(changeType string) string {
+	switch changeType {
+	case "feat":
+		return changelogSectionFeatures
+	case "fix":
+		return changelogSectionFixes
+	default:
+		return changelogSectionOther
+	}
+}
+
+// buildChangelog groups entries into changelog sections. Breaking changes are
+// listed in their own section and under their type.
+func buildChangelog(version string, date time.Time, entries []ChangeEntry) *Changelog {
+	changelog := &Changelog{
+		Version:  version,
+		Date:     date,
+		Changes:  entries,
+		Sections: make(map[string][]ChangeEntry),
+	}
+	for _, entry := range entries {
+		if entry.Breaking {
+			changelog.Sections[changelogSectionBreaking] = append(changelog.Sections[changelogSectionBreaking], entry)
+		}
+		section := changelogSection(entry.Type)
+		changelog.Sections[section] = append(changelog.Sections[section], entry)
+	}
+	return changelog
+}
+
+// renderChangelogSection renders a changelog as a Keep a Changelog section
+func renderChangelogSection(changelog *Changelog) string {
+	var sb strings.Builder
+	sb.WriteString(changelogHeading(changelog))
+	sb.WriteString("\n")
+	if len(changelog.Changes) == 0 {
+		sb.WriteString("\nNo changes.\n")
+		return sb.String()
+	}
+	for _, section := range changelogSections {
+		entries := changelog.Sections[section]
+		if len(entries) == 0 {
+			continue
+		}
+		sb.WriteString("\n### " + section + "\n\n")
+		for _, entry := range entries {
+			sb.WriteString(renderChangeEntry(entry, section) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// changelogHeading returns the "## [version] - date" heading of a changelog
+func changelogHeading(changelog *Changelog) string {
+	heading := "## [" + changelog.Version + "]"
+	if changelog.Version != changelogUnreleased && !changelog.Date.IsZero() {
+		heading += " - " + changelog.Date.Format(time.DateOnly)
+	}
+	return heading
+}
+
+// renderChangeEntry renders one changelog line
+func renderChangeEntry(entry ChangeEntry, section string) string {
+	description := entry.Description
+	if section == changelogSectionBreaking && entry.BreakingNote != "" {
+		description = entry.BreakingNote
+	}
+
+	prefix := entry.Scope
+	if section == changelogSectionOther && entry.Type != "" {
+		prefix = entry.Type
+		if entry.Scope != "" {
+			prefix += "(" + entry.Scope + ")"
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("- ")
+	if prefix != "" {
+		sb.WriteString("**" + prefix + ":** ")
+	}
+	sb.WriteString(description)
+
+	var refs []string
+	for _, issue := range entry.Issues {
+		if !strings.Contains(description, issue) {
+			refs = append(refs, issue)
+		}
+	}
+	if len(refs) > 0 {
+		sb.WriteString(" (" + strings.Join(refs, ", ") + ")")
+	}
+	if entry.CommitHash != "" {
+		sb.WriteString(" (" + entry.CommitHash + ")")
+	}
+	return sb.String()
+}
+
+// updateChangelogFile writes section into the changelog at path. A section with
+// the same version is replaced, as is the Unreleased section once a version is
+// released; otherwise the section goes above the first older release.
+func updateChangelogFile(path, version, section string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the project changelog
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		content = changelogHeader
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	start, end := changelogSectionBounds(lines, version)
+
+	before := strings.TrimRight(strings.Join(lines[:start], ""), "\n")
+	content = before + "\n\n" + section + "\n" + strings.Join(lines[end:], "")
+
+	if err := os.WriteFile(path, []byte(strings.TrimRight(content, "\n")+"\n"), fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// changelogHeadingMatches reports whether a "## [version]" heading is for version
+func changelogHeadingMatches(line, version string) bool {
+	return changelogHeadingVersion(line) == version
+}
+
+// changelogHeadingVersion returns the version of a "## [version] - date" heading
+func changelogHeadingVersion(line string) string {
+	heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+	if rest, ok := strings.CutPrefix(heading, "["); ok {
+		if version, _, found := strings.Cut(rest, "]"); found {
+			return version
+		}
+	}
+	version, _, _ := strings.Cut(heading, " ")
+	return strings.TrimPrefix(version, "v")
+}
+
+// changelogSectionBounds returns the lines the new section replaces: the section
+// with the same version, or a leading Unreleased section when a version is
+// released. Otherwise start equals end at the first older release, below any
+// Unreleased section, or at the end of the file. Lines inside fenced code blocks
+// are never headings.
+func changelogSectionBounds(lines []string, version string) (start, end int) {
+	var headings []int
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case strings.HasPrefix(line, "## "):
+			headings = append(headings, i)
+		}
+	}
+
+	for n, i := range headings {
+		matches := changelogHeadingMatches(lines[i], version)
+		if !matches && n == 0 && version != changelogUnreleased {
+			matches = changelogHeadingMatches(lines[i], changelogUnreleased)
+		}
+		if !matches {
+			continue
+		}
+		if n+1 < len(headings) {
+			return i, headings[n+1]
+		}
+		return i, len(lines)
+	}
+
+	for _, i := range headings {
+		if version == changelogUnreleased {
+			return i, i
+		}
+		headingVersion := changelogHeadingVersion(lines[i])
+		if headingVersion != changelogUnreleased && isVersionHigher(version, headingVersion) {
+			return i, i
+		}
+	}
+	return len(lines), len(lines)
+}
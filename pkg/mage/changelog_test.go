@@ -0,0 +1,219 @@
+package mage
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useChangelogRunner installs a VersionMockRunner for changelog tests
+func useChangelogRunner(t *testing.T) *VersionMockRunner {
+	t.Helper()
+	originalRunner := GetRunner()
+	t.Cleanup(func() { require.NoError(t, SetRunner(originalRunner)) })
+	mock := NewVersionMockRunner()
+	require.NoError(t, SetRunner(mock))
+	return mock
+}
+
+// changelogLog formats commits as changelogLogFormat prints them
+func changelogLog(commits ...string) string {
+	var sb strings.Builder
+	for i, message := range commits {
+		hash := strings.Repeat(string(rune('a'+i)), 7)
+		sb.WriteString(hash + "\x1f" + hash + "\x1f2024-03-0" + string(rune('9'-i)) + "\x1fdev\x1f" + message + "\n\x1e\n")
+	}
+	return sb.String()
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		want    ChangeEntry
+	}{
+		{
+			name:    "feature with scope",
+			message: "feat(api): add pagination (#12)",
+			want:    ChangeEntry{Type: "feat", Scope: "api", Description: "add pagination (#12)", Issues: []string{"#12"}},
+		},
+		{
+			name:    "breaking marker",
+			message: "Fix!: drop Go 1.22",
+			want:    ChangeEntry{Type: "fix", Description: "drop Go 1.22", Breaking: true},
+		},
+		{
+			name: "breaking footer and issue footers",
+			message: "refactor(config): rename keys\n\nThe loader now reads mage.yaml.\n\n" +
+				"BREAKING CHANGE: the .mage.yml file\nis no longer read\nRefs: #7\nCloses #9, owner/repo#3",
+			want: ChangeEntry{
+				Type: "refactor", Scope: "config", Description: "rename keys", Breaking: true,
+				BreakingNote: "the .mage.yml file is no longer read", Issues: []string{"#7", "#9"},
+			},
+		},
+		{
+			name:    "not conventional",
+			message: "Update README.md",
+			want:    ChangeEntry{Description: "Update README.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseConventionalCommit(tt.message))
+		})
+	}
+}
+
+func TestRenderChangelogSection(t *testing.T) {
+	t.Parallel()
+
+	entries := []ChangeEntry{
+		parseConventionalCommit("feat(cli)!: replace flags\n\nBREAKING CHANGE: use params instead of flags"),
+		parseConventionalCommit("fix: handle empty tags (#4)"),
+		parseConventionalCommit("docs: describe changelogs\n\nFixes #5"),
+		parseConventionalCommit("Merge old work"),
+	}
+	for i := range entries {
+		entries[i].CommitHash = string(rune('a' + i))
+	}
+	changelog := buildChangelog("1.2.0", time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), entries)
+
+	assert.Equal(t, `## [1.2.0] - 2024-03-09
+
+### Breaking Changes
+
+- **cli:** use params instead of flags (a)
+
+### Features
+
+- **cli:** replace flags (a)
+
+### Fixes
+
+- handle empty tags (#4) (b)
+
+### Other
+
+- **docs:** describe changelogs (#5) (c)
+- Merge old work (d)
+`, renderChangelogSection(changelog))
+
+	assert.Equal(t, "## [Unreleased]\n\nNo changes.\n", renderChangelogSection(buildChangelog(changelogUnreleased, time.Now(), nil)))
+}
+
+func TestVersionChangelog_WritesChangelog(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mock := useChangelogRunner(t)
+	mock.SetOutput("git tag -l v* --sort=-version:refname", "v1.1.0\nv1.0.0\n", nil)
+	mock.SetOutput("git log --no-merges "+changelogLogFormat+" v1.1.0..HEAD", changelogLog("feat: first change"), nil)
+
+	require.NoError(t, Version{}.Changelog("write=true"))
+	data, err := os.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, changelogHeader+"\n## [Unreleased]\n\n### Features\n\n- first change (aaaaaaa)\n", string(data))
+
+	// Releasing v1.2.0 replaces the Unreleased section
+	mock.SetOutput("git tag -l v* --sort=-version:refname", "v1.2.0\nv1.1.0\nv1.0.0\n", nil)
+	mock.SetOutput("git tag -l v* --sort=-version:refname --points-at HEAD", "v1.2.0\n", nil)
+	mock.SetOutput("git log --no-merges "+changelogLogFormat+" v1.1.0..HEAD", changelogLog("feat: first change", "fix: second change"), nil)
+	require.NoError(t, Version{}.Changelog("write=true"))
+
+	// An older release is written below the newer ones
+	mock.SetOutput("git log --no-merges "+changelogLogFormat+" v1.0.0..v1.1.0", changelogLog("fix: old fix"), nil)
+	require.NoError(t, Version{}.Changelog("write=true", "to=v1.1.0"))
+
+	data, err = os.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, changelogHeader+`
+## [1.2.0] - 2024-03-09
+
+### Features
+
+- first change (aaaaaaa)
+
+### Fixes
+
+- second change (bbbbbbb)
+
+## [1.1.0] - 2024-03-09
+
+### Fixes
+
+- old fix (aaaaaaa)
+`, string(data))
+}
+
+func TestVersionChangelog_FallsBackToDescribe(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mock := useChangelogRunner(t)
+	mock.SetOutput("git describe --tags --abbrev=0 HEAD", "release-2\n", nil)
+	mock.SetOutput("git describe --tags --abbrev=0 release-2^", "release-1\n", nil)
+	mock.SetOutput("git describe --tags --abbrev=0 release-1^", "", errNoTags)
+
+	// Untagged HEAD: the changes since the nearest tag are unreleased
+	require.NoError(t, Version{}.Changelog())
+	assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" release-2..HEAD")
+
+	// Tagged HEAD: the changes since the tag before it are released
+	mock.SetOutput("git tag -l * --sort=-version:refname --points-at HEAD", "release-2\n", nil)
+	mock.commands = nil
+	require.NoError(t, Version{}.Changelog())
+	assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" release-1..HEAD")
+}
+
+func TestChangelogSectionBounds_SkipsCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.SplitAfter("# Changelog\n\n## [1.1.0] - 2024-01-01\n\n```md\n## [1.0.0] - example\n```\n\n"+
+		"~~~\n## not a heading\n~~~\n\n## [1.0.0] - 2023-01-01\n\n- first\n", "\n")
+	start, end := changelogSectionBounds(lines, "1.1.0")
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 12, end, "the fenced headings belong to the 1.1.0 section")
+
+	start, end = changelogSectionBounds(lines, "1.0.0")
+	assert.Equal(t, 12, start)
+	assert.Equal(t, len(lines), end)
+}
+
+func TestUpdateChangelogFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	existing := "# Changelog\n\nNotes.\n\n## [1.1.0] - 2024-01-01\n\n- old\n\n## [1.0.0] - 2023-01-01\n\n- first\n"
+	require.NoError(t, os.WriteFile(changelogFile, []byte(existing), 0o600))
+
+	require.NoError(t, updateChangelogFile(changelogFile, "1.1.0", "## [1.1.0] - 2024-01-02\n\n- replaced\n"))
+	require.NoError(t, updateChangelogFile(changelogFile, "1.2.0", "## [1.2.0] - 2024-02-01\n\n- new\n"))
+	require.NoError(t, updateChangelogFile(changelogFile, "0.9.0", "## [0.9.0] - 2022-01-01\n\n- beta\n"))
+	require.NoError(t, updateChangelogFile(changelogFile, changelogUnreleased, "## [Unreleased]\n\n- next\n"))
+
+	data, err := os.ReadFile(changelogFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# Changelog\n\nNotes.\n\n## [Unreleased]\n\n- next\n\n## [1.2.0] - 2024-02-01\n\n- new\n\n"+
+		"## [1.1.0] - 2024-01-02\n\n- replaced\n\n## [1.0.0] - 2023-01-01\n\n- first\n\n## [0.9.0] - 2022-01-01\n\n- beta\n", string(data))
+}
+
+func TestVersionChangelog_Modules(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mock := useChangelogRunner(t)
+	mock.SetOutput("find . -name go.mod -type f", "./go.mod\n./models/go.mod\n", nil)
+	mock.SetOutput("git tag -l models/v* --sort=-version:refname", "models/v0.2.0\nmodels/v0.1.0\n", nil)
+	require.NoError(t, os.Mkdir("models", 0o750))
+
+	require.NoError(t, Version{}.Changelog("module=all", "write=true"))
+	assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" HEAD -- . :(exclude)models")
+	assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" models/v0.2.0..HEAD -- models")
+	assert.FileExists(t, changelogFile)
+	assert.FileExists(t, "models/"+changelogFile)
+
+	mock.commands = nil
+	require.NoError(t, Version{}.Changelog("module=models", "from=v0.1.0", "to=v0.2.0"))
+	assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" models/v0.1.0..models/v0.2.0 -- models")
+
+	require.ErrorIs(t, Version{}.Changelog("module=engine"), errSubmoduleNotFound)
+}
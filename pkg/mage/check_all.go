@@ -0,0 +1,412 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for check:all
+var (
+	errChecksFailed     = errors.New("checks failed")
+	errUnknownCheck     = errors.New("unknown check")
+	errCheckSkipped     = errors.New("check skipped")
+	errInvalidCheckJobs = errors.New("invalid jobs value")
+	errUnformattedFiles = errors.New("files are not formatted")
+	errMisspelledWords  = errors.New("misspelled words found")
+	errGeneratedDrift   = errors.New("generated code is out of date")
+	errGoModuleNotFound = errors.New("no go.mod found")
+)
+
+// Check result statuses
+const (
+	checkStatusPassed  = "passed"
+	checkStatusFailed  = "failed"
+	checkStatusSkipped = "skipped"
+)
+
+// checkDefinition is one check run by check:all
+type checkDefinition struct {
+	name string
+	desc string
+	run  func() (string, error)
+}
+
+// checkResult is the outcome of a single check
+type checkResult struct {
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	Duration   time.Duration `json:"-"`
+	DurationMs int64         `json:"duration_ms"`
+	Output     string        `json:"output,omitempty"`
+}
+
+// checkAllReport is the JSON summary written by check:all
+type checkAllReport struct {
+	Status     string        `json:"status"`
+	Passed     int           `json:"passed"`
+	Failed     int           `json:"failed"`
+	Skipped    int           `json:"skipped"`
+	DurationMs int64         `json:"duration_ms"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Metadata   CIMetadata    `json:"metadata"`
+	Checks     []checkResult `json:"checks"`
+}
+
+// allChecks returns every check in report order
+func allChecks() []checkDefinition {
+	return []checkDefinition{
+		{name: "format", desc: "gofmt -l", run: checkGofmt},
+		{name: "imports", desc: "goimports -l", run: checkGoimports},
+		{name: "tidy", desc: "go mod tidy drift", run: checkTidyDiff},
+		{name: "generate", desc: "go generate drift", run: checkGenerateDiff},
+		{name: "spelling", desc: "misspell", run: checkSpelling},
+		{name: "license", desc: "license headers", run: checkLicense},
+		{name: "vuln", desc: "govulncheck", run: checkVulnerabilities},
+	}
+}
+
+// All runs all available checks
+func (c Check) All() error {
+	return c.AllWithArgs()
+}
+
+// AllWithArgs runs every check concurrently, keeps going when one fails and prints
+// a consolidated pass/fail table. A JSON summary is written for CI dashboards.
+// Supports:
+//   - only: Comma-separated checks to run (format, imports, tidy, generate, spelling, license, vuln)
+//   - skip: Comma-separated checks to leave out
+//   - jobs: Number of checks to run at once (default: number of CPUs)
+//   - output: JSON summary path (default: .mage-x/check-all.json)
+func (c Check) AllWithArgs(argsList ...string) error {
+	utils.Header("Running All Checks")
+	start := time.Now()
+	params := utils.ParseParams(argsList)
+
+	checks, err := selectChecks(allChecks(), utils.GetParam(params, "only", ""), utils.GetParam(params, "skip", ""))
+	if err != nil {
+		return err
+	}
+
+	jobs := runtime.NumCPU()
+	if v := utils.GetParam(params, "jobs", ""); v != "" {
+		jobs, err = strconv.Atoi(v)
+		if err != nil || jobs < 1 {
+			return fmt.Errorf("%w: %s", errInvalidCheckJobs, v)
+		}
+	}
+
+	results := runChecks(checks, jobs)
+	report := newCheckAllReport(results, time.Since(start), ciMetadata(NewCIDetector()))
+	printCheckResults(checks, results)
+
+	output := utils.GetParam(params, "output", DefaultCheckAllReport)
+	if writeErr := report.writeJSON(output); writeErr != nil {
+		utils.Warn("Failed to write check summary: %v", writeErr)
+	}
+
+	if report.Failed > 0 {
+		var failed []string
+		for _, r := range results {
+			if r.Status == checkStatusFailed {
+				failed = append(failed, r.Name)
+			}
+		}
+		return fmt.Errorf("%w: %s", errChecksFailed, strings.Join(failed, ", "))
+	}
+
+	utils.Success("All checks passed (%d passed, %d skipped) in %s", report.Passed, report.Skipped, utils.FormatDuration(time.Since(start)))
+	return nil
+}
+
+// selectChecks applies the only= and skip= lists, keeping report order
+func selectChecks(checks []checkDefinition, only, skip string) ([]checkDefinition, error) {
+	known := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		known[check.name] = true
+	}
+	parse := func(list string) (map[string]bool, error) {
+		names := make(map[string]bool)
+		for _, name := range strings.Split(list, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !known[name] {
+				return nil, fmt.Errorf("%w: %s", errUnknownCheck, name)
+			}
+			names[name] = true
+		}
+		return names, nil
+	}
+
+	onlySet, err := parse(only)
+	if err != nil {
+		return nil, err
+	}
+	skipSet, err := parse(skip)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]checkDefinition, 0, len(checks))
+	for _, check := range checks {
+		if (len(onlySet) > 0 && !onlySet[check.name]) || skipSet[check.name] {
+			continue
+		}
+		selected = append(selected, check)
+	}
+	return selected, nil
+}
+
+// runChecks runs the checks with at most jobs at a time and returns results in input order
+func runChecks(checks []checkDefinition, jobs int) []checkResult {
+	results := make([]checkResult, len(checks))
+	var g errgroup.Group
+	g.SetLimit(jobs)
+
+	for i, check := range checks {
+		g.Go(func() error {
+			start := time.Now()
+			output, err := check.run()
+			result := checkResult{Name: check.name, Status: checkStatusPassed, Output: strings.TrimSpace(output)}
+			switch {
+			case errors.Is(err, errCheckSkipped):
+				result.Status = checkStatusSkipped
+				result.Output = err.Error()
+			case err != nil:
+				result.Status = checkStatusFailed
+				if result.Output == "" {
+					result.Output = err.Error()
+				} else {
+					result.Output = err.Error() + "\n" + result.Output
+				}
+			}
+			result.Duration = time.Since(start)
+			result.DurationMs = result.Duration.Milliseconds()
+			results[i] = result // each goroutine owns its slot
+			utils.Info("%s %s (%s)", checkStatusIcon(result.Status), check.name, utils.FormatDuration(result.Duration))
+			return nil // Keep going: every check reports its own status
+		})
+	}
+	//nolint:errcheck,gosec // g.Wait() always returns nil since all goroutines return nil (results collected separately)
+	g.Wait()
+	return results
+}
+
+// newCheckAllReport totals the results
+func newCheckAllReport(results []checkResult, duration time.Duration, metadata CIMetadata) *checkAllReport {
+	report := &checkAllReport{
+		Status:     checkStatusPassed,
+		DurationMs: duration.Milliseconds(),
+		Timestamp:  time.Now(),
+		Metadata:   metadata,
+		Checks:     results,
+	}
+	for _, r := range results {
+		switch r.Status {
+		case checkStatusPassed:
+			report.Passed++
+		case checkStatusFailed:
+			report.Failed++
+		case checkStatusSkipped:
+			report.Skipped++
+		}
+	}
+	if report.Failed > 0 {
+		report.Status = checkStatusFailed
+	}
+	return report
+}
+
+// writeJSON writes the summary, creating the directory if needed
+func (r *checkAllReport) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode check summary: %w", err)
+	}
+	fileOps := fileops.New()
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := fileOps.File.MkdirAll(dir, fileops.PermDir); err != nil {
+			return fmt.Errorf("failed to create summary directory: %w", err)
+		}
+	}
+	return fileOps.File.WriteFile(path, data, fileops.PermFile)
+}
+
+// checkStatusIcon returns the marker shown next to a status
+func checkStatusIcon(status string) string {
+	switch status {
+	case checkStatusPassed:
+		return "✅"
+	case checkStatusFailed:
+		return "❌"
+	default:
+		return "⏭️"
+	}
+}
+
+// printCheckResults prints the consolidated table followed by the output of failed checks
+func printCheckResults(checks []checkDefinition, results []checkResult) {
+	utils.Print("\n%-10s %-20s %-8s %10s\n", "CHECK", "RUNS", "STATUS", "DURATION")
+	for i, r := range results {
+		utils.Print("%-10s %-20s %-8s %10s\n", r.Name, checks[i].desc, r.Status, utils.FormatDuration(r.Duration))
+	}
+
+	for _, r := range results {
+		if r.Status == checkStatusSkipped {
+			utils.Print("\n%s %s: %s\n", checkStatusIcon(r.Status), r.Name, r.Output)
+		}
+	}
+	for _, r := range results {
+		if r.Status != checkStatusFailed {
+			continue
+		}
+		utils.Print("\n%s %s:\n", checkStatusIcon(r.Status), r.Name)
+		for _, line := range strings.Split(r.Output, "\n") {
+			utils.Print("    %s\n", line)
+		}
+	}
+	utils.Println("")
+}
+
+// requireTool reports a missing tool as a skipped check
+func requireTool(name string) error {
+	if !commandExists(name) {
+		return fmt.Errorf("%w: %s is not installed", errCheckSkipped, name)
+	}
+	return nil
+}
+
+// listedFiles returns the non-empty lines of a tool's file listing, sorted
+func listedFiles(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// checkGofmt fails when gofmt would reformat any file
+func checkGofmt() (string, error) {
+	output, err := GetRunner().RunCmdOutput("gofmt", "-l", ".")
+	if err != nil {
+		return "", fmt.Errorf("gofmt failed: %w", err)
+	}
+	if files := listedFiles(output); len(files) > 0 {
+		return strings.Join(files, "\n"), fmt.Errorf("%w: %d file(s)", errUnformattedFiles, len(files))
+	}
+	return "", nil
+}
+
+// checkGoimports fails when goimports would rewrite any file
+func checkGoimports() (string, error) {
+	if err := requireTool("goimports"); err != nil {
+		return "", err
+	}
+	output, err := GetRunner().RunCmdOutput("goimports", "-l", ".")
+	if err != nil {
+		return "", fmt.Errorf("goimports failed: %w", err)
+	}
+	if files := listedFiles(output); len(files) > 0 {
+		return strings.Join(files, "\n"), fmt.Errorf("%w: %d file(s) have unsorted or missing imports", errUnformattedFiles, len(files))
+	}
+	return "", nil
+}
+
+// checkTidyDiff fails when go mod tidy would change go.mod or go.sum
+func checkTidyDiff() (string, error) {
+	diff, err := tidyDrift()
+	if errors.Is(err, errGoModuleNotFound) {
+		return "", fmt.Errorf("%w: %w", errCheckSkipped, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if diff != "" {
+		return diff, errModuleNotTidy
+	}
+	return "", nil
+}
+
+// checkGenerateDiff fails when go generate would change any file in the module
+func checkGenerateDiff() (string, error) {
+	diff, err := generateDrift(goGenerateStep())
+	if errors.Is(err, errGoModuleNotFound) {
+		return "", fmt.Errorf("%w: %w", errCheckSkipped, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if diff != "" {
+		return diff, errGeneratedDrift
+	}
+	return "", nil
+}
+
+// checkSpelling fails when misspell finds a misspelled word
+func checkSpelling() (string, error) {
+	if err := requireTool("misspell"); err != nil {
+		return "", err
+	}
+	output, err := GetRunner().RunCmdOutput("misspell", "-error", ".")
+	if err != nil {
+		return output, fmt.Errorf("%w: %w", errMisspelledWords, err)
+	}
+	return "", nil
+}
+
+// checkLicense fails when a file is missing the configured license header
+func checkLicense() (string, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	licenseCfg := config.Lint.License
+	if strings.TrimSpace(licenseCfg.Header) == "" {
+		return "", fmt.Errorf("%w: no license header configured (lint.license.header)", errCheckSkipped)
+	}
+
+	files, err := findLicenseFiles(".", licenseCfg)
+	if err != nil {
+		return "", err
+	}
+	issues, _, err := checkLicenseHeaders(files, licenseCfg, false)
+	if err != nil {
+		return "", err
+	}
+	if len(issues) > 0 {
+		lines := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			lines = append(lines, issue.String())
+		}
+		return strings.Join(lines, "\n"), fmt.Errorf("%w: %d of %d files", errLicenseHeaderIssues, len(issues), len(files))
+	}
+	return "", nil
+}
+
+// checkVulnerabilities fails when govulncheck reports a reachable vulnerability
+func checkVulnerabilities() (string, error) {
+	if err := requireTool(CmdGoVulnCheck); err != nil {
+		return "", err
+	}
+	output, err := GetRunner().RunCmdOutput(CmdGoVulnCheck, "./...")
+	if err != nil {
+		return output, fmt.Errorf("vulnerability check failed: %w", err)
+	}
+	return "", nil
+}
@@ -0,0 +1,179 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/mage/testutil"
+)
+
+// useFakeRunner installs a fake runner answering through output for the duration of the test
+func useFakeRunner(t *testing.T, output func(cmd string) (string, error)) *testutil.FakeRunner {
+	t.Helper()
+	runner := testutil.NewFakeRunner(output)
+	original := GetRunner()
+	require.NoError(t, SetRunner(runner))
+	t.Cleanup(func() {
+		require.NoError(t, SetRunner(original))
+	})
+	return runner
+}
+
+func TestSelectChecks(t *testing.T) {
+	t.Parallel()
+
+	names := func(checks []checkDefinition) string {
+		list := make([]string, 0, len(checks))
+		for _, c := range checks {
+			list = append(list, c.name)
+		}
+		return strings.Join(list, ",")
+	}
+
+	checks, err := selectChecks(allChecks(), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "format,imports,tidy,generate,spelling,license,vuln", names(checks))
+
+	checks, err = selectChecks(allChecks(), "vuln, format", "")
+	require.NoError(t, err)
+	assert.Equal(t, "format,vuln", names(checks), "report order is kept")
+
+	checks, err = selectChecks(allChecks(), "", "vuln,spelling")
+	require.NoError(t, err)
+	assert.Equal(t, "format,imports,tidy,generate,license", names(checks))
+
+	_, err = selectChecks(allChecks(), "lint", "")
+	require.ErrorIs(t, err, errUnknownCheck)
+}
+
+func TestRunChecks(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	slow := func(output string, err error) func() (string, error) {
+		return func() (string, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			running.Add(-1)
+			return output, err
+		}
+	}
+	checks := []checkDefinition{
+		{name: "a", run: slow("", nil)},
+		{name: "b", run: slow("bad.go", errUnformattedFiles)},
+		{name: "c", run: slow("", errCheckSkipped)},
+		{name: "d", run: slow("", errors.New("boom"))}, //nolint:err113 // test error
+	}
+
+	results := runChecks(checks, 2)
+	require.Len(t, results, 4)
+	assert.LessOrEqual(t, peak.Load(), int32(2), "jobs bounds concurrency")
+	assert.Equal(t, int32(2), peak.Load(), "checks run concurrently")
+
+	assert.Equal(t, checkStatusPassed, results[0].Status)
+	assert.Equal(t, checkStatusFailed, results[1].Status, "a failure does not stop the others")
+	assert.Equal(t, "files are not formatted\nbad.go", results[1].Output)
+	assert.Equal(t, checkStatusSkipped, results[2].Status)
+	assert.Equal(t, checkStatusFailed, results[3].Status)
+	assert.Equal(t, "boom", results[3].Output)
+	assert.Positive(t, results[0].DurationMs)
+
+	report := newCheckAllReport(results, time.Second, CIMetadata{})
+	assert.Equal(t, checkStatusFailed, report.Status)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+}
+
+func TestCheckAllWithArgs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("go.mod", []byte("module example.com/x\n\ngo 1.25\n"), 0o600))
+	setCommandsMissing(t, "goimports", "misspell", CmdGoVulnCheck)
+	TestSetConfig(defaultConfig())
+	defer TestResetConfig()
+
+	runner := useFakeRunner(t, nil)
+	runner.Outputs["gofmt -l ."] = "b.go\na.go\n"
+
+	output := filepath.Join(t.TempDir(), "checks.json")
+	err := Check{}.AllWithArgs("output=" + output)
+	require.ErrorIs(t, err, errChecksFailed)
+	assert.Contains(t, err.Error(), "format")
+
+	data, err := os.ReadFile(output) //nolint:gosec // test file in temp dir
+	require.NoError(t, err)
+	var report checkAllReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, checkStatusFailed, report.Status)
+	require.Len(t, report.Checks, 7)
+	assert.Equal(t, "format", report.Checks[0].Name)
+	assert.Contains(t, report.Checks[0].Output, "a.go\nb.go")
+	statuses := make(map[string]string)
+	for _, c := range report.Checks {
+		statuses[c.Name] = c.Status
+	}
+	assert.Equal(t, map[string]string{
+		"format":   checkStatusFailed,
+		"imports":  checkStatusSkipped,
+		"tidy":     checkStatusPassed,
+		"generate": checkStatusPassed,
+		"spelling": checkStatusSkipped,
+		"license":  checkStatusSkipped,
+		"vuln":     checkStatusSkipped,
+	}, statuses)
+
+	runner.Outputs["gofmt -l ."] = ""
+	require.NoError(t, Check{}.AllWithArgs("skip=generate", "output="+output))
+	require.NoError(t, os.WriteFile(output, nil, 0o600))
+
+	require.ErrorIs(t, Check{}.AllWithArgs("only=nope"), errUnknownCheck)
+	require.ErrorIs(t, Check{}.AllWithArgs("jobs=0"), errInvalidCheckJobs)
+}
+
+func TestCheckGenerateDiff(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_, err := checkGenerateDiff()
+	require.ErrorIs(t, err, errCheckSkipped, "no go.mod")
+
+	chdirTempModule(t)
+	useFakeRunner(t, func(cmd string) (string, error) {
+		if cmd == "go generate ./..." {
+			return "", os.WriteFile("mock_store.go", []byte("package x\n"), 0o600)
+		}
+		return "", nil
+	})
+	output, err := checkGenerateDiff()
+	require.ErrorIs(t, err, errGeneratedDrift)
+	assert.Contains(t, output, "+++ b/mock_store.go")
+	assert.NoFileExists(t, "mock_store.go", "the working tree is untouched")
+}
+
+func TestCheckVulnerabilitiesOutput(t *testing.T) {
+	setCommandExists(t, func(string) bool { return true })
+	findings := "Vulnerability #1: GO-2024-0001\n    Found in: example.com/dep@v1.0.0\n"
+	useFakeRunner(t, func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, CmdGoVulnCheck) {
+			return findings, errors.New("exit status 3") //nolint:err113 // test error
+		}
+		return "", nil
+	})
+
+	output, err := checkVulnerabilities()
+	require.Error(t, err)
+	assert.Equal(t, findings, output, "findings are shown in the report")
+}
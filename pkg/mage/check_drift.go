@@ -0,0 +1,390 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for drift detection
+var errModuleNotTidy = errors.New("go.mod/go.sum are not tidy")
+
+// generateStep regenerates code inside dir, a scratch copy of the module
+type generateStep func(dir string) error
+
+// commandStep runs a generator command at the root of the scratch copy
+func commandStep(name string, args ...string) generateStep {
+	return func(dir string) error {
+		module := ModuleInfo{Path: dir, Relative: "."}
+		if _, err := runCommandInModuleOutputWithRunner(module, GetRunner(), name, args...); err != nil {
+			return fmt.Errorf("%s failed: %w", name, err)
+		}
+		return nil
+	}
+}
+
+// goGenerateStep runs go generate on all packages
+func goGenerateStep() generateStep {
+	return commandStep(CmdGo, CmdGoGenerate, "./...")
+}
+
+// treeFilter reports whether a file or directory (relative and slash-separated)
+// takes part in a drift check; excluded directories are skipped entirely
+type treeFilter func(rel string, d fs.DirEntry) bool
+
+// generatorInputs includes every file a generator may read: sources, specs, templates
+// and configs. Version control, dependency trees (vendor, node_modules), build output
+// (build.output and dist), the tools directory and magex state are skipped.
+func generatorInputs(config *Config) treeFilter {
+	skipped := map[string]bool{DirBin: true, DirDist: true, ".mage": true, ".mage-x": true}
+	for _, dir := range []string{config.Build.Output, config.Tools.BinDir} {
+		if dir != "" && filepath.IsLocal(dir) {
+			skipped[filepath.ToSlash(filepath.Clean(dir))] = true
+		}
+	}
+	return func(rel string, d fs.DirEntry) bool {
+		if !d.IsDir() {
+			return true
+		}
+		switch d.Name() {
+		case ".git", DirVendor, "node_modules":
+			return false
+		}
+		return !skipped[rel]
+	}
+}
+
+// moduleSources includes go.mod, go.sum and the Go sources of the module at root.
+// Directories the go command ignores (vendor, testdata, names starting with "." or "_"),
+// node_modules and nested modules are skipped.
+func moduleSources(root string) treeFilter {
+	return func(rel string, d fs.DirEntry) bool {
+		name := d.Name()
+		if !d.IsDir() {
+			return rel == "go.mod" || rel == "go.sum" || strings.HasSuffix(name, ".go")
+		}
+		if rel == "." {
+			return true
+		}
+		if name == "vendor" || name == "testdata" || name == "node_modules" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return false
+		}
+		return !utils.FileExists(filepath.Join(root, filepath.FromSlash(rel), "go.mod"))
+	}
+}
+
+// tidyDrift runs go mod tidy in a scratch copy of the module's go.mod, go.sum and Go
+// sources and returns a unified diff of what it would change in go.mod and go.sum
+func tidyDrift() (string, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return scratchDrift(moduleSources(root), commandStep(CmdGo, CmdGoMod, "tidy"))
+}
+
+// generateDrift runs the steps in a scratch copy of the generator inputs of the module
+// and returns a unified diff of every file they would add, change or delete. The
+// working tree is never touched.
+func generateDrift(steps ...generateStep) (string, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return scratchDrift(generatorInputs(config), steps...)
+}
+
+// scratchDrift copies the files of the module selected by include into a scratch
+// directory, runs the steps there and returns a unified diff of the selected files.
+// Relative replace directives are pointed at the module's real location while the
+// steps run, so local replacements outside the module still resolve.
+func scratchDrift(include treeFilter, steps ...generateStep) (string, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if !utils.FileExists(filepath.Join(root, "go.mod")) {
+		return "", errGoModuleNotFound
+	}
+
+	scratch, err := os.MkdirTemp("", "magex-generate-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(scratch); removeErr != nil {
+			utils.Warn("Failed to remove %s: %v", scratch, removeErr)
+		}
+	}()
+
+	if err := copyTree(root, scratch, include); err != nil {
+		return "", err
+	}
+
+	scratchMod := filepath.Join(scratch, "go.mod")
+	replaced, err := absolutizeReplaces(scratchMod, root)
+	if err != nil {
+		return "", err
+	}
+
+	for _, step := range steps {
+		if err := step(scratch); err != nil {
+			return "", err
+		}
+	}
+
+	if err := restoreReplaces(scratchMod, replaced); err != nil {
+		return "", err
+	}
+	return diffTrees(root, scratch, include)
+}
+
+// goModLocalPath reports whether a replacement path in go.mod is a relative local path
+func goModLocalPath(path string) bool {
+	return path == "." || path == ".." || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") ||
+		strings.HasPrefix(path, ".\\") || strings.HasPrefix(path, "..\\")
+}
+
+// absolutizeReplaces rewrites the relative replacement paths in the go.mod at path to
+// absolute paths under dir. It returns the rewritten tokens mapped to the originals.
+func absolutizeReplaces(path, dir string) (map[string]string, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- go.mod of the scratch copy
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	replaced := make(map[string]string)
+	lines := strings.Split(string(content), "\n")
+	inReplaceBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "replace (") || strings.HasPrefix(trimmed, "replace("):
+			inReplaceBlock = true
+			continue
+		case inReplaceBlock && trimmed == ")":
+			inReplaceBlock = false
+			continue
+		case !inReplaceBlock && !strings.HasPrefix(trimmed, "replace "):
+			continue
+		}
+
+		_, target, found := strings.Cut(line, "=>")
+		fields := strings.Fields(target)
+		if !found || len(fields) == 0 {
+			continue
+		}
+		original := fields[0]
+		unquoted, err := strconv.Unquote(original)
+		if err != nil {
+			unquoted = original
+		}
+		if !goModLocalPath(unquoted) {
+			continue
+		}
+
+		absolute := filepath.Join(dir, filepath.FromSlash(unquoted))
+		token := absolute
+		if strings.ContainsAny(absolute, " \t\"'`") {
+			token = strconv.Quote(absolute)
+		}
+		replaced[token] = original
+		arrow := strings.Index(line, "=>")
+		lines[i] = line[:arrow] + strings.Replace(line[arrow:], original, token, 1)
+	}
+
+	if len(replaced) == 0 {
+		return nil, nil
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), fileops.PermFile); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return replaced, nil
+}
+
+// restoreReplaces puts the original relative replacement paths back into the go.mod
+// at path, so they do not show up in the diff
+func restoreReplaces(path string, replaced map[string]string) error {
+	if len(replaced) == 0 {
+		return nil
+	}
+	content, err := os.ReadFile(path) // #nosec G304 -- go.mod of the scratch copy
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	updated := string(content)
+	for token, original := range replaced {
+		updated = strings.ReplaceAll(updated, "=> "+token, "=> "+original)
+	}
+	if err := os.WriteFile(path, []byte(updated), fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
+
+// copyTree copies the files of a directory tree selected by include, keeping file
+// modes and symlinks
+func copyTree(src, dst string, include treeFilter) error {
+	return walkTree(src, include, func(path, rel string, d fs.DirEntry) error {
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			content, err := os.ReadFile(path) // #nosec G304 -- path comes from walking the module tree
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, content, info.Mode().Perm())
+		default:
+			// Sockets, pipes and devices are not source
+			return nil
+		}
+	})
+}
+
+// walkTree calls fn for every file and directory under root selected by include,
+// with its slash-separated path relative to root
+func walkTree(root string, include treeFilter, fn func(path, rel string, d fs.DirEntry) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !include(rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, rel, d)
+	})
+}
+
+// treeFiles lists the regular files under root selected by include, relative and slash-separated
+func treeFiles(root string, include treeFilter) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := walkTree(root, include, func(_, rel string, d fs.DirEntry) error {
+		if d.Type().IsRegular() {
+			files[rel] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// diffTrees returns a unified diff of every regular file selected by include that
+// differs between two trees
+func diffTrees(before, after string, include treeFilter) (string, error) {
+	beforeFiles, err := treeFiles(before, include)
+	if err != nil {
+		return "", err
+	}
+	afterFiles, err := treeFiles(after, include)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(beforeFiles)+len(afterFiles))
+	for name := range beforeFiles {
+		names = append(names, name)
+	}
+	for name := range afterFiles {
+		if !beforeFiles[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	read := func(root, name string, exists bool) ([]byte, error) {
+		if !exists {
+			return nil, nil
+		}
+		return os.ReadFile(filepath.Join(root, filepath.FromSlash(name))) // #nosec G304 -- path comes from walking the tree
+	}
+
+	var sb strings.Builder
+	for _, name := range names {
+		oldContent, err := read(before, name, beforeFiles[name])
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		newContent, err := read(after, name, afterFiles[name])
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		sb.WriteString(unifiedDiff(name, oldContent, newContent, beforeFiles[name], afterFiles[name]))
+	}
+	return sb.String(), nil
+}
+
+// Tidy checks that go.mod and go.sum are tidy without modifying them.
+// go mod tidy runs in a scratch copy of go.mod, go.sum and the Go sources of the
+// module and any change it would make is printed as a unified diff. Relative replace
+// directives are resolved against the module directory; files outside the module
+// that the go command reads through them are used in place.
+func (c Check) Tidy() error {
+	utils.Header("Checking go.mod/go.sum")
+
+	diff, err := tidyDrift()
+	if err != nil {
+		return err
+	}
+	if diff != "" {
+		fmt.Print(diff)
+		return errModuleNotTidy
+	}
+	utils.Success("go.mod and go.sum are tidy")
+	return nil
+}
+
+// Generate checks that generated code is up to date without modifying the working tree.
+// go generate runs in a scratch copy of the module, without vendor, node_modules,
+// build output and the tools directory, and any file it would add, change or delete
+// is printed as a unified diff. Relative replace directives are resolved against the
+// module directory.
+func (c Check) Generate() error {
+	utils.Header("Checking generated code")
+
+	diff, err := generateDrift(goGenerateStep())
+	if err != nil {
+		return err
+	}
+	if diff != "" {
+		fmt.Print(diff)
+		return errGeneratedDrift
+	}
+	utils.Success("Generated code is up to date")
+	return nil
+}
@@ -0,0 +1,200 @@
+package mage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGoMod = "module example.com/x\n\ngo 1.25\n"
+
+func TestTidyDrift(t *testing.T) {
+	t.Run("reports the diff without touching the module", func(t *testing.T) {
+		chdirTempModule(t)
+		root, err := os.Getwd()
+		require.NoError(t, err)
+		useFakeRunner(t, func(cmd string) (string, error) {
+			require.Equal(t, "go mod tidy", cmd)
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NotEqual(t, root, wd, "tidy runs in a scratch copy")
+			require.NoError(t, os.WriteFile("go.mod", []byte(testGoMod+"\nrequire example.com/y v1.0.0\n"), 0o600))
+			return "", os.WriteFile("go.sum", []byte("example.com/y v1.0.0 h1:abc=\n"), 0o600)
+		})
+
+		diff, err := tidyDrift()
+		require.NoError(t, err)
+		assert.Contains(t, diff, "--- a/go.mod\n+++ b/go.mod\n")
+		assert.Contains(t, diff, "+require example.com/y v1.0.0\n")
+		assert.Contains(t, diff, "--- /dev/null\n+++ b/go.sum\n")
+
+		data, err := os.ReadFile(filepath.Join(root, "go.mod")) //nolint:gosec // test file in temp dir
+		require.NoError(t, err)
+		assert.Equal(t, testGoMod, string(data))
+		assert.NoFileExists(t, filepath.Join(root, "go.sum"))
+
+		require.ErrorIs(t, Check{}.Tidy(), errModuleNotTidy)
+	})
+
+	t.Run("tidy failure leaves the module alone", func(t *testing.T) {
+		chdirTempModule(t)
+		useFakeRunner(t, func(string) (string, error) {
+			require.NoError(t, os.WriteFile("go.mod", []byte("broken"), 0o600))
+			return "", assert.AnError
+		})
+
+		_, err := tidyDrift()
+		require.ErrorIs(t, err, assert.AnError)
+		data, err := os.ReadFile("go.mod")
+		require.NoError(t, err)
+		assert.Equal(t, testGoMod, string(data))
+	})
+
+	t.Run("tidy module", func(t *testing.T) {
+		chdirTempModule(t)
+		useFakeRunner(t, func(string) (string, error) { return "", nil })
+
+		diff, err := tidyDrift()
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+		require.NoError(t, Check{}.Tidy())
+	})
+
+	t.Run("copies only the module sources", func(t *testing.T) {
+		chdirTempModule(t)
+		for _, dir := range []string{"vendor/dep", "node_modules/pkg", "internal/x", "nested", ".cache"} {
+			require.NoError(t, os.MkdirAll(dir, 0o750))
+		}
+		for name, content := range map[string]string{
+			"main.go":              "package x\n",
+			"internal/x/x.go":      "package x\n",
+			"README.md":            "# x\n",
+			"vendor/dep/dep.go":    "package dep\n",
+			"node_modules/pkg/a":   "x\n",
+			"nested/go.mod":        "module example.com/nested\n",
+			"nested/nested.go":     "package nested\n",
+			".cache/cached.go":     "package cache\n",
+			"internal/x/x_test.go": "package x\n",
+		} {
+			require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+		}
+		useFakeRunner(t, func(string) (string, error) {
+			assert.FileExists(t, "main.go")
+			assert.FileExists(t, filepath.Join("internal", "x", "x.go"))
+			assert.FileExists(t, filepath.Join("internal", "x", "x_test.go"))
+			for _, path := range []string{"README.md", "vendor", "node_modules", "nested", ".cache"} {
+				assert.NoFileExists(t, path)
+				assert.NoDirExists(t, path)
+			}
+			return "", nil
+		})
+
+		diff, err := tidyDrift()
+		require.NoError(t, err)
+		assert.Empty(t, diff, "files that are not copied are not reported as deleted")
+	})
+
+	t.Run("relative replace directives resolve against the module", func(t *testing.T) {
+		parent := t.TempDir()
+		root := filepath.Join(parent, "app")
+		require.NoError(t, os.MkdirAll(root, 0o750))
+		require.NoError(t, os.MkdirAll(filepath.Join(parent, "lib"), 0o750))
+		goMod := testGoMod + "\nreplace example.com/lib => ../lib\n\nreplace (\n\texample.com/a v1.0.0 => ./a\n\texample.com/b => example.com/c v1.2.0\n)\n"
+		require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte(goMod), 0o600))
+		t.Chdir(root)
+
+		useFakeRunner(t, func(string) (string, error) {
+			data, err := os.ReadFile("go.mod")
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "replace example.com/lib => "+filepath.Join(parent, "lib")+"\n")
+			assert.Contains(t, string(data), "\texample.com/a v1.0.0 => "+filepath.Join(root, "a")+"\n")
+			assert.Contains(t, string(data), "\texample.com/b => example.com/c v1.2.0\n")
+			return "", os.WriteFile("go.mod", append(data, "\nrequire example.com/lib v0.0.0\n"...), 0o600)
+		})
+
+		diff, err := tidyDrift()
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+require example.com/lib v0.0.0\n")
+		assert.NotContains(t, diff, parent, "the scratch go.mod keeps the relative paths")
+	})
+
+	t.Run("no go.mod", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		_, err := tidyDrift()
+		require.ErrorIs(t, err, errGoModuleNotFound)
+	})
+}
+
+func TestGenerateDrift(t *testing.T) {
+	chdirTempModule(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(".git", "objects"), 0o750))
+	require.NoError(t, os.WriteFile("stale.go", []byte("package x\n\nconst V = 1\n"), 0o600))
+	require.NoError(t, os.WriteFile("obsolete.go", []byte("package x\n"), 0o600))
+	require.NoError(t, os.Symlink("stale.go", "link.go"))
+
+	useFakeRunner(t, func(cmd string) (string, error) {
+		require.Equal(t, "go generate ./...", cmd)
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoDirExists(t, filepath.Join(wd, ".git"), ".git is not copied")
+		require.NoError(t, os.WriteFile("stale.go", []byte("package x\n\nconst V = 2\n"), 0o600))
+		require.NoError(t, os.Remove("obsolete.go"))
+		return "", os.WriteFile("new_gen.go", []byte("package x\n"), 0o600)
+	})
+
+	diff, err := generateDrift(goGenerateStep())
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- /dev/null\n+++ b/new_gen.go\n")
+	assert.Contains(t, diff, "--- a/obsolete.go\n+++ /dev/null\n")
+	assert.Contains(t, diff, "-const V = 1\n+const V = 2\n")
+	assert.NotContains(t, diff, "link.go", "symlinks are copied, not compared")
+
+	data, err := os.ReadFile("stale.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package x\n\nconst V = 1\n", string(data), "the working tree is untouched")
+	assert.FileExists(t, "obsolete.go")
+	assert.NoFileExists(t, "new_gen.go")
+
+	require.ErrorIs(t, Check{}.Generate(), errGeneratedDrift)
+}
+
+func TestGenerateDrift_SkipsDependenciesAndBuildOutput(t *testing.T) {
+	chdirTempModule(t)
+	config := defaultConfig()
+	config.Build.Output = "out"
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+
+	files := map[string]string{
+		"main.go":                   "package x\n",
+		"api/openapi.yaml":          "openapi: 3.0.0\n",
+		"web/node_modules/pkg/a.js": "x\n",
+		"vendor/dep/dep.go":         "package dep\n",
+		"out/x":                     "binary\n",
+		"dist/x.tar.gz":             "archive\n",
+		".mage/bin/tool":            "binary\n",
+		".mage-x/check-all.json":    "{}\n",
+		"internal/bin/keep.go":      "package bin\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o750))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	}
+
+	useFakeRunner(t, func(string) (string, error) {
+		assert.FileExists(t, "main.go")
+		assert.FileExists(t, filepath.Join("api", "openapi.yaml"), "non-Go generator inputs are copied")
+		assert.FileExists(t, filepath.Join("internal", "bin", "keep.go"), "only the top-level output directories are skipped")
+		for _, dir := range []string{filepath.Join("web", "node_modules"), "vendor", "out", "dist", ".mage", ".mage-x"} {
+			assert.NoDirExists(t, dir)
+		}
+		return "", nil
+	})
+
+	diff, err := generateDrift(goGenerateStep())
+	require.NoError(t, err)
+	assert.Empty(t, diff, "files that are not copied are not reported as deleted")
+}
@@ -173,6 +173,8 @@ func (d *ciDetector) applyEnvOverrides(mode *CIMode) {
 			mode.Format = CIFormatGitHub
 		case "json":
 			mode.Format = CIFormatJSON
+		case "junit":
+			mode.Format = CIFormatJUnit
 		case "auto":
 			mode.Format = CIFormatAuto
 		}
@@ -201,6 +203,14 @@ func (d *ciDetector) applyEnvOverrides(mode *CIMode) {
 		mode.OutputPath = v
 	}
 
+	// Flaky retries override
+	if v := d.envGetter("MAGE_X_CI_FLAKY_RETRIES"); v != "" {
+		var retries int
+		if err := parseIntEnv(v, &retries); err == nil && retries >= 0 && retries <= MaxFlakyRetries {
+			mode.FlakyRetries = retries
+		}
+	}
+
 	// Dedup override
 	if v := d.envGetter("MAGE_X_CI_DEDUP"); v != "" {
 		switch strings.ToLower(v) {
@@ -236,10 +246,20 @@ func (d *ciDetector) applyParamOverrides(mode *CIMode, params map[string]string)
 			mode.Format = CIFormatGitHub
 		case "json":
 			mode.Format = CIFormatJSON
+		case "junit":
+			mode.Format = CIFormatJUnit
 		case "auto":
 			mode.Format = CIFormatAuto
 		}
 	}
+
+	// Flaky retries override
+	if v, ok := params["flaky_retries"]; ok {
+		var retries int
+		if err := parseIntEnv(v, &retries); err == nil && retries >= 0 && retries <= MaxFlakyRetries {
+			mode.FlakyRetries = retries
+		}
+	}
 }
 
 // selectFormat returns the appropriate format based on detected platform
@@ -303,6 +303,22 @@ func TestCIDetector_GetConfig(t *testing.T) {
 			wantEnable: true,
 			wantFormat: CIFormatJSON,
 		},
+		{
+			name:       "param junit format in GitHub Actions",
+			env:        map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"},
+			params:     map[string]string{"ci_format": "junit"},
+			cfg:        nil,
+			wantEnable: true,
+			wantFormat: CIFormatJUnit,
+		},
+		{
+			name:       "env override - junit format",
+			env:        map[string]string{"CI": "true", "MAGE_X_CI_FORMAT": "JUnit"},
+			params:     nil,
+			cfg:        nil,
+			wantEnable: true,
+			wantFormat: CIFormatJUnit,
+		},
 	}
 
 	for _, tt := range tests {
@@ -40,6 +40,13 @@ type JSONReporterInterface interface {
 	GetOutputPath() string
 }
 
+// TestEventObserver is implemented by reporters that need every parsed test event
+// (passes and skips included), not only the failures reported through CIReporter
+type TestEventObserver interface {
+	// ObserveEvent receives each go test -json event after it is parsed
+	ObserveEvent(event TestEvent)
+}
+
 // MultiReporter combines multiple reporters into one
 type MultiReporter struct {
 	reporters []CIReporter
@@ -72,6 +79,15 @@ func (m *MultiReporter) ReportFailure(failure CITestFailure) error {
 	return nil
 }
 
+// ObserveEvent forwards a test event to every reporter that observes events
+func (m *MultiReporter) ObserveEvent(event TestEvent) {
+	for _, r := range m.reporters {
+		if observer, ok := r.(TestEventObserver); ok {
+			observer.ObserveEvent(event)
+		}
+	}
+}
+
 // WriteSummary writes summary to all reporters
 func (m *MultiReporter) WriteSummary(result *CIResult) error {
 	for _, r := range m.reporters {
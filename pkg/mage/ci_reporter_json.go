@@ -27,6 +27,7 @@ type jsonLineType string
 const (
 	jsonLineTypeStart   jsonLineType = "start"
 	jsonLineTypeFailure jsonLineType = "failure"
+	jsonLineTypeFlaky   jsonLineType = "flaky" // A failure that passed on a rerun
 	jsonLineTypeSummary jsonLineType = "summary"
 )
 
@@ -146,6 +147,12 @@ func (r *jsonReporter) WriteSummary(result *CIResult) error {
 		return ErrReporterClosed
 	}
 
+	for i := range result.Flaky {
+		if err := r.writeLine(jsonLine{Type: jsonLineTypeFlaky, Failure: &result.Flaky[i]}); err != nil {
+			return err
+		}
+	}
+
 	line := jsonLine{
 		Type:    jsonLineTypeSummary,
 		Summary: &result.Summary,
@@ -235,6 +235,43 @@ func TestJSONReporter_WriteSummary(t *testing.T) {
 	}
 }
 
+func TestJSONReporter_WriteSummaryFlaky(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "ci-results.jsonl")
+	reporter, err := NewJSONReporter(outputPath)
+	if err != nil {
+		t.Fatalf("NewJSONReporter() error = %v", err)
+	}
+
+	result := &CIResult{
+		Summary: CISummary{Status: TestStatusPassed, Flaky: 1},
+		Flaky:   []CITestFailure{{Package: "ex/a", Test: "TestFlaky", Flaky: true, Reruns: 1}},
+	}
+	if err := reporter.WriteSummary(result); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+	if err := reporter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath) //nolint:gosec // Test file reading is safe
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	var line jsonLine
+	if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if line.Type != jsonLineTypeFlaky || line.Failure == nil || line.Failure.Test != "TestFlaky" {
+		t.Errorf("Expected a flaky line for TestFlaky, got %+v", line)
+	}
+}
+
 func TestJSONReporter_FullWorkflow(t *testing.T) {
 	t.Parallel()
 
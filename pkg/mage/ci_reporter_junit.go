@@ -0,0 +1,465 @@
+// Package mage provides JUnit XML reporter for CI test output
+package mage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+)
+
+const (
+	// maxJUnitOutputSize caps captured output per test case (64KB)
+	maxJUnitOutputSize = 64 * 1024
+	// junitBuildFailedCase is the test case name used for package build failures
+	junitBuildFailedCase = "[build failed]"
+	// junitUnknownSuite is the suite name used for failures without a package (e.g. crashes)
+	junitUnknownSuite = "unknown"
+)
+
+// junitTestSuites is the root <testsuites> element
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a <testsuite> element, one per Go package
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+// junitProperty is a <property> element carrying run metadata
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase is a <testcase> element
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure is a <failure> or <error> element
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// junitSkipped is a <skipped> element
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// junitCase tracks a single test run as events arrive
+type junitCase struct {
+	pkg     string
+	test    string
+	tags    string // build tags of the run, empty for an untagged run
+	action  string // pass, fail, skip
+	elapsed float64
+	output  strings.Builder
+}
+
+// className returns the test case classname: the package, qualified with the
+// build tags for a tagged run so each run of a multi-tag test stays a distinct case
+func (tc *junitCase) className() string {
+	if tc.tags == "" {
+		return tc.pkg
+	}
+	return tc.pkg + " [" + tc.tags + "]"
+}
+
+// key returns the identity of the test case, see junitCaseKey
+func (tc *junitCase) key() string {
+	return junitCaseKey(tc.pkg, tc.test, tc.tags)
+}
+
+// junitCaseKey identifies a test run by package, test and build tags, so failure
+// details and flaky reruns attach to the run of the test they came from
+func junitCaseKey(pkg, test, tags string) string {
+	return pkg + ":" + test + "@" + tags
+}
+
+// junitSuite tracks a package and its finished test cases
+type junitSuite struct {
+	name      string
+	timestamp string
+	elapsed   float64
+	cases     []*junitCase
+	output    strings.Builder
+}
+
+// junitReporter implements CIReporter and TestEventObserver, writing a JUnit XML file on Close
+type junitReporter struct {
+	path     string
+	mu       sync.Mutex
+	closed   bool
+	metadata CIMetadata
+	result   *CIResult
+	suites   map[string]*junitSuite
+	running  map[string]*junitCase
+	failures []CITestFailure
+}
+
+// NewJUnitReporter creates a JUnit XML reporter that writes to path when closed
+func NewJUnitReporter(path string) CIReporter {
+	return &junitReporter{
+		path:    path,
+		suites:  make(map[string]*junitSuite),
+		running: make(map[string]*junitCase),
+	}
+}
+
+// junitOutputPath derives the JUnit XML path from the JSONL output path (ci-results.jsonl -> ci-results.xml)
+func junitOutputPath(outputPath string) string {
+	if outputPath == "" {
+		outputPath = DefaultCIMode().OutputPath
+	}
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".xml"
+}
+
+// Start begins the test run report
+func (r *junitReporter) Start(metadata CIMetadata) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrReporterClosed
+	}
+	r.metadata = metadata
+	return nil
+}
+
+// ObserveEvent records every test as it runs, so passed and skipped tests appear in the report
+func (r *junitReporter) ObserveEvent(event TestEvent) {
+	if event.Package == "" {
+		return // build-output events are keyed by ImportPath; build failures arrive via ReportFailure
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	suite := r.suite(event.Package)
+	if suite.timestamp == "" {
+		suite.timestamp = event.Time
+	}
+
+	if event.Test == "" {
+		switch event.Action {
+		case "output":
+			appendBounded(&suite.output, event.Output)
+		case "pass", "fail", "skip":
+			suite.elapsed += event.Elapsed
+		}
+		return
+	}
+
+	key := junitCaseKey(event.Package, event.Test, event.BuildTags)
+	switch event.Action {
+	case "run":
+		r.running[key] = &junitCase{pkg: event.Package, test: event.Test, tags: event.BuildTags}
+	case "output":
+		if tc, ok := r.running[key]; ok {
+			appendBounded(&tc.output, event.Output)
+		}
+	case "pass", "fail", "skip":
+		tc, ok := r.running[key]
+		if !ok {
+			tc = &junitCase{pkg: event.Package, test: event.Test, tags: event.BuildTags}
+		}
+		delete(r.running, key)
+		tc.action = event.Action
+		tc.elapsed = event.Elapsed
+		if event.Action == "pass" {
+			tc.output.Reset() // Passing output is not reported, free it early
+		}
+		suite.cases = append(suite.cases, tc)
+	}
+}
+
+// ReportFailure records the detailed failure context for a test
+func (r *junitReporter) ReportFailure(failure CITestFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrReporterClosed
+	}
+	r.failures = append(r.failures, failure)
+	return nil
+}
+
+// WriteSummary records the final summary
+func (r *junitReporter) WriteSummary(result *CIResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrReporterClosed
+	}
+	r.result = result
+	return nil
+}
+
+// Close writes the JUnit XML file
+func (r *junitReporter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	data, err := xml.MarshalIndent(r.build(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+
+	if dir := filepath.Dir(r.path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, fileops.PermDirSensitive); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	content := append([]byte(xml.Header), data...)
+	content = append(content, '\n')
+	if err := os.WriteFile(r.path, content, fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to write JUnit XML: %w", err)
+	}
+	return nil
+}
+
+// GetOutputPath returns the path to the output file
+func (r *junitReporter) GetOutputPath() string {
+	return r.path
+}
+
+// suite returns the tracked suite for a package, creating it on first use.
+// Caller MUST hold r.mu.
+func (r *junitReporter) suite(pkg string) *junitSuite {
+	s, ok := r.suites[pkg]
+	if !ok {
+		s = &junitSuite{name: pkg}
+		r.suites[pkg] = s
+	}
+	return s
+}
+
+// build assembles the XML document from observed tests and reported failures.
+// Caller MUST hold r.mu.
+func (r *junitReporter) build() junitTestSuites {
+	// Index failure details by test so observed cases can carry the full context
+	details := make(map[string]CITestFailure, len(r.failures))
+	for _, f := range r.failures {
+		if f.Test != "" {
+			details[junitCaseKey(f.Package, f.Test, f.BuildTags)] = f
+		}
+	}
+
+	// Tests that failed and then passed on a rerun are reported as passed
+	flaky := make(map[string]CITestFailure)
+	if r.result != nil {
+		for _, f := range r.result.Flaky {
+			flaky[junitCaseKey(f.Package, f.Test, f.BuildTags)] = f
+		}
+	}
+
+	// Failures with no observed test case (build errors, crashes, or a reporter
+	// used without an event stream) still need a test case of their own
+	observed := make(map[string]bool)
+	for _, s := range r.suites {
+		for _, tc := range s.cases {
+			observed[tc.key()] = true
+		}
+	}
+	for _, f := range r.failures {
+		if f.Test != "" && observed[junitCaseKey(f.Package, f.Test, f.BuildTags)] {
+			continue
+		}
+		name := f.Test
+		if name == "" {
+			name = junitBuildFailedCase
+			if f.Type != FailureTypeBuild {
+				name = string(f.Type)
+			}
+		}
+		pkg := f.Package
+		if pkg == "" {
+			pkg = junitUnknownSuite
+		}
+		suite := r.suite(pkg)
+		tc := &junitCase{pkg: pkg, test: name, tags: f.BuildTags, action: "fail"}
+		suite.cases = append(suite.cases, tc)
+		details[tc.key()] = f
+	}
+
+	names := make([]string, 0, len(r.suites))
+	for name, s := range r.suites {
+		if len(s.cases) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	root := junitTestSuites{Suites: make([]junitTestSuite, 0, len(names))}
+	var totalElapsed float64
+	for _, name := range names {
+		s := r.suites[name]
+		suite := junitTestSuite{
+			Name:       s.name,
+			Time:       formatJUnitSeconds(s.elapsed),
+			Timestamp:  s.timestamp,
+			Properties: r.properties(),
+			SystemOut:  s.output.String(),
+		}
+		for _, tc := range s.cases {
+			testCase := junitTestCase{
+				ClassName: tc.className(),
+				Name:      tc.test,
+				Time:      formatJUnitSeconds(tc.elapsed),
+			}
+			if f, ok := flaky[tc.key()]; ok && tc.action == "fail" {
+				testCase.SystemOut = fmt.Sprintf("Flaky: failed, then passed on rerun %d\n\n%s", f.Reruns, tc.output.String())
+				suite.TestCases = append(suite.TestCases, testCase)
+				continue
+			}
+			switch tc.action {
+			case "fail":
+				f, ok := details[tc.key()]
+				if !ok {
+					f = CITestFailure{Type: FailureTypeTest, Error: extractErrorMessage(tc.output.String()), Output: tc.output.String()}
+				}
+				element := &junitFailure{Message: f.Error, Type: string(f.Type), Body: junitFailureBody(f)}
+				if f.Type == FailureTypeBuild || f.Type == FailureTypeFatal {
+					testCase.Error = element
+					suite.Errors++
+				} else {
+					testCase.Failure = element
+					suite.Failures++
+				}
+			case "skip":
+				testCase.Skipped = &junitSkipped{Message: extractSkipReason(tc.output.String())}
+				suite.Skipped++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suite.Tests = len(suite.TestCases)
+
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.Skipped += suite.Skipped
+		totalElapsed += s.elapsed
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if r.result != nil && r.result.Duration > 0 {
+		totalElapsed = r.result.Duration.Seconds()
+	}
+	root.Time = formatJUnitSeconds(totalElapsed)
+	return root
+}
+
+// properties returns the run metadata as suite properties.
+// Caller MUST hold r.mu.
+func (r *junitReporter) properties() []junitProperty {
+	var props []junitProperty
+	add := func(name, value string) {
+		if value != "" {
+			props = append(props, junitProperty{Name: name, Value: value})
+		}
+	}
+	add("go.version", r.metadata.GoVersion)
+	add("ci.platform", r.metadata.Platform)
+	add("git.branch", r.metadata.Branch)
+	add("git.commit", r.metadata.Commit)
+	add("ci.run_id", r.metadata.RunID)
+	return props
+}
+
+// junitFailureBody renders the failure location, source context and captured output
+func junitFailureBody(f CITestFailure) string {
+	var sb strings.Builder
+	if f.File != "" {
+		fmt.Fprintf(&sb, "%s:%d\n", f.File, f.Line)
+	}
+	if len(f.Context) > 0 {
+		sb.WriteString("\n")
+		for _, line := range f.Context {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+	if f.Output != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimRight(f.Output, "\n"))
+		sb.WriteString("\n")
+	} else if f.Stack != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimRight(f.Stack, "\n"))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// extractSkipReason returns the t.Skip message from a skipped test's output
+func extractSkipReason(output string) string {
+	var reasons []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- SKIP") {
+			continue
+		}
+		reasons = append(reasons, trimmed)
+	}
+	return strings.Join(reasons, "\n")
+}
+
+// appendBounded appends output to sb until maxJUnitOutputSize is reached
+func appendBounded(sb *strings.Builder, output string) {
+	if sb.Len() >= maxJUnitOutputSize {
+		return
+	}
+	if sb.Len()+len(output) > maxJUnitOutputSize {
+		sb.WriteString(output[:maxJUnitOutputSize-sb.Len()])
+		sb.WriteString(TruncationMarker)
+		return
+	}
+	sb.WriteString(output)
+}
+
+// formatJUnitSeconds formats elapsed seconds with millisecond precision
+func formatJUnitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,329 @@
+package mage
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// junitTestStream is go test -json output covering pass, fail, skip and a build failure
+const junitTestStream = `{"Time":"2026-01-02T15:04:05Z","Action":"start","Package":"example.com/app"}
+{"Action":"run","Package":"example.com/app","Test":"TestPass"}
+{"Action":"output","Package":"example.com/app","Test":"TestPass","Output":"=== RUN   TestPass\n"}
+{"Action":"pass","Package":"example.com/app","Test":"TestPass","Elapsed":0.25}
+{"Action":"run","Package":"example.com/app","Test":"TestFail"}
+{"Action":"output","Package":"example.com/app","Test":"TestFail","Output":"=== RUN   TestFail\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestFail","Output":"    app_test.go:12: expected 1, got 2\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestFail","Output":"--- FAIL: TestFail (0.10s)\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestFail","Elapsed":0.1}
+{"Action":"run","Package":"example.com/app","Test":"TestSkip"}
+{"Action":"output","Package":"example.com/app","Test":"TestSkip","Output":"=== RUN   TestSkip\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestSkip","Output":"    app_test.go:20: requires docker\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestSkip","Output":"--- SKIP: TestSkip (0.00s)\n"}
+{"Action":"skip","Package":"example.com/app","Test":"TestSkip","Elapsed":0}
+{"Action":"output","Package":"example.com/app","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/app","Elapsed":0.4}
+{"ImportPath":"example.com/broken","Action":"build-output","Output":"broken.go:3:1: syntax error\n"}
+{"ImportPath":"example.com/broken","Action":"build-fail"}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0,"FailedBuild":"example.com/broken"}
+{"Action":"skip","Package":"example.com/notests","Elapsed":0}
+`
+
+// runJUnitStream feeds a go test -json stream through a parser into a JUnit reporter
+// the way the CI runner does, and returns the decoded XML document
+func runJUnitStream(t *testing.T, stream string) junitTestSuites {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ci-results.xml")
+	reporter := NewJUnitReporter(path)
+	parser := (&ciRunner{mode: CIMode{ContextLines: 5, Dedup: true}, reporter: reporter}).newParser()
+
+	if err := reporter.Start(CIMetadata{Platform: "local", GoVersion: "go1.25.0", Branch: "main"}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := parser.Parse(strings.NewReader(stream)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, failure := range parser.GetFailures() {
+		if failure.Test == "TestFail" {
+			failure.Context = []string{">  12 | assert.Equal(t, 1, got)"}
+		}
+		if err := reporter.ReportFailure(failure); err != nil {
+			t.Fatalf("ReportFailure() error = %v", err)
+		}
+	}
+	if err := reporter.WriteSummary(&CIResult{Duration: 2 * time.Second}); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+	if err := reporter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled path
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("JUnit XML should start with the XML header")
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode JUnit XML: %v\n%s", err, data)
+	}
+	return doc
+}
+
+func TestJUnitReporter_FullStream(t *testing.T) {
+	t.Parallel()
+
+	doc := runJUnitStream(t, junitTestStream)
+
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 || doc.Skipped != 1 {
+		t.Errorf("totals = tests %d failures %d errors %d skipped %d, want 4/1/1/1",
+			doc.Tests, doc.Failures, doc.Errors, doc.Skipped)
+	}
+	if doc.Time != "2.000" {
+		t.Errorf("root time = %q, want run duration 2.000", doc.Time)
+	}
+
+	// Packages without tests are omitted and suites are sorted by package
+	if len(doc.Suites) != 2 {
+		t.Fatalf("suites = %d, want 2", len(doc.Suites))
+	}
+	app, broken := doc.Suites[0], doc.Suites[1]
+	if app.Name != "example.com/app" || broken.Name != "example.com/broken" {
+		t.Fatalf("suite names = %q, %q", app.Name, broken.Name)
+	}
+
+	if app.Tests != 3 || app.Time != "0.400" || app.Timestamp != "2026-01-02T15:04:05Z" {
+		t.Errorf("app suite = tests %d time %s timestamp %s", app.Tests, app.Time, app.Timestamp)
+	}
+	if !strings.Contains(app.SystemOut, "FAIL") {
+		t.Errorf("app suite system-out = %q, want package output", app.SystemOut)
+	}
+	if len(app.Properties) == 0 || app.Properties[0].Name != "go.version" || app.Properties[0].Value != "go1.25.0" {
+		t.Errorf("app suite properties = %+v", app.Properties)
+	}
+
+	cases := make(map[string]junitTestCase, len(app.TestCases))
+	for _, tc := range app.TestCases {
+		cases[tc.Name] = tc
+	}
+
+	if pass := cases["TestPass"]; pass.Time != "0.250" || pass.Failure != nil || pass.Skipped != nil || pass.ClassName != "example.com/app" {
+		t.Errorf("TestPass = %+v", pass)
+	}
+
+	fail := cases["TestFail"]
+	if fail.Failure == nil {
+		t.Fatal("TestFail should have a failure element")
+	}
+	if fail.Failure.Type != string(FailureTypeTest) || !strings.Contains(fail.Failure.Message, "expected 1, got 2") {
+		t.Errorf("TestFail failure = %+v", fail.Failure)
+	}
+	for _, want := range []string{"app_test.go:12", "assert.Equal(t, 1, got)", "--- FAIL: TestFail"} {
+		if !strings.Contains(fail.Failure.Body, want) {
+			t.Errorf("TestFail failure body missing %q:\n%s", want, fail.Failure.Body)
+		}
+	}
+
+	skip := cases["TestSkip"]
+	if skip.Skipped == nil || skip.Skipped.Message != "app_test.go:20: requires docker" {
+		t.Errorf("TestSkip skipped = %+v", skip.Skipped)
+	}
+
+	if len(broken.TestCases) != 1 || broken.Errors != 1 {
+		t.Fatalf("broken suite = %+v", broken)
+	}
+	buildCase := broken.TestCases[0]
+	if buildCase.Name != junitBuildFailedCase || buildCase.Error == nil || buildCase.Error.Type != string(FailureTypeBuild) {
+		t.Errorf("build failure case = %+v", buildCase)
+	}
+	if !strings.Contains(buildCase.Error.Body, "syntax error") {
+		t.Errorf("build failure body = %q, want compiler output", buildCase.Error.Body)
+	}
+}
+
+func TestJUnitReporter_FailuresWithoutEvents(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "junit.xml")
+	reporter := NewJUnitReporter(path)
+
+	failures := []CITestFailure{
+		{Package: "example.com/app", Test: "TestA", Type: FailureTypePanic, Error: "panic: boom", Stack: "goroutine 1 [running]:"},
+		{Type: FailureTypeFatal, Error: "Test binary crashed", Output: "SIGSEGV"},
+	}
+	for _, f := range failures {
+		if err := reporter.ReportFailure(f); err != nil {
+			t.Fatalf("ReportFailure() error = %v", err)
+		}
+	}
+	if err := reporter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := reporter.ReportFailure(failures[0]); err != ErrReporterClosed {
+		t.Errorf("ReportFailure() after Close error = %v, want ErrReporterClosed", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled path
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML: %v", err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode JUnit XML: %v", err)
+	}
+
+	if doc.Tests != 2 || doc.Failures != 1 || doc.Errors != 1 {
+		t.Errorf("totals = tests %d failures %d errors %d, want 2/1/1", doc.Tests, doc.Failures, doc.Errors)
+	}
+	if len(doc.Suites) != 2 || doc.Suites[0].Name != "example.com/app" || doc.Suites[1].Name != junitUnknownSuite {
+		t.Fatalf("suites = %+v", doc.Suites)
+	}
+	if body := doc.Suites[0].TestCases[0].Failure.Body; !strings.Contains(body, "goroutine 1") {
+		t.Errorf("panic failure body = %q, want stack", body)
+	}
+	if name := doc.Suites[1].TestCases[0].Name; name != string(FailureTypeFatal) {
+		t.Errorf("crash case name = %q", name)
+	}
+}
+
+func TestJUnitOutputPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: ".mage-x/ci-results.jsonl", want: ".mage-x/ci-results.xml"},
+		{input: "out/results", want: "out/results.xml"},
+		{input: "", want: ".mage-x/ci-results.xml"},
+	}
+	for _, tt := range tests {
+		if got := junitOutputPath(tt.input); got != tt.want {
+			t.Errorf("junitOutputPath(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExtractSkipReason(t *testing.T) {
+	t.Parallel()
+
+	output := "=== RUN   TestX\n    x_test.go:5: short mode\n    x_test.go:6: second line\n--- SKIP: TestX (0.00s)\n"
+	if got := extractSkipReason(output); got != "x_test.go:5: short mode\nx_test.go:6: second line" {
+		t.Errorf("extractSkipReason() = %q", got)
+	}
+	if got := extractSkipReason("=== RUN   TestX\n--- SKIP: TestX (0.00s)\n"); got != "" {
+		t.Errorf("extractSkipReason() without message = %q, want empty", got)
+	}
+}
+
+func TestMultiReporter_ObserveEvent(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	junit := NewJUnitReporter(path)
+	multi := NewMultiReporter(NullReporter{}, junit)
+
+	multi.ObserveEvent(TestEvent{Action: "run", Package: "p", Test: "TestA"})
+	multi.ObserveEvent(TestEvent{Action: "pass", Package: "p", Test: "TestA", Elapsed: 0.5})
+	if err := multi.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled path
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML: %v", err)
+	}
+	if !strings.Contains(string(data), `name="TestA" time="0.500"`) {
+		t.Errorf("expected forwarded test case in XML:\n%s", data)
+	}
+}
+
+func TestJUnitReporter_BuildTagRuns(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	runner := &ciRunner{reporter: NewJUnitReporter(path)}
+	parser := runner.newParser()
+
+	// TestA fails differently under each tag set; the tagged run also has a build failure
+	for _, args := range [][]string{{"test", "./..."}, {"test", "-tags", "integration", "./..."}} {
+		runner.buildTags = buildTagsFromArgs(args)
+		msg := "boom untagged"
+		if runner.buildTags != "" {
+			msg = "boom " + runner.buildTags
+		}
+		stream := `{"Action":"run","Package":"p","Test":"TestA"}
+{"Action":"output","Package":"p","Test":"TestA","Output":"    a_test.go:5: ` + msg + `\n"}
+{"Action":"fail","Package":"p","Test":"TestA","Elapsed":0.1}
+`
+		if runner.buildTags != "" {
+			stream += `{"Action":"fail","Package":"q","Elapsed":0,"FailedBuild":"q"}
+`
+		}
+		if err := parser.Parse(strings.NewReader(stream)); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+	}
+	for _, failure := range parser.GetFailures() {
+		if err := runner.reporter.ReportFailure(failure); err != nil {
+			t.Fatalf("ReportFailure() error = %v", err)
+		}
+	}
+	if err := runner.reporter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled path
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML: %v", err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode JUnit XML: %v", err)
+	}
+	if len(doc.Suites) != 2 || len(doc.Suites[0].TestCases) != 2 || len(doc.Suites[1].TestCases) != 1 {
+		t.Fatalf("suites = %+v, want p with two cases and q with one", doc.Suites)
+	}
+
+	want := []struct{ className, message string }{
+		{"p", "boom untagged"},
+		{"p [integration]", "boom integration"},
+	}
+	for i, w := range want {
+		tc := doc.Suites[0].TestCases[i]
+		if tc.ClassName != w.className {
+			t.Errorf("case %d classname = %q, want %q", i, tc.ClassName, w.className)
+		}
+		if tc.Failure == nil || !strings.Contains(tc.Failure.Body, w.message) {
+			t.Errorf("case %d failure = %+v, want the details of its own run (%q)", i, tc.Failure, w.message)
+		}
+	}
+	if got := doc.Suites[1].TestCases[0].ClassName; got != "q [integration]" {
+		t.Errorf("build failure classname = %q, want \"q [integration]\"", got)
+	}
+}
+
+func TestBuildTagsFromArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"test", "./..."}, want: ""},
+		{args: []string{"test", "-tags", "unit,e2e", "./..."}, want: "e2e,unit"},
+		{args: []string{"test", "-tags=integration sqlite", "./..."}, want: "integration,sqlite"},
+	}
+	for _, tt := range tests {
+		if got := buildTagsFromArgs(tt.args); got != tt.want {
+			t.Errorf("buildTagsFromArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
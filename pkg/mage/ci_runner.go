@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -98,6 +99,8 @@ type ciRunner struct {
 	mu        sync.Mutex
 	getCtx    func() context.Context // Function to retrieve context instead of storing it
 	started   bool                   // Track if reporter has been started (prevents duplicate start entries)
+	buildTags string                 // -tags value of the go test run in progress
+	flaky     []CITestFailure        // Failures that passed on a rerun, across every run
 }
 
 // NewCIRunner wraps a CommandRunner with CI capabilities
@@ -107,15 +110,30 @@ func NewCIRunner(base CommandRunner, opts CIRunnerOptions) CIRunner {
 		opts.Detector = NewCIDetector()
 	}
 
-	return &ciRunner{
+	r := &ciRunner{
 		base:     base,
 		mode:     opts.Mode,
-		parser:   NewStreamParser(opts.Mode.ContextLines, opts.Mode.Dedup),
 		reporter: opts.Reporter,
 		detector: opts.Detector,
 		results:  &CIResult{},
 		getCtx:   func() context.Context { return runtimectx.Context() },
 	}
+	r.parser = r.newParser()
+	return r
+}
+
+// newParser creates a parser that stamps the build tags of the current run on every
+// event and failure, and forwards every event to the reporter if it observes events
+func (r *ciRunner) newParser() StreamParser {
+	observer, _ := r.reporter.(TestEventObserver)
+	return NewStreamParserWithOptions(StreamParserOptions{
+		ContextLines: r.mode.ContextLines,
+		Strategy:     StrategySmartCapture,
+		Dedup:        r.mode.Dedup,
+		AdaptiveMode: true,
+		Observer:     observer,
+		BuildTags:    r.currentBuildTags,
+	})
 }
 
 // WithContext returns a context-aware runner.
@@ -123,15 +141,39 @@ func NewCIRunner(base CommandRunner, opts CIRunnerOptions) CIRunner {
 // to prevent race conditions when multiple runners execute concurrently.
 // The reporter is shared (it has its own mutex for thread safety).
 func (r *ciRunner) WithContext(ctx context.Context) CIRunner {
-	return &ciRunner{
+	runner := &ciRunner{
 		base:     r.base,
 		mode:     r.mode,
-		parser:   NewStreamParser(r.mode.ContextLines, r.mode.Dedup), // Fresh parser to avoid race
-		reporter: r.reporter,                                         // Reporter has its own mutex - safe to share
+		reporter: r.reporter, // Reporter has its own mutex - safe to share
 		detector: r.detector,
 		results:  &CIResult{}, // Fresh results to avoid race
 		getCtx:   func() context.Context { return ctx },
 	}
+	runner.parser = runner.newParser() // Fresh parser to avoid race
+	return runner
+}
+
+// currentBuildTags returns the -tags value of the go test run in progress
+func (r *ciRunner) currentBuildTags() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buildTags
+}
+
+// buildTagsFromArgs returns the normalized -tags value of go test arguments
+func buildTagsFromArgs(args []string) string {
+	var value string
+	for i, arg := range args {
+		switch {
+		case (arg == "-tags" || arg == "--tags") && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "-tags="), strings.HasPrefix(arg, "--tags="):
+			_, value, _ = strings.Cut(arg, "=")
+		}
+	}
+	tags := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	slices.Sort(tags)
+	return strings.Join(tags, ",")
 }
 
 // RunCmd executes a command, intercepting go test -json output
@@ -169,6 +211,7 @@ func (r *ciRunner) runTestWithCI(ctx context.Context, name string, args ...strin
 	if shouldStart {
 		r.started = true
 	}
+	r.buildTags = buildTagsFromArgs(args)
 	r.mu.Unlock()
 
 	if shouldStart {
@@ -256,6 +299,13 @@ func (r *ciRunner) runTestWithCI(ctx context.Context, name string, args ...strin
 		r.handleCrash(stderrBuf.String())
 	}
 
+	// Rerun failed tests to tell flaky failures from real ones; a run whose
+	// failures all passed on a rerun is reported as passed
+	if cmdErr != nil && r.mode.FlakyRetries > 0 && r.retryFailures(ctx, args) {
+		fmt.Fprintln(os.Stderr, "Warning: all failures passed on a rerun (flaky); treating the test run as passed")
+		return nil
+	}
+
 	return cmdErr
 }
 
@@ -287,7 +337,7 @@ func (r *ciRunner) collectResults(exitCode int) {
 
 	total, passed, failed, skipped := r.parser.GetStats()
 	uniqueTotal := r.parser.GetUniqueTestCount()
-	failures := r.parser.GetFailures()
+	failures := r.withoutFlaky(r.parser.GetFailures())
 
 	// Determine status. A non-zero exit code with zero parsed failures means the
 	// process failed for a reason the parser doesn't attribute to any package or
@@ -295,7 +345,8 @@ func (r *ciRunner) collectResults(exitCode int) {
 	// report it as an error rather than silently reporting a passed run.
 	status := TestStatusPassed
 	switch {
-	case failed > 0:
+	case failed > 0 && (len(failures) > 0 || len(r.flaky) == 0):
+		// Failures left over from tests found flaky in an earlier run do not count
 		status = TestStatusFailed
 	case exitCode != 0:
 		status = TestStatusError
@@ -311,10 +362,12 @@ func (r *ciRunner) collectResults(exitCode int) {
 			Passed:      passed,
 			Failed:      len(failures), // Use deduplicated count, not raw count which includes parent tests
 			Skipped:     skipped,
+			Flaky:       len(r.flaky),
 			Duration:    formatDurationForSummary(duration),
 			ExitCode:    exitCode,
 		},
 		Failures:  failures,
+		Flaky:     append([]CITestFailure(nil), r.flaky...),
 		Timestamp: r.startTime,
 		Duration:  duration,
 	}
@@ -335,10 +388,11 @@ func (r *ciRunner) handleCrash(stderr string) {
 		strings.Contains(stderr, "panic:") {
 
 		failure := CITestFailure{
-			Type:   FailureTypeFatal,
-			Error:  "Test binary crashed",
-			Output: stderr,
-			Stack:  stderr, // Include full crash dump as stack
+			Type:      FailureTypeFatal,
+			Error:     "Test binary crashed",
+			Output:    stderr,
+			Stack:     stderr, // Include full crash dump as stack
+			BuildTags: r.currentBuildTags(),
 		}
 
 		// Try to extract location from crash dump
@@ -402,6 +456,7 @@ func (r *ciRunner) GenerateReport() error {
 	resultsCopy := &CIResult{
 		Summary:   r.results.Summary,
 		Failures:  make([]CITestFailure, len(r.results.Failures)),
+		Flaky:     append([]CITestFailure(nil), r.results.Flaky...),
 		Timestamp: r.results.Timestamp,
 		Duration:  r.results.Duration,
 		Metadata:  r.results.Metadata,
@@ -459,6 +514,9 @@ func printCIModeBanner(platform string, mode CIMode) {
 		"============================================================",
 		"",
 	}
+	if mode.Format == CIFormatJUnit {
+		lines = slices.Insert(lines, 7, fmt.Sprintf("🧾 JUnit File:     %s", junitOutputPath(mode.OutputPath)))
+	}
 	for _, line := range lines {
 		if _, err := fmt.Fprintln(os.Stdout, line); err != nil {
 			// Log to stderr so users know the banner was truncated
@@ -581,31 +639,49 @@ func GetCIRunner(base CommandRunner, params map[string]string, cfg *Config) Comm
 		}
 	}
 
+	// Add JUnit XML reporter next to the JSON output when requested
+	if mode.Format == CIFormatJUnit {
+		reporters = append(reporters, NewJUnitReporter(junitOutputPath(mode.OutputPath)))
+	}
+
 	// Add platform-specific reporter
+	if platformReporter := newPlatformReporter(detector, mode); platformReporter != nil {
+		reporters = append(reporters, platformReporter)
+	}
+
+	return NewCIRunner(base, CIRunnerOptions{
+		Mode:     mode,
+		Reporter: combineReporters(reporters),
+		Detector: detector,
+	})
+}
+
+// newPlatformReporter returns the GitHub or terminal reporter for the detected platform, or nil.
+// GitHub Actions gets annotations for the github, auto and junit formats; an explicit json
+// format writes the JSON Lines file only.
+func newPlatformReporter(detector CIDetector, mode CIMode) CIReporter {
 	platform := detector.Platform()
-	if mode.Format == CIFormatGitHub || (mode.Format == CIFormatAuto && platform == CIPlatformGitHub) {
-		reporters = append(reporters, NewGitHubReporter())
-	} else if platform == CIPlatformLocal {
+	if mode.Format == CIFormatGitHub ||
+		(platform == CIPlatformGitHub && (mode.Format == CIFormatAuto || mode.Format == CIFormatJUnit)) {
+		return NewGitHubReporter()
+	}
+	if platform == CIPlatformLocal {
 		// Add terminal reporter for local CI mode preview
-		reporters = append(reporters, NewTerminalReporter())
+		return NewTerminalReporter()
 	}
+	return nil
+}
 
-	// Combine reporters
-	var reporter CIReporter
+// combineReporters collapses a list of reporters into a single CIReporter
+func combineReporters(reporters []CIReporter) CIReporter {
 	switch len(reporters) {
 	case 0:
-		reporter = NullReporter{}
+		return NullReporter{}
 	case 1:
-		reporter = reporters[0]
+		return reporters[0]
 	default:
-		reporter = NewMultiReporter(reporters...)
+		return NewMultiReporter(reporters...)
 	}
-
-	return NewCIRunner(base, CIRunnerOptions{
-		Mode:     mode,
-		Reporter: reporter,
-		Detector: detector,
-	})
 }
 
 // PrintCIBannerIfEnabled prints CI mode banner if CI is enabled.
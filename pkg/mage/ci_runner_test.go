@@ -269,6 +269,34 @@ func TestGetCIRunner(t *testing.T) {
 	})
 }
 
+func TestNewPlatformReporter(t *testing.T) {
+	t.Parallel()
+
+	github := newCIDetectorWithEnv(func(key string) string {
+		if key == "GITHUB_ACTIONS" {
+			return trueValue
+		}
+		return ""
+	})
+	local := newCIDetectorWithEnv(func(string) string { return "" })
+
+	// GitHub Actions gets annotations next to the JUnit file, but not for explicit json
+	for _, format := range []CIFormat{CIFormatAuto, CIFormatGitHub, CIFormatJUnit} {
+		if _, ok := newPlatformReporter(github, CIMode{Format: format}).(GitHubReporterInterface); !ok {
+			t.Errorf("newPlatformReporter(github, %s) did not return a GitHub reporter", format)
+		}
+	}
+	if reporter := newPlatformReporter(github, CIMode{Format: CIFormatJSON}); reporter != nil {
+		t.Errorf("newPlatformReporter(github, json) = %T, want nil (JSON Lines file only)", reporter)
+	}
+	if _, ok := newPlatformReporter(local, CIMode{Format: CIFormatGitHub}).(GitHubReporterInterface); !ok {
+		t.Error("newPlatformReporter(local, github) did not return a GitHub reporter")
+	}
+	if _, ok := newPlatformReporter(local, CIMode{Format: CIFormatJUnit}).(GitHubReporterInterface); ok {
+		t.Error("newPlatformReporter(local, junit) returned a GitHub reporter")
+	}
+}
+
 func TestExtractCrashLocation(t *testing.T) {
 	t.Parallel()
 
@@ -623,6 +651,18 @@ func TestGetCIParams(t *testing.T) {
 			wantCIValue:   "true",
 			wantRemaining: 1,
 		},
+		{
+			name:          "ci_format param is consumed",
+			args:          []string{"ci", "ci_format=junit", "-v"},
+			wantCIValue:   "true",
+			wantRemaining: 1,
+		},
+		{
+			name:          "flaky_retries param is consumed",
+			args:          []string{"ci", "flaky_retries=3", "-v"},
+			wantCIValue:   "true",
+			wantRemaining: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -37,6 +37,10 @@ type TestEvent struct {
 	Elapsed     float64 `json:"Elapsed"`
 	Output      string  `json:"Output"`
 	FailedBuild string  `json:"FailedBuild"`
+
+	// BuildTags is the -tags value of the go test run that produced the event.
+	// It is set by the parser from StreamParserOptions.BuildTags, not by go test.
+	BuildTags string `json:"-"`
 }
 
 // TestEventHandler processes test events from go test -json
@@ -159,7 +163,9 @@ type streamParser struct {
 	currentTest   map[string]*testState       // pkg:test -> state
 	buildOutput   map[string]*strings.Builder // ImportPath -> accumulated build-output text
 	dedup         bool
-	adaptiveMode  bool // Whether to adapt strategy based on test count
+	adaptiveMode  bool              // Whether to adapt strategy based on test count
+	observer      TestEventObserver // Optional: receives every parsed event
+	buildTags     func() string     // Optional: -tags value of the go test run being parsed
 }
 
 // maxBuildOutputSize caps accumulated build-output text per import path.
@@ -228,6 +234,8 @@ func NewStreamParserWithOptions(opts StreamParserOptions) StreamParser {
 		buildOutput:   make(map[string]*strings.Builder),
 		dedup:         opts.Dedup,
 		adaptiveMode:  opts.AdaptiveMode,
+		observer:      opts.Observer,
+		buildTags:     opts.BuildTags,
 	}
 }
 
@@ -254,6 +262,8 @@ type StreamParserOptions struct {
 	MaxOutputSize int
 	Dedup         bool
 	AdaptiveMode  bool
+	Observer      TestEventObserver
+	BuildTags     func() string // Returns the -tags value of the run being parsed, stamped on events and failures
 }
 
 // ParseLine processes a single line of JSON output
@@ -270,10 +280,22 @@ func (p *streamParser) ParseLine(line []byte) error {
 		return nil
 	}
 
+	event.BuildTags = p.currentBuildTags()
 	p.processEvent(&event)
+	if p.observer != nil {
+		p.observer.ObserveEvent(event)
+	}
 	return nil
 }
 
+// currentBuildTags returns the -tags value of the run being parsed, or "" when unknown
+func (p *streamParser) currentBuildTags() string {
+	if p.buildTags == nil {
+		return ""
+	}
+	return p.buildTags()
+}
+
 // Parse processes an entire reader of JSON output
 func (p *streamParser) Parse(r io.Reader) error {
 	scanner := bufio.NewScanner(r)
@@ -476,10 +498,11 @@ func (p *streamParser) onBuildFail(pkg, failedBuild string) {
 	p.mu.Unlock()
 
 	failure := CITestFailure{
-		Package: pkg,
-		Type:    FailureTypeBuild,
-		Output:  output,
-		Error:   extractBuildErrorMessage(failedBuild, output),
+		Package:   pkg,
+		Type:      FailureTypeBuild,
+		Output:    output,
+		Error:     extractBuildErrorMessage(failedBuild, output),
+		BuildTags: p.currentBuildTags(),
 	}
 	failure.Signature = generateSignature(pkg, "", "", 0, FailureTypeBuild)
 
@@ -623,11 +646,12 @@ func (p *streamParser) getTestOutput(key string) string {
 // extractFailure extracts failure details from test output
 func (p *streamParser) extractFailure(pkg, test string, elapsed float64, output string) CITestFailure {
 	failure := CITestFailure{
-		Package:  pkg,
-		Test:     test,
-		Output:   output,
-		Type:     FailureTypeTest,
-		Duration: formatDurationSeconds(elapsed),
+		Package:   pkg,
+		Test:      test,
+		Output:    output,
+		Type:      FailureTypeTest,
+		Duration:  formatDurationSeconds(elapsed),
+		BuildTags: p.currentBuildTags(),
 	}
 
 	// Detect failure type and extract location
@@ -23,6 +23,8 @@ const (
 	FailureTypeTimeout FailureType = "timeout"
 	// FailureTypeFatal represents a test binary crash (SIGSEGV, cgo crash)
 	FailureTypeFatal FailureType = "fatal"
+	// FailureTypeLint represents a lint finding (e.g. docs:lint)
+	FailureTypeLint FailureType = "lint"
 )
 
 // FuzzInfo contains fuzz test specific failure details
@@ -43,6 +45,8 @@ const (
 	CIFormatGitHub CIFormat = "github"
 	// CIFormatJSON outputs JSON Lines file only
 	CIFormatJSON CIFormat = "json"
+	// CIFormatJUnit outputs a JUnit XML file alongside the JSON Lines file
+	CIFormatJUnit CIFormat = "junit"
 )
 
 // CIMode represents CI mode configuration
@@ -53,6 +57,7 @@ type CIMode struct {
 	MaxMemoryMB  int      `yaml:"max_memory_mb" json:"max_memory_mb"`
 	Dedup        bool     `yaml:"dedup" json:"dedup"`
 	OutputPath   string   `yaml:"output_path" json:"output_path"`
+	FlakyRetries int      `yaml:"flaky_retries" json:"flaky_retries"` // Reruns of each failed test to detect flakiness (0 disables)
 }
 
 // DefaultCIMode returns default CI configuration
@@ -75,6 +80,9 @@ func (m *CIMode) Validate() error {
 	if m.MaxMemoryMB < 10 || m.MaxMemoryMB > 1000 {
 		return ErrCIMaxMemoryOutOfRange
 	}
+	if m.FlakyRetries < 0 || m.FlakyRetries > MaxFlakyRetries {
+		return ErrCIFlakyRetriesOutOfRange
+	}
 	return nil
 }
 
@@ -98,6 +106,7 @@ type CISummary struct {
 	Passed            int        `json:"passed"`
 	Failed            int        `json:"failed"`
 	Skipped           int        `json:"skipped"`
+	Flaky             int        `json:"flaky,omitempty"` // Failures that passed on a rerun (not counted in Failed)
 	DeadlineTolerated int        `json:"deadline_tolerated,omitempty"`
 	Duration          string     `json:"duration"`
 	// ExitCode is the real `go test` process exit code. It is the ground-truth
@@ -121,6 +130,7 @@ type CIMetadata struct {
 type CIResult struct {
 	Summary   CISummary       `json:"summary"`
 	Failures  []CITestFailure `json:"failures"`
+	Flaky     []CITestFailure `json:"flaky,omitempty"` // Failures that passed on a rerun; not in Failures
 	Timestamp time.Time       `json:"timestamp"`
 	Duration  time.Duration   `json:"duration"`
 	Metadata  CIMetadata      `json:"metadata"`
@@ -146,6 +156,9 @@ type CITestFailure struct {
 	Duration    string      `json:"duration,omitempty"`
 	RaceRelated bool        `json:"race_related,omitempty"`
 	FuzzInfo    *FuzzInfo   `json:"fuzz_info,omitempty"`
+	Flaky       bool        `json:"flaky,omitempty"`      // Passed on at least one rerun
+	Reruns      int         `json:"reruns,omitempty"`     // Reruns performed to classify the failure
+	BuildTags   string      `json:"build_tags,omitempty"` // -tags value of the go test run, empty for an untagged run
 }
 
 // ToTestFailure converts CITestFailure to basic TestFailure for backwards compatibility
@@ -206,6 +219,7 @@ func SelectStrategy(testCount int) CaptureStrategy {
 var (
 	ErrCIContextLinesOutOfRange = ciContextLinesOutOfRangeError{}
 	ErrCIMaxMemoryOutOfRange    = ciMaxMemoryOutOfRangeError{}
+	ErrCIFlakyRetriesOutOfRange = ciFlakyRetriesOutOfRangeError{}
 )
 
 type ciContextLinesOutOfRangeError struct{}
@@ -219,3 +233,9 @@ type ciMaxMemoryOutOfRangeError struct{}
 func (ciMaxMemoryOutOfRangeError) Error() string {
 	return "max_memory_mb must be between 10 and 1000"
 }
+
+type ciFlakyRetriesOutOfRangeError struct{}
+
+func (ciFlakyRetriesOutOfRangeError) Error() string {
+	return "flaky_retries must be between 0 and 10"
+}
@@ -4,6 +4,7 @@ package mage
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -97,3 +98,36 @@ func getVersionFromGit() string {
 
 	return tag
 }
+
+// shouldSkipScanDir reports whether a directory is skipped when scanning project
+// files: hidden directories, vendor, node_modules and testdata
+func shouldSkipScanDir(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	switch name {
+	case "vendor", "node_modules", "testdata":
+		return true
+	}
+	return false
+}
+
+// matchesIgnorePattern reports whether a slash-separated relative path matches an ignore pattern.
+// Patterns match the full path or the base name; a trailing "/**" matches everything below a directory.
+func matchesIgnorePattern(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok && strings.HasPrefix(rel, prefix+"/") {
+			return true
+		}
+		if matched, _ := path.Match(pattern, rel); matched { //nolint:errcheck // invalid patterns never match
+			return true
+		}
+		if matched, _ := path.Match(pattern, path.Base(rel)); matched { //nolint:errcheck // invalid patterns never match
+			return true
+		}
+	}
+	return false
+}
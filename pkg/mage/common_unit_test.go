@@ -218,3 +218,21 @@ func TestGetVersionFromGitUnit(t *testing.T) {
 	assert.NotNil(t, version)
 	// The result depends on git state - could be version, "dev", or ""
 }
+
+func TestMatchesIgnorePattern(t *testing.T) {
+	patterns := []string{"CHANGELOG.md", "docs/generated/**", "*.draft.md"}
+
+	assert.True(t, matchesIgnorePattern("CHANGELOG.md", patterns))
+	assert.True(t, matchesIgnorePattern("sub/CHANGELOG.md", patterns))
+	assert.True(t, matchesIgnorePattern("docs/generated/api/pkg.md", patterns))
+	assert.True(t, matchesIgnorePattern("notes.draft.md", patterns))
+	assert.False(t, matchesIgnorePattern("docs/guide.md", patterns))
+	assert.False(t, matchesIgnorePattern("README.md", nil))
+}
+
+func TestShouldSkipScanDir(t *testing.T) {
+	for _, name := range []string{".git", ".github", "vendor", "node_modules", "testdata"} {
+		assert.True(t, shouldSkipScanDir(name), name)
+	}
+	assert.False(t, shouldSkipScanDir("pkg"))
+}
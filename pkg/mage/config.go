@@ -14,19 +14,27 @@ import (
 
 // Config represents the mage configuration
 type Config struct {
-	AgentOS  AgentOSConfig     `yaml:"agentos"`
-	Bmad     BmadConfig        `yaml:"bmad"`
-	Build    BuildConfig       `yaml:"build"`
-	Docs     DocsConfig        `yaml:"docs"`
-	Download DownloadConfig    `yaml:"download"`
-	Format   FormatConfig      `yaml:"format"`
-	Lint     LintConfig        `yaml:"lint"`
-	Metadata map[string]string `yaml:"metadata,omitempty"`
-	Project  ProjectConfig     `yaml:"project"`
-	Release  ReleaseConfig     `yaml:"release"`
-	Speckit  SpeckitConfig     `yaml:"speckit"`
-	Test     TestConfig        `yaml:"test"`
-	Tools    ToolsConfig       `yaml:"tools"`
+	AgentOS       AgentOSConfig       `yaml:"agentos"`
+	Audit         AuditConfig         `yaml:"audit"`
+	Bmad          BmadConfig          `yaml:"bmad"`
+	Build         BuildConfig         `yaml:"build"`
+	Cache         CacheConfig         `yaml:"cache"`
+	Certs         CertsConfig         `yaml:"certs"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Deps          DepsConfig          `yaml:"deps"`
+	Docs          DocsConfig          `yaml:"docs"`
+	Download      DownloadConfig      `yaml:"download"`
+	Format        FormatConfig        `yaml:"format"`
+	Generate      GenerateConfig      `yaml:"generate"`
+	Hooks         HooksConfig         `yaml:"hooks"`
+	Lint          LintConfig          `yaml:"lint"`
+	Metadata      map[string]string   `yaml:"metadata,omitempty"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Project       ProjectConfig       `yaml:"project"`
+	Release       ReleaseConfig       `yaml:"release"`
+	Speckit       SpeckitConfig       `yaml:"speckit"`
+	Test          TestConfig          `yaml:"test"`
+	Tools         ToolsConfig         `yaml:"tools"`
 }
 
 // ProjectConfig contains project-specific settings
@@ -75,41 +83,72 @@ type PreBuildConfig struct {
 
 // TestConfig contains test-specific settings
 type TestConfig struct {
-	AutoDiscoverBuildTags        bool     `yaml:"auto_discover_build_tags"`
-	AutoDiscoverBuildTagsExclude []string `yaml:"auto_discover_build_tags_exclude"`
-	CombineBuildTags             bool     `yaml:"combine_build_tags"` // Run all discovered tags in a single test pass instead of one pass per tag
-	BenchCPU                     int      `yaml:"bench_cpu"`
-	BenchMem                     bool     `yaml:"bench_mem"`
-	BenchTime                    string   `yaml:"bench_time"`
-	CIMode                       CIMode   `yaml:"ci_mode"`
-	Cover                        bool     `yaml:"cover"`
-	CoverMode                    string   `yaml:"covermode"`
-	CoverPkg                     []string `yaml:"coverpkg"`
-	CoverageExclude              []string `yaml:"coverage_exclude"`
-	ExcludeModules               []string `yaml:"exclude_modules"`
-	FuzzBaselineBuffer           string   `yaml:"fuzz_baseline_buffer"`            // Extra buffer time for fuzz baseline (default: "1m")
-	FuzzBaselineOverheadPerSeed  string   `yaml:"fuzz_baseline_overhead_per_seed"` // Time per seed during baseline (default: "500ms")
-	IntegrationTag               string   `yaml:"integration_tag"`
-	IntegrationTimeout           string   `yaml:"integration_timeout"`
-	Parallel                     int      `yaml:"parallel"`
-	Race                         bool     `yaml:"race"`
-	Short                        bool     `yaml:"short"`
-	Shuffle                      bool     `yaml:"shuffle"`
-	SkipFuzz                     bool     `yaml:"skip_fuzz"`
-	Tags                         string   `yaml:"tags"`
-	Timeout                      string   `yaml:"timeout"`
-	Verbose                      bool     `yaml:"verbose"`
+	AutoDiscoverBuildTags        bool                  `yaml:"auto_discover_build_tags"`
+	AutoDiscoverBuildTagsExclude []string              `yaml:"auto_discover_build_tags_exclude"`
+	CombineBuildTags             bool                  `yaml:"combine_build_tags"` // Run all discovered tags in a single test pass instead of one pass per tag
+	BenchCPU                     int                   `yaml:"bench_cpu"`
+	BenchMem                     bool                  `yaml:"bench_mem"`
+	BenchRegression              BenchRegressionConfig `yaml:"bench_regression"` // Thresholds for bench:regression
+	BenchTime                    string                `yaml:"bench_time"`
+	CIMode                       CIMode                `yaml:"ci_mode"`
+	Cover                        bool                  `yaml:"cover"`
+	CoverMode                    string                `yaml:"covermode"`
+	CoverPkg                     []string              `yaml:"coverpkg"`
+	CoverageExclude              []string              `yaml:"coverage_exclude"`
+	ExcludeModules               []string              `yaml:"exclude_modules"`
+	FuzzBaselineBuffer           string                `yaml:"fuzz_baseline_buffer"`            // Extra buffer time for fuzz baseline (default: "1m")
+	FuzzBaselineOverheadPerSeed  string                `yaml:"fuzz_baseline_overhead_per_seed"` // Time per seed during baseline (default: "500ms")
+	IntegrationTag               string                `yaml:"integration_tag"`
+	IntegrationTimeout           string                `yaml:"integration_timeout"`
+	Parallel                     int                   `yaml:"parallel"`
+	Quarantine                   []string              `yaml:"quarantine"` // Top-level test names skipped in every package (see test:flaky)
+	Race                         bool                  `yaml:"race"`
+	ResultCache                  bool                  `yaml:"result_cache"`     // Skip packages whose tests already passed with identical inputs
+	ResultCacheDir               string                `yaml:"result_cache_dir"` // Test result cache location (default: <user cache dir>/mage-x/test-results)
+	Short                        bool                  `yaml:"short"`
+	Shuffle                      bool                  `yaml:"shuffle"`
+	SkipFuzz                     bool                  `yaml:"skip_fuzz"`
+	Tags                         string                `yaml:"tags"`
+	Timeout                      string                `yaml:"timeout"`
+	Verbose                      bool                  `yaml:"verbose"`
+}
+
+// BenchRegressionConfig contains the benchmark regression gate settings
+type BenchRegressionConfig struct {
+	Alpha      float64                    `yaml:"alpha"`      // Significance level (default: 0.05)
+	Thresholds BenchThresholds            `yaml:"thresholds"` // Allowed increase for every benchmark
+	Benchmarks map[string]BenchThresholds `yaml:"benchmarks"` // Overrides keyed by benchmark name or glob
+	Report     string                     `yaml:"report"`     // JSON report path (default: .mage-x/bench-regression.json)
+}
+
+// BenchThresholds holds the allowed increase per metric in percent. Unset
+// metrics fall back to the next level; a negative value disables the check.
+type BenchThresholds struct {
+	NsPerOp     *float64 `yaml:"ns_op"`
+	BytesPerOp  *float64 `yaml:"b_op"`
+	AllocsPerOp *float64 `yaml:"allocs_op"`
 }
 
 // LintConfig contains linting settings
 type LintConfig struct {
-	DisableLinters  []string `yaml:"disable_linters"`
-	EnableAll       bool     `yaml:"enable_all"`
-	EnableLinters   []string `yaml:"enable_linters"`
-	GolangciVersion string   `yaml:"golangci_version"`
-	SkipDirs        []string `yaml:"skip_dirs"`
-	SkipFiles       []string `yaml:"skip_files"`
-	Timeout         string   `yaml:"timeout"`
+	DisableLinters  []string      `yaml:"disable_linters"`
+	EnableAll       bool          `yaml:"enable_all"`
+	EnableLinters   []string      `yaml:"enable_linters"`
+	GolangciVersion string        `yaml:"golangci_version"`
+	License         LicenseConfig `yaml:"license"` // License header settings (check:license)
+	SkipDirs        []string      `yaml:"skip_dirs"`
+	SkipFiles       []string      `yaml:"skip_files"`
+	Timeout         string        `yaml:"timeout"`
+}
+
+// LicenseConfig contains settings for the license header check
+type LicenseConfig struct {
+	Header       string   `yaml:"header"`        // Header template; {{year}} and {{holder}} are substituted. Empty disables the check
+	Holder       string   `yaml:"holder"`        // Copyright holder for {{holder}}
+	Year         string   `yaml:"year"`          // Year used when inserting headers (default: current year)
+	IncludeShell bool     `yaml:"include_shell"` // Also check *.sh files
+	IncludeYAML  bool     `yaml:"include_yaml"`  // Also check *.yml and *.yaml files
+	Ignore       []string `yaml:"ignore"`        // Glob patterns of files to skip (e.g. "internal/legacy/**")
 }
 
 // ToolsConfig contains tool versions
@@ -121,18 +160,32 @@ type ToolsConfig struct {
 	GolangciLint string            `yaml:"golangci_lint"`
 	Mockgen      string            `yaml:"mockgen"`
 	Swag         string            `yaml:"swag"`
+	BinDir       string            `yaml:"bin_dir"` // Install directory for tools locked in .mage-tools.lock (default: .mage/bin)
 }
 
 // ReleaseConfig contains release settings
 type ReleaseConfig struct {
 	Changelog   bool     `yaml:"changelog"`
 	Draft       bool     `yaml:"draft"`
-	Formats     []string `yaml:"formats"`
+	Formats     []string `yaml:"formats"` // Archive formats for release:package: tar.gz, zip or binary
 	GitHubToken string   `yaml:"github_token_env"`
-	NameTmpl    string   `yaml:"name_template"`
+	NameTmpl    string   `yaml:"name_template"` // Go template for artifact names (default: {{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }})
 	Prerelease  bool     `yaml:"prerelease"`
 }
 
+// DepsConfig contains dependency management settings
+type DepsConfig struct {
+	Licenses LicensePolicyConfig `yaml:"licenses"`
+}
+
+// LicensePolicyConfig contains the dependency license policy enforced by deps:licenses
+type LicensePolicyConfig struct {
+	Allow         []string `yaml:"allow"`           // SPDX IDs permitted in the module graph (empty permits anything not denied)
+	Deny          []string `yaml:"deny"`            // SPDX IDs that fail deps:licenses when found (e.g. AGPL-3.0)
+	Ignore        []string `yaml:"ignore"`          // Module path prefixes excluded from policy checks
+	FailOnUnknown bool     `yaml:"fail_on_unknown"` // Fail when a dependency license cannot be detected
+}
+
 // DownloadConfig contains download retry settings
 type DownloadConfig struct {
 	BackoffMultiplier float64 `yaml:"backoff_multiplier"`
@@ -144,10 +197,27 @@ type DownloadConfig struct {
 	UserAgent         string  `yaml:"user_agent"`
 }
 
+// DatabaseConfig contains database migration settings
+type DatabaseConfig struct {
+	Driver        string `yaml:"driver"`         // database/sql driver name (default: "sqlite")
+	DSN           string `yaml:"dsn"`            // Data source name, e.g. "file:app.db" ($VAR references are expanded)
+	MigrationsDir string `yaml:"migrations_dir"` // Directory of <version>_<name>.up.sql/.down.sql files (default: "db/migrations")
+	SeedsDir      string `yaml:"seeds_dir"`      // Directory of named <seed>.sql files (default: "db/seeds")
+}
+
 // DocsConfig contains documentation settings
 type DocsConfig struct {
-	Tool string `yaml:"tool"` // "pkgsite", "godoc", or "" for auto-detect
-	Port int    `yaml:"port"` // 0 for default port
+	Tool string         `yaml:"tool"` // "pkgsite", "godoc", or "" for auto-detect
+	Port int            `yaml:"port"` // 0 for default port
+	Lint DocsLintConfig `yaml:"lint"` // Native Markdown linter settings (docs:lint)
+}
+
+// DocsLintConfig contains settings for the native Markdown linter
+type DocsLintConfig struct {
+	Disable       []string `yaml:"disable"`         // Rule names to skip (e.g. "line-length")
+	Ignore        []string `yaml:"ignore"`          // Glob patterns of Markdown files to skip (e.g. "CHANGELOG.md", "vendor/**")
+	ListMarker    string   `yaml:"list_marker"`     // "consistent" (default), "-", "*" or "+"
+	MaxLineLength int      `yaml:"max_line_length"` // Maximum line length; 0 disables the rule (default: 120)
 }
 
 // FormatConfig contains formatter-specific settings
@@ -159,6 +229,104 @@ type FormatConfig struct {
 	GoimportsTimeout string `yaml:"goimports_timeout"`
 }
 
+// GenerateConfig contains settings for the Generate namespace
+type GenerateConfig struct {
+	Swagger SwaggerConfig `yaml:"swagger"` // swag settings for generate:swagger
+	OpenAPI OpenAPIConfig `yaml:"openapi"` // Generator and spec files for generate:openapi
+}
+
+// SwaggerConfig contains settings for generating Swagger docs with swag
+type SwaggerConfig struct {
+	Main   string   `yaml:"main"`   // Main package directory or file with the general API annotations (default: project.main, then "main.go")
+	Output string   `yaml:"output"` // Output directory for docs.go, swagger.json and swagger.yaml (default: "docs")
+	Args   []string `yaml:"args"`   // Extra arguments passed to "swag init" (e.g. "--parseDependency")
+}
+
+// OpenAPIConfig contains settings for generating code from OpenAPI specs
+type OpenAPIConfig struct {
+	Generator string        `yaml:"generator"` // Generator command (default: "oapi-codegen")
+	Module    string        `yaml:"module"`    // Module installed when the generator is missing (default: oapi-codegen's module)
+	Version   string        `yaml:"version"`   // Generator version to install (default: "latest")
+	Specs     []OpenAPISpec `yaml:"specs"`     // Spec files to generate code from
+}
+
+// OpenAPISpec is one OpenAPI document and where its generated code goes
+type OpenAPISpec struct {
+	Spec    string   `yaml:"spec"`    // Path to the OpenAPI document
+	Output  string   `yaml:"output"`  // Generated Go file (passed as -o)
+	Package string   `yaml:"package"` // Package name of the generated code (passed as -package)
+	Config  string   `yaml:"config"`  // Generator config file (passed as -config)
+	Args    []string `yaml:"args"`    // Extra generator arguments placed before the spec path
+}
+
+// NotificationsConfig contains settings for command failure notifications
+type NotificationsConfig struct {
+	Enabled     bool                        `yaml:"enabled"`      // Notify the channels when a magex command fails
+	MinDuration string                      `yaml:"min_duration"` // Only notify for commands that ran at least this long outside CI (default: "1m"; "0" notifies every failure)
+	Channels    []NotificationChannelConfig `yaml:"channels"`     // Where notifications are sent
+}
+
+// NotificationChannelConfig configures one notification channel
+type NotificationChannelConfig struct {
+	Name        string            `yaml:"name"`         // Channel name (default: "<type>-<n>")
+	Type        string            `yaml:"type"`         // "slack", "webhook", "console" or "email"
+	URL         string            `yaml:"url"`          // Webhook URL; $VAR references are expanded
+	Format      string            `yaml:"format"`       // Webhook payload: "json" (default) or "slack"
+	Headers     map[string]string `yaml:"headers"`      // Extra webhook headers; $VAR references are expanded
+	SMTPHost    string            `yaml:"smtp_host"`    // Email: SMTP server host
+	SMTPPort    int               `yaml:"smtp_port"`    // Email: SMTP server port (default: 587)
+	Username    string            `yaml:"username"`     // Email: SMTP username
+	PasswordEnv string            `yaml:"password_env"` // Email: environment variable holding the SMTP password
+	From        string            `yaml:"from"`         // Email: sender address
+	To          []string          `yaml:"to"`           // Email: recipient addresses
+}
+
+// AuditConfig contains settings for the persistent command audit log
+type AuditConfig struct {
+	Enabled   bool   `yaml:"enabled"`     // Record every command magex runs (override: MAGE_AUDIT_ENABLED)
+	Path      string `yaml:"path"`        // JSON lines log file (default: ".mage-x/audit.jsonl"; override: MAGE_X_AUDIT_LOG)
+	MaxSizeMB int    `yaml:"max_size_mb"` // Rotate the log once it grows past this size (default: 10)
+	MaxFiles  int    `yaml:"max_files"`   // Rotated logs to keep (default: 5)
+	Retention string `yaml:"retention"`   // Remove rotated logs older than this (default: "2160h"; "0" keeps them)
+}
+
+// CacheConfig contains settings for the build and test result caches
+type CacheConfig struct {
+	Remote RemoteCacheConfig `yaml:"remote"` // Shared remote cache (default: none)
+}
+
+// RemoteCacheConfig selects a shared remote cache. The bearer token and signing key
+// are secrets, so they are only read from MAGE_X_CACHE_REMOTE_TOKEN and
+// MAGE_X_CACHE_REMOTE_SIGNING_KEY.
+type RemoteCacheConfig struct {
+	URL      string `yaml:"url"`       // Base URL; entries are GET/PUT at <url>/<kind>/<hash> (override: MAGE_X_CACHE_REMOTE_URL)
+	ReadOnly bool   `yaml:"read_only"` // Download entries but never upload (override: MAGE_X_CACHE_REMOTE_READ_ONLY)
+	Timeout  string `yaml:"timeout"`   // Per-request timeout (default: "10s"; override: MAGE_X_CACHE_REMOTE_TIMEOUT)
+}
+
+// CertsConfig contains settings for the development CA and certificates install:certs creates
+type CertsConfig struct {
+	Dir          string          `yaml:"dir"`          // Where the CA and certificates are written (default: "certs")
+	KeyType      string          `yaml:"key_type"`     // "ecdsa" (P-256, default) or "rsa"
+	RSABits      int             `yaml:"rsa_bits"`     // RSA key size (default: 2048)
+	Validity     string          `yaml:"validity"`     // Certificate lifetime (default: "8760h")
+	CAValidity   string          `yaml:"ca_validity"`  // CA lifetime (default: "87600h")
+	RenewBefore  string          `yaml:"renew_before"` // Reissue certificates expiring within this window (default: "720h")
+	Certificates []DevCertConfig `yaml:"certificates"` // Certificates to issue (default: one named "server")
+}
+
+// DevCertConfig is one certificate signed by the development CA
+type DevCertConfig struct {
+	Name  string   `yaml:"name"`  // File name without extension (<name>.crt and <name>.key)
+	Hosts []string `yaml:"hosts"` // Hostnames and IPs covered besides localhost, 127.0.0.1 and ::1
+}
+
+// HooksConfig maps git hooks to the magex commands install:githooks runs in them
+type HooksConfig struct {
+	StagedOnly bool                `yaml:"staged_only"` // Format and lint only the staged Go files in pre-commit
+	Commands   map[string][]string `yaml:",inline"`     // Hook name (pre-commit, commit-msg, pre-push, ...) to magex commands
+}
+
 // SpeckitConfig contains spec-kit CLI management settings
 type SpeckitConfig struct {
 	ConstitutionPath string `yaml:"constitution_path"` // Path to constitution file (default: ".specify/memory/constitution.md")
@@ -260,6 +428,7 @@ func cleanConfigValues(config *Config) {
 	config.Test.IntegrationTimeout = env.CleanValue(config.Test.IntegrationTimeout)
 	config.Test.IntegrationTag = env.CleanValue(config.Test.IntegrationTag)
 	config.Test.CoverMode = env.CleanValue(config.Test.CoverMode)
+	config.Test.ResultCacheDir = env.CleanValue(config.Test.ResultCacheDir)
 	config.Test.Tags = env.CleanValue(config.Test.Tags)
 	config.Test.BenchTime = env.CleanValue(config.Test.BenchTime)
 	for i, pkg := range config.Test.CoverPkg {
@@ -284,6 +453,12 @@ func cleanConfigValues(config *Config) {
 	for i, linter := range config.Lint.EnableLinters {
 		config.Lint.EnableLinters[i] = env.CleanValue(linter)
 	}
+	// The header template is kept verbatim: it may legitimately start with "#"
+	config.Lint.License.Holder = env.CleanValue(config.Lint.License.Holder)
+	config.Lint.License.Year = env.CleanValue(config.Lint.License.Year)
+	for i, pattern := range config.Lint.License.Ignore {
+		config.Lint.License.Ignore[i] = env.CleanValue(pattern)
+	}
 
 	// Clean Tools config strings
 	config.Tools.GolangciLint = env.CleanValue(config.Tools.GolangciLint)
@@ -291,10 +466,65 @@ func cleanConfigValues(config *Config) {
 	config.Tools.GoVulnCheck = env.CleanValue(config.Tools.GoVulnCheck)
 	config.Tools.Mockgen = env.CleanValue(config.Tools.Mockgen)
 	config.Tools.Swag = env.CleanValue(config.Tools.Swag)
+	config.Tools.BinDir = env.CleanValue(config.Tools.BinDir)
 	for k, v := range config.Tools.Custom {
 		config.Tools.Custom[k] = env.CleanValue(v)
 	}
 
+	// Clean Generate config strings
+	config.Generate.Swagger.Main = env.CleanValue(config.Generate.Swagger.Main)
+	config.Generate.Swagger.Output = env.CleanValue(config.Generate.Swagger.Output)
+	config.Generate.OpenAPI.Generator = env.CleanValue(config.Generate.OpenAPI.Generator)
+	config.Generate.OpenAPI.Module = env.CleanValue(config.Generate.OpenAPI.Module)
+	config.Generate.OpenAPI.Version = env.CleanValue(config.Generate.OpenAPI.Version)
+	for i := range config.Generate.OpenAPI.Specs {
+		spec := &config.Generate.OpenAPI.Specs[i]
+		spec.Spec = env.CleanValue(spec.Spec)
+		spec.Output = env.CleanValue(spec.Output)
+		spec.Package = env.CleanValue(spec.Package)
+		spec.Config = env.CleanValue(spec.Config)
+	}
+
+	// Clean Notifications config strings (URLs and headers are expanded when channels are built)
+	config.Notifications.MinDuration = env.CleanValue(config.Notifications.MinDuration)
+	for i := range config.Notifications.Channels {
+		channel := &config.Notifications.Channels[i]
+		channel.Name = env.CleanValue(channel.Name)
+		channel.Type = env.CleanValue(channel.Type)
+		channel.Format = env.CleanValue(channel.Format)
+		channel.SMTPHost = env.CleanValue(channel.SMTPHost)
+		channel.Username = env.CleanValue(channel.Username)
+		channel.PasswordEnv = env.CleanValue(channel.PasswordEnv)
+		channel.From = env.CleanValue(channel.From)
+	}
+
+	// Clean Audit config strings
+	config.Audit.Path = env.CleanValue(config.Audit.Path)
+	config.Audit.Retention = env.CleanValue(config.Audit.Retention)
+
+	// Clean Certs config strings
+	config.Cache.Remote.URL = env.CleanValue(config.Cache.Remote.URL)
+	config.Cache.Remote.Timeout = env.CleanValue(config.Cache.Remote.Timeout)
+	config.Certs.Dir = env.CleanValue(config.Certs.Dir)
+	config.Certs.KeyType = env.CleanValue(config.Certs.KeyType)
+	config.Certs.Validity = env.CleanValue(config.Certs.Validity)
+	config.Certs.CAValidity = env.CleanValue(config.Certs.CAValidity)
+	config.Certs.RenewBefore = env.CleanValue(config.Certs.RenewBefore)
+	for i := range config.Certs.Certificates {
+		cert := &config.Certs.Certificates[i]
+		cert.Name = env.CleanValue(cert.Name)
+		for j, host := range cert.Hosts {
+			cert.Hosts[j] = env.CleanValue(host)
+		}
+	}
+
+	// Clean Hooks config strings
+	for _, commands := range config.Hooks.Commands {
+		for i, command := range commands {
+			commands[i] = env.CleanValue(command)
+		}
+	}
+
 	// Clean Release config strings
 	config.Release.GitHubToken = env.CleanValue(config.Release.GitHubToken)
 	config.Release.NameTmpl = env.CleanValue(config.Release.NameTmpl)
@@ -302,11 +532,35 @@ func cleanConfigValues(config *Config) {
 		config.Release.Formats[i] = env.CleanValue(format)
 	}
 
+	// Clean Deps config strings
+	for i, id := range config.Deps.Licenses.Allow {
+		config.Deps.Licenses.Allow[i] = env.CleanValue(id)
+	}
+	for i, id := range config.Deps.Licenses.Deny {
+		config.Deps.Licenses.Deny[i] = env.CleanValue(id)
+	}
+	for i, path := range config.Deps.Licenses.Ignore {
+		config.Deps.Licenses.Ignore[i] = env.CleanValue(path)
+	}
+
+	// Clean Database config strings
+	config.Database.Driver = env.CleanValue(config.Database.Driver)
+	config.Database.DSN = env.CleanValue(config.Database.DSN)
+	config.Database.MigrationsDir = env.CleanValue(config.Database.MigrationsDir)
+	config.Database.SeedsDir = env.CleanValue(config.Database.SeedsDir)
+
 	// Clean Download config strings
 	config.Download.UserAgent = env.CleanValue(config.Download.UserAgent)
 
 	// Clean Docs config strings
 	config.Docs.Tool = env.CleanValue(config.Docs.Tool)
+	config.Docs.Lint.ListMarker = env.CleanValue(config.Docs.Lint.ListMarker)
+	for i, rule := range config.Docs.Lint.Disable {
+		config.Docs.Lint.Disable[i] = env.CleanValue(rule)
+	}
+	for i, pattern := range config.Docs.Lint.Ignore {
+		config.Docs.Lint.Ignore[i] = env.CleanValue(pattern)
+	}
 
 	// Clean Format config strings
 	config.Format.GoimportsTimeout = env.CleanValue(config.Format.GoimportsTimeout)
@@ -387,6 +641,17 @@ func defaultConfig() *Config {
 			GolangciVersion: VersionLatest,
 			Timeout:         "5m",
 		},
+		Database: DatabaseConfig{
+			Driver:        databaseDriverSQLite,
+			MigrationsDir: DefaultDatabaseMigrationsDir,
+			SeedsDir:      DefaultDatabaseSeedsDir,
+		},
+		Docs: DocsConfig{
+			Lint: DocsLintConfig{
+				ListMarker:    DocsLintListMarkerConsistent,
+				MaxLineLength: DefaultDocsLintMaxLineLength,
+			},
+		},
 		Tools: ToolsConfig{
 			GolangciLint: VersionLatest,
 			Fumpt:        VersionLatest,
@@ -519,6 +784,38 @@ func applyEnvOverrides(c *Config) {
 		c.Test.Timeout = v
 	}
 
+	// Test result cache overrides (can enable or disable)
+	if v, ok := env.ParseBool("MAGE_X_TEST_RESULT_CACHE"); ok {
+		c.Test.ResultCache = v
+	}
+	if v := env.MustGet("MAGE_X_TEST_RESULT_CACHE_DIR"); v != "" {
+		c.Test.ResultCacheDir = v
+	}
+
+	// Remote cache overrides
+	if v := env.MustGet("MAGE_X_CACHE_REMOTE_URL"); v != "" {
+		c.Cache.Remote.URL = v
+	}
+	if v, ok := env.ParseBool("MAGE_X_CACHE_REMOTE_READ_ONLY"); ok {
+		c.Cache.Remote.ReadOnly = v
+	}
+	if v := env.MustGet("MAGE_X_CACHE_REMOTE_TIMEOUT"); v != "" {
+		c.Cache.Remote.Timeout = v
+	}
+
+	// Database DSN override (keeps credentials out of .mage.yaml)
+	if v := env.MustGet("MAGE_X_DATABASE_DSN"); v != "" {
+		c.Database.DSN = v
+	}
+
+	// Audit log overrides (can enable or disable)
+	if v, ok := env.ParseBool(EnvMageAuditEnabled); ok {
+		c.Audit.Enabled = v
+	}
+	if v := env.MustGet("MAGE_X_AUDIT_LOG"); v != "" {
+		c.Audit.Path = v
+	}
+
 	// Download config overrides
 	applyDownloadEnvOverrides(&c.Download)
 
@@ -1,6 +1,9 @@
 package mage
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 // Command names
 const (
@@ -25,6 +28,9 @@ const (
 	CmdGoVulnCheck  = "govulncheck"
 	CmdMockgen      = "mockgen"
 	CmdSwag         = "swag"
+	CmdSqlc         = "sqlc"
+	CmdWire         = "wire"
+	CmdGqlgen       = "gqlgen"
 
 	// Shell commands
 	CmdFind  = "find"
@@ -353,3 +359,59 @@ const (
 	DefaultAgentOSSpecsDir   = "agent-os/specs"
 	DefaultAgentOSProductDir = "agent-os/product"
 )
+
+// Docs lint default configuration values
+const (
+	DefaultDocsLintMaxLineLength = 120
+	DocsLintListMarkerConsistent = "consistent"
+)
+
+// Database default configuration values
+const (
+	DefaultDatabaseMigrationsDir = "db/migrations"
+	DefaultDatabaseSeedsDir      = "db/seeds"
+)
+
+// Test result cache default configuration values
+const (
+	DefaultTestResultCacheDir = "mage-x/test-results" // Relative to the user cache directory
+)
+
+// Flaky test detection default configuration values
+const (
+	MaxFlakyRetries         = 10                   // Upper bound for ci_mode.flaky_retries
+	MaxFlakyRerunFailures   = 20                   // Failures rerun per go test invocation; the rest stay unclassified
+	DefaultFlakyHistoryFile = "flaky-history.json" // Written next to the CI output file
+)
+
+// Benchmark regression gate default configuration values
+const (
+	DefaultBenchRegressionAlpha     = 0.05                            // Significance level for the Mann-Whitney U test
+	DefaultBenchRegressionThreshold = 10.0                            // Allowed increase in percent for every metric
+	DefaultBenchRegressionReport    = ".mage-x/bench-regression.json" // JSON comparison report
+	DefaultBenchHistoryDir          = ".mage-x/bench-history"         // One record per commit, branch, Go version and machine
+	MinBenchRegressionSamples       = 5                               // Samples per benchmark the U test needs to reach significance
+)
+
+// Check defaults
+const (
+	DefaultCheckAllReport = ".mage-x/check-all.json" // JSON summary written by check:all
+)
+
+// Tool lock defaults
+const (
+	ToolsLockFile      = ".mage-tools.lock" // Committed record of every tool's module, version and checksums
+	DefaultToolsBinDir = ".mage/bin"        // Project-local install directory for locked tools
+)
+
+// Audit log defaults
+const (
+	DefaultAuditLog       = ".mage-x/audit.jsonl" // JSON lines written when audit.enabled is set
+	DefaultAuditMaxSizeMB = 10                    // Size at which the log is rotated
+	DefaultAuditRetention = 90 * 24 * time.Hour   // Age after which rotated logs are removed
+	DefaultAuditQueryLast = 50                    // Events shown by magex audit without last=
+
+	// EnvAuditCommand carries the magex command being run into every audit event,
+	// including those recorded by mage processes magex delegates to
+	EnvAuditCommand = "MAGE_X_AUDIT_COMMAND"
+)
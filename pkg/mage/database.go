@@ -0,0 +1,865 @@
+package mage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/mage/runtimectx"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for database operations
+var (
+	errDatabaseDSNRequired       = errors.New("database.dsn is not configured in .mage.yaml (or MAGE_X_DATABASE_DSN)")
+	errUnsupportedDatabaseDriver = errors.New("unsupported database driver")
+	errInvalidMigrationDirection = errors.New("invalid migration direction (use up or down)")
+	errInvalidMigrationFile      = errors.New("invalid migration file name")
+	errDuplicateMigration        = errors.New("duplicate migration version")
+	errMissingDownMigration      = errors.New("down migration not found")
+	errMissingMigration          = errors.New("applied migration file is missing")
+	errSeedNotFound              = errors.New("seed file not found")
+	errDatabaseNameRequired      = errors.New("database name is required")
+	errDatabaseOpNotSupported    = errors.New("operation is only supported for sqlite databases")
+	errInvalidDatabaseName       = errors.New("invalid database name")
+)
+
+const (
+	// schemaMigrationsTable records which migrations have been applied
+	schemaMigrationsTable = "schema_migrations"
+	// databaseDriverSQLite is the name the built-in SQLite driver is registered under
+	databaseDriverSQLite = "sqlite"
+)
+
+// migrationFilePattern matches "<version>_<name>.(up|down).sql"
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_\-]+)\.(up|down)\.sql$`)
+	databaseNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Migration is a versioned schema change with optional up and down SQL files
+type Migration struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	UpFile    string     `json:"up_file,omitempty"`
+	DownFile  string     `json:"down_file,omitempty"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// appliedMigration is a row in the schema_migrations table
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// databaseConn bundles an open database with its resolved settings
+type databaseConn struct {
+	db     *sql.DB
+	driver string
+	dsn    string
+	cfg    DatabaseConfig
+}
+
+// Migrate runs database migrations in the specified direction.
+// "up" applies every pending migration; "down" rolls back the most recent one.
+// Append ":N" (e.g. "up:2", "down:3") to limit the number of steps.
+func (db Database) Migrate(direction string) error {
+	utils.Header("Running Database Migrations")
+
+	dir, steps, err := parseMigrationDirection(direction)
+	if err != nil {
+		return err
+	}
+
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	ctx := runtimectx.Context()
+	if dir == "up" {
+		return conn.migrateUp(ctx, steps)
+	}
+	return conn.migrateDown(ctx, steps)
+}
+
+// Seed seeds the database with test data.
+// The seed name maps to <seeds_dir>/<name>.sql; an empty name or "all" runs every seed file in order.
+func (db Database) Seed(seedName string) error {
+	utils.Header("Seeding Database")
+
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	files, err := resolveSeedFiles(conn.cfg.SeedsDir, seedName)
+	if err != nil {
+		return err
+	}
+
+	ctx := runtimectx.Context()
+	for _, file := range files {
+		utils.Info("Running seed %s", filepath.Base(file))
+		if err := conn.execFile(ctx, file); err != nil {
+			return err
+		}
+	}
+
+	utils.Success("Applied %d seed file(s)", len(files))
+	return nil
+}
+
+// Reset resets the database to a clean state.
+// Every applied migration is rolled back and then all migrations are applied again.
+func (db Database) Reset() error {
+	utils.Header("Resetting Database")
+
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	ctx := runtimectx.Context()
+	if err := conn.migrateDown(ctx, 0); err != nil {
+		return err
+	}
+	return conn.migrateUp(ctx, 0)
+}
+
+// Backup creates a backup of the database.
+// SQLite databases are copied with VACUUM INTO, producing a consistent snapshot.
+func (db Database) Backup(backupFile string) error {
+	utils.Header("Backing Up Database")
+
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	if conn.driver != databaseDriverSQLite {
+		return fmt.Errorf("%w: backup (driver %q)", errDatabaseOpNotSupported, conn.driver)
+	}
+
+	if backupFile == "" {
+		backupFile = fmt.Sprintf("backup-%s.db", time.Now().Format("20060102-150405"))
+	}
+	if _, statErr := os.Stat(backupFile); statErr == nil {
+		return fmt.Errorf("backup file %s already exists: %w", backupFile, os.ErrExist)
+	}
+	if dir := filepath.Dir(backupFile); dir != "." {
+		if err := os.MkdirAll(dir, fileops.PermDir); err != nil {
+			return fmt.Errorf("failed to create backup directory: %w", err)
+		}
+	}
+
+	if _, err := conn.db.ExecContext(runtimectx.Context(), "VACUUM INTO ?", backupFile); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	utils.Success("Database backed up to %s", backupFile)
+	return nil
+}
+
+// Restore restores the database from a backup file.
+// The SQLite database file is replaced with the backup.
+func (db Database) Restore(backupFile string) error {
+	utils.Header("Restoring Database")
+
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return err
+	}
+
+	path, err := sqliteFilePath(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(backupFile); err != nil {
+		return fmt.Errorf("backup file not found: %w", err)
+	}
+
+	if err := fileops.New().File.Copy(backupFile, path); err != nil {
+		return fmt.Errorf("failed to restore database: %w", err)
+	}
+
+	// Stale WAL/SHM files would otherwise be replayed over the restored database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s%s: %w", path, suffix, err)
+		}
+	}
+
+	utils.Success("Database restored from %s", backupFile)
+	return nil
+}
+
+// Status checks the database status.
+// It lists every migration with whether it has been applied and when.
+func (db Database) Status() error {
+	utils.Header("Database Migration Status")
+
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	migrations, err := conn.migrationStatus(runtimectx.Context())
+	if err != nil {
+		return err
+	}
+
+	utils.Info("Driver: %s", conn.driver)
+	utils.Info("Migrations: %s", conn.cfg.MigrationsDir)
+
+	if len(migrations) == 0 {
+		utils.Info("No migrations found")
+		return nil
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT") //nolint:errcheck // writes to strings.Builder never fail
+	pending := 0
+	for _, m := range migrations {
+		status, appliedAt := "pending", ""
+		if m.Applied {
+			status = "applied"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Format(time.RFC3339)
+			}
+		} else {
+			pending++
+		}
+		if m.Applied && m.UpFile == "" {
+			status = "applied (missing file)"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", m.Version, m.Name, status, appliedAt) //nolint:errcheck // writes to strings.Builder never fail
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to format status: %w", err)
+	}
+	utils.Print("%s", sb.String())
+
+	utils.Info("%d applied, %d pending", len(migrations)-pending, pending)
+	return nil
+}
+
+// Create creates a new database with the given name.
+// For SQLite the name is a file path; other drivers run CREATE DATABASE.
+func (db Database) Create(dbName string) error {
+	utils.Header("Creating Database")
+
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Driver == databaseDriverSQLite {
+		path := dbName
+		if path == "" {
+			if path, err = sqliteFilePath(cfg); err != nil {
+				return err
+			}
+		}
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, fileops.PermDir); err != nil {
+				return fmt.Errorf("failed to create database directory: %w", err)
+			}
+		}
+		cfg.DSN = "file:" + path
+		conn, err := openDatabaseWithConfig(cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.close()
+		utils.Success("Created database %s", path)
+		return nil
+	}
+
+	return execServerStatement(cfg, "CREATE DATABASE", dbName)
+}
+
+// Drop drops a database with the given name.
+// For SQLite the name is a file path (default: the configured database file).
+func (db Database) Drop(dbName string) error {
+	utils.Header("Dropping Database")
+
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Driver == databaseDriverSQLite {
+		path := dbName
+		if path == "" {
+			if path, err = sqliteFilePath(cfg); err != nil {
+				return err
+			}
+		}
+		for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+			if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s%s: %w", path, suffix, err)
+			}
+		}
+		utils.Success("Dropped database %s", path)
+		return nil
+	}
+
+	return execServerStatement(cfg, "DROP DATABASE", dbName)
+}
+
+// Console opens a database console.
+// SQLite databases are opened with the sqlite3 command-line shell.
+func (db Database) Console() error {
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return err
+	}
+
+	path, err := sqliteFilePath(cfg)
+	if err != nil {
+		return err
+	}
+
+	return GetRunner().RunCmd("sqlite3", path)
+}
+
+// Query executes a database query.
+// Row-returning statements are printed as a table; other statements report rows affected.
+func (db Database) Query(query string) error {
+	conn, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	ctx := runtimectx.Context()
+	if !isRowQuery(query) {
+		result, err := conn.db.ExecContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("query failed: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			affected = 0
+		}
+		utils.Success("Query OK, %d row(s) affected", affected)
+		return nil
+	}
+
+	rows, err := conn.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			utils.Debug("failed to close rows: %v", closeErr)
+		}
+	}()
+
+	var sb strings.Builder
+	count, err := writeQueryRows(&sb, rows)
+	if err != nil {
+		return err
+	}
+	utils.Print("%s", sb.String())
+	utils.Info("%d row(s)", count)
+	return nil
+}
+
+// loadDatabaseConfig returns the database section of the config with defaults applied
+func loadDatabaseConfig() (DatabaseConfig, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return DatabaseConfig{}, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cfg := config.Database
+	cfg.DSN = os.ExpandEnv(cfg.DSN)
+	if cfg.DSN == "" {
+		return cfg, errDatabaseDSNRequired
+	}
+	cfg.Driver = normalizeDatabaseDriver(cfg.Driver)
+	if cfg.MigrationsDir == "" {
+		cfg.MigrationsDir = DefaultDatabaseMigrationsDir
+	}
+	if cfg.SeedsDir == "" {
+		cfg.SeedsDir = DefaultDatabaseSeedsDir
+	}
+	return cfg, nil
+}
+
+// normalizeDatabaseDriver maps common driver aliases onto registered driver names
+func normalizeDatabaseDriver(driver string) string {
+	switch strings.ToLower(driver) {
+	case "", "sqlite", "sqlite3":
+		return databaseDriverSQLite
+	case "postgresql":
+		return "postgres"
+	default:
+		return strings.ToLower(driver)
+	}
+}
+
+// openDatabase opens the configured database
+func openDatabase() (*databaseConn, error) {
+	cfg, err := loadDatabaseConfig()
+	if err != nil {
+		return nil, err
+	}
+	return openDatabaseWithConfig(cfg)
+}
+
+// openDatabaseWithConfig opens a database with any database/sql driver registered in the binary
+func openDatabaseWithConfig(cfg DatabaseConfig) (*databaseConn, error) {
+	if !slices.Contains(sql.Drivers(), cfg.Driver) {
+		return nil, fmt.Errorf("%w: %q (available: %s)", errUnsupportedDatabaseDriver, cfg.Driver, strings.Join(sql.Drivers(), ", "))
+	}
+
+	db, err := sql.Open(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(runtimectx.Context()); err != nil {
+		_ = db.Close() //nolint:errcheck // already returning the ping error
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return &databaseConn{db: db, driver: cfg.Driver, dsn: cfg.DSN, cfg: cfg}, nil
+}
+
+// close closes the underlying database handle
+func (c *databaseConn) close() {
+	if err := c.db.Close(); err != nil {
+		utils.Debug("failed to close database: %v", err)
+	}
+}
+
+// placeholder returns the bind parameter syntax for the driver
+func (c *databaseConn) placeholder(n int) string {
+	switch c.driver {
+	case "postgres", "pgx":
+		return "$" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// ensureMigrationsTable creates the schema_migrations table when missing
+func (c *databaseConn) ensureMigrationsTable(ctx context.Context) error {
+	_, err := c.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+schemaMigrationsTable+
+		" (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at VARCHAR(64) NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("failed to create %s table: %w", schemaMigrationsTable, err)
+	}
+	return nil
+}
+
+// appliedMigrations returns the applied migrations keyed by version
+func (c *databaseConn) appliedMigrations(ctx context.Context) (map[int64]appliedMigration, error) {
+	if err := c.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx, "SELECT version, name, applied_at FROM "+schemaMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schemaMigrationsTable, err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			utils.Debug("failed to close rows: %v", closeErr)
+		}
+	}()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var m appliedMigration
+		var appliedAt string
+		if err := rows.Scan(&m.Version, &m.Name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", schemaMigrationsTable, err)
+		}
+		if t, parseErr := time.Parse(time.RFC3339, appliedAt); parseErr == nil {
+			m.AppliedAt = t
+		}
+		applied[m.Version] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schemaMigrationsTable, err)
+	}
+	return applied, nil
+}
+
+// migrationStatus merges migration files with the schema_migrations table, ordered by version
+func (c *databaseConn) migrationStatus(ctx context.Context) ([]Migration, error) {
+	migrations, err := loadMigrations(c.cfg.MigrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]bool, len(migrations))
+	for i := range migrations {
+		known[migrations[i].Version] = true
+		if a, ok := applied[migrations[i].Version]; ok {
+			migrations[i].Applied = true
+			appliedAt := a.AppliedAt
+			migrations[i].AppliedAt = &appliedAt
+		}
+	}
+
+	// Applied migrations whose files were deleted still show up so they are not silently lost
+	for version, a := range applied {
+		if !known[version] {
+			appliedAt := a.AppliedAt
+			migrations = append(migrations, Migration{Version: version, Name: a.Name, Applied: true, AppliedAt: &appliedAt})
+		}
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// migrateUp applies pending migrations in version order; steps <= 0 applies all
+func (c *databaseConn) migrateUp(ctx context.Context, steps int) error {
+	migrations, err := c.migrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if m.Applied || m.UpFile == "" {
+			continue
+		}
+		if steps > 0 && count >= steps {
+			break
+		}
+		if err := runtimectx.CheckCanceled(); err != nil {
+			return err
+		}
+
+		utils.Info("Applying %d_%s", m.Version, m.Name)
+		if err := c.applyMigration(ctx, m.UpFile,
+			"INSERT INTO "+schemaMigrationsTable+" (version, name, applied_at) VALUES ("+c.placeholder(1)+", "+c.placeholder(2)+", "+c.placeholder(3)+")",
+			m.Version, m.Name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("migration %s failed: %w", filepath.Base(m.UpFile), err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		utils.Success("Database is up to date")
+		return nil
+	}
+	utils.Success("Applied %d migration(s)", count)
+	return nil
+}
+
+// migrateDown rolls back applied migrations in reverse version order; steps <= 0 rolls back all
+func (c *databaseConn) migrateDown(ctx context.Context, steps int) error {
+	migrations, err := c.migrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if !m.Applied {
+			continue
+		}
+		if steps > 0 && count >= steps {
+			break
+		}
+		if err := runtimectx.CheckCanceled(); err != nil {
+			return err
+		}
+		if m.UpFile == "" && m.DownFile == "" {
+			return fmt.Errorf("%w: %d_%s", errMissingMigration, m.Version, m.Name)
+		}
+		if m.DownFile == "" {
+			return fmt.Errorf("%w: %d_%s", errMissingDownMigration, m.Version, m.Name)
+		}
+
+		utils.Info("Rolling back %d_%s", m.Version, m.Name)
+		if err := c.applyMigration(ctx, m.DownFile,
+			"DELETE FROM "+schemaMigrationsTable+" WHERE version = "+c.placeholder(1),
+			m.Version); err != nil {
+			return fmt.Errorf("rollback %s failed: %w", filepath.Base(m.DownFile), err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		utils.Success("No migrations to roll back")
+		return nil
+	}
+	utils.Success("Rolled back %d migration(s)", count)
+	return nil
+}
+
+// applyMigration runs a migration file and its bookkeeping statement in one transaction
+func (c *databaseConn) applyMigration(ctx context.Context, file, bookkeeping string, args ...any) error {
+	content, err := os.ReadFile(file) // #nosec G304 -- migration files come from the configured migrations directory
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() //nolint:errcheck // no-op after a successful commit
+	}()
+
+	if strings.TrimSpace(string(content)) != "" {
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return fmt.Errorf("failed to update %s: %w", schemaMigrationsTable, err)
+	}
+	return tx.Commit()
+}
+
+// execFile runs every statement in a SQL file in one transaction
+func (c *databaseConn) execFile(ctx context.Context, file string) error {
+	content, err := os.ReadFile(file) // #nosec G304 -- seed files come from the configured seeds directory
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() //nolint:errcheck // no-op after a successful commit
+	}()
+
+	if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+		return fmt.Errorf("%s failed: %w", filepath.Base(file), err)
+	}
+	return tx.Commit()
+}
+
+// loadMigrations reads migration files from dir and pairs up/down files by version
+func loadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		m := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("%w: %s (expected <version>_<name>.up.sql or .down.sql)", errInvalidMigrationFile, entry.Name())
+		}
+		version, parseErr := strconv.ParseInt(m[1], 10, 64)
+		if parseErr != nil {
+			return nil, fmt.Errorf("%w: %s: %w", errInvalidMigrationFile, entry.Name(), parseErr)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("%w: %d (%s and %s)", errDuplicateMigration, version, migration.Name, m[2])
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if m[3] == "up" {
+			migration.UpFile = path
+		} else {
+			migration.DownFile = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigrationDirection parses "up", "down", "up:N" or "down:N"
+func parseMigrationDirection(direction string) (string, int, error) {
+	dir, stepStr, hasSteps := strings.Cut(strings.ToLower(strings.TrimSpace(direction)), ":")
+	if dir == "" {
+		dir = "up"
+	}
+	if dir != "up" && dir != "down" {
+		return "", 0, fmt.Errorf("%w: %q", errInvalidMigrationDirection, direction)
+	}
+
+	steps := 0
+	if dir == "down" {
+		steps = 1 // roll back one migration unless told otherwise
+	}
+	if hasSteps {
+		n, err := strconv.Atoi(stepStr)
+		if err != nil || n < 0 {
+			return "", 0, fmt.Errorf("%w: %q", errInvalidMigrationDirection, direction)
+		}
+		steps = n
+	}
+	return dir, steps, nil
+}
+
+// resolveSeedFiles returns the seed file for name, or every seed file for "" / "all"
+func resolveSeedFiles(dir, name string) ([]string, error) {
+	if name == "" || name == "all" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list seed files: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w: no .sql files in %s", errSeedNotFound, dir)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("%w: %s", errSeedNotFound, name)
+	}
+	file := filepath.Join(dir, strings.TrimSuffix(name, ".sql")+".sql")
+	if _, err := os.Stat(file); err != nil {
+		return nil, fmt.Errorf("%w: %s", errSeedNotFound, file)
+	}
+	return []string{file}, nil
+}
+
+// sqliteFilePath extracts the database file path from a SQLite DSN
+func sqliteFilePath(cfg DatabaseConfig) (string, error) {
+	if cfg.Driver != databaseDriverSQLite {
+		return "", fmt.Errorf("%w (driver %q)", errDatabaseOpNotSupported, cfg.Driver)
+	}
+	path := strings.TrimPrefix(cfg.DSN, "file:")
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
+	}
+	if path == "" || path == ":memory:" {
+		return "", fmt.Errorf("%w: in-memory databases have no file", errDatabaseOpNotSupported)
+	}
+	return path, nil
+}
+
+// execServerStatement runs CREATE/DROP DATABASE on a server-based database
+func execServerStatement(cfg DatabaseConfig, statement, name string) error {
+	if name == "" {
+		return errDatabaseNameRequired
+	}
+	if !databaseNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", errInvalidDatabaseName, name)
+	}
+
+	conn, err := openDatabaseWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	// Identifiers cannot be bound as parameters; the name is validated above
+	if _, err := conn.db.ExecContext(runtimectx.Context(), statement+" "+name); err != nil {
+		return fmt.Errorf("%s %s failed: %w", statement, name, err)
+	}
+	utils.Success("%s %s", statement, name)
+	return nil
+}
+
+// isRowQuery reports whether a statement returns rows
+func isRowQuery(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "PRAGMA", "EXPLAIN", "VALUES", "SHOW", "DESCRIBE":
+		return true
+	}
+	return false
+}
+
+// writeQueryRows writes rows as an aligned table and returns the number of rows
+func writeQueryRows(out io.Writer, rows *sql.Rows) (int, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, strings.ToUpper(strings.Join(columns, "\t"))); err != nil {
+		return 0, err
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, fmt.Errorf("failed to read row: %w", err)
+		}
+		cells := make([]string, len(values))
+		for i, v := range values {
+			switch val := v.(type) {
+			case nil:
+				cells[i] = "NULL"
+			case []byte:
+				cells[i] = string(val)
+			default:
+				cells[i] = fmt.Sprint(val)
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to read rows: %w", err)
+	}
+	return count, w.Flush()
+}
@@ -0,0 +1,3 @@
+package mage
+
+import _ "modernc.org/sqlite" // registers the pure Go "sqlite" database/sql driver
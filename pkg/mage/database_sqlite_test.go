@@ -0,0 +1,142 @@
+package mage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countUsers returns the number of rows in the users table
+func countUsers(t *testing.T) int {
+	t.Helper()
+	conn, err := openDatabase()
+	require.NoError(t, err)
+	defer conn.close()
+
+	var count int
+	require.NoError(t, conn.db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM users").Scan(&count))
+	return count
+}
+
+func TestDatabaseMigrate(t *testing.T) {
+	setupTestDatabase(t, testMigrations(), nil)
+	db := Database{}
+
+	require.NoError(t, db.Migrate("up:1"))
+	assert.Equal(t, map[int64]bool{1: true, 2: false}, migrationStates(t))
+
+	require.NoError(t, db.Migrate("up"))
+	assert.Equal(t, map[int64]bool{1: true, 2: true}, migrationStates(t))
+
+	// Re-running is a no-op
+	require.NoError(t, db.Migrate("up"))
+
+	require.NoError(t, db.Migrate("down"))
+	assert.Equal(t, map[int64]bool{1: true, 2: false}, migrationStates(t))
+
+	require.NoError(t, db.Migrate("down:0"))
+	assert.Equal(t, map[int64]bool{1: false, 2: false}, migrationStates(t))
+
+	require.NoError(t, db.Status())
+}
+
+func TestDatabaseMigrateFailureRollsBack(t *testing.T) {
+	migrations := testMigrations()
+	migrations["0003_broken.up.sql"] = "CREATE TABLE posts (id INTEGER PRIMARY KEY);\nNOT VALID SQL;"
+	setupTestDatabase(t, migrations, nil)
+
+	err := Database{}.Migrate("up")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0003_broken")
+
+	// Earlier migrations stay applied; the failed one is not recorded
+	assert.Equal(t, map[int64]bool{1: true, 2: true, 3: false}, migrationStates(t))
+}
+
+func TestDatabaseMigrateDownRequiresDownFile(t *testing.T) {
+	setupTestDatabase(t, map[string]string{
+		"0001_create_users.up.sql": "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+	}, nil)
+
+	require.NoError(t, Database{}.Migrate("up"))
+	err := Database{}.Migrate("down")
+	require.ErrorIs(t, err, errMissingDownMigration)
+}
+
+func TestDatabaseSeedAndReset(t *testing.T) {
+	setupTestDatabase(t, testMigrations(), map[string]string{
+		"01_users.sql": "INSERT INTO users (name, email) VALUES ('alice', 'a@example.com'), ('bob', 'b@example.com');",
+		"02_more.sql":  "INSERT INTO users (name) VALUES ('carol');",
+	})
+	db := Database{}
+
+	require.NoError(t, db.Migrate("up"))
+	require.NoError(t, db.Seed("01_users"))
+	assert.Equal(t, 2, countUsers(t))
+
+	require.NoError(t, db.Seed("all"))
+	assert.Equal(t, 5, countUsers(t))
+
+	require.ErrorIs(t, db.Seed("missing"), errSeedNotFound)
+	require.ErrorIs(t, db.Seed("../01_users"), errSeedNotFound)
+
+	require.NoError(t, db.Reset())
+	assert.Equal(t, 0, countUsers(t))
+	assert.Equal(t, map[int64]bool{1: true, 2: true}, migrationStates(t))
+}
+
+func TestDatabaseBackupRestore(t *testing.T) {
+	cfg := setupTestDatabase(t, testMigrations(), map[string]string{
+		"users.sql": "INSERT INTO users (name) VALUES ('alice');",
+	})
+	db := Database{}
+
+	require.NoError(t, db.Migrate("up"))
+	require.NoError(t, db.Seed("users"))
+
+	backup := filepath.Join(filepath.Dir(cfg.MigrationsDir), "backups", "app.db")
+	require.NoError(t, db.Backup(backup))
+	require.FileExists(t, backup)
+	require.ErrorIs(t, db.Backup(backup), os.ErrExist)
+
+	require.NoError(t, db.Query("DELETE FROM users"))
+	assert.Equal(t, 0, countUsers(t))
+
+	require.NoError(t, db.Restore(backup))
+	assert.Equal(t, 1, countUsers(t))
+}
+
+func TestDatabaseCreateDropQuery(t *testing.T) {
+	cfg := setupTestDatabase(t, testMigrations(), nil)
+	db := Database{}
+
+	path, err := sqliteFilePath(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(""))
+	require.FileExists(t, path)
+
+	require.NoError(t, db.Migrate("up"))
+	require.NoError(t, db.Query("INSERT INTO users (name) VALUES ('alice')"))
+	require.NoError(t, db.Query("SELECT id, name, email FROM users"))
+	require.Error(t, db.Query("SELECT * FROM missing_table"))
+
+	require.NoError(t, db.Drop(""))
+	assert.NoFileExists(t, path)
+}
+
+func TestDatabaseDSNExpandsEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TEST_DB_DIR", dir)
+	cfg := defaultConfig()
+	cfg.Database.DSN = "file:${TEST_DB_DIR}/env.db"
+	TestSetConfig(cfg)
+	defer TestResetConfig()
+
+	require.NoError(t, Database{}.Create(""))
+	assert.FileExists(t, filepath.Join(dir, "env.db"))
+}
@@ -0,0 +1,400 @@
+package mage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestDatabase writes migration and seed files to a temp dir and points the config at a SQLite file there
+func setupTestDatabase(t *testing.T, migrations, seeds map[string]string) DatabaseConfig {
+	t.Helper()
+	dir := t.TempDir()
+
+	cfg := defaultConfig()
+	cfg.Database.DSN = "file:" + filepath.Join(dir, "app.db")
+	cfg.Database.MigrationsDir = filepath.Join(dir, "migrations")
+	cfg.Database.SeedsDir = filepath.Join(dir, "seeds")
+
+	for name, content := range migrations {
+		require.NoError(t, os.MkdirAll(cfg.Database.MigrationsDir, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.Database.MigrationsDir, name), []byte(content), 0o600))
+	}
+	for name, content := range seeds {
+		require.NoError(t, os.MkdirAll(cfg.Database.SeedsDir, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.Database.SeedsDir, name), []byte(content), 0o600))
+	}
+
+	TestSetConfig(cfg)
+	t.Cleanup(TestResetConfig)
+	return cfg.Database
+}
+
+// testMigrations is a small schema with two reversible migrations
+func testMigrations() map[string]string {
+	return map[string]string{
+		"0001_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);",
+		"0001_create_users.down.sql": "DROP TABLE users;",
+		"0002_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email TEXT;\nCREATE INDEX idx_users_email ON users (email);",
+		"0002_add_email.down.sql":    "DROP INDEX idx_users_email;\nALTER TABLE users DROP COLUMN email;",
+	}
+}
+
+// migrationStates returns version -> applied for the configured database
+func migrationStates(t *testing.T) map[int64]bool {
+	t.Helper()
+	conn, err := openDatabase()
+	require.NoError(t, err)
+	defer conn.close()
+
+	migrations, err := conn.migrationStatus(context.Background())
+	require.NoError(t, err)
+
+	states := make(map[int64]bool, len(migrations))
+	for _, m := range migrations {
+		states[m.Version] = m.Applied
+	}
+	return states
+}
+
+// fakeDatabaseDriver is a driver-agnostic database/sql driver for testing the migration
+// engine without SQLite. It understands the schema_migrations bookkeeping statements,
+// records every other statement, and fails statements containing "NOT VALID SQL".
+const fakeDatabaseDriver = "magefake"
+
+var errFakeSQLSyntax = errors.New("fake driver: syntax error")
+
+//nolint:gochecknoglobals // database/sql drivers are registered once per process
+var fakeDatabases = struct {
+	sync.Mutex
+	byDSN map[string]*fakeDatabase
+}{byDSN: make(map[string]*fakeDatabase)}
+
+func init() { //nolint:gochecknoinits // database/sql drivers are registered in init
+	sql.Register(fakeDatabaseDriver, fakeDriver{})
+}
+
+// fakeDatabase is the state shared by every connection to one DSN
+type fakeDatabase struct {
+	mu         sync.Mutex
+	migrations map[int64][]driver.Value
+	statements []string
+}
+
+// clone copies the state so a transaction can be rolled back
+func (d *fakeDatabase) clone() *fakeDatabase {
+	c := &fakeDatabase{migrations: make(map[int64][]driver.Value, len(d.migrations)), statements: slices.Clone(d.statements)}
+	for k, v := range d.migrations {
+		c.migrations[k] = v
+	}
+	return c
+}
+
+// fakeDatabaseFor returns the state for a DSN, creating it on first use
+func fakeDatabaseFor(dsn string) *fakeDatabase {
+	fakeDatabases.Lock()
+	defer fakeDatabases.Unlock()
+	db, ok := fakeDatabases.byDSN[dsn]
+	if !ok {
+		db = &fakeDatabase{migrations: make(map[int64][]driver.Value)}
+		fakeDatabases.byDSN[dsn] = db
+	}
+	return db
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{db: fakeDatabaseFor(dsn)}, nil
+}
+
+type fakeConn struct {
+	db       *fakeDatabase
+	snapshot *fakeDatabase
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: strings.TrimSpace(query)}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	c.snapshot = c.db.clone()
+	c.db.mu.Unlock()
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	if c.snapshot != nil {
+		c.db.mu.Lock()
+		c.db.migrations, c.db.statements = c.snapshot.migrations, c.snapshot.statements
+		c.db.mu.Unlock()
+		c.snapshot = nil
+	}
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	switch {
+	case strings.Contains(s.query, "NOT VALID SQL"):
+		return nil, errFakeSQLSyntax
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS "+schemaMigrationsTable):
+	case strings.HasPrefix(s.query, "INSERT INTO "+schemaMigrationsTable):
+		db.migrations[args[0].(int64)] = args
+	case strings.HasPrefix(s.query, "DELETE FROM "+schemaMigrationsTable):
+		delete(db.migrations, args[0].(int64))
+	default:
+		db.statements = append(db.statements, s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT version, name, applied_at FROM "+schemaMigrationsTable) {
+		return nil, errFakeSQLSyntax
+	}
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	rows := &fakeRows{}
+	for _, row := range db.migrations {
+		rows.values = append(rows.values, row)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version", "name", "applied_at"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// setupFakeDatabase points the config at a fresh fake database and returns its state
+func setupFakeDatabase(t *testing.T, migrations, seeds map[string]string) *fakeDatabase {
+	t.Helper()
+	cfg := setupTestDatabase(t, migrations, seeds)
+	dsn := "fake:" + filepath.Dir(cfg.MigrationsDir)
+
+	config, err := GetConfig()
+	require.NoError(t, err)
+	config.Database.Driver = fakeDatabaseDriver
+	config.Database.DSN = dsn
+	TestSetConfig(config)
+	return fakeDatabaseFor(dsn)
+}
+
+func TestDatabaseEngineMigrate(t *testing.T) {
+	state := setupFakeDatabase(t, testMigrations(), nil)
+	db := Database{}
+
+	require.NoError(t, db.Migrate("up:1"))
+	assert.Equal(t, map[int64]bool{1: true, 2: false}, migrationStates(t))
+
+	require.NoError(t, db.Migrate("up"))
+	assert.Equal(t, map[int64]bool{1: true, 2: true}, migrationStates(t))
+
+	// Re-running is a no-op
+	require.NoError(t, db.Migrate("up"))
+	assert.Len(t, state.statements, 2)
+
+	require.NoError(t, db.Migrate("down"))
+	assert.Equal(t, map[int64]bool{1: true, 2: false}, migrationStates(t))
+
+	require.NoError(t, db.Migrate("down:0"))
+	assert.Equal(t, map[int64]bool{1: false, 2: false}, migrationStates(t))
+	assert.Equal(t, "DROP TABLE users;", state.statements[len(state.statements)-1])
+
+	require.NoError(t, db.Status())
+}
+
+func TestDatabaseEngineFailureRollsBack(t *testing.T) {
+	migrations := testMigrations()
+	migrations["0003_broken.up.sql"] = "CREATE TABLE posts (id INTEGER PRIMARY KEY);\nNOT VALID SQL;"
+	state := setupFakeDatabase(t, migrations, nil)
+
+	err := Database{}.Migrate("up")
+	require.ErrorIs(t, err, errFakeSQLSyntax)
+	assert.Contains(t, err.Error(), "0003_broken")
+
+	// Earlier migrations stay applied; the failed one is not recorded
+	assert.Equal(t, map[int64]bool{1: true, 2: true, 3: false}, migrationStates(t))
+	assert.Len(t, state.statements, 2)
+}
+
+func TestDatabaseEngineDownRequiresFiles(t *testing.T) {
+	t.Run("missing down file", func(t *testing.T) {
+		setupFakeDatabase(t, map[string]string{"0001_create_users.up.sql": "CREATE TABLE users (id INTEGER);"}, nil)
+		require.NoError(t, Database{}.Migrate("up"))
+		require.ErrorIs(t, Database{}.Migrate("down"), errMissingDownMigration)
+	})
+
+	t.Run("deleted migration files", func(t *testing.T) {
+		setupFakeDatabase(t, testMigrations(), nil)
+		require.NoError(t, Database{}.Migrate("up"))
+
+		config, err := GetConfig()
+		require.NoError(t, err)
+		require.NoError(t, os.RemoveAll(config.Database.MigrationsDir))
+
+		// Applied migrations without files are still listed
+		assert.Equal(t, map[int64]bool{1: true, 2: true}, migrationStates(t))
+		require.ErrorIs(t, Database{}.Migrate("down"), errMissingMigration)
+	})
+}
+
+func TestDatabaseEngineSeedAndReset(t *testing.T) {
+	state := setupFakeDatabase(t, testMigrations(), map[string]string{
+		"01_users.sql": "INSERT INTO users (name) VALUES ('alice');",
+		"02_more.sql":  "INSERT INTO users (name) VALUES ('bob');",
+	})
+	db := Database{}
+
+	require.NoError(t, db.Migrate("up"))
+	require.NoError(t, db.Seed("all"))
+	assert.Equal(t, []string{"INSERT INTO users (name) VALUES ('alice');", "INSERT INTO users (name) VALUES ('bob');"}, state.statements[2:])
+	require.ErrorIs(t, db.Seed("missing"), errSeedNotFound)
+
+	require.NoError(t, db.Reset())
+	assert.Equal(t, map[int64]bool{1: true, 2: true}, migrationStates(t))
+	assert.Equal(t, []string{
+		"DROP INDEX idx_users_email;\nALTER TABLE users DROP COLUMN email;",
+		"DROP TABLE users;",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);",
+	}, state.statements[4:7])
+
+	// Backup and restore are SQLite-only
+	require.ErrorIs(t, db.Backup(filepath.Join(t.TempDir(), "backup.db")), errDatabaseOpNotSupported)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("pairs up and down files in version order", func(t *testing.T) {
+		cfg := setupTestDatabase(t, testMigrations(), nil)
+
+		migrations, err := loadMigrations(cfg.MigrationsDir)
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, int64(1), migrations[0].Version)
+		assert.Equal(t, "create_users", migrations[0].Name)
+		assert.NotEmpty(t, migrations[0].UpFile)
+		assert.NotEmpty(t, migrations[0].DownFile)
+		assert.Equal(t, int64(2), migrations[1].Version)
+	})
+
+	t.Run("missing directory has no migrations", func(t *testing.T) {
+		migrations, err := loadMigrations(filepath.Join(t.TempDir(), "missing"))
+		require.NoError(t, err)
+		assert.Empty(t, migrations)
+	})
+
+	t.Run("rejects invalid file names", func(t *testing.T) {
+		cfg := setupTestDatabase(t, map[string]string{"create_users.sql": ""}, nil)
+		_, err := loadMigrations(cfg.MigrationsDir)
+		require.ErrorIs(t, err, errInvalidMigrationFile)
+	})
+
+	t.Run("rejects duplicate versions", func(t *testing.T) {
+		cfg := setupTestDatabase(t, map[string]string{
+			"0001_a.up.sql": "",
+			"0001_b.up.sql": "",
+		}, nil)
+		_, err := loadMigrations(cfg.MigrationsDir)
+		require.ErrorIs(t, err, errDuplicateMigration)
+	})
+}
+
+func TestParseMigrationDirection(t *testing.T) {
+	tests := []struct {
+		input string
+		dir   string
+		steps int
+	}{
+		{input: "", dir: "up", steps: 0},
+		{input: "up", dir: "up", steps: 0},
+		{input: "UP:2", dir: "up", steps: 2},
+		{input: "down", dir: "down", steps: 1},
+		{input: "down:0", dir: "down", steps: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			dir, steps, err := parseMigrationDirection(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.dir, dir)
+			assert.Equal(t, tt.steps, steps)
+		})
+	}
+
+	for _, invalid := range []string{"sideways", "up:x", "down:-1"} {
+		_, _, err := parseMigrationDirection(invalid)
+		require.ErrorIs(t, err, errInvalidMigrationDirection, invalid)
+	}
+}
+
+func TestDatabaseConfigErrors(t *testing.T) {
+	t.Run("missing dsn", func(t *testing.T) {
+		TestSetConfig(defaultConfig())
+		defer TestResetConfig()
+		require.ErrorIs(t, Database{}.Status(), errDatabaseDSNRequired)
+	})
+
+	t.Run("unregistered driver", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Database.Driver = "oracle"
+		cfg.Database.DSN = "oracle://localhost"
+		TestSetConfig(cfg)
+		defer TestResetConfig()
+		require.ErrorIs(t, Database{}.Status(), errUnsupportedDatabaseDriver)
+	})
+}
+
+func TestSQLiteFilePath(t *testing.T) {
+	path, err := sqliteFilePath(DatabaseConfig{Driver: databaseDriverSQLite, DSN: "file:data/app.db?_pragma=foreign_keys(1)"})
+	require.NoError(t, err)
+	assert.Equal(t, "data/app.db", path)
+
+	_, err = sqliteFilePath(DatabaseConfig{Driver: databaseDriverSQLite, DSN: ":memory:"})
+	require.ErrorIs(t, err, errDatabaseOpNotSupported)
+
+	_, err = sqliteFilePath(DatabaseConfig{Driver: "postgres", DSN: "postgres://localhost/app"})
+	require.ErrorIs(t, err, errDatabaseOpNotSupported)
+}
@@ -480,13 +480,6 @@ func (Deps) Init(module string) error {
 	return nil
 }
 
-// Licenses shows dependency licenses
-func (Deps) Licenses() error {
-	utils.Header("Checking Dependency Licenses")
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Checking dependency licenses")
-}
-
 // Check checks for updates
 func (Deps) Check() error {
 	utils.Header("Checking Dependencies")
@@ -0,0 +1,564 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for license scanning
+var (
+	errLicensePolicyViolation = errors.New("dependency license policy violated")
+	errParseModuleList        = errors.New("failed to parse go list output")
+)
+
+// License status values reported by deps:licenses
+const (
+	LicenseStatusAllowed    = "allowed"
+	LicenseStatusDenied     = "denied"
+	LicenseStatusNotAllowed = "not-allowed"
+	LicenseStatusUnknown    = "unknown"
+	LicenseStatusIgnored    = "ignored"
+
+	// LicenseUnknown is reported when no license could be detected for a module
+	LicenseUnknown = "UNKNOWN"
+
+	// maxLicenseFileSize caps how much of a license file is read for matching
+	maxLicenseFileSize = 256 * 1024
+)
+
+// goListModule mirrors the subset of `go list -m -json` output used for license scanning
+type goListModule struct {
+	Path     string        `json:"Path"`
+	Version  string        `json:"Version"`
+	Dir      string        `json:"Dir"`
+	Main     bool          `json:"Main"`
+	Indirect bool          `json:"Indirect"`
+	Replace  *goListModule `json:"Replace"`
+}
+
+// DependencyLicense holds the detected license information for a single module
+type DependencyLicense struct {
+	Module   string   `json:"module"`
+	Version  string   `json:"version,omitempty"`
+	Indirect bool     `json:"indirect"`
+	Licenses []string `json:"licenses"`
+	Files    []string `json:"files,omitempty"`
+	Status   string   `json:"status"`
+}
+
+// LicenseReport is the machine-readable result of deps:licenses
+type LicenseReport struct {
+	Dependencies []DependencyLicense `json:"dependencies"`
+	Total        int                 `json:"total"`
+	Violations   int                 `json:"violations"`
+	Unknown      int                 `json:"unknown"`
+	Allow        []string            `json:"allow,omitempty"`
+	Deny         []string            `json:"deny,omitempty"`
+}
+
+// licenseSignature describes how to recognize a license from its text.
+// Every phrase must appear in the normalized license text for a match.
+type licenseSignature struct {
+	id      string
+	phrases []string
+}
+
+// licenseSignatures returns the known license signatures ordered from most to least specific,
+// so that e.g. BSD-3-Clause is matched before BSD-2-Clause. The GNU family is matched on the
+// title and version line of each license, since the full texts mention one another (GPL-3.0
+// refers to the Affero and Lesser GPL, LGPL-3.0 incorporates GPL-3.0).
+func licenseSignatures() []licenseSignature {
+	return []licenseSignature{
+		{id: "AGPL-3.0", phrases: []string{"gnu affero general public license version 3 19 november 2007"}},
+		{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license version 3 29 june 2007"}},
+		{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license version 2 1 february 1999"}},
+		{id: "LGPL-2.0", phrases: []string{"gnu library general public license version 2 june 1991"}},
+		{id: "GPL-3.0", phrases: []string{"gnu general public license version 3 29 june 2007"}},
+		{id: "GPL-2.0", phrases: []string{"gnu general public license version 2 june 1991"}},
+		{id: "MPL-2.0", phrases: []string{"mozilla public license version 2 0"}},
+		{id: "EPL-2.0", phrases: []string{"eclipse public license v 2 0"}},
+		{id: "Apache-2.0", phrases: []string{"apache license", "version 2 0"}},
+		{id: "BSL-1.0", phrases: []string{"boost software license version 1 0"}},
+		{id: "CC0-1.0", phrases: []string{"cc0 1 0 universal"}},
+		{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+		{id: "Zlib", phrases: []string{"altered source versions must be plainly marked as such", "this notice may not be removed or altered from any source distribution"}},
+		{id: "BSD-3-Clause", phrases: []string{
+			"redistribution and use in source and binary forms with or without modification are permitted",
+			"may be used to endorse or promote products derived from this software",
+		}},
+		{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms with or without modification are permitted"}},
+		{id: "ISC", phrases: []string{"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted"}},
+		{id: "MIT", phrases: []string{"permission is hereby granted free of charge to any person obtaining a copy"}},
+	}
+}
+
+// Licenses shows dependency licenses
+func (Deps) Licenses() error {
+	return (Deps{}).LicensesWithArgs()
+}
+
+// LicensesWithArgs scans the resolved module graph and reports each dependency's license.
+// Licenses are detected from LICENSE/COPYING files in the module cache and checked against
+// the deps.licenses allow/deny policy in .mage.yaml.
+// Supports:
+//   - json: Print the report as JSON for archiving (default: false)
+//   - output: Also write the JSON report to the given file
+func (Deps) LicensesWithArgs(argsList ...string) error {
+	params := utils.ParseParams(argsList)
+	jsonOutput := utils.IsParamTrue(params, "json")
+	outputFile := utils.GetParam(params, "output", "")
+
+	if !jsonOutput {
+		utils.Header("Checking Dependency Licenses")
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	policy := config.Deps.Licenses
+
+	output, err := GetRunner().RunCmdOutput("go", "list", "-m", "-json", "all")
+	if err != nil {
+		return fmt.Errorf("failed to list modules: %w", err)
+	}
+
+	modules, err := parseGoListModules(output)
+	if err != nil {
+		return err
+	}
+
+	report := buildLicenseReport(modules, policy)
+
+	if outputFile != "" {
+		if writeErr := writeLicenseReport(outputFile, report); writeErr != nil {
+			return writeErr
+		}
+	}
+
+	if jsonOutput {
+		jsonBytes, marshalErr := json.MarshalIndent(report, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", marshalErr)
+		}
+		utils.Println(string(jsonBytes))
+	} else {
+		printLicenseReport(report)
+	}
+
+	if report.Violations > 0 {
+		return fmt.Errorf("%w: %d violation(s)", errLicensePolicyViolation, report.Violations)
+	}
+
+	if !jsonOutput {
+		utils.Success("License check passed for %d dependencies", report.Total)
+	}
+	return nil
+}
+
+// parseGoListModules parses the concatenated JSON objects emitted by `go list -m -json all`
+func parseGoListModules(output string) ([]goListModule, error) {
+	var modules []goListModule
+
+	decoder := json.NewDecoder(strings.NewReader(output))
+	for {
+		var m goListModule
+		if err := decoder.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: %w", errParseModuleList, err)
+		}
+		modules = append(modules, m)
+	}
+
+	return modules, nil
+}
+
+// buildLicenseReport detects licenses for every non-main module and applies the policy
+func buildLicenseReport(modules []goListModule, policy LicensePolicyConfig) *LicenseReport {
+	report := &LicenseReport{
+		Dependencies: make([]DependencyLicense, 0, len(modules)),
+		Allow:        policy.Allow,
+		Deny:         policy.Deny,
+	}
+
+	for _, m := range modules {
+		if m.Main {
+			continue
+		}
+
+		dir := m.Dir
+		if dir == "" && m.Replace != nil {
+			dir = m.Replace.Dir
+		}
+
+		dep := DependencyLicense{
+			Module:   m.Path,
+			Version:  m.Version,
+			Indirect: m.Indirect,
+		}
+		if dir != "" {
+			dep.Licenses, dep.Files = detectModuleLicenses(dir)
+		}
+		if len(dep.Licenses) == 0 {
+			dep.Licenses = []string{LicenseUnknown}
+		}
+		dep.Status = evaluateLicensePolicy(dep, policy)
+
+		switch dep.Status {
+		case LicenseStatusDenied, LicenseStatusNotAllowed:
+			report.Violations++
+		case LicenseStatusUnknown:
+			report.Unknown++
+			if policy.FailOnUnknown {
+				report.Violations++
+			}
+		}
+
+		report.Dependencies = append(report.Dependencies, dep)
+	}
+
+	sort.Slice(report.Dependencies, func(i, j int) bool {
+		return report.Dependencies[i].Module < report.Dependencies[j].Module
+	})
+	report.Total = len(report.Dependencies)
+
+	return report
+}
+
+// evaluateLicensePolicy returns the policy status for a dependency.
+// A module is denied if any of its licenses is denied, and not allowed when an
+// allow list is configured and one of its licenses is not on it. A license may
+// be an SPDX expression; it passes when one of its OR alternatives passes.
+func evaluateLicensePolicy(dep DependencyLicense, policy LicensePolicyConfig) string {
+	for _, prefix := range policy.Ignore {
+		if prefix != "" && strings.HasPrefix(dep.Module, prefix) {
+			return LicenseStatusIgnored
+		}
+	}
+
+	if len(dep.Licenses) == 0 || (len(dep.Licenses) == 1 && dep.Licenses[0] == LicenseUnknown) {
+		return LicenseStatusUnknown
+	}
+
+	status := LicenseStatusAllowed
+	for _, license := range dep.Licenses {
+		switch evaluateLicenseExpression(license, policy) {
+		case LicenseStatusDenied:
+			return LicenseStatusDenied
+		case LicenseStatusNotAllowed:
+			status = LicenseStatusNotAllowed
+		}
+	}
+	return status
+}
+
+// evaluateLicenseExpression returns the best status of the alternatives of an
+// SPDX license expression. Every license of an alternative (joined with AND)
+// must pass the policy.
+func evaluateLicenseExpression(expression string, policy LicensePolicyConfig) string {
+	best := LicenseStatusDenied
+	for _, alternative := range licenseAlternatives(expression) {
+		status := LicenseStatusAllowed
+		for _, id := range alternative {
+			if licenseListContains(policy.Deny, id) {
+				status = LicenseStatusDenied
+				break
+			}
+			if len(policy.Allow) > 0 && !licenseListContains(policy.Allow, id) {
+				status = LicenseStatusNotAllowed
+			}
+		}
+		switch {
+		case status == LicenseStatusAllowed:
+			return LicenseStatusAllowed
+		case status == LicenseStatusNotAllowed:
+			best = LicenseStatusNotAllowed
+		}
+	}
+	return best
+}
+
+// licenseAlternatives expands an SPDX license expression into its OR
+// alternatives, each a list of license IDs that apply together. AND binds
+// tighter than OR, parentheses group, and "WITH <exception>" only grants extra
+// permissions, so the exception is dropped. A plain ID is one alternative.
+func licenseAlternatives(expression string) [][]string {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))
+	alternatives, _ := parseLicenseOr(tokens)
+	if len(alternatives) == 0 {
+		return [][]string{{strings.TrimSpace(expression)}}
+	}
+	return alternatives
+}
+
+// parseLicenseOr parses "term AND term OR term ..." up to a closing parenthesis
+// and returns the alternatives with the remaining tokens
+func parseLicenseOr(tokens []string) ([][]string, []string) {
+	var alternatives [][]string
+	current := [][]string{{}}
+	for len(tokens) > 0 {
+		token := tokens[0]
+		tokens = tokens[1:]
+		switch strings.ToUpper(token) {
+		case ")":
+			return append(alternatives, current...), tokens
+		case "OR":
+			alternatives = append(alternatives, current...)
+			current = [][]string{{}}
+		case "AND":
+		case "WITH":
+			if len(tokens) > 0 {
+				tokens = tokens[1:]
+			}
+		default:
+			group := [][]string{{strings.TrimSuffix(token, "+")}}
+			if token == "(" {
+				group, tokens = parseLicenseOr(tokens)
+			}
+			current = combineLicenseAlternatives(current, group)
+		}
+	}
+	return append(alternatives, current...), tokens
+}
+
+// combineLicenseAlternatives returns every alternative of left joined with every alternative of right
+func combineLicenseAlternatives(left, right [][]string) [][]string {
+	combined := make([][]string, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			combined = append(combined, append(slices.Clone(l), r...))
+		}
+	}
+	return combined
+}
+
+// licenseListContains reports whether list contains id. Matching is case-insensitive and
+// a bare family entry (e.g. "AGPL") matches any versioned ID of that family (e.g. "AGPL-3.0").
+func licenseListContains(list []string, id string) bool {
+	for _, entry := range list {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.EqualFold(entry, id) {
+			return true
+		}
+		if !strings.Contains(entry, "-") && strings.HasPrefix(strings.ToUpper(id), strings.ToUpper(entry)+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectModuleLicenses finds license files in a module directory and identifies their SPDX IDs
+func detectModuleLicenses(dir string) (ids, files []string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !isLicenseFileName(entry.Name()) {
+			continue
+		}
+
+		content, readErr := readLicenseFile(filepath.Join(dir, entry.Name()))
+		if readErr != nil {
+			continue
+		}
+		files = append(files, entry.Name())
+
+		for _, id := range detectLicenseIDs(content) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, files
+}
+
+// isLicenseFileName reports whether a file name looks like a license file
+func isLicenseFileName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readLicenseFile reads up to maxLicenseFileSize bytes of a license file
+func readLicenseFile(path string) (string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is a license file inside the Go module cache
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			utils.Debug("failed to close %s: %v", path, closeErr)
+		}
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxLicenseFileSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// detectLicenseIDs identifies SPDX license IDs in a license text.
+// An explicit SPDX-License-Identifier line wins; otherwise the text is matched
+// against known license signatures. A file may contain several licenses.
+func detectLicenseIDs(text string) []string {
+	if expression := spdxIdentifier(text); expression != "" {
+		return []string{expression}
+	}
+
+	normalized := normalizeLicenseText(text)
+	var ids []string
+	for _, sig := range licenseSignatures() {
+		if !containsAllPhrases(normalized, sig.phrases) {
+			continue
+		}
+		// Skip less specific variants of a family that already matched
+		// (e.g. BSD-2-Clause once BSD-3-Clause is detected)
+		if licenseFamilyMatched(ids, sig.id) {
+			continue
+		}
+		ids = append(ids, sig.id)
+	}
+	return ids
+}
+
+// spdxIdentifier extracts the license expression of an SPDX-License-Identifier
+// line (e.g. "MIT OR Apache-2.0"), if present
+func spdxIdentifier(text string) string {
+	const marker = "SPDX-License-Identifier:"
+	idx := strings.Index(text, marker)
+	if idx < 0 {
+		return ""
+	}
+	line := text[idx+len(marker):]
+	if end := strings.IndexAny(line, "\r\n"); end >= 0 {
+		line = line[:end]
+	}
+	line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "*/"))
+	return line
+}
+
+// normalizeLicenseText lowercases text and collapses punctuation and whitespace to single spaces
+func normalizeLicenseText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	lastSpace := true
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastSpace = false
+			continue
+		}
+		if !lastSpace {
+			b.WriteByte(' ')
+			lastSpace = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// containsAllPhrases reports whether text contains every phrase
+func containsAllPhrases(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+// licenseFamilyMatched reports whether a license of the same family as id was already detected
+func licenseFamilyMatched(ids []string, id string) bool {
+	family := licenseFamily(id)
+	for _, existing := range ids {
+		if licenseFamily(existing) == family {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseFamily returns the family portion of an SPDX ID (e.g. "BSD" for "BSD-3-Clause").
+// GPL, LGPL and AGPL are separate families since they call for different policies.
+func licenseFamily(id string) string {
+	if idx := strings.Index(id, "-"); idx > 0 {
+		return id[:idx]
+	}
+	return id
+}
+
+// printLicenseReport prints the license report as a markdown table
+func printLicenseReport(report *LicenseReport) {
+	if report.Total == 0 {
+		utils.Info("No dependencies found")
+		return
+	}
+
+	moduleWidth := len("Module")
+	versionWidth := len("Version")
+	licenseWidth := len("License")
+	for _, dep := range report.Dependencies {
+		moduleWidth = max(moduleWidth, len(dep.Module))
+		versionWidth = max(versionWidth, len(dep.Version))
+		licenseWidth = max(licenseWidth, len(strings.Join(dep.Licenses, ", ")))
+	}
+
+	utils.Println("")
+	utils.Print("| %-*s | %-*s | %-*s | %-11s |\n", moduleWidth, "Module", versionWidth, "Version", licenseWidth, "License", "Status")
+	utils.Print("|%s|%s|%s|%s|\n",
+		strings.Repeat("-", moduleWidth+2),
+		strings.Repeat("-", versionWidth+2),
+		strings.Repeat("-", licenseWidth+2),
+		strings.Repeat("-", 13))
+	for _, dep := range report.Dependencies {
+		utils.Print("| %-*s | %-*s | %-*s | %-11s |\n",
+			moduleWidth, dep.Module,
+			versionWidth, dep.Version,
+			licenseWidth, strings.Join(dep.Licenses, ", "),
+			dep.Status)
+	}
+	utils.Println("")
+
+	if report.Unknown > 0 {
+		utils.Warn("Could not detect a license for %d dependencies (run 'magex deps:download' to populate the module cache)", report.Unknown)
+	}
+	if report.Violations > 0 {
+		utils.Error("Found %d dependency license policy violation(s)", report.Violations)
+	}
+}
+
+// writeLicenseReport writes the JSON license report to a file
+func writeLicenseReport(path string, report *LicenseReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write license report: %w", err)
+	}
+	return nil
+}
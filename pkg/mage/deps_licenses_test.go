@@ -0,0 +1,288 @@
+package mage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMITLicense = `MIT License
+
+Copyright (c) 2024 Example
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.`
+
+	testBSD3License = `Copyright (c) 2009 The Go Authors. All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Neither the name of Google Inc. nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.`
+
+	testGPL2License = `                    GNU GENERAL PUBLIC LICENSE
+                       Version 2, June 1991`
+
+	testApacheLicense = `                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/`
+)
+
+// licensesMockRunner returns canned `go list -m -json all` output
+type licensesMockRunner struct {
+	output string
+	err    error
+}
+
+func (m *licensesMockRunner) RunCmd(_ string, _ ...string) error {
+	return nil
+}
+
+func (m *licensesMockRunner) RunCmdOutput(_ string, _ ...string) (string, error) {
+	return m.output, m.err
+}
+
+// readLicenseFixture returns the complete text of a license from testdata/licenses
+func readLicenseFixture(t *testing.T, id string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "licenses", id+".txt"))
+	require.NoError(t, err)
+	return string(data)
+}
+
+// writeTestModule creates a fake module cache directory with the given license files
+func writeTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestDetectLicenseIDs(t *testing.T) {
+	agpl := readLicenseFixture(t, "AGPL-3.0")
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "MIT", text: testMITLicense, expected: []string{"MIT"}},
+		{name: "BSD-3-Clause wins over BSD-2-Clause", text: testBSD3License, expected: []string{"BSD-3-Clause"}},
+		{name: "Apache", text: testApacheLicense, expected: []string{"Apache-2.0"}},
+		{name: "SPDX identifier", text: "// SPDX-License-Identifier: MPL-2.0\n", expected: []string{"MPL-2.0"}},
+		{name: "SPDX expression", text: "// SPDX-License-Identifier: MIT OR Apache-2.0\n", expected: []string{"MIT OR Apache-2.0"}},
+		{name: "GPL and AGPL in one file", text: agpl + "\n" + testGPL2License, expected: []string{"AGPL-3.0", "GPL-2.0"}},
+		{name: "unrecognized", text: "All rights reserved.", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, detectLicenseIDs(tt.text))
+		})
+	}
+}
+
+func TestDetectLicenseIDsFullGNUTexts(t *testing.T) {
+	// The complete texts refer to one another: GPL-3.0 mentions the Affero and
+	// Lesser GPL, LGPL-3.0 and AGPL-3.0 mention the GPL
+	for _, id := range []string{"GPL-3.0", "LGPL-3.0", "AGPL-3.0"} {
+		t.Run(id, func(t *testing.T) {
+			assert.Equal(t, []string{id}, detectLicenseIDs(readLicenseFixture(t, id)))
+		})
+	}
+
+	t.Run("GPL-3.0 deny policy", func(t *testing.T) {
+		dep := DependencyLicense{Licenses: detectLicenseIDs(readLicenseFixture(t, "GPL-3.0"))}
+		assert.Equal(t, LicenseStatusDenied, evaluateLicensePolicy(dep, LicensePolicyConfig{Deny: []string{"GPL-3.0"}}))
+		assert.Equal(t, LicenseStatusAllowed, evaluateLicensePolicy(dep, LicensePolicyConfig{Deny: []string{"AGPL"}}))
+	})
+}
+
+func TestLicenseAlternatives(t *testing.T) {
+	assert.Equal(t, [][]string{{"MIT"}}, licenseAlternatives("MIT"))
+	assert.Equal(t, [][]string{{"MIT"}, {"Apache-2.0"}}, licenseAlternatives("MIT OR Apache-2.0"))
+	assert.Equal(t, [][]string{{"MIT", "BSD-3-Clause"}, {"Apache-2.0"}}, licenseAlternatives("MIT AND BSD-3-Clause OR Apache-2.0"))
+	assert.Equal(t, [][]string{{"MIT", "ISC"}, {"Apache-2.0", "ISC"}}, licenseAlternatives("(MIT or Apache-2.0) and ISC"))
+	assert.Equal(t, [][]string{{"GPL-2.0"}}, licenseAlternatives("GPL-2.0+ WITH Classpath-exception-2.0"))
+}
+
+func TestIsLicenseFileName(t *testing.T) {
+	for _, name := range []string{"LICENSE", "LICENSE.md", "license.txt", "LICENCE", "COPYING", "COPYING.LESSER", "UNLICENSE"} {
+		assert.True(t, isLicenseFileName(name), name)
+	}
+	for _, name := range []string{"README.md", "go.mod", "NOTICE"} {
+		assert.False(t, isLicenseFileName(name), name)
+	}
+}
+
+func TestEvaluateLicensePolicy(t *testing.T) {
+	dep := func(ids ...string) DependencyLicense {
+		return DependencyLicense{Module: "github.com/example/dep", Licenses: ids}
+	}
+
+	tests := []struct {
+		name     string
+		dep      DependencyLicense
+		policy   LicensePolicyConfig
+		expected string
+	}{
+		{name: "no policy allows", dep: dep("MIT"), expected: LicenseStatusAllowed},
+		{name: "denied", dep: dep("AGPL-3.0"), policy: LicensePolicyConfig{Deny: []string{"AGPL-3.0"}}, expected: LicenseStatusDenied},
+		{name: "family deny", dep: dep("AGPL-3.0"), policy: LicensePolicyConfig{Deny: []string{"agpl"}}, expected: LicenseStatusDenied},
+		{name: "any denied license denies", dep: dep("GPL-3.0", "MIT"), policy: LicensePolicyConfig{Deny: []string{"GPL"}}, expected: LicenseStatusDenied},
+		{name: "allowed", dep: dep("MIT"), policy: LicensePolicyConfig{Allow: []string{"MIT", "Apache-2.0"}}, expected: LicenseStatusAllowed},
+		{name: "not on allow list", dep: dep("MPL-2.0"), policy: LicensePolicyConfig{Allow: []string{"MIT"}}, expected: LicenseStatusNotAllowed},
+		{name: "unknown", dep: dep(LicenseUnknown), policy: LicensePolicyConfig{Allow: []string{"MIT"}}, expected: LicenseStatusUnknown},
+		{name: "ignored", dep: dep("AGPL-3.0"), policy: LicensePolicyConfig{Deny: []string{"AGPL"}, Ignore: []string{"github.com/example/"}}, expected: LicenseStatusIgnored},
+		{name: "GPL family does not cover LGPL", dep: dep("LGPL-3.0"), policy: LicensePolicyConfig{Deny: []string{"GPL", "AGPL"}}, expected: LicenseStatusAllowed},
+		{name: "OR passes when one alternative is allowed", dep: dep("MIT OR Apache-2.0"), policy: LicensePolicyConfig{Allow: []string{"Apache-2.0"}}, expected: LicenseStatusAllowed},
+		{name: "OR with a denied alternative", dep: dep("GPL-3.0 OR MIT"), policy: LicensePolicyConfig{Deny: []string{"GPL"}}, expected: LicenseStatusAllowed},
+		{name: "AND needs every license", dep: dep("MIT AND BSD-3-Clause"), policy: LicensePolicyConfig{Allow: []string{"MIT"}}, expected: LicenseStatusNotAllowed},
+		{name: "AND with a denied license", dep: dep("(MIT OR Apache-2.0) AND AGPL-3.0"), policy: LicensePolicyConfig{Deny: []string{"AGPL"}}, expected: LicenseStatusDenied},
+		{name: "WITH exception", dep: dep("GPL-2.0 WITH Classpath-exception-2.0"), policy: LicensePolicyConfig{Allow: []string{"GPL-2.0"}}, expected: LicenseStatusAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, evaluateLicensePolicy(tt.dep, tt.policy))
+		})
+	}
+}
+
+func TestParseGoListModules(t *testing.T) {
+	t.Run("parses concatenated objects", func(t *testing.T) {
+		output := `{
+	"Path": "example.com/main",
+	"Main": true
+}
+{
+	"Path": "github.com/a/b",
+	"Version": "v1.2.3",
+	"Dir": "/tmp/b",
+	"Indirect": true
+}
+`
+		modules, err := parseGoListModules(output)
+		require.NoError(t, err)
+		require.Len(t, modules, 2)
+		assert.True(t, modules[0].Main)
+		assert.Equal(t, "github.com/a/b", modules[1].Path)
+		assert.Equal(t, "v1.2.3", modules[1].Version)
+		assert.True(t, modules[1].Indirect)
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		modules, err := parseGoListModules("")
+		require.NoError(t, err)
+		assert.Empty(t, modules)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := parseGoListModules("{not json")
+		require.ErrorIs(t, err, errParseModuleList)
+	})
+}
+
+func TestBuildLicenseReport(t *testing.T) {
+	mitDir := writeTestModule(t, map[string]string{"LICENSE": testMITLicense})
+	agplDir := writeTestModule(t, map[string]string{"LICENSE.txt": readLicenseFixture(t, "AGPL-3.0")})
+	emptyDir := writeTestModule(t, map[string]string{"README.md": "no license"})
+
+	modules := []goListModule{
+		{Path: "example.com/main", Main: true},
+		{Path: "github.com/z/mit", Version: "v1.0.0", Dir: mitDir},
+		{Path: "github.com/a/agpl", Version: "v0.1.0", Dir: agplDir, Indirect: true},
+		{Path: "github.com/m/none", Version: "v2.0.0", Dir: emptyDir},
+		{Path: "github.com/r/replaced", Version: "v1.0.0", Replace: &goListModule{Path: "../replaced", Dir: mitDir}},
+	}
+
+	report := buildLicenseReport(modules, LicensePolicyConfig{Deny: []string{"AGPL"}})
+
+	require.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Violations)
+	assert.Equal(t, 1, report.Unknown)
+
+	// Sorted by module path, main module excluded
+	assert.Equal(t, "github.com/a/agpl", report.Dependencies[0].Module)
+	assert.Equal(t, []string{"AGPL-3.0"}, report.Dependencies[0].Licenses)
+	assert.Equal(t, []string{"LICENSE.txt"}, report.Dependencies[0].Files)
+	assert.Equal(t, LicenseStatusDenied, report.Dependencies[0].Status)
+	assert.Equal(t, LicenseStatusUnknown, report.Dependencies[1].Status)
+	assert.Equal(t, []string{"MIT"}, report.Dependencies[2].Licenses)
+	assert.Equal(t, LicenseStatusAllowed, report.Dependencies[3].Status)
+
+	t.Run("fail on unknown", func(t *testing.T) {
+		strict := buildLicenseReport(modules, LicensePolicyConfig{FailOnUnknown: true})
+		assert.Equal(t, 1, strict.Violations)
+	})
+}
+
+func TestDepsLicensesWithArgs(t *testing.T) {
+	mitDir := writeTestModule(t, map[string]string{"LICENSE": testMITLicense})
+	agplDir := writeTestModule(t, map[string]string{"COPYING": readLicenseFixture(t, "AGPL-3.0")})
+
+	listOutput := func(dirs ...string) string {
+		out := `{"Path":"example.com/main","Main":true}`
+		for i, dir := range dirs {
+			b, err := json.Marshal(goListModule{Path: "github.com/dep/" + string(rune('a'+i)), Version: "v1.0.0", Dir: dir})
+			require.NoError(t, err)
+			out += "\n" + string(b)
+		}
+		return out
+	}
+
+	run := func(t *testing.T, runner CommandRunner, cfg *Config, args ...string) error {
+		t.Helper()
+		originalRunner := GetRunner()
+		require.NoError(t, SetRunner(runner))
+		TestSetConfig(cfg)
+		defer func() {
+			_ = SetRunner(originalRunner) //nolint:errcheck // test cleanup
+			TestResetConfig()
+		}()
+		return Deps{}.LicensesWithArgs(args...)
+	}
+
+	t.Run("passes when no license is denied", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Deps.Licenses.Deny = []string{"AGPL"}
+		err := run(t, &licensesMockRunner{output: listOutput(mitDir)}, cfg)
+		require.NoError(t, err)
+	})
+
+	t.Run("fails when a denied license is present", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Deps.Licenses.Deny = []string{"AGPL"}
+		err := run(t, &licensesMockRunner{output: listOutput(mitDir, agplDir)}, cfg, "json=true")
+		require.ErrorIs(t, err, errLicensePolicyViolation)
+	})
+
+	t.Run("writes JSON report", func(t *testing.T) {
+		reportPath := filepath.Join(t.TempDir(), "licenses.json")
+		err := run(t, &licensesMockRunner{output: listOutput(mitDir, agplDir)}, defaultConfig(), "output="+reportPath)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(reportPath) //nolint:gosec // test file path
+		require.NoError(t, err)
+		var report LicenseReport
+		require.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, 0, report.Violations)
+	})
+
+	t.Run("returns go list errors", func(t *testing.T) {
+		err := run(t, &licensesMockRunner{err: assert.AnError}, defaultConfig())
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
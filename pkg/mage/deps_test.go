@@ -712,7 +712,8 @@ func (ts *DepsTestSuite) TestDeps_Init_Error() {
 
 // TestDeps_Licenses tests the Licenses function
 func (ts *DepsTestSuite) TestDeps_Licenses() {
-	ts.env.Runner.On("RunCmd", "echo", []string{"Checking dependency licenses"}).Return(nil)
+	ts.env.Runner.On("RunCmdOutput", "go", []string{"list", "-m", "-json", "all"}).
+		Return(`{"Path":"test/module","Main":true}`, nil)
 
 	err := ts.env.WithMockRunner(
 		func(r any) error {
@@ -868,24 +868,12 @@ func (Docs) Build() error {
 	return nil
 }
 
-// Lint lints the documentation
-func (Docs) Lint() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Linting documentation")
-}
-
 // Spell checks spelling in documentation
 func (Docs) Spell() error {
 	runner := GetRunner()
 	return runner.RunCmd("echo", "Spell checking documentation")
 }
 
-// Links checks links in documentation
-func (Docs) Links() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Checking documentation links")
-}
-
 // API generates API documentation
 func (Docs) API() error {
 	runner := GetRunner()
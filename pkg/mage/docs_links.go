@@ -0,0 +1,514 @@
+package mage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/mrz1836/mage-x/pkg/mage/runtimectx"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// errBrokenDocLinks is returned when docs:links finds broken links
+var errBrokenDocLinks = errors.New("broken documentation links found")
+
+const (
+	// defaultExternalLinkTimeout bounds a single external link check
+	defaultExternalLinkTimeout = 10 * time.Second
+	// defaultExternalLinkWorkers limits concurrent external link checks
+	defaultExternalLinkWorkers = 8
+)
+
+// Markdown link patterns
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var (
+	mdInlineLinkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+	mdRefDefPattern     = regexp.MustCompile(`^\s{0,3}\[[^\]^][^\]]*\]:\s*<?(\S+?)>?(?:\s+.*)?$`)
+	mdAutolinkPattern   = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	mdHTMLLinkPattern   = regexp.MustCompile(`(?i)<(?:a|img)\s[^>]*(?:href|src)\s*=\s*"([^"]+)"`)
+	mdHTMLAnchorPattern = regexp.MustCompile(`(?i)<[a-z]+\s[^>]*(?:name|id)\s*=\s*"([^"]+)"`)
+	mdATXHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?\s*$`)
+	mdCodeSpanPattern   = regexp.MustCompile("`+[^`]*`+")
+	mdHTMLTagPattern    = regexp.MustCompile(`<[^>]+>`)
+)
+
+// markdownLink is a link target found in a Markdown document
+type markdownLink struct {
+	Target string
+	Line   int
+}
+
+// markdownHeading is a heading found in a Markdown document
+type markdownHeading struct {
+	Level int
+	Text  string
+	Line  int
+}
+
+// markdownDocument holds the parts of a Markdown file used by the docs checks
+type markdownDocument struct {
+	Links    []markdownLink
+	Headings []markdownHeading
+	Anchors  map[string]bool
+}
+
+// brokenLink describes a link that could not be resolved
+type brokenLink struct {
+	File   string
+	Line   int
+	Target string
+	Reason string
+}
+
+// externalLink is an external URL referenced from a Markdown file
+type externalLink struct {
+	File string
+	Line int
+	URL  string
+}
+
+// linkCheckResult is the outcome of checking Markdown links
+type linkCheckResult struct {
+	Files    int
+	Links    int
+	External []externalLink
+	Broken   []brokenLink
+}
+
+// Links checks links in documentation
+func (Docs) Links() error {
+	return (Docs{}).LinksWithArgs()
+}
+
+// LinksWithArgs checks every Markdown file for broken relative links and #anchor fragments.
+// The default mode is fully offline; external URLs are only collected.
+// Supports:
+//   - dir: Root directory to scan (default: ".")
+//   - external: Also check external http(s) URLs (default: false)
+//   - timeout: Timeout per external request (default: 10s)
+func (Docs) LinksWithArgs(argsList ...string) error {
+	utils.Header("Checking Documentation Links")
+
+	params := utils.ParseParams(argsList)
+	root := utils.GetParam(params, "dir", ".")
+	checkExternal := utils.IsParamTrue(params, "external")
+
+	timeout := defaultExternalLinkTimeout
+	if v := utils.GetParam(params, "timeout", ""); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", v, err)
+		}
+		timeout = d
+	}
+
+	result, err := checkMarkdownLinks(root)
+	if err != nil {
+		return err
+	}
+
+	if checkExternal && len(result.External) > 0 {
+		utils.Info("Checking %d external links...", len(result.External))
+		client := &http.Client{Timeout: timeout}
+		result.Broken = append(result.Broken, checkExternalLinks(runtimectx.Context(), client, result.External, defaultExternalLinkWorkers)...)
+	}
+
+	sort.Slice(result.Broken, func(i, j int) bool {
+		if result.Broken[i].File != result.Broken[j].File {
+			return result.Broken[i].File < result.Broken[j].File
+		}
+		return result.Broken[i].Line < result.Broken[j].Line
+	})
+
+	utils.Info("Checked %d links in %d Markdown files", result.Links, result.Files)
+	if !checkExternal && len(result.External) > 0 {
+		utils.Info("Skipped %d external links (use external=true to check them)", len(result.External))
+	}
+
+	if len(result.Broken) > 0 {
+		for _, b := range result.Broken {
+			utils.Print("%s:%d: %s (%s)\n", b.File, b.Line, b.Target, b.Reason)
+		}
+		return fmt.Errorf("%w: %d", errBrokenDocLinks, len(result.Broken))
+	}
+
+	utils.Success("No broken links found")
+	return nil
+}
+
+// checkMarkdownLinks parses every Markdown file under root and resolves its local links
+func checkMarkdownLinks(root string) (*linkCheckResult, error) {
+	files, err := findMarkdownFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &linkCheckResult{Files: len(files)}
+	docs := make(map[string]*markdownDocument, len(files))
+
+	loadDoc := func(path string) (*markdownDocument, error) {
+		if doc, ok := docs[path]; ok {
+			return doc, nil
+		}
+		content, readErr := os.ReadFile(path) // #nosec G304 -- path is a Markdown file inside the scanned tree
+		if readErr != nil {
+			return nil, readErr
+		}
+		doc := parseMarkdownDocument(string(content))
+		docs[path] = doc
+		return doc, nil
+	}
+
+	for _, file := range files {
+		doc, loadErr := loadDoc(file)
+		if loadErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, loadErr)
+		}
+
+		for _, link := range doc.Links {
+			result.Links++
+			if isExternalLink(link.Target) {
+				result.External = append(result.External, externalLink{File: file, Line: link.Line, URL: link.Target})
+				continue
+			}
+			if reason := resolveLocalLink(root, file, doc, link.Target, loadDoc); reason != "" {
+				result.Broken = append(result.Broken, brokenLink{File: file, Line: link.Line, Target: link.Target, Reason: reason})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// resolveLocalLink checks a relative link and its fragment, returning a reason when broken
+func resolveLocalLink(root, file string, doc *markdownDocument, target string, loadDoc func(string) (*markdownDocument, error)) string {
+	if isIgnoredLinkScheme(target) {
+		return ""
+	}
+
+	pathPart, fragment, _ := strings.Cut(target, "#")
+	if idx := strings.Index(pathPart, "?"); idx >= 0 {
+		pathPart = pathPart[:idx]
+	}
+
+	if unescaped, err := url.PathUnescape(pathPart); err == nil {
+		pathPart = unescaped
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	// Same-document fragment
+	if pathPart == "" {
+		if fragment == "" || doc.Anchors[strings.ToLower(fragment)] {
+			return ""
+		}
+		return "anchor not found"
+	}
+
+	var resolved string
+	if strings.HasPrefix(pathPart, "/") {
+		resolved = filepath.Join(root, filepath.FromSlash(pathPart))
+	} else {
+		resolved = filepath.Join(filepath.Dir(file), filepath.FromSlash(pathPart))
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "file not found"
+	}
+
+	if fragment == "" || info.IsDir() || !isMarkdownFile(resolved) {
+		return ""
+	}
+
+	targetDoc, err := loadDoc(resolved)
+	if err != nil {
+		return "file not readable"
+	}
+	if !targetDoc.Anchors[strings.ToLower(fragment)] {
+		return "anchor not found"
+	}
+	return ""
+}
+
+// checkExternalLinks requests each unique external URL and reports failures
+func checkExternalLinks(ctx context.Context, client *http.Client, links []externalLink, workers int) []brokenLink {
+	unique := make(map[string][]externalLink)
+	urls := make([]string, 0, len(links))
+	for _, link := range links {
+		if _, ok := unique[link.URL]; !ok {
+			urls = append(urls, link.URL)
+		}
+		unique[link.URL] = append(unique[link.URL], link)
+	}
+
+	var mu sync.Mutex
+	var broken []brokenLink
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(workers, 1))
+	for _, u := range urls {
+		g.Go(func() error {
+			reason := checkExternalURL(gctx, client, u)
+			if reason == "" {
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, link := range unique[u] {
+				broken = append(broken, brokenLink{File: link.File, Line: link.Line, Target: link.URL, Reason: reason})
+			}
+			return nil
+		})
+	}
+	_ = g.Wait() //nolint:errcheck // workers never return errors; failures are collected in broken
+
+	return broken
+}
+
+// checkExternalURL performs a HEAD request (falling back to GET) and returns a reason when the URL is broken
+func checkExternalURL(ctx context.Context, client *http.Client, target string) string {
+	status, err := requestStatus(ctx, client, http.MethodHead, target)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusForbidden || status == http.StatusNotImplemented) {
+		// Some servers reject HEAD; retry with GET before declaring the link broken
+		status, err = requestStatus(ctx, client, http.MethodGet, target)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if status >= http.StatusBadRequest {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	return ""
+}
+
+// requestStatus issues a request and returns the response status code
+func requestStatus(ctx context.Context, client *http.Client, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req) // #nosec G704 -- URL comes from the project's own documentation
+	if err != nil {
+		return 0, err
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		utils.Debug("failed to close response body: %v", closeErr)
+	}
+	return resp.StatusCode, nil
+}
+
+// findMarkdownFiles returns every Markdown file under root, skipping hidden, vendor and dependency directories
+func findMarkdownFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && shouldSkipScanDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isMarkdownFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find markdown files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isMarkdownFile reports whether path has a Markdown extension
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// isExternalLink reports whether a link target is an http(s) URL
+func isExternalLink(target string) bool {
+	lower := strings.ToLower(target)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// isIgnoredLinkScheme reports whether a link uses a scheme that cannot be checked
+func isIgnoredLinkScheme(target string) bool {
+	lower := strings.ToLower(target)
+	for _, scheme := range []string{"mailto:", "tel:", "data:", "ftp://", "javascript:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	// Other URL schemes (e.g. vscode://) are not local paths
+	if idx := strings.Index(lower, "://"); idx > 0 && !strings.ContainsAny(lower[:idx], "/.#") {
+		return true
+	}
+	return false
+}
+
+// parseMarkdownDocument extracts links, headings and anchors from Markdown content.
+// Fenced code blocks, YAML front matter and inline code spans are ignored.
+func parseMarkdownDocument(content string) *markdownDocument {
+	doc := &markdownDocument{Anchors: make(map[string]bool)}
+	slugCounts := make(map[string]int)
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	fence := ""
+	inFrontMatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	prevText := ""
+
+	addHeading := func(level int, text string, line int) {
+		doc.Headings = append(doc.Headings, markdownHeading{Level: level, Text: text, Line: line})
+		slug := markdownHeadingSlug(text)
+		if n, ok := slugCounts[slug]; ok {
+			slugCounts[slug] = n + 1
+			slug = fmt.Sprintf("%s-%d", slug, n+1)
+		} else {
+			slugCounts[slug] = 0
+		}
+		doc.Anchors[slug] = true
+	}
+
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if inFrontMatter {
+			if i > 0 && (strings.TrimSpace(line) == "---" || strings.TrimSpace(line) == "...") {
+				inFrontMatter = false
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if marker := markdownFenceMarker(trimmed); marker != "" {
+			fence = marker
+			prevText = ""
+			continue
+		}
+
+		if m := mdATXHeadingPattern.FindStringSubmatch(line); m != nil {
+			text := strings.TrimSpace(strings.TrimRight(m[2], "#"))
+			addHeading(len(m[1]), text, lineNum)
+		} else if prevText != "" && isSetextUnderline(trimmed) {
+			level := 1
+			if trimmed[0] == '-' {
+				level = 2
+			}
+			addHeading(level, prevText, lineNum-1)
+			prevText = ""
+			continue
+		}
+
+		for _, m := range mdHTMLAnchorPattern.FindAllStringSubmatch(line, -1) {
+			doc.Anchors[strings.ToLower(m[1])] = true
+		}
+
+		scan := mdCodeSpanPattern.ReplaceAllStringFunc(line, func(s string) string {
+			return strings.Repeat(" ", len(s))
+		})
+		for _, pattern := range []*regexp.Regexp{mdInlineLinkPattern, mdAutolinkPattern, mdHTMLLinkPattern} {
+			for _, m := range pattern.FindAllStringSubmatch(scan, -1) {
+				doc.Links = append(doc.Links, markdownLink{Target: m[1], Line: lineNum})
+			}
+		}
+		if m := mdRefDefPattern.FindStringSubmatch(scan); m != nil {
+			doc.Links = append(doc.Links, markdownLink{Target: m[1], Line: lineNum})
+		}
+
+		if isMarkdownParagraphLine(trimmed) {
+			prevText = trimmed
+		} else {
+			prevText = ""
+		}
+	}
+
+	return doc
+}
+
+// markdownFenceMarker returns the fence marker (``` or ~~~) opening a code block, or ""
+func markdownFenceMarker(trimmed string) string {
+	for _, ch := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, ch+ch+ch) {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+			return strings.Repeat(ch, n)
+		}
+	}
+	return ""
+}
+
+// isMarkdownParagraphLine reports whether a line is plain paragraph text that
+// could be turned into a heading by a setext underline on the next line
+func isMarkdownParagraphLine(trimmed string) bool {
+	if trimmed == "" {
+		return false
+	}
+	switch trimmed[0] {
+	case '#', '>', '|', '<', '-', '*', '+', '=':
+		return false
+	}
+	if i := strings.IndexAny(trimmed, ".)"); i > 0 && i < 10 && strings.Trim(trimmed[:i], "0123456789") == "" {
+		return false // ordered list item
+	}
+	return true
+}
+
+// isSetextUnderline reports whether a line is a setext heading underline (=== or ---)
+func isSetextUnderline(trimmed string) bool {
+	if trimmed == "" {
+		return false
+	}
+	return strings.Trim(trimmed, "=") == "" || (len(trimmed) >= 2 && strings.Trim(trimmed, "-") == "")
+}
+
+// markdownHeadingSlug generates a GitHub-compatible anchor slug for heading text
+func markdownHeadingSlug(text string) string {
+	// Render links and images as their text, and drop inline HTML
+	text = mdInlineLinkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		start := strings.Index(s, "[")
+		end := strings.Index(s, "]")
+		if start >= 0 && end > start {
+			return s[start+1 : end]
+		}
+		return s
+	})
+	text = mdHTMLTagPattern.ReplaceAllString(text, "")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), r == '_', r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package mage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMarkdownTree creates Markdown files under a temporary root
+func writeMarkdownTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return root
+}
+
+func TestMarkdownHeadingSlug(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "Getting Started", expected: "getting-started"},
+		{text: "What's New?", expected: "whats-new"},
+		{text: "`go test` Options", expected: "go-test-options"},
+		{text: "📦 Dependency Configuration", expected: "-dependency-configuration"},
+		{text: "[Link](http://example.com) Heading", expected: "link-heading"},
+		{text: "snake_case and-dash", expected: "snake_case-and-dash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, markdownHeadingSlug(tt.text))
+		})
+	}
+}
+
+func TestParseMarkdownDocument(t *testing.T) {
+	content := `---
+title: "[not](a-link.md)"
+---
+# Title
+
+See [guide](guide.md#setup) and <https://example.com>.
+
+` + "```go" + `
+// [ignored](missing.md)
+# not a heading
+` + "```" + `
+
+Use ` + "`[code](missing.md)`" + ` inline.
+
+Setext Heading
+--------------
+
+## Title
+
+<a name="custom-anchor"></a>
+
+[ref]: other.md
+`
+	doc := parseMarkdownDocument(content)
+
+	targets := make([]string, 0, len(doc.Links))
+	for _, link := range doc.Links {
+		targets = append(targets, link.Target)
+	}
+	assert.Equal(t, []string{"guide.md#setup", "https://example.com", "other.md"}, targets)
+	assert.Equal(t, 6, doc.Links[0].Line)
+
+	require.Len(t, doc.Headings, 3)
+	assert.Equal(t, markdownHeading{Level: 1, Text: "Title", Line: 4}, doc.Headings[0])
+	assert.Equal(t, markdownHeading{Level: 2, Text: "Setext Heading", Line: 15}, doc.Headings[1])
+
+	for _, anchor := range []string{"title", "title-1", "setext-heading", "custom-anchor"} {
+		assert.True(t, doc.Anchors[anchor], anchor)
+	}
+	assert.False(t, doc.Anchors["not-a-heading"])
+}
+
+func TestCheckMarkdownLinks(t *testing.T) {
+	root := writeMarkdownTree(t, map[string]string{
+		"README.md": `# Project
+
+- [Docs](docs/guide.md#installation)
+- [Missing anchor](docs/guide.md#nope)
+- [Missing file](docs/missing.md)
+- [Local section](#project)
+- [Bad local section](#unknown)
+- [Directory](docs/)
+- [Email](mailto:dev@example.com)
+- [External](https://example.com/page)
+`,
+		"docs/guide.md": `# Guide
+
+## Installation
+
+[Back](../README.md#project)
+`,
+		"node_modules/pkg/README.md": "[broken](nowhere.md)",
+	})
+
+	result, err := checkMarkdownLinks(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, 9, result.Links)
+	require.Len(t, result.External, 1)
+	assert.Equal(t, "https://example.com/page", result.External[0].URL)
+
+	broken := make(map[string]string, len(result.Broken))
+	for _, b := range result.Broken {
+		broken[b.Target] = b.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"docs/guide.md#nope": "anchor not found",
+		"docs/missing.md":    "file not found",
+		"#unknown":           "anchor not found",
+	}, broken)
+}
+
+func TestCheckExternalLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/head-not-allowed":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	links := []externalLink{
+		{File: "README.md", Line: 1, URL: server.URL + "/ok"},
+		{File: "README.md", Line: 2, URL: server.URL + "/head-not-allowed"},
+		{File: "README.md", Line: 3, URL: server.URL + "/missing"},
+		{File: "docs/guide.md", Line: 7, URL: server.URL + "/missing"},
+	}
+
+	broken := checkExternalLinks(context.Background(), server.Client(), links, 2)
+	require.Len(t, broken, 2)
+	for _, b := range broken {
+		assert.Equal(t, server.URL+"/missing", b.Target)
+		assert.Equal(t, "HTTP 404", b.Reason)
+	}
+}
+
+func TestDocsLinksWithArgs(t *testing.T) {
+	t.Run("passes with valid links", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md":    "# Readme\n\n[Guide](guide.md#guide)\n",
+			"guide.md":     "# Guide\n",
+			"CHANGELOG.md": "[External](https://example.invalid)\n",
+		})
+		require.NoError(t, Docs{}.LinksWithArgs("dir="+root))
+	})
+
+	t.Run("fails with broken links", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md": "[Guide](guide.md)\n",
+		})
+		err := Docs{}.LinksWithArgs("dir=" + root)
+		require.ErrorIs(t, err, errBrokenDocLinks)
+	})
+
+	t.Run("rejects invalid timeout", func(t *testing.T) {
+		err := Docs{}.LinksWithArgs("dir="+t.TempDir(), "timeout=soon")
+		require.Error(t, err)
+	})
+}
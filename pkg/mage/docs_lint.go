@@ -0,0 +1,381 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for the Markdown linter
+var (
+	errMarkdownLintIssues    = errors.New("markdown lint issues found")
+	errInvalidDocsListMarker = errors.New("invalid docs.lint.list_marker")
+	errLintReportFail        = errors.New("failed to report lint findings")
+)
+
+// Markdown lint rule names
+const (
+	MarkdownRuleHeadingIncrement = "heading-increment"
+	MarkdownRuleDuplicateHeading = "no-duplicate-heading"
+	MarkdownRuleTrailingSpaces   = "no-trailing-spaces"
+	MarkdownRuleFenceLanguage    = "fenced-code-language"
+	MarkdownRuleListMarker       = "list-marker-style"
+	MarkdownRuleLineLength       = "line-length"
+)
+
+// docsLintReportFile is the JSON Lines report written next to the CI results when ci_format=json
+const docsLintReportFile = "docs-lint.jsonl"
+
+// mdListItemPattern matches an unordered list item and captures its marker
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var mdListItemPattern = regexp.MustCompile(`^(\s*)([-*+])\s+\S`)
+
+// markdownLintIssue is a single finding reported by docs:lint
+type markdownLintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as file:line:column: [rule] message
+func (i markdownLintIssue) String() string {
+	location := fmt.Sprintf("%s:%d", i.File, i.Line)
+	if i.Column > 0 {
+		location += fmt.Sprintf(":%d", i.Column)
+	}
+	return fmt.Sprintf("%s: [%s] %s", location, i.Rule, i.Message)
+}
+
+// toCIFailure converts the issue into a CITestFailure for the CI reporters
+func (i markdownLintIssue) toCIFailure() CITestFailure {
+	return CITestFailure{
+		Package:   "docs",
+		Test:      i.Rule,
+		Error:     i.Message,
+		Output:    i.String(),
+		Type:      FailureTypeLint,
+		File:      i.File,
+		Line:      i.Line,
+		Column:    i.Column,
+		Signature: fmt.Sprintf("%s:%d:%s", i.File, i.Line, i.Rule),
+	}
+}
+
+// Lint lints the documentation
+func (Docs) Lint() error {
+	return (Docs{}).LintWithArgs()
+}
+
+// LintWithArgs runs the built-in Markdown linter over every Markdown file.
+// Rules are configured in the docs.lint section of .mage.yaml; under ci=true
+// issues are emitted through the CI reporters (GitHub annotations in Actions).
+// Supports:
+//   - dir: Root directory to scan (default: ".")
+//   - ci: Enable CI mode reporting (auto-detected in CI environments)
+//   - ci_format: CI output format (github, json, auto)
+func (Docs) LintWithArgs(argsList ...string) error {
+	utils.Header("Linting Documentation")
+	start := time.Now()
+
+	params := utils.ParseParams(argsList)
+	root := utils.GetParam(params, "dir", ".")
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	lintCfg := config.Docs.Lint
+
+	switch lintCfg.ListMarker {
+	case "", DocsLintListMarkerConsistent, "-", "*", "+":
+	default:
+		return fmt.Errorf("%w: %q (use consistent, -, * or +)", errInvalidDocsListMarker, lintCfg.ListMarker)
+	}
+
+	files, err := findMarkdownFiles(root)
+	if err != nil {
+		return err
+	}
+
+	issues, checked, err := lintMarkdownFiles(root, files, lintCfg)
+	if err != nil {
+		return err
+	}
+
+	detector := NewCIDetector()
+	mode := detector.GetConfig(params, config)
+	var platformReporter CIReporter
+	if mode.Enabled {
+		platformReporter = newPlatformReporter(detector, mode)
+		if reportErr := reportMarkdownLintIssues(newLintReporter(platformReporter, mode, docsLintReportFile), ciMetadata(detector), checked, issues, time.Since(start)); reportErr != nil {
+			return reportErr
+		}
+	}
+
+	if platformReporter == nil {
+		for _, issue := range issues {
+			utils.Println(issue.String())
+		}
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %d in %d files", errMarkdownLintIssues, len(issues), checked)
+	}
+
+	utils.Success("No Markdown lint issues found in %d files", checked)
+	return nil
+}
+
+// lintMarkdownFiles lints each file that is not ignored and returns the issues and number of files checked
+func lintMarkdownFiles(root string, files []string, cfg DocsLintConfig) ([]markdownLintIssue, int, error) {
+	var issues []markdownLintIssue
+	checked := 0
+
+	for _, file := range files {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			rel = file
+		}
+		if matchesIgnorePattern(filepath.ToSlash(rel), cfg.Ignore) {
+			continue
+		}
+
+		content, err := os.ReadFile(file) // #nosec G304 -- path is a Markdown file inside the scanned tree
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		checked++
+
+		for _, issue := range lintMarkdown(string(content), cfg) {
+			issue.File = file
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, checked, nil
+}
+
+// lintMarkdown runs every enabled rule against the content of a single Markdown file
+func lintMarkdown(content string, cfg DocsLintConfig) []markdownLintIssue {
+	enabled := func(rule string) bool {
+		for _, disabled := range cfg.Disable {
+			if strings.EqualFold(disabled, rule) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var issues []markdownLintIssue
+	report := func(rule string, line, column int, format string, args ...any) {
+		if enabled(rule) {
+			issues = append(issues, markdownLintIssue{Line: line, Column: column, Rule: rule, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	lintMarkdownHeadings(parseMarkdownDocument(content), report)
+
+	listMarker := cfg.ListMarker
+	if listMarker == DocsLintListMarkerConsistent {
+		listMarker = ""
+	}
+	listMarkerLine := 0
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	fence := ""
+	inFrontMatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if inFrontMatter {
+			if i > 0 && (strings.TrimSpace(line) == "---" || strings.TrimSpace(line) == "...") {
+				inFrontMatter = false
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		// Two trailing spaces after text are a Markdown hard line break
+		if right := strings.TrimRight(line, " \t"); right != line {
+			hardBreak := fence == "" && right != "" && line[len(right):] == "  "
+			if !hardBreak {
+				report(MarkdownRuleTrailingSpaces, lineNum, utf8.RuneCountInString(right)+1, "trailing whitespace")
+			}
+		}
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if marker := markdownFenceMarker(trimmed); marker != "" {
+			fence = marker
+			if strings.TrimSpace(strings.TrimLeft(trimmed, marker[:1])) == "" {
+				report(MarkdownRuleFenceLanguage, lineNum, 0, "fenced code block has no language")
+			}
+			continue
+		}
+
+		if m := mdListItemPattern.FindStringSubmatchIndex(line); m != nil && !isThematicBreak(trimmed) {
+			marker := line[m[4]:m[5]]
+			switch {
+			case listMarker == "":
+				listMarker = marker
+				listMarkerLine = lineNum
+			case marker != listMarker && listMarkerLine > 0:
+				report(MarkdownRuleListMarker, lineNum, m[4]+1, "list marker %q does not match %q used on line %d", marker, listMarker, listMarkerLine)
+			case marker != listMarker:
+				report(MarkdownRuleListMarker, lineNum, m[4]+1, "list marker %q does not match configured %q", marker, listMarker)
+			}
+		}
+
+		if cfg.MaxLineLength > 0 && !strings.HasPrefix(trimmed, "|") {
+			if length := utf8.RuneCountInString(line); length > cfg.MaxLineLength && !isUnbreakableLine(line, cfg.MaxLineLength) {
+				report(MarkdownRuleLineLength, lineNum, cfg.MaxLineLength+1, "line is %d characters (max %d)", length, cfg.MaxLineLength)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].Line < issues[b].Line
+	})
+	return issues
+}
+
+// lintMarkdownHeadings checks heading level increments and duplicate sibling headings
+func lintMarkdownHeadings(doc *markdownDocument, report func(rule string, line, column int, format string, args ...any)) {
+	var parents [7]string
+	seen := make(map[string]int)
+	prevLevel := 0
+
+	for _, h := range doc.Headings {
+		if prevLevel > 0 && h.Level > prevLevel+1 {
+			report(MarkdownRuleHeadingIncrement, h.Line, 0, "heading level jumps from h%d to h%d", prevLevel, h.Level)
+		}
+		prevLevel = h.Level
+
+		// Headings only count as duplicates when they share the same parent section,
+		// so repeated "### Added" sections under different releases are fine
+		key := strings.Join(parents[1:h.Level], "\x00") + "\x00" + strings.ToLower(h.Text)
+		if first, ok := seen[key]; ok {
+			report(MarkdownRuleDuplicateHeading, h.Line, 0, "duplicate heading %q (first on line %d)", h.Text, first)
+		} else {
+			seen[key] = h.Line
+		}
+
+		parents[h.Level] = strings.ToLower(h.Text)
+		for level := h.Level + 1; level < len(parents); level++ {
+			parents[level] = ""
+		}
+	}
+}
+
+// isThematicBreak reports whether a line is a thematic break such as "- - -" or "* * *"
+func isThematicBreak(trimmed string) bool {
+	compact := strings.ReplaceAll(strings.ReplaceAll(trimmed, " ", ""), "\t", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == "" && strings.ContainsAny(compact[:1], "-*_")
+}
+
+// isUnbreakableLine reports whether everything past the limit is a single word (e.g. a long URL)
+func isUnbreakableLine(line string, limit int) bool {
+	runes := []rune(line)
+	return !strings.ContainsAny(string(runes[limit:]), " \t")
+}
+
+// newLintReporter returns the reporter used for a lint check (docs:lint, check:license) in CI mode.
+// The JSON report goes to its own file so it does not replace the test results.
+func newLintReporter(platformReporter CIReporter, mode CIMode, reportFile string) CIReporter {
+	var reporters []CIReporter
+	if mode.Format == CIFormatJSON && mode.OutputPath != "" {
+		jsonReporter, err := NewJSONReporter(filepath.Join(filepath.Dir(mode.OutputPath), reportFile))
+		if err == nil {
+			reporters = append(reporters, jsonReporter)
+		} else {
+			utils.Warn("Failed to create JSON lint report: %v", err)
+		}
+	}
+	if platformReporter != nil {
+		reporters = append(reporters, platformReporter)
+	}
+	return combineReporters(reporters)
+}
+
+// ciMetadata returns execution metadata when the detector provides it
+func ciMetadata(detector CIDetector) CIMetadata {
+	if d, ok := detector.(*ciDetector); ok {
+		return d.GetMetadata()
+	}
+	return CIMetadata{}
+}
+
+// reportMarkdownLintIssues sends lint issues and a summary through a CI reporter
+func reportMarkdownLintIssues(reporter CIReporter, metadata CIMetadata, files int, issues []markdownLintIssue, duration time.Duration) error {
+	failures := make([]CITestFailure, 0, len(issues))
+	for _, issue := range issues {
+		failures = append(failures, issue.toCIFailure())
+	}
+	return reportLintFailures(reporter, metadata, files, failures, duration)
+}
+
+// reportLintFailures sends lint findings and a summary over the checked files through a
+// CI reporter, and closes the reporter even when reporting fails
+func reportLintFailures(reporter CIReporter, metadata CIMetadata, files int, failures []CITestFailure, duration time.Duration) (err error) {
+	defer func() {
+		if closeErr := reporter.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("%w: %w", errLintReportFail, closeErr)
+		}
+	}()
+
+	if err := reporter.Start(metadata); err != nil {
+		return fmt.Errorf("%w: %w", errLintReportFail, err)
+	}
+
+	failedFiles := make(map[string]bool)
+	result := &CIResult{
+		Failures:  make([]CITestFailure, 0, len(failures)),
+		Timestamp: time.Now(),
+		Duration:  duration,
+		Metadata:  metadata,
+	}
+	for _, failure := range failures {
+		if err := reporter.ReportFailure(failure); err != nil {
+			return fmt.Errorf("%w: %w", errLintReportFail, err)
+		}
+		result.Failures = append(result.Failures, failure)
+		failedFiles[failure.File] = true
+	}
+
+	result.Summary = CISummary{
+		Status:      TestStatusPassed,
+		Total:       files,
+		UniqueTotal: files,
+		Passed:      files - len(failedFiles),
+		Failed:      len(failedFiles),
+		Duration:    formatDurationForSummary(duration),
+	}
+	if len(failures) > 0 {
+		result.Summary.Status = TestStatusFailed
+	}
+
+	if err := reporter.WriteSummary(result); err != nil {
+		return fmt.Errorf("%w: %w", errLintReportFail, err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test errors returned by captureReporter
+var (
+	errLintReportWrite = errors.New("report write failed")
+	errLintReportClose = errors.New("report close failed")
+)
+
+// captureReporter records everything sent through the CIReporter interface
+type captureReporter struct {
+	started  bool
+	closed   bool
+	failures []CITestFailure
+	result   *CIResult
+
+	reportErr error // Returned by ReportFailure
+	closeErr  error // Returned by Close
+}
+
+func (r *captureReporter) Start(_ CIMetadata) error {
+	r.started = true
+	return nil
+}
+
+func (r *captureReporter) ReportFailure(failure CITestFailure) error {
+	r.failures = append(r.failures, failure)
+	return r.reportErr
+}
+
+func (r *captureReporter) WriteSummary(result *CIResult) error {
+	r.result = result
+	return nil
+}
+
+func (r *captureReporter) Close() error {
+	r.closed = true
+	return r.closeErr
+}
+
+// lintRules returns "rule@line" pairs for compact assertions
+func lintRules(issues []markdownLintIssue) []string {
+	rules := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		rules = append(rules, fmt.Sprintf("%s@%d", issue.Rule, issue.Line))
+	}
+	return rules
+}
+
+func defaultDocsLintConfig() DocsLintConfig {
+	return defaultConfig().Docs.Lint
+}
+
+func TestLintMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		cfg      func(*DocsLintConfig)
+		expected []string
+	}{
+		{
+			name:     "clean document",
+			content:  "# Title\n\n## Section\n\n- one\n- two\n\n```go\nfmt.Println()\n```\n",
+			expected: []string{},
+		},
+		{
+			name:     "heading level jump",
+			content:  "# Title\n\n### Too deep\n\n## Fine\n",
+			expected: []string{MarkdownRuleHeadingIncrement + "@3"},
+		},
+		{
+			name:     "duplicate sibling headings",
+			content:  "# Title\n\n## Setup\n\n## Setup\n",
+			expected: []string{MarkdownRuleDuplicateHeading + "@5"},
+		},
+		{
+			name:     "same heading under different parents",
+			content:  "# Changelog\n\n## v1.1.0\n\n### Added\n\n## v1.0.0\n\n### Added\n",
+			expected: []string{},
+		},
+		{
+			name:     "trailing whitespace",
+			content:  "# Title\n\ntext \nhard break  \n\t\n",
+			expected: []string{MarkdownRuleTrailingSpaces + "@3", MarkdownRuleTrailingSpaces + "@5"},
+		},
+		{
+			name:     "fence without language",
+			content:  "# Title\n\n```\ncode\n```\n\n~~~yaml\nkey: value\n~~~\n",
+			expected: []string{MarkdownRuleFenceLanguage + "@3"},
+		},
+		{
+			name:     "inconsistent list markers",
+			content:  "# Title\n\n- one\n* two\n\n---\n\n**bold** text\n",
+			expected: []string{MarkdownRuleListMarker + "@4"},
+		},
+		{
+			name:     "configured list marker",
+			content:  "# Title\n\n- one\n",
+			cfg:      func(c *DocsLintConfig) { c.ListMarker = "*" },
+			expected: []string{MarkdownRuleListMarker + "@3"},
+		},
+		{
+			name:     "long lines",
+			content:  "# Title\n\n" + strings.TrimSpace(strings.Repeat("word ", 30)) + "\n\nSee " + strings.Repeat("x", 150) + "\n\n| " + strings.Repeat("cell ", 30) + "|\n",
+			expected: []string{MarkdownRuleLineLength + "@3"},
+		},
+		{
+			name:     "code blocks are ignored",
+			content:  "# Title\n\n```text\n### not a heading\n* not a list\n" + strings.TrimSpace(strings.Repeat("long ", 40)) + "\n```\n",
+			expected: []string{},
+		},
+		{
+			name:     "disabled rules",
+			content:  "# Title\n\n```\ncode\n```\n",
+			cfg:      func(c *DocsLintConfig) { c.Disable = []string{"Fenced-Code-Language"} },
+			expected: []string{},
+		},
+		{
+			name:     "front matter is skipped",
+			content:  "---\ntitle: x \n---\n# Title\n",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultDocsLintConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			assert.Equal(t, tt.expected, lintRules(lintMarkdown(tt.content, cfg)))
+		})
+	}
+}
+
+func TestReportMarkdownLintIssues(t *testing.T) {
+	issues := []markdownLintIssue{
+		{File: "README.md", Line: 3, Rule: MarkdownRuleHeadingIncrement, Message: "heading level jumps from h1 to h3"},
+		{File: "README.md", Line: 9, Column: 5, Rule: MarkdownRuleTrailingSpaces, Message: "trailing whitespace"},
+		{File: "docs/guide.md", Line: 1, Rule: MarkdownRuleFenceLanguage, Message: "fenced code block has no language"},
+	}
+
+	reporter := &captureReporter{}
+	require.NoError(t, reportMarkdownLintIssues(reporter, CIMetadata{Platform: CIPlatformGitHub}, 4, issues, time.Second))
+
+	assert.True(t, reporter.started)
+	assert.True(t, reporter.closed)
+	require.Len(t, reporter.failures, 3)
+	assert.Equal(t, FailureTypeLint, reporter.failures[1].Type)
+	assert.Equal(t, "README.md", reporter.failures[1].File)
+	assert.Equal(t, 9, reporter.failures[1].Line)
+	assert.Equal(t, 5, reporter.failures[1].Column)
+	assert.Equal(t, MarkdownRuleTrailingSpaces, reporter.failures[1].Test)
+
+	require.NotNil(t, reporter.result)
+	assert.Equal(t, TestStatusFailed, reporter.result.Summary.Status)
+	assert.Equal(t, 4, reporter.result.Summary.Total)
+	assert.Equal(t, 2, reporter.result.Summary.Failed)
+	assert.Equal(t, 2, reporter.result.Summary.Passed)
+
+	t.Run("no issues passes", func(t *testing.T) {
+		clean := &captureReporter{}
+		require.NoError(t, reportMarkdownLintIssues(clean, CIMetadata{}, 2, nil, time.Second))
+		assert.Equal(t, TestStatusPassed, clean.result.Summary.Status)
+		assert.Empty(t, clean.failures)
+	})
+
+	t.Run("failed report still closes", func(t *testing.T) {
+		broken := &captureReporter{reportErr: errLintReportWrite, closeErr: errLintReportClose}
+		err := reportMarkdownLintIssues(broken, CIMetadata{}, 4, issues, time.Second)
+		require.ErrorIs(t, err, errLintReportWrite, "the first error is kept")
+		assert.True(t, broken.closed)
+	})
+
+	t.Run("close error is returned", func(t *testing.T) {
+		unclosable := &captureReporter{closeErr: errLintReportClose}
+		err := reportMarkdownLintIssues(unclosable, CIMetadata{}, 4, issues, time.Second)
+		require.ErrorIs(t, err, errLintReportClose)
+		require.ErrorIs(t, err, errLintReportFail)
+	})
+}
+
+func TestDocsLintWithArgs(t *testing.T) {
+	run := func(t *testing.T, cfg *Config, args ...string) error {
+		t.Helper()
+		TestSetConfig(cfg)
+		defer TestResetConfig()
+		return Docs{}.LintWithArgs(append(args, "ci=false")...)
+	}
+
+	t.Run("passes on clean docs", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md":     "# Readme\n\n## Usage\n\n- item\n",
+			"docs/guide.md": "# Guide\n\n```bash\nmagex docs:lint\n```\n",
+		})
+		require.NoError(t, run(t, defaultConfig(), "dir="+root))
+	})
+
+	t.Run("fails on lint issues", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md": "# Readme\n\n### Skipped level\n",
+		})
+		err := run(t, defaultConfig(), "dir="+root)
+		require.ErrorIs(t, err, errMarkdownLintIssues)
+	})
+
+	t.Run("honors ignore patterns", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md":           "# Readme\n",
+			"generated/api.md":    "# API\n\n### Skipped level\n",
+			"generated/nested.md": "text \n",
+		})
+		cfg := defaultConfig()
+		cfg.Docs.Lint.Ignore = []string{"generated/**"}
+		require.NoError(t, run(t, cfg, "dir="+root))
+	})
+
+	t.Run("rejects invalid list marker", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Docs.Lint.ListMarker = "#"
+		err := run(t, cfg, "dir="+t.TempDir())
+		require.ErrorIs(t, err, errInvalidDocsListMarker)
+	})
+
+	t.Run("ci mode writes json report", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"README.md": "# Readme\n\n```\ncode\n```\n",
+		})
+		cfg := defaultConfig()
+		cfg.Test.CIMode.OutputPath = filepath.Join(t.TempDir(), "ci-results.jsonl")
+		TestSetConfig(cfg)
+		defer TestResetConfig()
+
+		err := Docs{}.LintWithArgs("dir="+root, "ci=true", "ci_format=json")
+		require.ErrorIs(t, err, errMarkdownLintIssues)
+		assert.FileExists(t, filepath.Join(filepath.Dir(cfg.Test.CIMode.OutputPath), docsLintReportFile))
+	})
+}
@@ -96,6 +96,7 @@ func getTestCommands() []CommandDef {
 		// "run" is registered explicitly below with Options + alias (test:specific)
 		{Method: "coverage", Desc: "Run tests and generate coverage"},
 		{Method: "vet", Desc: "Run go vet"},
+		{Method: "flaky", Desc: "List flaky tests detected by CI mode reruns", Usage: "magex test:flaky [min=<n>]", Examples: []string{"magex test:flaky", "magex test:flaky min=3"}},
 	}
 }
 
@@ -109,6 +110,15 @@ func getLintCommands() []CommandDef {
 	}
 }
 
+func getCheckCommands() []CommandDef {
+	return []CommandDef{
+		{Method: "all", Desc: "Run format, imports, tidy, generate, spelling, license and vuln checks concurrently", Usage: "magex check:all [only=<list>] [skip=<list>] [jobs=<n>] [output=<file>]", Examples: []string{"magex check:all", "magex check:all skip=vuln,spelling", "magex check:all only=format,tidy output=reports/checks.json"}},
+		{Method: "license", Desc: "Check (or fix) license headers in Go, shell and YAML files", Usage: "magex check:license [fix=true] [dir=<path>] [ci=true] [ci_format=github|json]", Examples: []string{"magex check:license", "magex check:license fix=true", "magex check:license ci=true"}},
+		{Method: "tidy", Desc: "Fail with a diff when go mod tidy would change go.mod or go.sum"},
+		{Method: "generate", Desc: "Fail with a diff when go generate would change any file"},
+	}
+}
+
 func getFormatCommands() []CommandDef {
 	return []CommandDef{
 		{Method: "default", Desc: "Format Go code", Aliases: []string{"format", "fmt"}},
@@ -128,7 +138,7 @@ func getDepsCommands() []CommandDef {
 		{Method: "verify", Desc: "Verify dependencies"},
 		{Method: "outdated", Desc: "List outdated dependencies"},
 		{Method: "graph", Desc: "Show dependency graph"},
-		{Method: "licenses", Desc: "List dependency licenses"},
+		{Method: "licenses", Desc: "Scan dependency licenses and enforce the allow/deny policy", Usage: "magex deps:licenses [json=true] [output=<file>]", Examples: []string{"magex deps:licenses", "magex deps:licenses json=true", "magex deps:licenses output=licenses.json"}},
 	}
 }
 
@@ -146,6 +156,7 @@ func getGitCommands() []CommandDef {
 		{Method: "init", Desc: "Initialize git repository"},
 		{Method: "add", Desc: "Add files to git"},
 		{Method: "clone", Desc: "Clone a repository"},
+		{Method: "hook", Desc: "Run the magex commands configured for a git hook", Usage: "magex git:hook <name> [hook arguments]", Examples: []string{"magex git:hook pre-commit", "magex git:hook commit-msg .git/COMMIT_EDITMSG"}},
 	}
 }
 
@@ -156,10 +167,11 @@ func getReleaseCommands() []CommandDef {
 		{Method: "snapshot", Desc: "Create a snapshot release"},
 		{Method: "check", Desc: "Check release configuration"},
 		{Method: "init", Desc: "Initialize release configuration"},
-		{Method: "changelog", Desc: "Generate changelog"},
+		{Method: "changelog", Desc: "Print the Conventional Commits changelog of the next release"},
 		{Method: "validate", Desc: "Comprehensive release readiness validation"},
 		{Method: "clean", Desc: "Clean release artifacts and build cache"},
 		{Method: "localinstall", Desc: "Build from latest tag and install locally"},
+		{Method: "package", Desc: "Package build:all binaries into archives with checksums and a manifest (no goreleaser)", Usage: "magex release:package [version=<tag>] [skip-build=true]", Examples: []string{"magex release:package", "magex release:package version=v1.2.3", "magex release:package skip-build=true"}},
 	}
 }
 
@@ -170,6 +182,8 @@ func getDocsCommands() []CommandDef {
 		{Method: "generate", Desc: "Generate documentation from code"},
 		{Method: "serve", Desc: "Serve documentation locally"},
 		{Method: "check", Desc: "Check documentation quality"},
+		{Method: "links", Desc: "Check Markdown files for broken links and anchors", Usage: "magex docs:links [dir=<path>] [external=true] [timeout=<duration>]", Examples: []string{"magex docs:links", "magex docs:links external=true", "magex docs:links dir=docs external=true timeout=5s"}},
+		{Method: "lint", Desc: "Lint Markdown files (headings, whitespace, code fences, lists, line length)", Usage: "magex docs:lint [dir=<path>] [ci=true] [ci_format=github|json]", Examples: []string{"magex docs:lint", "magex docs:lint dir=docs", "magex docs:lint ci=true"}},
 		{Method: "godocs", Desc: "Generate godocs"},
 		{Method: "examples", Desc: "Generate example documentation"},
 		{Method: "readme", Desc: "Generate README documentation"},
@@ -182,7 +196,8 @@ func getToolsCommands() []CommandDef {
 	return []CommandDef{
 		{Method: "install", Desc: "Install development tools"},
 		{Method: "update", Desc: "Update development tools"},
-		{Method: "verify", Desc: "Verify installed tools"},
+		{Method: "verify", Desc: "Verify installed tools (against .mage-tools.lock when present)"},
+		{Method: "lock", Desc: "Install tools into .mage/bin and record them in .mage-tools.lock"},
 		{Method: "clean", Desc: "Clean tool installations"},
 	}
 }
@@ -244,6 +259,13 @@ func getGenerateCommands() []CommandDef {
 		{Method: "mocks", Desc: "Generate mock files"},
 		{Method: "proto", Desc: "Generate from protobuf files"},
 		{Method: "clean", Desc: "Clean generated files"},
+		{Method: "check", Desc: "Fail with a diff when go generate, swag or OpenAPI generation would change any file"},
+		{Method: "swagger", Desc: "Generate Swagger docs with the pinned swag version"},
+		{Method: "openapi", Desc: "Generate Go code from the OpenAPI specs in generate.openapi.specs"},
+		{Method: "sql", Desc: "Run sqlc in every module with a sqlc.yaml"},
+		{Method: "wire", Desc: "Run wire in every module with wireinject injectors"},
+		{Method: "graphql", Desc: "Run gqlgen in every module with a gqlgen.yml"},
+		{Method: "config", Desc: "Run every config-driven generator (sqlc, wire, gqlgen) the project uses"},
 	}
 }
 
@@ -294,12 +316,13 @@ func getBenchCommands() []CommandDef {
 	return []CommandDef{
 		{Method: "default", Desc: "Run benchmarks with optional parameters (time=duration)", Examples: []string{"magex bench", "magex bench time=50ms", "magex bench time=10s count=3"}},
 		{Method: "compare", Desc: "Compare benchmark results with optional file parameters", Examples: []string{"magex bench:compare", "magex bench:compare old=baseline.txt new=current.txt"}},
-		{Method: "save", Desc: "Save benchmark results with optional parameters (time=duration, output=file)", Examples: []string{"magex bench:save", "magex bench:save time=1s output=results.txt"}},
+		{Method: "save", Desc: "Save benchmark results with optional parameters (time=duration, output=file, history=false)", Examples: []string{"magex bench:save", "magex bench:save time=1s output=results.txt"}},
+		{Method: "history", Desc: "Show benchmark trends over recent commits and flag step changes (n=commits, bench=regex, threshold=percent, format=table|csv|json|html, output=file)", Examples: []string{"magex bench:history", "magex bench:history n=50 bench=Parse", "magex bench:history format=html output=bench-history.html"}},
 		{Method: "cpu", Desc: "Run CPU benchmarks with optional parameters (time=duration, profile=file)", Examples: []string{"magex bench:cpu", "magex bench:cpu time=30s profile=cpu-profile.out"}},
 		{Method: "mem", Desc: "Run memory benchmarks with optional parameters (time=duration, profile=file)", Examples: []string{"magex bench:mem", "magex bench:mem time=2s profile=mem-profile.out"}},
 		{Method: "profile", Desc: "Generate benchmark profiles with optional parameters (time=duration, cpu-profile=file, mem-profile=file)", Examples: []string{"magex bench:profile", "magex bench:profile time=5s cpu-profile=cpu.prof mem-profile=mem.prof"}},
 		{Method: "trace", Desc: "Generate execution traces with optional parameters (time=duration, trace=file)", Examples: []string{"magex bench:trace", "magex bench:trace time=10s trace=bench-trace.out"}},
-		{Method: "regression", Desc: "Run regression benchmarks with optional parameters (time=duration, update-baseline=true)", Examples: []string{"magex bench:regression", "magex bench:regression time=5s update-baseline=true"}},
+		{Method: "regression", Desc: "Compare benchmarks with the baseline and fail on regressions beyond thresholds (count=N, time=duration, json=path, update-baseline=true)", Examples: []string{"magex bench:regression count=5", "magex bench:regression time=5s update-baseline=true"}},
 	}
 }
 
@@ -349,7 +372,7 @@ func getVersionCommands() []CommandDef {
 		// version:check and version:update are registered explicitly below as
 		// deprecated aliases over the go-selfupdate-backed update surface.
 		{Method: "bump", Desc: "Bump version with parameters: bump=<major|minor|patch> branch=<branch-name> push dry-run force major-confirm", Usage: "magex version:bump [bump=<type>] [branch=<branch-name>] [push] [dry-run] [force] [major-confirm]", Examples: []string{"magex version:bump bump=patch branch=master push", "magex version:bump bump=minor branch=main", "magex version:bump bump=major major-confirm branch=master push", "magex version:bump bump=patch dry-run", "magex version:bump bump=patch"}},
-		{Method: "changelog", Desc: "Generate changelog from git history with parameters: from=<tag> to=<tag>", Usage: "magex version:changelog [from=<tag>] [to=<tag>]", Examples: []string{"magex version:changelog", "magex version:changelog from=v1.0.0", "magex version:changelog from=v1.0.0 to=v1.1.0"}},
+		{Method: "changelog", Desc: "Generate a Conventional Commits changelog with parameters: from=<tag> to=<tag> version=<name> write=true module=<name|all>", Usage: "magex version:changelog [from=<tag>] [to=<tag>] [version=<name>] [write=true] [file=CHANGELOG.md] [module=<name|all>]", Examples: []string{"magex version:changelog", "magex version:changelog from=v1.0.0 to=v1.1.0", "magex version:changelog write=true version=v1.2.0", "magex version:changelog module=all write=true"}},
 		{Method: "tag", Desc: "Create version tag"},
 	}
 }
@@ -365,15 +388,21 @@ func getInstallCommands() []CommandDef {
 		{Method: "systemwide", Desc: "Install system-wide"},
 		{Method: "deps", Desc: "Install dependencies"},
 		{Method: "mage", Desc: "Install mage"},
-		{Method: "githooks", Desc: "Install git hooks"},
+		{Method: "githooks", Desc: "Install the git hooks configured in .mage.yaml", Usage: "magex install:githooks [uninstall=true]", Examples: []string{"magex install:githooks", "magex install:githooks uninstall=true"}},
 		{Method: "ci", Desc: "Install CI components"},
-		{Method: "certs", Desc: "Install certificates"},
+		{Method: "certs", Desc: "Create a development CA and certificates signed by it", Usage: "magex install:certs [force=true]", Examples: []string{"magex install:certs", "magex install:certs force=true"}},
 		{Method: "package", Desc: "Install package"},
 		{Method: "all", Desc: "Install everything"},
 		{Method: "uninstall", Desc: "Remove installation"},
 	}
 }
 
+func getCertsCommands() []CommandDef {
+	return []CommandDef{
+		{Method: "status", Desc: "List development certificates with their hosts and expiry"},
+	}
+}
+
 func getYamlCommands() []CommandDef {
 	return []CommandDef{
 		{Method: "init", Desc: "Create mage.yaml configuration"},
@@ -479,6 +508,7 @@ func testMethodBindings(t mage.Test) map[string]MethodBinding {
 		// "run" is registered separately in registerTestCommands with WithArgs + Options + test:specific alias
 		"coverage": {WithArgs: t.Coverage},
 		"vet":      {NoArgs: t.Vet},
+		"flaky":    {WithArgs: t.Flaky},
 	}
 }
 
@@ -492,6 +522,15 @@ func lintMethodBindings(l mage.Lint) map[string]MethodBinding {
 	}
 }
 
+func checkMethodBindings(c mage.Check) map[string]MethodBinding {
+	return map[string]MethodBinding{
+		"all":      {WithArgs: c.AllWithArgs},
+		"license":  {WithArgs: c.LicenseWithArgs},
+		"tidy":     {NoArgs: c.Tidy},
+		"generate": {NoArgs: c.Generate},
+	}
+}
+
 func formatMethodBindings(f mage.Format) map[string]MethodBinding {
 	return map[string]MethodBinding{
 		"default": {NoArgs: f.Default},
@@ -511,7 +550,7 @@ func depsMethodBindings(d mage.Deps) map[string]MethodBinding {
 		"verify":   {NoArgs: d.Verify},
 		"outdated": {NoArgs: d.Outdated},
 		"graph":    {NoArgs: d.Graph},
-		"licenses": {NoArgs: d.Licenses},
+		"licenses": {WithArgs: d.LicensesWithArgs},
 	}
 }
 
@@ -529,6 +568,7 @@ func gitMethodBindings(g mage.Git) map[string]MethodBinding {
 		"init":      {NoArgs: g.Init},
 		"add":       {WithArgs: g.Add},
 		"clone":     {NoArgs: g.Clone},
+		"hook":      {WithArgs: g.Hook},
 	}
 }
 
@@ -543,6 +583,7 @@ func releaseMethodBindings(r mage.Release) map[string]MethodBinding {
 		"validate":     {NoArgs: r.Validate},
 		"clean":        {NoArgs: r.Clean},
 		"localinstall": {NoArgs: r.LocalInstall},
+		"package":      {WithArgs: r.Package},
 	}
 }
 
@@ -553,6 +594,8 @@ func docsMethodBindings(d mage.Docs) map[string]MethodBinding {
 		"generate": {NoArgs: d.Generate},
 		"serve":    {NoArgs: d.Serve},
 		"check":    {NoArgs: d.Check},
+		"links":    {WithArgs: d.LinksWithArgs},
+		"lint":     {WithArgs: d.LintWithArgs},
 		"godocs":   {WithArgs: d.GoDocs},
 		"examples": {NoArgs: d.Examples},
 		"readme":   {NoArgs: d.Readme},
@@ -566,6 +609,7 @@ func toolsMethodBindings(t mage.Tools) map[string]MethodBinding {
 		"install": {NoArgs: t.Install},
 		"update":  {NoArgs: t.Update},
 		"verify":  {NoArgs: t.Verify},
+		"lock":    {NoArgs: t.Lock},
 		"clean":   {NoArgs: t.Clean},
 	}
 }
@@ -588,6 +632,13 @@ func generateMethodBindings(g mage.Generate) map[string]MethodBinding {
 		"mocks":   {NoArgs: g.Mocks},
 		"proto":   {NoArgs: g.Proto},
 		"clean":   {NoArgs: g.Clean},
+		"check":   {NoArgs: g.Check},
+		"swagger": {NoArgs: g.Swagger},
+		"openapi": {NoArgs: g.OpenAPI},
+		"sql":     {NoArgs: g.SQL},
+		"wire":    {NoArgs: g.Wire},
+		"graphql": {NoArgs: g.GraphQL},
+		"config":  {NoArgs: g.Config},
 	}
 }
 
@@ -629,6 +680,7 @@ func benchMethodBindings(b mage.Bench) map[string]MethodBinding {
 		"default":    {NoArgs: b.Default, WithArgs: b.DefaultWithArgs},
 		"compare":    {NoArgs: b.Compare, WithArgs: b.CompareWithArgs},
 		"save":       {NoArgs: b.Save, WithArgs: b.SaveWithArgs},
+		"history":    {NoArgs: b.History, WithArgs: b.HistoryWithArgs},
 		"cpu":        {NoArgs: b.CPU, WithArgs: b.CPUWithArgs},
 		"mem":        {NoArgs: b.Mem, WithArgs: b.MemWithArgs},
 		"profile":    {NoArgs: b.Profile, WithArgs: b.ProfileWithArgs},
@@ -688,15 +740,21 @@ func installMethodBindings(i mage.Install) map[string]MethodBinding {
 		"systemwide": {NoArgs: i.SystemWide},
 		"deps":       {NoArgs: i.Deps},
 		"mage":       {NoArgs: i.Mage},
-		"githooks":   {NoArgs: i.GitHooks},
+		"githooks":   {WithArgs: i.GitHooksWithArgs},
 		"ci":         {NoArgs: i.CI},
-		"certs":      {NoArgs: i.Certs},
+		"certs":      {WithArgs: i.CertsWithArgs},
 		"package":    {NoArgs: i.Package},
 		"all":        {NoArgs: i.All},
 		"uninstall":  {NoArgs: i.Uninstall},
 	}
 }
 
+func certsMethodBindings(c mage.Certs) map[string]MethodBinding {
+	return map[string]MethodBinding{
+		"status": {NoArgs: c.Status},
+	}
+}
+
 func yamlMethodBindings(y mage.Yaml) map[string]MethodBinding {
 	return map[string]MethodBinding{
 		"init":     {NoArgs: y.Init},
@@ -761,6 +819,7 @@ func RegisterAll(reg *registry.Registry) {
 	registerBuildCommands(reg)
 	registerTestCommands(reg)
 	registerLintCommands(reg)
+	registerCheckCommands(reg)
 	registerFormatCommands(reg)
 	registerDepsCommands(reg)
 	registerGitCommands(reg)
@@ -778,6 +837,7 @@ func RegisterAll(reg *registry.Registry) {
 	registerHelpCommands(reg)
 	registerVersionCommands(reg)
 	registerInstallCommands(reg)
+	registerCertsCommands(reg)
 	registerYamlCommands(reg)
 	registerAgentOSCommands(reg)
 	registerBmadCommands(reg)
@@ -838,6 +898,11 @@ func registerLintCommands(reg *registry.Registry) {
 	registerNamespaceCommands(reg, "lint", "Lint", getLintCommands(), lintMethodBindings(l))
 }
 
+func registerCheckCommands(reg *registry.Registry) {
+	c := mage.Check{}
+	registerNamespaceCommands(reg, "check", "Lint", getCheckCommands(), checkMethodBindings(c))
+}
+
 func registerFormatCommands(reg *registry.Registry) {
 	f := mage.Format{}
 	registerNamespaceCommands(reg, "format", "Format", getFormatCommands(), formatMethodBindings(f))
@@ -1012,6 +1077,11 @@ func registerInstallCommands(reg *registry.Registry) {
 	registerNamespaceCommands(reg, "install", "Installation", getInstallCommands(), installMethodBindings(i))
 }
 
+func registerCertsCommands(reg *registry.Registry) {
+	c := mage.Certs{}
+	registerNamespaceCommands(reg, "certs", "Installation", getCertsCommands(), certsMethodBindings(c))
+}
+
 func registerYamlCommands(reg *registry.Registry) {
 	y := mage.Yaml{}
 	registerNamespaceCommands(reg, "yaml", "Configuration", getYamlCommands(), yamlMethodBindings(y))
@@ -1042,6 +1112,8 @@ func registerTopLevelCommands(reg *registry.Registry) {
 	t := mage.Test{}
 	l := mage.Lint{}
 	f := mage.Format{}
+	r := mage.Run{}
+	a := mage.Audit{}
 
 	// Top-level convenience commands
 	// Note: Some commands need wrappers because the underlying method takes variadic args
@@ -1101,4 +1173,43 @@ func registerTopLevelCommands(reg *registry.Registry) {
 			WithCategory("Common").
 			MustBuild(),
 	)
+
+	reg.MustRegister(
+		registry.NewCommand("watch").
+			WithDescription("Re-run commands when Go sources change").
+			WithLongDescription("Watch Go sources (respecting .gitignore and lint skip_dirs) and re-run on change.\n\n"+
+				"Commands run in order and stop at the first failure; quote a command to pass it parameters.\n"+
+				"A run still in progress is interrupted when newer changes arrive.\n"+
+				"With no commands, only the packages affected by the change are tested.\n"+
+				"On Linux changes arrive through inotify; elsewhere the tree is polled every interval.").
+			WithArgsFunc(r.WatchWithArgs).
+			WithCategory("Common").
+			WithUsage("magex watch [command...] [debounce=<duration>] [interval=<duration>]").
+			WithExamples(
+				"magex watch",
+				"magex watch lint test:unit",
+				`magex watch "test:run name=TestFoo pkg=./pkg/mage"`,
+				"magex watch build debounce=1s",
+			).
+			MustBuild(),
+	)
+
+	reg.MustRegister(
+		registry.NewCommand("audit").
+			WithDescription("Query the command audit log").
+			WithLongDescription("Show the commands magex ran, as recorded when audit.enabled is set in .mage.yaml.\n\n"+
+				"Each entry names the user, machine, magex command, exit code, duration and the program it executed.\n"+
+				"command= matches the program or the magex command; a namespace such as release matches all of its commands.\n"+
+				"since= and until= take RFC 3339 timestamps, dates (YYYY-MM-DD) or durations back from now (24h).").
+			WithArgsFunc(a.Query).
+			WithCategory("Common").
+			WithUsage("magex audit [command=<name>] [user=<name>] [since=<time>] [until=<time>] [exit_code=<n>] [last=<n>] [format=table|json]").
+			WithExamples(
+				"magex audit",
+				"magex audit command=release since=168h",
+				"magex audit exit_code=1 user=alice",
+				"magex audit since=2026-10-01 until=2026-10-15 format=json",
+			).
+			MustBuild(),
+	)
 }
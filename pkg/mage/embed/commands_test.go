@@ -227,7 +227,7 @@ func TestRegisterAllNamespaces(t *testing.T) {
 	t.Parallel()
 
 	expectedNamespaces := []string{
-		"build", "test", "lint", "format", "deps", "git", "release",
+		"build", "test", "lint", "check", "format", "deps", "git", "release",
 		"docs", "tools", "generate", "update", "mod",
 		"metrics", "bench", "vet", "configure",
 		"help", "version", "install", "yaml",
@@ -786,7 +786,7 @@ func TestAllNamespaceImplementationsRegistered(t *testing.T) {
 	// This list should match all `type Xxx mg.Namespace` definitions in pkg/mage.
 	// When adding a new namespace, add it here to ensure registration is not forgotten.
 	expectedNamespaces := []string{
-		"build", "test", "lint", "format", "deps", "git", "release",
+		"build", "test", "lint", "check", "format", "deps", "git", "release",
 		"docs", "tools", "generate", "update", "mod", "metrics",
 		"bench", "vet", "configure", "help", "version", "install",
 		"yaml", "bmad", "aws", "speckit",
@@ -964,6 +964,7 @@ func TestMageNamespaceTypes(t *testing.T) {
 		"Help":      mage.Help{},
 		"Version":   mage.Version{},
 		"Install":   mage.Install{},
+		"Certs":     mage.Certs{},
 		"Yaml":      mage.Yaml{},
 	}
 
@@ -1431,6 +1432,7 @@ func TestDataTableIntegrity(t *testing.T) {
 		{"buildCommands", getBuildCommands(), 10},
 		{"testCommands", getTestCommands(), 20},
 		{"lintCommands", getLintCommands(), 5},
+		{"checkCommands", getCheckCommands(), 1},
 		{"formatCommands", getFormatCommands(), 4},
 		{"depsCommands", getDepsCommands(), 9},
 		{"gitCommands", getGitCommands(), 12},
@@ -1447,6 +1449,7 @@ func TestDataTableIntegrity(t *testing.T) {
 		{"helpCommands", getHelpCommands(), 7},
 		{"versionCommands", getVersionCommands(), 4}, // show, bump, changelog, tag (check/update registered explicitly)
 		{"installCommands", getInstallCommands(), 15},
+		{"certsCommands", getCertsCommands(), 1},
 		{"yamlCommands", getYamlCommands(), 5},
 		{"bmadCommands", getBmadCommands(), 3},
 		{"awsCommands", getAWSCommands(), 4},
@@ -1546,6 +1549,7 @@ func TestMethodBindingFactories(t *testing.T) {
 			{"build", func() map[string]MethodBinding { return buildMethodBindings(mage.Build{}) }},
 			{"test", func() map[string]MethodBinding { return testMethodBindings(mage.Test{}) }},
 			{"lint", func() map[string]MethodBinding { return lintMethodBindings(mage.Lint{}) }},
+			{"check", func() map[string]MethodBinding { return checkMethodBindings(mage.Check{}) }},
 			{"format", func() map[string]MethodBinding { return formatMethodBindings(mage.Format{}) }},
 			{"deps", func() map[string]MethodBinding { return depsMethodBindings(mage.Deps{}) }},
 			{"git", func() map[string]MethodBinding { return gitMethodBindings(mage.Git{}) }},
@@ -1562,6 +1566,7 @@ func TestMethodBindingFactories(t *testing.T) {
 			{"help", func() map[string]MethodBinding { return helpMethodBindings(mage.Help{}) }},
 			{"version", func() map[string]MethodBinding { return versionMethodBindings(mage.Version{}) }},
 			{"install", func() map[string]MethodBinding { return installMethodBindings(mage.Install{}) }},
+			{"certs", func() map[string]MethodBinding { return certsMethodBindings(mage.Certs{}) }},
 			{"yaml", func() map[string]MethodBinding { return yamlMethodBindings(mage.Yaml{}) }},
 			{"bmad", func() map[string]MethodBinding { return bmadMethodBindings(mage.Bmad{}) }},
 			{"aws", func() map[string]MethodBinding { return awsMethodBindings(mage.AWS{}) }},
@@ -1659,6 +1664,7 @@ func TestBindingsMatchDataTables(t *testing.T) {
 		{"build", getBuildCommands(), buildMethodBindings(mage.Build{})},
 		{"test", getTestCommands(), testMethodBindings(mage.Test{})},
 		{"lint", getLintCommands(), lintMethodBindings(mage.Lint{})},
+		{"check", getCheckCommands(), checkMethodBindings(mage.Check{})},
 		{"format", getFormatCommands(), formatMethodBindings(mage.Format{})},
 		{"deps", getDepsCommands(), depsMethodBindings(mage.Deps{})},
 		{"git", getGitCommands(), gitMethodBindings(mage.Git{})},
@@ -1675,6 +1681,7 @@ func TestBindingsMatchDataTables(t *testing.T) {
 		{"help", getHelpCommands(), helpMethodBindings(mage.Help{})},
 		{"version", getVersionCommands(), versionMethodBindings(mage.Version{})},
 		{"install", getInstallCommands(), installMethodBindings(mage.Install{})},
+		{"certs", getCertsCommands(), certsMethodBindings(mage.Certs{})},
 		{"yaml", getYamlCommands(), yamlMethodBindings(mage.Yaml{})},
 		{"bmad", getBmadCommands(), bmadMethodBindings(mage.Bmad{})},
 		{"aws", getAWSCommands(), awsMethodBindings(mage.AWS{})},
@@ -1739,12 +1746,12 @@ func TestTotalCommandCount(t *testing.T) {
 	// of the version data table into explicit deprecated registrations, so the
 	// count is the same. Top-level grew by one: the new `update` verb (its
 	// `upgrade` alias is not a separate command).
-	assert.Equal(t, 176, namespaceCommands,
-		"Should have 176 namespace commands (data tables + deps:audit + test:run + explicit version:check/update)")
-	assert.Equal(t, 8, topLevelCommands,
-		"Should have 8 top-level commands (incl. the new update verb)")
-	assert.Len(t, commands, 184,
-		"Should have 184 total commands")
+	assert.Equal(t, 195, namespaceCommands,
+		"Should have 195 namespace commands (data tables + deps:audit + test:run + explicit version:check/update)")
+	assert.Equal(t, 10, topLevelCommands,
+		"Should have 10 top-level commands (incl. the new update verb)")
+	assert.Len(t, commands, 205,
+		"Should have 205 total commands")
 }
 
 // TestMissingBindingPanics verifies commands without bindings cause panic
@@ -1872,6 +1879,7 @@ func TestGetterFunctionsReturnFreshSlices(t *testing.T) {
 		{"getBuildCommands", getBuildCommands},
 		{"getTestCommands", getTestCommands},
 		{"getLintCommands", getLintCommands},
+		{"getCheckCommands", getCheckCommands},
 		{"getFormatCommands", getFormatCommands},
 		{"getDepsCommands", getDepsCommands},
 		{"getGitCommands", getGitCommands},
@@ -1888,6 +1896,7 @@ func TestGetterFunctionsReturnFreshSlices(t *testing.T) {
 		{"getHelpCommands", getHelpCommands},
 		{"getVersionCommands", getVersionCommands},
 		{"getInstallCommands", getInstallCommands},
+		{"getCertsCommands", getCertsCommands},
 		{"getYamlCommands", getYamlCommands},
 		{"getBmadCommands", getBmadCommands},
 	}
@@ -1931,6 +1940,7 @@ func TestGetterFunctionsReturnConsistentData(t *testing.T) {
 		{"getBuildCommands", getBuildCommands},
 		{"getTestCommands", getTestCommands},
 		{"getLintCommands", getLintCommands},
+		{"getCheckCommands", getCheckCommands},
 		{"getFormatCommands", getFormatCommands},
 		{"getDepsCommands", getDepsCommands},
 		{"getGitCommands", getGitCommands},
@@ -1947,6 +1957,7 @@ func TestGetterFunctionsReturnConsistentData(t *testing.T) {
 		{"getHelpCommands", getHelpCommands},
 		{"getVersionCommands", getVersionCommands},
 		{"getInstallCommands", getInstallCommands},
+		{"getCertsCommands", getCertsCommands},
 		{"getYamlCommands", getYamlCommands},
 		{"getBmadCommands", getBmadCommands},
 	}
@@ -1983,6 +1994,7 @@ func TestGetterFunctionsConcurrentAccess(t *testing.T) {
 		{"getBuildCommands", getBuildCommands},
 		{"getTestCommands", getTestCommands},
 		{"getLintCommands", getLintCommands},
+		{"getCheckCommands", getCheckCommands},
 		{"getFormatCommands", getFormatCommands},
 		{"getDepsCommands", getDepsCommands},
 		{"getGitCommands", getGitCommands},
@@ -1999,6 +2011,7 @@ func TestGetterFunctionsConcurrentAccess(t *testing.T) {
 		{"getHelpCommands", getHelpCommands},
 		{"getVersionCommands", getVersionCommands},
 		{"getInstallCommands", getInstallCommands},
+		{"getCertsCommands", getCertsCommands},
 		{"getYamlCommands", getYamlCommands},
 		{"getBmadCommands", getBmadCommands},
 	}
@@ -2051,24 +2064,26 @@ func TestGetterFunctionsExpectedCounts(t *testing.T) {
 		expectedCount int
 	}{
 		{"getBuildCommands", getBuildCommands, 10},
-		{"getTestCommands", getTestCommands, 20}, // "run" is registered separately with Options + test:specific alias
+		{"getTestCommands", getTestCommands, 21}, // "run" is registered separately with Options + test:specific alias
 		{"getLintCommands", getLintCommands, 5},
+		{"getCheckCommands", getCheckCommands, 4},
 		{"getFormatCommands", getFormatCommands, 4},
 		{"getDepsCommands", getDepsCommands, 9},
-		{"getGitCommands", getGitCommands, 12},
-		{"getReleaseCommands", getReleaseCommands, 9},
-		{"getDocsCommands", getDocsCommands, 10},
-		{"getToolsCommands", getToolsCommands, 4},
-		{"getGenerateCommands", getGenerateCommands, 5},
+		{"getGitCommands", getGitCommands, 13},
+		{"getReleaseCommands", getReleaseCommands, 10},
+		{"getDocsCommands", getDocsCommands, 12},
+		{"getToolsCommands", getToolsCommands, 5},
+		{"getGenerateCommands", getGenerateCommands, 12},
 		{"getUpdateCommands", getUpdateCommands, 2},
 		{"getModCommands", getModCommands, 9},
 		{"getMetricsCommands", getMetricsCommands, 7},
-		{"getBenchCommands", getBenchCommands, 8},
+		{"getBenchCommands", getBenchCommands, 9},
 		{"getVetCommands", getVetCommands, 1},
 		{"getConfigureCommands", getConfigureCommands, 7},
 		{"getHelpCommands", getHelpCommands, 7},
 		{"getVersionCommands", getVersionCommands, 4}, // check/update registered explicitly as deprecated aliases
 		{"getInstallCommands", getInstallCommands, 15},
+		{"getCertsCommands", getCertsCommands, 1},
 		{"getYamlCommands", getYamlCommands, 5},
 		{"getBmadCommands", getBmadCommands, 3},
 		{"getAWSCommands", getAWSCommands, 4},
@@ -2097,6 +2112,7 @@ func TestGetterFunctionsNoDuplicateMethods(t *testing.T) {
 		{"getBuildCommands", getBuildCommands},
 		{"getTestCommands", getTestCommands},
 		{"getLintCommands", getLintCommands},
+		{"getCheckCommands", getCheckCommands},
 		{"getFormatCommands", getFormatCommands},
 		{"getDepsCommands", getDepsCommands},
 		{"getGitCommands", getGitCommands},
@@ -2113,6 +2129,7 @@ func TestGetterFunctionsNoDuplicateMethods(t *testing.T) {
 		{"getHelpCommands", getHelpCommands},
 		{"getVersionCommands", getVersionCommands},
 		{"getInstallCommands", getInstallCommands},
+		{"getCertsCommands", getCertsCommands},
 		{"getYamlCommands", getYamlCommands},
 		{"getBmadCommands", getBmadCommands},
 		{"getAWSCommands", getAWSCommands},
@@ -2146,6 +2163,7 @@ func TestGetterFunctionsAliasesValid(t *testing.T) {
 		{"getBuildCommands", getBuildCommands},
 		{"getTestCommands", getTestCommands},
 		{"getLintCommands", getLintCommands},
+		{"getCheckCommands", getCheckCommands},
 		{"getFormatCommands", getFormatCommands},
 		{"getDepsCommands", getDepsCommands},
 		{"getGitCommands", getGitCommands},
@@ -2162,6 +2180,7 @@ func TestGetterFunctionsAliasesValid(t *testing.T) {
 		{"getHelpCommands", getHelpCommands},
 		{"getVersionCommands", getVersionCommands},
 		{"getInstallCommands", getInstallCommands},
+		{"getCertsCommands", getCertsCommands},
 		{"getYamlCommands", getYamlCommands},
 		{"getBmadCommands", getBmadCommands},
 	}
@@ -2189,6 +2208,7 @@ func TestTotalCommandsFromGetters(t *testing.T) {
 		getBuildCommands,
 		getTestCommands,
 		getLintCommands,
+		getCheckCommands,
 		getFormatCommands,
 		getDepsCommands,
 		getGitCommands,
@@ -2205,6 +2225,7 @@ func TestTotalCommandsFromGetters(t *testing.T) {
 		getHelpCommands,
 		getVersionCommands,
 		getInstallCommands,
+		getCertsCommands,
 		getYamlCommands,
 		getBmadCommands,
 		getAWSCommands,
@@ -2216,12 +2237,12 @@ func TestTotalCommandsFromGetters(t *testing.T) {
 		total += len(getter())
 	}
 
-	// Expected: 163 commands from data tables. test:run is registered separately
+	// Expected: 182 commands from data tables. test:run is registered separately
 	// via an explicit builder (Options + test:specific alias), and version:check
 	// / version:update moved out of the version table into explicit deprecated
 	// registrations, so the version getter now returns 4 instead of 6.
-	assert.Equal(t, 163, total,
-		"Total commands from all getters should equal 163")
+	assert.Equal(t, 182, total,
+		"Total commands from all getters should equal 182")
 }
 
 // BenchmarkGetterFunctions benchmarks the getter function calls
@@ -0,0 +1,268 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for API generation
+var (
+	errOpenAPISpecMissing      = errors.New("openapi spec entry has no spec path")
+	errOpenAPIOutputMissing    = errors.New("openapi spec entry needs an output or config")
+	errOpenAPIGeneratorMissing = errors.New("openapi generator not found and no module configured to install it")
+)
+
+const (
+	// swagModule is the module that provides the swag CLI
+	swagModule = "github.com/swaggo/swag/cmd/swag"
+
+	// defaultSwaggerOutput is where swag writes docs.go, swagger.json and swagger.yaml
+	defaultSwaggerOutput = "docs"
+
+	// defaultOpenAPIGenerator is the generator used when generate.openapi.generator is unset
+	defaultOpenAPIGenerator = "oapi-codegen"
+
+	// defaultOpenAPIModule installs defaultOpenAPIGenerator
+	defaultOpenAPIModule = "github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen"
+)
+
+// Swagger generates Swagger docs with swag from the configured main package.
+// The swag version pinned in tools.swag (or MAGE_X_SWAG_VERSION) is installed first.
+func (Generate) Swagger() error {
+	utils.Header("Generating Swagger Docs")
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := ensureSwag(config); err != nil {
+		return err
+	}
+
+	args := swagInitArgs(config)
+	utils.Info("Running swag %s", strings.Join(args, " "))
+	if err := GetRunner().RunCmd(CmdSwag, args...); err != nil {
+		return fmt.Errorf("swag init failed: %w", err)
+	}
+
+	utils.Success("Swagger docs generated in %s", swaggerOutput(config))
+	return nil
+}
+
+// OpenAPI generates Go code from every spec listed under generate.openapi.specs
+func (Generate) OpenAPI() error {
+	utils.Header("Generating Code from OpenAPI Specs")
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	openAPI := config.Generate.OpenAPI
+	if len(openAPI.Specs) == 0 {
+		utils.Info("No OpenAPI specs configured (generate.openapi.specs)")
+		return nil
+	}
+	if err := validateOpenAPISpecs(openAPI.Specs); err != nil {
+		return err
+	}
+	if err := ensureOpenAPIGenerator(config); err != nil {
+		return err
+	}
+
+	generator := openAPIGenerator(openAPI)
+	for _, spec := range openAPI.Specs {
+		utils.Info("Generating code from %s", spec.Spec)
+		if err := ensureOutputDir(".", spec.Output); err != nil {
+			return err
+		}
+		if err := GetRunner().RunCmd(generator, openAPIArgs(spec)...); err != nil {
+			return fmt.Errorf("failed to generate code for %s: %w", spec.Spec, err)
+		}
+	}
+
+	utils.Success("Generated code for %d OpenAPI spec(s)", len(openAPI.Specs))
+	return nil
+}
+
+// apiGenerateSteps returns the drift-check steps for Swagger (when generate.swagger.main
+// is set) and OpenAPI (one per configured spec), installing the generators they need
+func apiGenerateSteps(config *Config) ([]generateStep, error) {
+	var steps []generateStep
+
+	if config.Generate.Swagger.Main != "" {
+		if err := ensureSwag(config); err != nil {
+			return nil, err
+		}
+		steps = append(steps, commandStep(CmdSwag, swagInitArgs(config)...))
+	}
+
+	openAPI := config.Generate.OpenAPI
+	if len(openAPI.Specs) > 0 {
+		if err := validateOpenAPISpecs(openAPI.Specs); err != nil {
+			return nil, err
+		}
+		if err := ensureOpenAPIGenerator(config); err != nil {
+			return nil, err
+		}
+		generator := openAPIGenerator(openAPI)
+		for _, spec := range openAPI.Specs {
+			run := commandStep(generator, openAPIArgs(spec)...)
+			steps = append(steps, func(dir string) error {
+				if err := ensureOutputDir(dir, spec.Output); err != nil {
+					return err
+				}
+				return run(dir)
+			})
+		}
+	}
+
+	return steps, nil
+}
+
+// swaggerOutput returns the configured swag output directory
+func swaggerOutput(config *Config) string {
+	if config.Generate.Swagger.Output != "" {
+		return config.Generate.Swagger.Output
+	}
+	return defaultSwaggerOutput
+}
+
+// swagInitArgs builds the "swag init" arguments. A main package directory is
+// resolved to its main.go, which holds the general API annotations.
+func swagInitArgs(config *Config) []string {
+	main := config.Generate.Swagger.Main
+	if main == "" {
+		main = config.Project.Main
+	}
+	if main == "" {
+		main = "main.go"
+	}
+	if !strings.HasSuffix(main, ".go") {
+		main = filepath.Join(main, "main.go")
+	}
+
+	args := []string{"init", "--generalInfo", filepath.ToSlash(filepath.Clean(main)), "--output", swaggerOutput(config)}
+	return append(args, config.Generate.Swagger.Args...)
+}
+
+// swagVersion returns the pinned swag version, or "latest" when none is pinned
+func swagVersion(config *Config) string {
+	version := config.Tools.Swag
+	if version == "" || version == VersionLatest {
+		version = GetDefaultSwagVersion()
+	}
+	if version == "" {
+		return VersionLatest
+	}
+	return version
+}
+
+// ensureSwag installs swag into the project tools directory when it is locked, missing
+// or does not report the pinned version
+func ensureSwag(config *Config) error {
+	version := swagVersion(config)
+	return installProjectTool(config, CmdSwag, swagModule, version, func() bool {
+		if !commandExists(CmdSwag) {
+			return false
+		}
+		if version == VersionLatest {
+			return true
+		}
+		output, err := GetRunner().RunCmdOutput(CmdSwag, "--version")
+		if err == nil && swagVersionMatches(output, version) {
+			return true
+		}
+		utils.Info("swag is not at the pinned version %s", version)
+		return false
+	})
+}
+
+// swagVersionMatches reports whether "swag --version" output (e.g. "swag version v1.16.4")
+// names the wanted version, with or without the leading v
+func swagVersionMatches(output, version string) bool {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return false
+	}
+	return strings.TrimPrefix(fields[len(fields)-1], "v") == strings.TrimPrefix(version, "v")
+}
+
+// openAPIGenerator returns the configured generator command
+func openAPIGenerator(openAPI OpenAPIConfig) string {
+	if openAPI.Generator != "" {
+		return openAPI.Generator
+	}
+	return defaultOpenAPIGenerator
+}
+
+// ensureOpenAPIGenerator installs the generator from its module into the project tools
+// directory when it is locked or not on PATH
+func ensureOpenAPIGenerator(config *Config) error {
+	openAPI := config.Generate.OpenAPI
+	generator := openAPIGenerator(openAPI)
+	module := openAPI.Module
+	if module == "" && generator == defaultOpenAPIGenerator {
+		module = defaultOpenAPIModule
+	}
+	if module == "" {
+		if commandExists(generator) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", errOpenAPIGeneratorMissing, generator)
+	}
+	version := openAPI.Version
+	if version == "" {
+		version = VersionLatest
+	}
+
+	return installProjectTool(config, generator, module, version, func() bool {
+		return commandExists(generator)
+	})
+}
+
+// validateOpenAPISpecs checks every spec entry names a document and somewhere to write code
+func validateOpenAPISpecs(specs []OpenAPISpec) error {
+	for i, spec := range specs {
+		if spec.Spec == "" {
+			return fmt.Errorf("%w (generate.openapi.specs[%d])", errOpenAPISpecMissing, i)
+		}
+		if spec.Output == "" && spec.Config == "" {
+			return fmt.Errorf("%w: %s", errOpenAPIOutputMissing, spec.Spec)
+		}
+	}
+	return nil
+}
+
+// openAPIArgs builds oapi-codegen style arguments for one spec
+func openAPIArgs(spec OpenAPISpec) []string {
+	var args []string
+	if spec.Config != "" {
+		args = append(args, "-config", spec.Config)
+	}
+	if spec.Package != "" {
+		args = append(args, "-package", spec.Package)
+	}
+	if spec.Output != "" {
+		args = append(args, "-o", spec.Output)
+	}
+	args = append(args, spec.Args...)
+	return append(args, spec.Spec)
+}
+
+// ensureOutputDir creates the directory an output file is written to, relative to root
+func ensureOutputDir(root, output string) error {
+	if output == "" {
+		return nil
+	}
+	dir := filepath.Join(root, filepath.Dir(output))
+	if err := os.MkdirAll(dir, fileops.PermDir); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package mage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/mage/testutil"
+)
+
+// useGenerateConfig installs a config and a fake runner for the duration of the test
+func useGenerateConfig(t *testing.T, config *Config, output func(cmd string) (string, error)) *testutil.FakeRunner {
+	t.Helper()
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+	return useFakeRunner(t, output)
+}
+
+func TestSwagInitArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		swagger  SwaggerConfig
+		main     string
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			expected: []string{"init", "--generalInfo", "main.go", "--output", "docs"},
+		},
+		{
+			name:     "project main package directory",
+			main:     "./cmd/api",
+			expected: []string{"init", "--generalInfo", "cmd/api/main.go", "--output", "docs"},
+		},
+		{
+			name:     "swagger settings win",
+			swagger:  SwaggerConfig{Main: "cmd/server/server.go", Output: "api/docs", Args: []string{"--parseDependency"}},
+			main:     "./cmd/api",
+			expected: []string{"init", "--generalInfo", "cmd/server/server.go", "--output", "api/docs", "--parseDependency"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := &Config{Project: ProjectConfig{Main: tt.main}, Generate: GenerateConfig{Swagger: tt.swagger}}
+			assert.Equal(t, tt.expected, swagInitArgs(config))
+		})
+	}
+}
+
+func TestSwagVersionMatches(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, swagVersionMatches("swag version v1.16.4\n", "v1.16.4"))
+	assert.True(t, swagVersionMatches("swag version v1.16.4", "1.16.4"))
+	assert.False(t, swagVersionMatches("swag version v1.8.12", "v1.16.4"))
+	assert.False(t, swagVersionMatches("", "v1.16.4"))
+}
+
+func TestGenerateSwagger(t *testing.T) {
+	t.Setenv("MAGE_X_SWAG_VERSION", "")
+	t.Setenv("SWAG_VERSION", "")
+
+	t.Run("installs the pinned version when another is on PATH", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		installs := useFakeGoInstall(t)
+		setCommandExists(t, func(string) bool { return true })
+		config := defaultConfig()
+		config.Tools.Swag = "v1.16.4"
+		config.Generate.Swagger.Main = "cmd/api"
+		runner := useGenerateConfig(t, config, func(string) (string, error) {
+			return "swag version v1.8.12", nil
+		})
+
+		require.NoError(t, Generate{}.Swagger())
+		assert.Equal(t, []string{"github.com/swaggo/swag/cmd/swag@v1.16.4"}, *installs)
+		assert.Equal(t, []string{
+			"swag --version",
+			"swag init --generalInfo cmd/api/main.go --output docs",
+		}, runner.Commands())
+		binDir, err := filepath.Abs(DefaultToolsBinDir)
+		require.NoError(t, err)
+		assert.FileExists(t, toolBinaryPath(binDir, CmdSwag), "installed into the project tools directory")
+		assert.True(t, strings.HasPrefix(os.Getenv("PATH"), binDir), "the tools directory is first on PATH")
+	})
+
+	t.Run("installs the locked version", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		installs := useFakeGoInstall(t)
+		setCommandExists(t, func(string) bool { return true })
+		binDir, err := filepath.Abs(DefaultToolsBinDir)
+		require.NoError(t, err)
+		locked := lockInstalledTool(t, binDir, swagModule)
+		require.NoError(t, os.Remove(toolBinaryPath(binDir, CmdSwag)))
+		require.NoError(t, writeToolsLock(ToolsLockFile, &ToolsLock{Tools: []LockedTool{locked}}))
+		*installs = nil
+
+		config := defaultConfig()
+		config.Tools.Swag = "v1.16.4"
+		runner := useGenerateConfig(t, config, nil)
+
+		require.NoError(t, Generate{}.Swagger())
+		assert.Equal(t, []string{swagModule + "@" + locked.Version}, *installs, "the lock wins over tools.swag")
+		assert.Equal(t, []string{"swag init --generalInfo main.go --output docs"}, runner.Commands())
+
+		*installs = nil
+		require.NoError(t, ensureSwag(config))
+		assert.Empty(t, *installs, "a binary matching the lock is kept")
+	})
+
+	t.Run("keeps a matching install", func(t *testing.T) {
+		setCommandExists(t, func(string) bool { return true })
+		config := defaultConfig()
+		config.Tools.Swag = "v1.16.4"
+		runner := useGenerateConfig(t, config, func(string) (string, error) {
+			return "swag version v1.16.4", nil
+		})
+
+		require.NoError(t, Generate{}.Swagger())
+		assert.Equal(t, []string{"swag --version", "swag init --generalInfo main.go --output docs"}, runner.Commands())
+	})
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	t.Run("nothing configured", func(t *testing.T) {
+		runner := useGenerateConfig(t, defaultConfig(), nil)
+		require.NoError(t, Generate{}.OpenAPI())
+		assert.Empty(t, runner.Commands())
+	})
+
+	t.Run("generates each spec", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		setCommandsMissing(t, defaultOpenAPIGenerator)
+		config := defaultConfig()
+		config.Generate.OpenAPI.Version = "v2.4.1"
+		config.Generate.OpenAPI.Specs = []OpenAPISpec{
+			{Spec: "api/petstore.yaml", Output: "internal/petstore/petstore.gen.go", Package: "petstore"},
+			{Spec: "api/users.yaml", Config: "api/users.cfg.yaml"},
+		}
+		runner := useGenerateConfig(t, config, nil)
+		installs := useFakeGoInstall(t)
+
+		require.NoError(t, Generate{}.OpenAPI())
+		assert.Equal(t, []string{"github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1"}, *installs)
+		assert.Equal(t, []string{
+			"oapi-codegen -package petstore -o internal/petstore/petstore.gen.go api/petstore.yaml",
+			"oapi-codegen -config api/users.cfg.yaml api/users.yaml",
+		}, runner.Commands())
+		assert.DirExists(t, filepath.Join("internal", "petstore"))
+	})
+
+	t.Run("custom generator needs a module to install", func(t *testing.T) {
+		setCommandsMissing(t, "ogen")
+		config := defaultConfig()
+		config.Generate.OpenAPI.Generator = "ogen"
+		config.Generate.OpenAPI.Specs = []OpenAPISpec{{Spec: "api.yaml", Output: "gen/api.go"}}
+		useGenerateConfig(t, config, nil)
+
+		require.ErrorIs(t, Generate{}.OpenAPI(), errOpenAPIGeneratorMissing)
+	})
+
+	t.Run("spec entries are validated", func(t *testing.T) {
+		config := defaultConfig()
+		config.Generate.OpenAPI.Specs = []OpenAPISpec{{Spec: "api.yaml"}}
+		useGenerateConfig(t, config, nil)
+		require.ErrorIs(t, Generate{}.OpenAPI(), errOpenAPIOutputMissing)
+
+		config.Generate.OpenAPI.Specs = []OpenAPISpec{{Output: "gen/api.go"}}
+		require.ErrorIs(t, Generate{}.OpenAPI(), errOpenAPISpecMissing)
+	})
+}
+
+func TestGenerateCheck_OpenAPIDrift(t *testing.T) {
+	chdirTempModule(t)
+	setCommandExists(t, func(string) bool { return true })
+	config := defaultConfig()
+	config.Generate.OpenAPI.Specs = []OpenAPISpec{{Spec: "api.yaml", Output: "gen/api.gen.go", Package: "gen"}}
+	generated := "package gen\n\n// Pet is generated\ntype Pet struct{}\n"
+	useGenerateConfig(t, config, func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, defaultOpenAPIGenerator) {
+			return "", os.WriteFile(filepath.Join("gen", "api.gen.go"), []byte(generated), 0o600)
+		}
+		return "", nil
+	})
+
+	require.ErrorIs(t, Generate{}.Check(), ErrGeneratedFilesNeedRegen)
+	assert.NoDirExists(t, "gen", "the working tree is untouched")
+
+	require.NoError(t, os.MkdirAll("gen", 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join("gen", "api.gen.go"), []byte(generated), 0o600))
+	require.NoError(t, Generate{}.Check())
+}
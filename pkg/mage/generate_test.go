@@ -251,6 +251,20 @@ func (ts *GenerateTestSuite) testEchoGenerateMethod(
 	})
 }
 
+// testSkippedGenerateMethod tests a config-driven Generate method in a module without its config
+func (ts *GenerateTestSuite) testSkippedGenerateMethod(testName string, methodFunc func() error) {
+	ts.Run(testName, func() {
+		err := ts.env.WithMockRunner(
+			setTestRunner,
+			func() any { return GetRunner() },
+			methodFunc,
+		)
+
+		ts.Require().NoError(err)
+		ts.env.Runner.AssertNotCalled(ts.T(), "RunCmd")
+	})
+}
+
 // TestGenerateDocs tests the Docs method
 func (ts *GenerateTestSuite) TestGenerateDocs() {
 	ts.testEchoGenerateMethod(
@@ -262,33 +276,10 @@ func (ts *GenerateTestSuite) TestGenerateDocs() {
 	)
 }
 
-// TestGenerateSwagger tests the Swagger method
-func (ts *GenerateTestSuite) TestGenerateSwagger() {
-	ts.testEchoGenerateMethod(
-		"successful Swagger generation",
-		[]string{"Generating Swagger docs"},
-		func() error {
-			return ts.generate.Swagger()
-		},
-	)
-}
-
-// TestGenerateOpenAPI tests the OpenAPI method
-func (ts *GenerateTestSuite) TestGenerateOpenAPI() {
-	ts.testEchoGenerateMethod(
-		"successful OpenAPI generation",
-		[]string{"Generating OpenAPI spec"},
-		func() error {
-			return ts.generate.OpenAPI()
-		},
-	)
-}
-
 // TestGenerateGraphQL tests the GraphQL method
 func (ts *GenerateTestSuite) TestGenerateGraphQL() {
-	ts.testEchoGenerateMethod(
-		"successful GraphQL generation",
-		[]string{"Generating GraphQL code"},
+	ts.testSkippedGenerateMethod(
+		"no gqlgen config",
 		func() error {
 			return ts.generate.GraphQL()
 		},
@@ -297,9 +288,8 @@ func (ts *GenerateTestSuite) TestGenerateGraphQL() {
 
 // TestGenerateSQL tests the SQL method
 func (ts *GenerateTestSuite) TestGenerateSQL() {
-	ts.testEchoGenerateMethod(
-		"successful SQL generation",
-		[]string{"Generating SQL files"},
+	ts.testSkippedGenerateMethod(
+		"no sqlc config",
 		func() error {
 			return ts.generate.SQL()
 		},
@@ -308,9 +298,8 @@ func (ts *GenerateTestSuite) TestGenerateSQL() {
 
 // TestGenerateWire tests the Wire method
 func (ts *GenerateTestSuite) TestGenerateWire() {
-	ts.testEchoGenerateMethod(
-		"successful Wire generation",
-		[]string{"Generating wire files"},
+	ts.testSkippedGenerateMethod(
+		"no wire config",
 		func() error {
 			return ts.generate.Wire()
 		},
@@ -319,9 +308,8 @@ func (ts *GenerateTestSuite) TestGenerateWire() {
 
 // TestGenerateConfig tests the Config method
 func (ts *GenerateTestSuite) TestGenerateConfig() {
-	ts.testEchoGenerateMethod(
-		"successful Config generation",
-		[]string{"Generating config files"},
+	ts.testSkippedGenerateMethod(
+		"no generator configs",
 		func() error {
 			return ts.generate.Config()
 		},
@@ -360,19 +348,6 @@ func (ts *GenerateTestSuite) TestGenerateHelperFunctions() {
 		hasService = checkForGRPCService()
 		ts.Require().True(hasService)
 	})
-
-	ts.Run("backupGeneratedFiles function", func() {
-		// Test backup function
-		backupGeneratedFiles()
-		// No error to check since function doesn't return error
-	})
-
-	ts.Run("compareGeneratedFiles function", func() {
-		// Test compare function
-		different, err := compareGeneratedFiles("/tmp/test")
-		ts.Require().NoError(err)
-		ts.Require().Empty(different)
-	})
 }
 
 // TestGenerateIntegration tests integration scenarios
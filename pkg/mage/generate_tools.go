@@ -0,0 +1,335 @@
+package mage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+const (
+	// sqlcModule provides the sqlc CLI
+	sqlcModule = "github.com/sqlc-dev/sqlc/cmd/sqlc"
+
+	// wireModule provides the wire CLI
+	wireModule = "github.com/google/wire/cmd/wire"
+
+	// gqlgenModule provides the gqlgen CLI
+	gqlgenModule = "github.com/99designs/gqlgen"
+
+	// wireInjectTag is the build tag that marks wire injector files
+	wireInjectTag = "wireinject"
+)
+
+// configGenerator is a code generator driven by a config file found in each module
+type configGenerator struct {
+	// Name is the command, also its key under tools.custom
+	Name string
+
+	// Module is the module installed when tools.custom does not name one
+	Module string
+
+	// Config describes what is looked for, for messages
+	Config string
+
+	// Detect returns the targets (config files or packages, relative to the module)
+	// the generator runs for, or none when the module has nothing to generate
+	Detect func(module ModuleInfo) ([]string, error)
+
+	// Args builds the generator arguments for one module
+	Args func(targets []string) []string
+}
+
+// sqlcGenerator runs "sqlc generate" for the sqlc config of each module
+func sqlcGenerator() configGenerator {
+	return configGenerator{
+		Name:   CmdSqlc,
+		Module: sqlcModule,
+		Config: "sqlc.yaml",
+		Detect: func(module ModuleInfo) ([]string, error) {
+			return findConfigFile(module.Path, "sqlc.yaml", "sqlc.yml", "sqlc.json"), nil
+		},
+		Args: func(targets []string) []string {
+			return []string{"generate", "--file", targets[0]}
+		},
+	}
+}
+
+// wireGenerator runs "wire gen" for every package holding wireinject injectors
+func wireGenerator() configGenerator {
+	return configGenerator{
+		Name:   CmdWire,
+		Module: wireModule,
+		Config: "//go:build " + wireInjectTag + " injectors",
+		Detect: func(module ModuleInfo) ([]string, error) {
+			return findWireInjectorPackages(module.Path)
+		},
+		Args: func(targets []string) []string {
+			return append([]string{"gen"}, targets...)
+		},
+	}
+}
+
+// gqlgenGenerator runs "gqlgen generate" for the gqlgen config of each module
+func gqlgenGenerator() configGenerator {
+	return configGenerator{
+		Name:   CmdGqlgen,
+		Module: gqlgenModule,
+		Config: "gqlgen.yml",
+		Detect: func(module ModuleInfo) ([]string, error) {
+			return findConfigFile(module.Path, "gqlgen.yml", "gqlgen.yaml", ".gqlgen.yml"), nil
+		},
+		Args: func(targets []string) []string {
+			return []string{"generate", "--config", targets[0]}
+		},
+	}
+}
+
+// GraphQL generates GraphQL code with gqlgen in every module that has a gqlgen.yml
+func (Generate) GraphQL() error {
+	return runConfigGenerators("Generating GraphQL Code", gqlgenGenerator())
+}
+
+// SQL generates type-safe database code with sqlc in every module that has a sqlc.yaml
+func (Generate) SQL() error {
+	return runConfigGenerators("Generating SQL Code", sqlcGenerator())
+}
+
+// Wire generates dependency injection code with wire in every module that has
+// wireinject injector files
+func (Generate) Wire() error {
+	return runConfigGenerators("Generating Wire Injectors", wireGenerator())
+}
+
+// Config runs every config-driven generator (sqlc, wire and gqlgen) whose config
+// is present, skipping the ones the project does not use
+func (Generate) Config() error {
+	return runConfigGenerators("Generating Code from Generator Configs", sqlcGenerator(), wireGenerator(), gqlgenGenerator())
+}
+
+// runConfigGenerators runs each generator in the modules where its config is found.
+// A generator is only installed, at the version pinned in tools.custom, when some
+// module needs it.
+func runConfigGenerators(header string, generators ...configGenerator) error {
+	ctx, err := prepareModuleCommand(ModuleCommandConfig{
+		Header:    header,
+		Operation: "generate",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prepare generate command: %w", err)
+	}
+	if ctx == nil {
+		return nil
+	}
+
+	for _, generator := range generators {
+		if err := runConfigGenerator(ctx, generator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runConfigGenerator runs one generator across the modules that have its config
+func runConfigGenerator(ctx *ModuleCommandContext, generator configGenerator) error {
+	targets := make(map[string][]string)
+	var modules []ModuleInfo
+	for _, module := range ctx.Modules {
+		found, err := generator.Detect(module)
+		if err != nil {
+			return fmt.Errorf("failed to look for %s config in %s: %w", generator.Name, module.Relative, err)
+		}
+		if len(found) > 0 {
+			targets[module.Path] = found
+			modules = append(modules, module)
+		}
+	}
+	if len(modules) == 0 {
+		utils.Info("No %s config found (%s), skipping %s", generator.Name, generator.Config, generator.Name)
+		return nil
+	}
+
+	if err := ensureConfigGenerator(ctx.Config, generator); err != nil {
+		return err
+	}
+
+	err := forEachModule(modules, ModuleIteratorOptions{
+		Operation: "Running " + generator.Name,
+		Verb:      "completed",
+	}, func(module ModuleInfo) error {
+		args := generator.Args(targets[module.Path])
+		utils.Info("Running: %s %s", generator.Name, strings.Join(args, " "))
+		return runCommandInModule(module, generator.Name, args...)
+	})
+	if err != nil {
+		return fmt.Errorf("%s generation failed: %w", generator.Name, err)
+	}
+	return nil
+}
+
+// customToolSpec returns the module and version to install for a tool, read from
+// its tools.custom entry ("module@version", as for tools:install) when there is one
+func customToolSpec(config *Config, name, defaultModule string) (module, version string) {
+	spec := strings.TrimSpace(config.Tools.Custom[name])
+	if spec == "" {
+		return defaultModule, VersionLatest
+	}
+	module, version, _ = strings.Cut(spec, "@")
+	if version == "" {
+		version = VersionLatest
+	}
+	return module, version
+}
+
+// ensureConfigGenerator installs the generator into the project tools directory when it
+// is locked, missing or, with a pinned version, built from another version
+func ensureConfigGenerator(config *Config, generator configGenerator) error {
+	module, version := customToolSpec(config, generator.Name, generator.Module)
+	return installProjectTool(config, generator.Name, module, version, func() bool {
+		if !commandExists(generator.Name) {
+			return false
+		}
+		if version == VersionLatest {
+			return true
+		}
+		if installed := installedToolVersion(generator.Name); installed == version {
+			return true
+		}
+		utils.Info("%s is not at the pinned version %s", generator.Name, version)
+		return false
+	})
+}
+
+// installedToolVersion returns the main module version recorded in a Go binary on
+// PATH ("go version -m"), or "" when it cannot be determined
+func installedToolVersion(name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
+	}
+	output, err := GetRunner().RunCmdOutput(CmdGo, "version", "-m", path)
+	if err != nil {
+		return ""
+	}
+	return parseBuildInfoVersion(output)
+}
+
+// parseBuildInfoVersion extracts the main module version from "go version -m" output
+func parseBuildInfoVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "mod" {
+			return fields[2]
+		}
+	}
+	return ""
+}
+
+// findConfigFile returns the first of names present in dir
+func findConfigFile(dir string, names ...string) []string {
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// findWireInjectorPackages returns the packages ("./path") of the module rooted at
+// root that contain wireinject files. Nested modules, hidden, vendor and testdata
+// directories are skipped.
+func findWireInjectorPackages(root string) ([]string, error) {
+	seen := make(map[string]bool)
+	var packages []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == root {
+				return nil
+			}
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			if _, statErr := os.Stat(filepath.Join(path, "go.mod")); statErr == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if seen[dir] {
+			return nil
+		}
+		injector, err := isWireInjectorFile(path)
+		if err != nil {
+			return err
+		}
+		if injector {
+			seen[dir] = true
+			rel, relErr := filepath.Rel(root, dir)
+			if relErr != nil {
+				return fmt.Errorf("failed to resolve %s: %w", dir, relErr)
+			}
+			if rel == "." {
+				packages = append(packages, ".")
+			} else {
+				packages = append(packages, "./"+filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for wire injectors: %w", err)
+	}
+	return packages, nil
+}
+
+// isWireInjectorFile reports whether a Go file carries the wireinject build
+// constraint, which must appear before the package clause
+func isWireInjectorFile(path string) (bool, error) {
+	file, err := os.Open(path) // #nosec G304 -- path comes from walking the module
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }() //nolint:errcheck // read-only file
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			return false, nil
+		}
+		if strings.HasPrefix(line, "//go:build ") || strings.HasPrefix(line, "// +build ") {
+			for _, field := range strings.FieldsFunc(line, isBuildConstraintSeparator) {
+				if field == wireInjectTag {
+					return true, nil
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, bufio.ErrTooLong) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return false, nil
+}
+
+// isBuildConstraintSeparator splits a build constraint line into tags; negation
+// stays attached, so the "!wireinject" of generated wire_gen.go files is not a match
+func isBuildConstraintSeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', '(', ')', '&', '|', ',':
+		return true
+	}
+	return false
+}
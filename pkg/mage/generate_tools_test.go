@@ -0,0 +1,173 @@
+package mage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errGenerateTest is returned by a failing generator in tests
+var errGenerateTest = errors.New("generator failed")
+
+// writeTestFiles creates files (and their directories) relative to the working directory
+func writeTestFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o750))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	}
+}
+
+func TestGenerateSQL_RunsPerModule(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{
+		"sqlc.yaml":               "version: \"2\"\n",
+		"services/api/go.mod":     "module example.com/api\n\ngo 1.24\n",
+		"services/api/sqlc.yml":   "version: \"2\"\n",
+		"services/worker/go.mod":  "module example.com/worker\n\ngo 1.24\n",
+		"services/worker/main.go": "package main\n",
+	})
+	setCommandsMissing(t, CmdSqlc)
+	config := defaultConfig()
+	config.Tools.Custom = map[string]string{"sqlc": sqlcModule + "@v1.27.0"}
+	runner := useGenerateConfig(t, config, nil)
+	installs := useFakeGoInstall(t)
+
+	require.NoError(t, Generate{}.SQL())
+
+	assert.Equal(t, []string{"github.com/sqlc-dev/sqlc/cmd/sqlc@v1.27.0"}, *installs)
+	assert.Equal(t, []string{
+		"sqlc generate --file sqlc.yaml",
+		"sqlc generate --file sqlc.yml",
+	}, runner.Commands(), "the module without a sqlc config is skipped")
+}
+
+func TestGenerateConfigGenerators_SkipWithoutConfig(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{"main.go": "package main\n"})
+	setCommandsMissing(t, CmdSqlc, CmdWire, CmdGqlgen)
+	runner := useGenerateConfig(t, defaultConfig(), nil)
+
+	require.NoError(t, Generate{}.Config())
+	assert.Empty(t, runner.Commands(), "nothing is installed or run without a config")
+}
+
+func TestGenerateConfig_RunsDetectedGenerators(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{
+		"gqlgen.yml":           "schema: [graph/*.graphqls]\n",
+		"internal/di/wire.go":  "//go:build wireinject\n\npackage di\n",
+		"internal/di/types.go": "package di\n",
+	})
+	setCommandsMissing(t, CmdWire, CmdGqlgen)
+	runner := useGenerateConfig(t, defaultConfig(), nil)
+	installs := useFakeGoInstall(t)
+
+	require.NoError(t, Generate{}.Config())
+
+	assert.Equal(t, []string{"github.com/google/wire/cmd/wire@latest", "github.com/99designs/gqlgen@latest"}, *installs)
+	assert.Equal(t, []string{
+		"wire gen ./internal/di",
+		"gqlgen generate --config gqlgen.yml",
+	}, runner.Commands())
+}
+
+func TestGenerateGraphQL_GeneratorFailure(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{"gqlgen.yml": "schema: [schema.graphqls]\n"})
+	setCommandExists(t, func(string) bool { return true })
+	original := GetRunner()
+	failing := &generateMockRunner{RunCmdErr: errGenerateTest}
+	require.NoError(t, SetRunner(failing))
+	t.Cleanup(func() { require.NoError(t, SetRunner(original)) })
+
+	err := Generate{}.GraphQL()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gqlgen generation failed")
+	assert.Contains(t, err.Error(), errGenerateTest.Error())
+}
+
+func TestEnsureConfigGenerator_PinnedVersion(t *testing.T) {
+	t.Chdir(t.TempDir())
+	// A fake sqlc on PATH whose build info is answered by the runner
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, CmdSqlc), []byte("#!/bin/sh\n"), 0o700)) //nolint:gosec // test executable
+	t.Setenv("PATH", bin)
+	setCommandExists(t, func(name string) bool { return name == CmdSqlc })
+
+	installed := "v1.27.0"
+	config := defaultConfig()
+	config.Tools.Custom = map[string]string{"sqlc": sqlcModule + "@v1.27.0"}
+	runner := useGenerateConfig(t, config, func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, "go version -m") {
+			return "sqlc: go1.24.0\n\tpath\tgithub.com/sqlc-dev/sqlc/cmd/sqlc\n\tmod\tgithub.com/sqlc-dev/sqlc\t" + installed + "\th1:abc=\n", nil
+		}
+		return "", nil
+	})
+
+	require.NoError(t, ensureConfigGenerator(config, sqlcGenerator()))
+	assert.Equal(t, []string{"go version -m " + filepath.Join(bin, CmdSqlc)}, runner.Commands(), "the pinned version is already installed")
+
+	installed = "v1.25.0"
+	installs := useFakeGoInstall(t)
+	require.NoError(t, ensureConfigGenerator(config, sqlcGenerator()))
+	assert.Equal(t, []string{"github.com/sqlc-dev/sqlc/cmd/sqlc@v1.27.0"}, *installs)
+}
+
+func TestCustomToolSpec(t *testing.T) {
+	t.Parallel()
+
+	config := defaultConfig()
+	config.Tools.Custom = map[string]string{
+		"sqlc": "github.com/sqlc-dev/sqlc/cmd/sqlc@v1.27.0",
+		"wire": "github.com/google/wire/cmd/wire",
+	}
+
+	module, version := customToolSpec(config, "sqlc", sqlcModule)
+	assert.Equal(t, sqlcModule, module)
+	assert.Equal(t, "v1.27.0", version)
+
+	module, version = customToolSpec(config, "wire", wireModule)
+	assert.Equal(t, wireModule, module)
+	assert.Equal(t, VersionLatest, version)
+
+	module, version = customToolSpec(config, "gqlgen", gqlgenModule)
+	assert.Equal(t, gqlgenModule, module)
+	assert.Equal(t, VersionLatest, version)
+}
+
+func TestParseBuildInfoVersion(t *testing.T) {
+	t.Parallel()
+
+	output := "/go/bin/wire: go1.24.2\n\tpath\tgithub.com/google/wire/cmd/wire\n\tmod\tgithub.com/google/wire\tv0.6.0\th1:x=\n\tdep\tgolang.org/x/tools\tv0.20.0\th1:y=\n"
+	assert.Equal(t, "v0.6.0", parseBuildInfoVersion(output))
+	assert.Empty(t, parseBuildInfoVersion("not a go binary"))
+}
+
+func TestFindWireInjectorPackages(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{
+		"wire.go":                   "// Copyright\n\n//go:build wireinject\n// +build wireinject\n\npackage main\n",
+		"wire_gen.go":               "//go:build !wireinject\n\npackage main\n",
+		"cmd/api/inject.go":         "//go:build linux && wireinject\n\npackage main\n",
+		"cmd/api/wire_gen.go":       "//go:build !wireinject\n\npackage main\n",
+		"pkg/plain/plain.go":        "package plain\n\n// wireinject in a comment is not a constraint\n",
+		"vendor/dep/wire.go":        "//go:build wireinject\n\npackage dep\n",
+		"tools/go.mod":              "module example.com/tools\n",
+		"tools/wire.go":             "//go:build wireinject\n\npackage tools\n",
+		"internal/gen/wire_gen.go":  "//go:build !wireinject\n\npackage gen\n",
+		"internal/gen/provider.go":  "package gen\n",
+		"internal/testdata/wire.go": "//go:build wireinject\n\npackage testdata\n",
+	})
+
+	root, err := os.Getwd()
+	require.NoError(t, err)
+	packages, err := findWireInjectorPackages(root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".", "./cmd/api"}, packages)
+}
@@ -324,43 +324,6 @@ message User {
 	})
 }
 
-// TestBackupGeneratedFilesUnit tests backupGeneratedFiles function
-func TestBackupGeneratedFilesUnit(t *testing.T) {
-	t.Run("runs without error", func(t *testing.T) {
-		tempDir := t.TempDir()
-		originalWd, err := os.Getwd()
-		require.NoError(t, err)
-		require.NoError(t, os.Chdir(tempDir))
-		defer func() {
-			require.NoError(t, os.Chdir(originalWd))
-		}()
-
-		// Function doesn't return error, just verify it doesn't panic
-		assert.NotPanics(t, func() {
-			backupGeneratedFiles()
-		})
-	})
-}
-
-// TestCompareGeneratedFilesUnit tests compareGeneratedFiles function
-func TestCompareGeneratedFilesUnit(t *testing.T) {
-	t.Run("returns empty for non-existent temp dir", func(t *testing.T) {
-		different, err := compareGeneratedFiles("/nonexistent/path")
-
-		require.NoError(t, err)
-		assert.Empty(t, different)
-	})
-
-	t.Run("handles empty directory", func(t *testing.T) {
-		tempDir := t.TempDir()
-
-		different, err := compareGeneratedFiles(tempDir)
-
-		require.NoError(t, err)
-		assert.Empty(t, different)
-	})
-}
-
 // TestGenerateCodeUnit tests Generate.Code
 func TestGenerateCodeUnit(t *testing.T) {
 	t.Run("runs go generate", func(t *testing.T) {
@@ -396,43 +359,10 @@ func TestGenerateDocsUnit(t *testing.T) {
 	})
 }
 
-// TestGenerateSwaggerUnit tests Generate.Swagger
-func TestGenerateSwaggerUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
-		mock := &generateMockRunner{}
-		originalRunner := GetRunner()
-		_ = SetRunner(mock) //nolint:errcheck // test cleanup
-		defer func() {
-			_ = SetRunner(originalRunner) //nolint:errcheck // cleanup
-		}()
-
-		g := Generate{}
-		err := g.Swagger()
-
-		require.NoError(t, err)
-	})
-}
-
-// TestGenerateOpenAPIUnit tests Generate.OpenAPI
-func TestGenerateOpenAPIUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
-		mock := &generateMockRunner{}
-		originalRunner := GetRunner()
-		_ = SetRunner(mock) //nolint:errcheck // test cleanup
-		defer func() {
-			_ = SetRunner(originalRunner) //nolint:errcheck // cleanup
-		}()
-
-		g := Generate{}
-		err := g.OpenAPI()
-
-		require.NoError(t, err)
-	})
-}
-
 // TestGenerateGraphQLUnit tests Generate.GraphQL
 func TestGenerateGraphQLUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
+	t.Run("skips a module without generator config", func(t *testing.T) {
+		chdirTempModule(t)
 		mock := &generateMockRunner{}
 		originalRunner := GetRunner()
 		_ = SetRunner(mock) //nolint:errcheck // test cleanup
@@ -444,12 +374,14 @@ func TestGenerateGraphQLUnit(t *testing.T) {
 		err := g.GraphQL()
 
 		require.NoError(t, err)
+		assert.Empty(t, mock.Commands)
 	})
 }
 
 // TestGenerateSQLUnit tests Generate.SQL
 func TestGenerateSQLUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
+	t.Run("skips a module without generator config", func(t *testing.T) {
+		chdirTempModule(t)
 		mock := &generateMockRunner{}
 		originalRunner := GetRunner()
 		_ = SetRunner(mock) //nolint:errcheck // test cleanup
@@ -461,12 +393,14 @@ func TestGenerateSQLUnit(t *testing.T) {
 		err := g.SQL()
 
 		require.NoError(t, err)
+		assert.Empty(t, mock.Commands)
 	})
 }
 
 // TestGenerateWireUnit tests Generate.Wire
 func TestGenerateWireUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
+	t.Run("skips a module without generator config", func(t *testing.T) {
+		chdirTempModule(t)
 		mock := &generateMockRunner{}
 		originalRunner := GetRunner()
 		_ = SetRunner(mock) //nolint:errcheck // test cleanup
@@ -478,12 +412,14 @@ func TestGenerateWireUnit(t *testing.T) {
 		err := g.Wire()
 
 		require.NoError(t, err)
+		assert.Empty(t, mock.Commands)
 	})
 }
 
 // TestGenerateConfigUnit tests Generate.Config
 func TestGenerateConfigUnit(t *testing.T) {
-	t.Run("runs echo command", func(t *testing.T) {
+	t.Run("skips a module without generator config", func(t *testing.T) {
+		chdirTempModule(t)
 		mock := &generateMockRunner{}
 		originalRunner := GetRunner()
 		_ = SetRunner(mock) //nolint:errcheck // test cleanup
@@ -495,6 +431,7 @@ func TestGenerateConfigUnit(t *testing.T) {
 		err := g.Config()
 
 		require.NoError(t, err)
+		assert.Empty(t, mock.Commands)
 	})
 }
 
@@ -0,0 +1,568 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/common/env"
+	mageErrors "github.com/mrz1836/mage-x/pkg/common/errors"
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for git hook operations
+var (
+	errGitHookNameRequired = errors.New("hook name is required. Use: magex git:hook <name> [hook arguments]")
+	errUnknownGitHook      = errors.New("unknown git hook")
+	errGitHookBackupExists = errors.New("cannot chain the existing hook, its backup already exists")
+	errGitDirNotFound      = errors.New("git directory not found")
+)
+
+const (
+	// gitHookMarker identifies the hook scripts written by install:githooks
+	gitHookMarker = "# Managed by magex install:githooks"
+
+	// gitHookLocalSuffix is appended to a user hook that a managed hook runs first
+	gitHookLocalSuffix = ".local"
+
+	// gitHookMagexEnv names the magex binary that hooks call back into
+	gitHookMagexEnv = "MAGEX"
+
+	// gitHookPreCommit is the hook staged_only applies to
+	gitHookPreCommit = "pre-commit"
+
+	// legacyPreCommitHook is the pre-commit hook older versions installed; it is
+	// replaced rather than chained
+	legacyPreCommitHook = `#!/bin/sh
+# Run linting before commit
+if command -v golangci-lint >/dev/null 2>&1; then
+    golangci-lint run
+fi
+`
+
+	// gitHookTemplate is the script installed for each hook. {{read}} and {{feed}}
+	// save stdin for hooks that read it, so the chained hook and magex both see it.
+	gitHookTemplate = `#!/bin/sh
+` + gitHookMarker + `
+# Runs the magex commands configured under hooks.{{hook}} in .mage.yaml.
+# Remove with: magex install:githooks uninstall=true
+
+hook_dir=$(dirname "$0")
+{{read}}if [ -x "$hook_dir/{{hook}}` + gitHookLocalSuffix + `" ]; then
+	"$hook_dir/{{hook}}` + gitHookLocalSuffix + `" "$@"{{feed}} || exit $?
+fi
+
+MAGEX=${MAGEX:-magex}
+if ! command -v "$MAGEX" >/dev/null 2>&1; then
+	echo "magex not found, skipping the {{hook}} hook" >&2
+	exit 0
+fi
+export MAGEX
+{{exec}}"$MAGEX" git:hook {{hook}} "$@"{{feed}}
+`
+)
+
+// gitHookNames lists the client-side hooks git runs
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var gitHookNames = map[string]bool{
+	"applypatch-msg":        true,
+	"pre-applypatch":        true,
+	"post-applypatch":       true,
+	"pre-commit":            true,
+	"pre-merge-commit":      true,
+	"prepare-commit-msg":    true,
+	"commit-msg":            true,
+	"post-commit":           true,
+	"pre-rebase":            true,
+	"post-checkout":         true,
+	"post-merge":            true,
+	"pre-push":              true,
+	"post-rewrite":          true,
+	"pre-auto-gc":           true,
+	"reference-transaction": true,
+	"sendemail-validate":    true,
+}
+
+// gitHooksWithStdin are the hooks git feeds input on stdin
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var gitHooksWithStdin = map[string]bool{
+	"pre-push":              true,
+	"post-rewrite":          true,
+	"reference-transaction": true,
+}
+
+// stagedFormatCommands are the commands staged_only narrows to the staged Go files
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var stagedFormatCommands = map[string]bool{
+	"format":         true,
+	"format:default": true,
+	"format:fix":     true,
+	"format:go":      true,
+	"format:fumpt":   true,
+	"format:gofmt":   true,
+}
+
+// stagedLintCommands are the lint commands staged_only narrows to the packages of
+// the staged Go files
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var stagedLintCommands = map[string]bool{
+	"lint":         true,
+	"lint:default": true,
+	"lint:go":      true,
+	"lint:fix":     true,
+}
+
+// GitHooks installs the git hooks configured in .mage.yaml
+func (Install) GitHooks() error {
+	return Install{}.GitHooksWithArgs()
+}
+
+// GitHooksWithArgs installs a hook for each entry of the hooks section in .mage.yaml
+// (a pre-commit hook running lint when there is none). The hooks call back into
+// "magex git:hook", so edits to the configured commands apply without reinstalling.
+// A hook magex did not write is kept as <hook>.local and run first, and managed
+// hooks that are no longer configured are removed.
+// Supports:
+//   - uninstall: Remove the managed hooks and restore the chained ones (default: false)
+func (Install) GitHooksWithArgs(argsList ...string) error {
+	params := utils.ParseParams(argsList)
+	uninstall := utils.IsParamTrue(params, "uninstall")
+
+	if uninstall {
+		utils.Header("Removing Git Hooks")
+	} else {
+		utils.Header("Installing Git Hooks")
+	}
+
+	// Check if we're in a git repository
+	if !utils.FileExists(".git") {
+		utils.Warn("Not a git repository, skipping git hooks installation")
+		return nil
+	}
+
+	hooksDir, err := gitHooksDir()
+	if err != nil {
+		return err
+	}
+
+	if uninstall {
+		removed, removeErr := removeGitHooks(hooksDir, nil)
+		if removeErr != nil {
+			return removeErr
+		}
+		if removed == 0 {
+			utils.Info("No magex git hooks installed")
+		} else {
+			utils.Success("Removed %d git hooks", removed)
+		}
+		return nil
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	hooks, err := configuredGitHooks(config)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.EnsureDir(hooksDir); err != nil {
+		return mageErrors.WrapError(err, "failed to create hooks directory")
+	}
+
+	// Hooks dropped from the config go away with their scripts
+	if _, err := removeGitHooks(hooksDir, hooks); err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(hooks)) {
+		if err := installGitHook(hooksDir, name); err != nil {
+			return err
+		}
+		utils.Info("%s: magex %s", name, strings.Join(hooks[name], ", magex "))
+	}
+
+	utils.Success("Git hooks installed")
+	if config.Hooks.StagedOnly {
+		utils.Info("Pre-commit formatting and linting only cover staged Go files")
+	}
+	return nil
+}
+
+// Hook runs the magex commands configured for a git hook. The hooks written by
+// install:githooks call it with the hook name and the arguments git passed them;
+// $1, $2... in a command are replaced by those arguments.
+func (Git) Hook(args ...string) error {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return errGitHookNameRequired
+	}
+	name, hookArgs := args[0], args[1:]
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	hooks, err := configuredGitHooks(config)
+	if err != nil {
+		return err
+	}
+
+	commands := hooks[name]
+	if len(commands) == 0 {
+		utils.Info("No commands configured for the %s hook", name)
+		return nil
+	}
+
+	utils.Header("Running " + name + " Hook")
+	stagedOnly := config.Hooks.StagedOnly && name == gitHookPreCommit
+	for _, command := range commands {
+		fields := strings.Fields(expandGitHookArgs(command, hookArgs))
+		if len(fields) == 0 {
+			continue
+		}
+		if err := runGitHookCommand(config, fields, stagedOnly); err != nil {
+			return fmt.Errorf("%s hook: magex %s failed: %w", name, strings.Join(fields, " "), err)
+		}
+	}
+
+	utils.Success("%s hook passed", name)
+	return nil
+}
+
+// configuredGitHooks returns the hooks section of the config, defaulting to a
+// pre-commit hook that runs lint
+func configuredGitHooks(config *Config) (map[string][]string, error) {
+	if len(config.Hooks.Commands) == 0 {
+		return map[string][]string{gitHookPreCommit: {"lint"}}, nil
+	}
+	for name := range config.Hooks.Commands {
+		if !gitHookNames[name] {
+			return nil, fmt.Errorf("%w: %s", errUnknownGitHook, name)
+		}
+	}
+	return config.Hooks.Commands, nil
+}
+
+// gitHooksDir returns the directory git runs hooks from: core.hooksPath when it
+// is set, otherwise the hooks directory of the repository, which worktrees share
+func gitHooksDir() (string, error) {
+	output, err := GetRunner().RunCmdOutput("git", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errGitDirNotFound, err)
+	}
+	dir := strings.TrimSpace(output)
+	if dir == "" {
+		return "", errGitDirNotFound
+	}
+	return dir, nil
+}
+
+// installGitHook writes the managed script for a hook, first moving a hook that
+// magex did not write to <hook>.local so it keeps running
+func installGitHook(hooksDir, name string) error {
+	path := filepath.Join(hooksDir, name)
+	managed, err := isManagedGitHook(path)
+	if err != nil {
+		return err
+	}
+
+	if !managed && utils.FileExists(path) {
+		local := path + gitHookLocalSuffix
+		if utils.FileExists(local) {
+			return fmt.Errorf("%w: %s", errGitHookBackupExists, local)
+		}
+		if err := os.Rename(path, local); err != nil {
+			return fmt.Errorf("failed to keep the existing %s hook: %w", name, err)
+		}
+		utils.Info("Existing %s hook kept as %s and run first", name, filepath.Base(local))
+	}
+
+	if err := os.WriteFile(path, []byte(gitHookScript(name)), fileops.PermFileExecutablePrivate); err != nil {
+		return mageErrors.WrapError(err, "failed to write "+name+" hook")
+	}
+	return nil
+}
+
+// removeGitHooks removes the managed hooks that are not in keep, restoring the
+// hooks they chained to, and returns how many were removed
+func removeGitHooks(hooksDir string, keep map[string][]string) (int, error) {
+	entries, err := os.ReadDir(hooksDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, kept := keep[name]; kept || entry.IsDir() || strings.HasSuffix(name, gitHookLocalSuffix) {
+			continue
+		}
+		path := filepath.Join(hooksDir, name)
+		managed, err := isManagedGitHook(path)
+		if err != nil {
+			return removed, err
+		}
+		if !managed {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s hook: %w", name, err)
+		}
+		removed++
+		utils.Info("Removed %s hook", name)
+
+		local := path + gitHookLocalSuffix
+		if utils.FileExists(local) {
+			if err := os.Rename(local, path); err != nil {
+				return removed, fmt.Errorf("failed to restore %s hook: %w", name, err)
+			}
+			utils.Info("Restored the original %s hook", name)
+		}
+	}
+	return removed, nil
+}
+
+// isManagedGitHook reports whether the hook at path was written by install:githooks
+func isManagedGitHook(path string) (bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is inside the hooks directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.Contains(string(data), gitHookMarker) || string(data) == legacyPreCommitHook, nil
+}
+
+// gitHookScript renders the hook script for a hook
+func gitHookScript(name string) string {
+	read, feed, execPrefix := "", "", "exec "
+	if gitHooksWithStdin[name] {
+		read = "input=$(mktemp) || exit 1\ntrap 'rm -f \"$input\"' EXIT\ncat >\"$input\"\n"
+		feed = ` <"$input"`
+		execPrefix = ""
+	}
+	return strings.NewReplacer(
+		"{{hook}}", name,
+		"{{read}}", read,
+		"{{feed}}", feed,
+		"{{exec}}", execPrefix,
+	).Replace(gitHookTemplate)
+}
+
+// expandGitHookArgs replaces $1, $2... in a hook command with the hook arguments
+// and other $VAR references with the environment
+func expandGitHookArgs(command string, hookArgs []string) string {
+	return os.Expand(command, func(key string) string {
+		if n, err := strconv.Atoi(key); err == nil {
+			if n >= 1 && n <= len(hookArgs) {
+				return hookArgs[n-1]
+			}
+			return ""
+		}
+		return os.Getenv(key)
+	})
+}
+
+// runGitHookCommand runs one configured command through magex. With staged_only,
+// formatting and linting are narrowed to the staged Go files.
+func runGitHookCommand(config *Config, fields []string, stagedOnly bool) error {
+	if stagedOnly {
+		switch {
+		case stagedFormatCommands[fields[0]]:
+			return formatStagedFiles()
+		case stagedLintCommands[fields[0]]:
+			return lintStagedFiles(config, fields[0] == "lint:fix")
+		}
+	}
+	return GetRunner().RunCmd(env.GetString(gitHookMagexEnv, "magex"), fields...)
+}
+
+// stagedGoFiles returns the staged Go files that are added, copied, modified or
+// renamed, relative to the repository root
+func stagedGoFiles() ([]string, error) {
+	output, err := GetRunner().RunCmdOutput("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	var files []string
+	for _, file := range utils.ParseNonEmptyLines(output) {
+		if strings.HasSuffix(file, ".go") && !strings.HasPrefix(file, "vendor/") && !strings.Contains(file, "/vendor/") {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// formatStagedFiles formats the staged Go files with gofumpt and stages the result
+func formatStagedFiles() error {
+	files, err := stagedGoFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		utils.Info("No staged Go files to format")
+		return nil
+	}
+
+	if err := ensureGofumpt(); err != nil {
+		return fmt.Errorf("failed to ensure gofumpt is installed: %w", err)
+	}
+	unstaged, err := unstagedFiles(files)
+	if err != nil {
+		return err
+	}
+	utils.Info("Formatting %d staged Go files...", len(files))
+	if err := GetRunner().RunCmd("gofumpt", append([]string{"-w", "-extra"}, files...)...); err != nil {
+		return fmt.Errorf("gofumpt failed: %w", err)
+	}
+	return restageFiles(files, unstaged)
+}
+
+// lintStagedFiles runs golangci-lint on the packages holding staged Go files, per module
+func lintStagedFiles(config *Config, fix bool) error {
+	files, err := stagedGoFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		utils.Info("No staged Go files to lint")
+		return nil
+	}
+
+	modules, err := findAllModules()
+	if err != nil {
+		return fmt.Errorf("failed to find modules: %w", err)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	packages := stagedPackages(root, modules, files)
+	if len(packages) == 0 {
+		utils.Info("No staged Go files belong to a module")
+		return nil
+	}
+
+	if err := ensureGolangciLint(config); err != nil {
+		return fmt.Errorf("failed to ensure golangci-lint: %w", err)
+	}
+	var unstaged map[string]bool
+	if fix {
+		if unstaged, err = unstagedFiles(files); err != nil {
+			return err
+		}
+	}
+	for _, module := range modules {
+		targets := packages[module.Path]
+		if len(targets) == 0 {
+			continue
+		}
+		argBuilder := &golangciLintArgs{
+			modulePath: module.Path,
+			config:     config,
+			withFix:    fix,
+			targets:    targets,
+		}
+		utils.Info("Linting %d staged packages in %s...", len(targets), module.Relative)
+		if err := runCommandInModule(module, "golangci-lint", argBuilder.buildArgs()...); err != nil {
+			return fmt.Errorf("golangci-lint failed for %s: %w", module.Relative, err)
+		}
+	}
+
+	if fix {
+		return restageFiles(files, unstaged)
+	}
+	return nil
+}
+
+// stagedPackages maps each module path to the packages ("./dir") of the staged
+// files it contains; a file belongs to the deepest module above it
+func stagedPackages(root string, modules []ModuleInfo, files []string) map[string][]string {
+	packages := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, file := range files {
+		dir := filepath.Dir(filepath.Join(root, filepath.FromSlash(file)))
+
+		var owner *ModuleInfo
+		for i := range modules {
+			module := &modules[i]
+			if dir != module.Path && !strings.HasPrefix(dir, module.Path+string(filepath.Separator)) {
+				continue
+			}
+			if owner == nil || len(module.Path) > len(owner.Path) {
+				owner = module
+			}
+		}
+		if owner == nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(owner.Path, dir)
+		if err != nil {
+			continue
+		}
+		pkg := "./" + filepath.ToSlash(rel)
+		if rel == "." {
+			pkg = "."
+		}
+		if key := owner.Path + "\x00" + pkg; !seen[key] {
+			seen[key] = true
+			packages[owner.Path] = append(packages[owner.Path], pkg)
+		}
+	}
+	for path := range packages {
+		slices.Sort(packages[path])
+	}
+	return packages
+}
+
+// unstagedFiles returns which of files also have changes that are not staged
+func unstagedFiles(files []string) (map[string]bool, error) {
+	output, err := GetRunner().RunCmdOutput("git", append([]string{"diff", "--name-only", "--"}, files...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unstaged changes: %w", err)
+	}
+	unstaged := make(map[string]bool)
+	for _, file := range utils.ParseNonEmptyLines(output) {
+		unstaged[file] = true
+	}
+	return unstaged, nil
+}
+
+// restageFiles stages files again so fixes made by a hook are part of the commit.
+// Files that had unstaged changes before the fixes are left alone, since staging
+// them would also commit the hunks held back from the index.
+func restageFiles(files []string, unstaged map[string]bool) error {
+	var restage []string
+	for _, file := range files {
+		if unstaged[file] {
+			utils.Warn("%s has unstaged changes; review the fixes in it and stage them yourself", file)
+			continue
+		}
+		restage = append(restage, file)
+	}
+	if len(restage) == 0 {
+		return nil
+	}
+	if err := GetRunner().RunCmd("git", append([]string{"add", "--"}, restage...)...); err != nil {
+		return fmt.Errorf("failed to stage the fixed files: %w", err)
+	}
+	return nil
+}
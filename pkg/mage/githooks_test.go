@@ -0,0 +1,231 @@
+package mage
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/mage/testutil"
+)
+
+// useGitHooksRepo creates a repository layout in a temp dir with the given hooks
+// config and returns its hooks directory
+func useGitHooksRepo(t *testing.T, hooks HooksConfig) (string, *testutil.FakeRunner) {
+	t.Helper()
+	chdirTempModule(t)
+	hooksDir := filepath.Join(".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0o750))
+	config := defaultConfig()
+	config.Hooks = hooks
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+	runner := useFakeRunner(t, nil)
+	runner.Outputs[gitHooksPathCmd] = hooksDir + "\n"
+	return hooksDir, runner
+}
+
+// gitHooksPathCmd is the command gitHooksDir asks git for the hooks directory with
+const gitHooksPathCmd = "git rev-parse --path-format=absolute --git-path hooks"
+
+func readHook(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // test file path
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestInstallGitHooks_ChainsAndRemoves(t *testing.T) {
+	hooksDir, _ := useGitHooksRepo(t, HooksConfig{Commands: map[string][]string{
+		"pre-commit": {"format:fix", "lint"},
+		"commit-msg": {"check:commit file=$1"},
+	}})
+	userHook := "#!/bin/sh\necho user hook\n"
+	preCommit := filepath.Join(hooksDir, "pre-commit")
+	require.NoError(t, os.WriteFile(preCommit, []byte(userHook), 0o700)) //nolint:gosec // test hook
+
+	require.NoError(t, Install{}.GitHooks())
+	assert.Equal(t, userHook, readHook(t, preCommit+gitHookLocalSuffix), "the user hook is kept and chained")
+	assert.Contains(t, readHook(t, preCommit), "git:hook pre-commit")
+	assert.Contains(t, readHook(t, filepath.Join(hooksDir, "commit-msg")), "git:hook commit-msg")
+
+	// Reinstalling leaves the chained hook alone
+	require.NoError(t, Install{}.GitHooks())
+	assert.Equal(t, userHook, readHook(t, preCommit+gitHookLocalSuffix))
+
+	// A hook dropped from the config is removed and the user hook restored
+	config := defaultConfig()
+	config.Hooks.Commands = map[string][]string{"commit-msg": {"lint"}}
+	TestSetConfig(config)
+	require.NoError(t, Install{}.GitHooks())
+	assert.Equal(t, userHook, readHook(t, preCommit))
+	assert.NoFileExists(t, preCommit+gitHookLocalSuffix)
+
+	require.NoError(t, Install{}.GitHooksWithArgs("uninstall=true"))
+	assert.NoFileExists(t, filepath.Join(hooksDir, "commit-msg"))
+	assert.Equal(t, userHook, readHook(t, preCommit), "only managed hooks are removed")
+}
+
+func TestInstallGitHooks_ReplacesLegacyHook(t *testing.T) {
+	hooksDir, _ := useGitHooksRepo(t, HooksConfig{})
+	preCommit := filepath.Join(hooksDir, "pre-commit")
+	require.NoError(t, os.WriteFile(preCommit, []byte(legacyPreCommitHook), 0o700)) //nolint:gosec // test hook
+
+	require.NoError(t, Install{}.GitHooks())
+	assert.NoFileExists(t, preCommit+gitHookLocalSuffix)
+	assert.Contains(t, readHook(t, preCommit), gitHookMarker)
+}
+
+func TestInstallGitHooks_Errors(t *testing.T) {
+	t.Run("unknown hook", func(t *testing.T) {
+		useGitHooksRepo(t, HooksConfig{Commands: map[string][]string{"pre-comit": {"lint"}}})
+		require.ErrorIs(t, Install{}.GitHooks(), errUnknownGitHook)
+	})
+
+	t.Run("backup already exists", func(t *testing.T) {
+		hooksDir, _ := useGitHooksRepo(t, HooksConfig{})
+		preCommit := filepath.Join(hooksDir, "pre-commit")
+		require.NoError(t, os.WriteFile(preCommit, []byte("#!/bin/sh\n"), 0o700))                    //nolint:gosec // test hook
+		require.NoError(t, os.WriteFile(preCommit+gitHookLocalSuffix, []byte("#!/bin/sh\n"), 0o700)) //nolint:gosec // test hook
+		require.ErrorIs(t, Install{}.GitHooks(), errGitHookBackupExists)
+	})
+}
+
+func TestGitHooksDir(t *testing.T) {
+	runner := useFakeRunner(t, nil)
+	runner.Outputs[gitHooksPathCmd] = "/repo/.githooks\n"
+
+	dir, err := gitHooksDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/repo/.githooks", dir, "core.hooksPath is whatever git reports")
+
+	runner.Errors[gitHooksPathCmd] = errors.New("not a git repository")
+	_, err = gitHooksDir()
+	require.ErrorIs(t, err, errGitDirNotFound)
+}
+
+func TestGitHook_RunsConfiguredCommands(t *testing.T) {
+	_, runner := useGitHooksRepo(t, HooksConfig{Commands: map[string][]string{
+		"commit-msg": {"lint", "check:commit file=$1"},
+	}})
+	t.Setenv(gitHookMagexEnv, "/opt/magex")
+
+	require.NoError(t, Git{}.Hook("commit-msg", ".git/COMMIT_EDITMSG"))
+	assert.Equal(t, []string{
+		"/opt/magex lint",
+		"/opt/magex check:commit file=.git/COMMIT_EDITMSG",
+	}, runner.Commands())
+
+	runner.Reset()
+	require.NoError(t, Git{}.Hook("pre-push"))
+	assert.Empty(t, runner.Commands(), "hooks without commands do nothing")
+
+	require.ErrorIs(t, Git{}.Hook(), errGitHookNameRequired)
+}
+
+func TestGitHook_StagedOnly(t *testing.T) {
+	chdirTempModule(t)
+	writeTestFiles(t, map[string]string{
+		"tools/go.mod":  "module example.com/tools\n\ngo 1.24\n",
+		"tools/gen.go":  "package tools\n",
+		"pkg/a/a.go":    "package a\n",
+		"main.go":       "package main\n",
+		"vendor/v/v.go": "package v\n",
+		"README.md":     "# readme\n",
+	})
+	setCommandExists(t, func(string) bool { return true })
+	config := defaultConfig()
+	config.Hooks = HooksConfig{StagedOnly: true, Commands: map[string][]string{
+		"pre-commit": {"format:fix", "lint", "test:short"},
+	}}
+	t.Setenv(gitHookMagexEnv, "magex")
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+	runner := useFakeRunner(t, func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, "git diff --cached") {
+			return "main.go\npkg/a/a.go\ntools/gen.go\nvendor/v/v.go\nREADME.md\n", nil
+		}
+		if strings.HasPrefix(cmd, "git diff --name-only --") {
+			return "pkg/a/a.go\n", nil
+		}
+		return "", nil
+	})
+
+	require.NoError(t, Git{}.Hook("pre-commit"))
+
+	var lint []string
+	for _, cmd := range runner.Commands() {
+		if strings.HasPrefix(cmd, "golangci-lint") {
+			lint = append(lint, cmd)
+		}
+	}
+	assert.Contains(t, runner.Commands(), "gofumpt -w -extra main.go pkg/a/a.go tools/gen.go")
+	assert.Contains(t, runner.Commands(), "git diff --name-only -- main.go pkg/a/a.go tools/gen.go")
+	assert.Contains(t, runner.Commands(), "git add -- main.go tools/gen.go", "files with unstaged changes are not restaged")
+	require.Len(t, lint, 2, "one golangci-lint run per module")
+	assert.True(t, strings.HasPrefix(lint[0], "golangci-lint run . ./pkg/a "), lint[0])
+	assert.True(t, strings.HasPrefix(lint[1], "golangci-lint run . "), lint[1])
+	assert.Equal(t, "magex test:short", runner.Commands()[len(runner.Commands())-1], "other commands run through magex")
+}
+
+func TestStagedPackages(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(string(filepath.Separator), "repo")
+	modules := []ModuleInfo{
+		{Path: root, Relative: "."},
+		{Path: filepath.Join(root, "tools"), Relative: "tools"},
+	}
+	packages := stagedPackages(root, modules, []string{"main.go", "cmd/app/main.go", "cmd/app/flags.go", "tools/gen.go", "toolsx/x.go"})
+	assert.Equal(t, map[string][]string{
+		root:                         {".", "./cmd/app", "./toolsx"},
+		filepath.Join(root, "tools"): {"."},
+	}, packages)
+}
+
+func TestExpandGitHookArgs(t *testing.T) {
+	t.Setenv("HOOK_TEST_VAR", "value")
+
+	assert.Equal(t, "check file=msg", expandGitHookArgs("check file=$1", []string{"msg"}))
+	assert.Equal(t, "check file=", expandGitHookArgs("check file=$2", []string{"msg"}))
+	assert.Equal(t, "check var=value", expandGitHookArgs("check var=${HOOK_TEST_VAR}", nil))
+}
+
+// TestGitHookScript runs the installed scripts to check they chain the user hook
+// and hand git's arguments and stdin to magex
+func TestGitHookScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	fake := "#!/bin/sh\n{ echo \"$(basename \"$0\") $*\"; cat; } >>" + log + "\n"
+	magex := filepath.Join(dir, "magex")
+	require.NoError(t, os.WriteFile(magex, []byte(fake), 0o700))                                //nolint:gosec // test executable
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-push.local"), []byte(fake), 0o700)) //nolint:gosec // test executable
+
+	for _, name := range []string{"commit-msg", "pre-push"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(gitHookScript(name)), 0o700)) //nolint:gosec // test executable
+	}
+
+	run := func(name, stdin string, args ...string) {
+		cmd := exec.Command(filepath.Join(dir, name), args...) //nolint:gosec // test executable
+		cmd.Env = append(os.Environ(), gitHookMagexEnv+"="+magex)
+		cmd.Stdin = strings.NewReader(stdin)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	run("commit-msg", "", ".git/COMMIT_EDITMSG")
+	run("pre-push", "refs/heads/main abc refs/heads/main def\n", "origin", "git@example.com:repo.git")
+
+	assert.Equal(t, "magex git:hook commit-msg .git/COMMIT_EDITMSG\n"+
+		"pre-push.local origin git@example.com:repo.git\nrefs/heads/main abc refs/heads/main def\n"+
+		"magex git:hook pre-push origin git@example.com:repo.git\nrefs/heads/main abc refs/heads/main def\n",
+		readHook(t, log))
+}
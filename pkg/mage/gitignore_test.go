@@ -0,0 +1,77 @@
+package mage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitignoreMatcher(t *testing.T) {
+	t.Parallel()
+
+	m := parseGitignore(`# build output
+/bin/
+*.log
+!keep.log
+dist
+docs/**/*.gen.go
+internal/tmp/
+\#literal
+gen_[ab].go
+`)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "bin", isDir: true, want: true},
+		{path: "bin/magex", want: true},
+		{path: "cmd/bin/tool.go", want: false},
+		{path: "debug.log", want: true},
+		{path: "logs/app/debug.log", want: true},
+		{path: "keep.log", want: false},
+		{path: "dist/app.go", want: true},
+		{path: "pkg/dist/app.go", want: true},
+		{path: "docs/a/b/api.gen.go", want: true},
+		{path: "docs/api.gen.go", want: true},
+		{path: "docs/api.go", want: false},
+		{path: "internal/tmp/x.go", want: true},
+		{path: "pkg/internal/tmp/x.go", want: false},
+		{path: "#literal", want: true},
+		{path: "gen_a.go", want: true},
+		{path: "gen_c.go", want: false},
+		{path: "main.go", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, m.Match(tt.path, tt.isDir), "Match(%q)", tt.path)
+	}
+}
+
+func TestGitignoreMatcher_DirOnlyPattern(t *testing.T) {
+	t.Parallel()
+
+	m := parseGitignore("build/\n")
+	assert.True(t, m.Match("build", true))
+	assert.False(t, m.Match("build", false), "a file named like a directory-only pattern is not ignored")
+	assert.True(t, m.Match("build/out.go", false))
+}
+
+func TestLoadGitignore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.False(t, loadGitignore(dir).Match("anything.go", false), "missing .gitignore ignores nothing")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileGitignore), []byte("vendor/\n*.tmp\n"), 0o600))
+	m := loadGitignore(dir)
+	assert.True(t, m.Match("vendor/lib/lib.go", false))
+	assert.True(t, m.Match("a/b.tmp", false))
+	assert.False(t, m.Match("a/b.go", false))
+
+	var nilMatcher *gitignoreMatcher
+	assert.False(t, nilMatcher.Match("a.go", false))
+}
@@ -506,40 +506,6 @@ func (Install) Docker() error {
 	return nil
 }
 
-// GitHooks installs git hooks
-func (Install) GitHooks() error {
-	utils.Header("Installing Git Hooks")
-
-	// Check if we're in a git repository
-	if !utils.FileExists(".git") {
-		utils.Warn("Not a git repository, skipping git hooks installation")
-		return nil
-	}
-
-	// Create hooks directory if it doesn't exist
-	hooksDir := ".git/hooks"
-	if err := utils.EnsureDir(hooksDir); err != nil {
-		return mageErrors.WrapError(err, "failed to create hooks directory")
-	}
-
-	// Install pre-commit hook
-	preCommitPath := filepath.Join(hooksDir, "pre-commit")
-	preCommitContent := `#!/bin/sh
-# Run linting before commit
-if command -v golangci-lint >/dev/null 2>&1; then
-    golangci-lint run
-fi
-`
-
-	if err := os.WriteFile(preCommitPath, []byte(preCommitContent), fileops.PermFileExecutablePrivate); err != nil {
-		return mageErrors.WrapError(err, "failed to write pre-commit hook")
-	}
-
-	utils.Success("Git hooks installed")
-	utils.Info("Pre-commit hook will run linting before commits")
-	return nil
-}
-
 // CI installs CI tools
 func (Install) CI() error {
 	utils.Header("Installing CI Tools")
@@ -578,49 +544,6 @@ func (Install) CI() error {
 	return nil
 }
 
-// Certs installs certificates
-func (Install) Certs() error {
-	utils.Header("Installing Certificates")
-
-	// Check if we need to install certificates
-	if !utils.CommandExists("openssl") {
-		utils.Warn("OpenSSL not found, cannot generate certificates")
-		utils.Info("Install OpenSSL for certificate management")
-		return nil
-	}
-
-	// Create certs directory
-	certsDir := "certs"
-	if err := utils.EnsureDir(certsDir); err != nil {
-		return mageErrors.WrapError(err, "failed to create certs directory")
-	}
-
-	// Generate self-signed certificate for development
-	certPath := filepath.Join(certsDir, "server.crt")
-	keyPath := filepath.Join(certsDir, "server.key")
-
-	if utils.FileExists(certPath) && utils.FileExists(keyPath) {
-		utils.Info("Certificates already exist")
-		return nil
-	}
-
-	utils.Info("Generating self-signed certificate for development...")
-	args := []string{
-		"req", "-x509", "-newkey", "rsa:4096", "-keyout", keyPath,
-		"-out", certPath, "-days", "365", "-nodes",
-		"-subj", "/C=US/ST=Development/L=Local/O=Dev/CN=localhost",
-	}
-
-	if err := GetRunner().RunCmd("openssl", args...); err != nil {
-		return mageErrors.WrapError(err, "failed to generate certificates")
-	}
-
-	utils.Success("Development certificates generated")
-	utils.Info("Certificate: %s", certPath)
-	utils.Info("Private key: %s", keyPath)
-	return nil
-}
-
 // Package installs package
 func (Install) Package() error {
 	utils.Header("Installing Package")
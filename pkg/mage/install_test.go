@@ -342,6 +342,7 @@ func TestInstallGitHooks(t *testing.T) {
 		gitDir := filepath.Join(h.tmpDir, ".git")
 		hooksDir := filepath.Join(gitDir, "hooks")
 		require.NoError(t, os.MkdirAll(hooksDir, fileops.PermDirSensitive))
+		h.expectCmdOutput("git", hooksDir+"\n", nil)
 
 		// Change to temp directory
 		oldDir, err := os.Getwd()
@@ -358,7 +359,8 @@ func TestInstallGitHooks(t *testing.T) {
 		preCommitPath := filepath.Join(hooksDir, "pre-commit")
 		content, err := os.ReadFile(preCommitPath) //nolint:gosec // test file path
 		require.NoError(t, err)
-		assert.Contains(t, string(content), "golangci-lint")
+		assert.Contains(t, string(content), gitHookMarker)
+		assert.Contains(t, string(content), "git:hook pre-commit")
 	})
 
 	t.Run("skips when not a git repo", func(t *testing.T) {
@@ -407,27 +409,20 @@ func TestInstallCI(t *testing.T) {
 
 // TestInstallCerts tests Install.Certs
 func TestInstallCerts(t *testing.T) {
-	t.Run("generates certificates when openssl available", func(t *testing.T) {
+	t.Run("generates certificates without openssl", func(t *testing.T) {
 		h := newInstallTestHelper(t)
 		defer h.teardown(t)
-		h.expectCmd("openssl")
+		t.Chdir(h.tmpDir)
 
-		// Create certs directory
-		certsDir := filepath.Join(h.tmpDir, "certs")
-		require.NoError(t, os.MkdirAll(certsDir, fileops.PermDirSensitive))
-
-		oldDir, err := os.Getwd()
+		err := Install{}.Certs()
 		require.NoError(t, err)
-		require.NoError(t, os.Chdir(h.tmpDir))
-		defer func() {
-			require.NoError(t, os.Chdir(oldDir))
-		}()
 
-		err = Install{}.Certs()
-		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join("certs", "ca.crt"))
+		assert.FileExists(t, filepath.Join("certs", "server.crt"))
+		assert.FileExists(t, filepath.Join("certs", "server.key"))
 	})
 
-	t.Run("skips when certificates exist", func(t *testing.T) {
+	t.Run("replaces unreadable certificates with force", func(t *testing.T) {
 		h := newInstallTestHelper(t)
 		defer h.teardown(t)
 
@@ -436,16 +431,16 @@ func TestInstallCerts(t *testing.T) {
 		require.NoError(t, os.MkdirAll(certsDir, fileops.PermDirSensitive))
 		require.NoError(t, os.WriteFile(filepath.Join(certsDir, "server.crt"), []byte("cert"), fileops.PermFile))
 		require.NoError(t, os.WriteFile(filepath.Join(certsDir, "server.key"), []byte("key"), fileops.PermFileSensitive))
+		t.Chdir(h.tmpDir)
 
-		oldDir, err := os.Getwd()
+		require.ErrorIs(t, Install{}.Certs(), errDevCertNotManaged)
+
+		err := Install{}.CertsWithArgs("force=true")
 		require.NoError(t, err)
-		require.NoError(t, os.Chdir(h.tmpDir))
-		defer func() {
-			require.NoError(t, os.Chdir(oldDir))
-		}()
 
-		err = Install{}.Certs()
+		_, err = readCertificate(filepath.Join("certs", "server.crt"))
 		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join("certs", "server.crt"+certBackupSuffix))
 	})
 }
 
@@ -453,12 +453,14 @@ type Changelog struct {
 
 // ChangeEntry represents a single change
 type ChangeEntry struct {
-	Type        string // feat, fix, docs, etc.
-	Description string
-	Scope       string
-	Breaking    bool
-	Author      string
-	CommitHash  string
+	Type         string // feat, fix, docs, etc.
+	Description  string
+	Scope        string
+	Breaking     bool
+	Author       string
+	CommitHash   string
+	BreakingNote string   // Text of a BREAKING CHANGE footer
+	Issues       []string // Issue references such as #123
 }
 
 // ArchiveFormat represents archive file format
@@ -0,0 +1,397 @@
+package mage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for the license header check
+var (
+	errLicenseHeaderIssues   = errors.New("license header issues found")
+	errLicenseHolderRequired = errors.New("lint.license.holder is required when the header uses {{holder}}")
+)
+
+// License header rule names
+const (
+	LicenseRuleMissing  = "missing-license-header"
+	LicenseRuleMismatch = "license-header-mismatch"
+)
+
+// licenseReportFile is the JSON Lines report written next to the CI results when ci_format=json
+const licenseReportFile = "license-check.jsonl"
+
+// License header template placeholders
+const (
+	licensePlaceholderYear   = "{{year}}"
+	licensePlaceholderHolder = "{{holder}}"
+)
+
+// licenseYearPattern matches a single year or a year range such as "2019-2025" or "2019, 2025"
+const licenseYearPattern = `\d{4}(?:\s*[-,]\s*\d{4})?`
+
+// licenseGeneratedPattern matches the standard marker for generated files (https://go.dev/s/generatedcode)
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var licenseGeneratedPattern = regexp.MustCompile(`^(?://|#) Code generated .* DO NOT EDIT\.$`)
+
+// licenseYearRegexp finds the year (or year range) inside an existing header
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var licenseYearRegexp = regexp.MustCompile(licenseYearPattern)
+
+// licenseIssue is a single finding reported by check:license
+type licenseIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as file:line: [rule] message
+func (i licenseIssue) String() string {
+	return fmt.Sprintf("%s:%d: [%s] %s", i.File, i.Line, i.Rule, i.Message)
+}
+
+// toCIFailure converts the issue into a CITestFailure for the CI reporters
+func (i licenseIssue) toCIFailure() CITestFailure {
+	return CITestFailure{
+		Package:   "license",
+		Test:      i.Rule,
+		Error:     i.Message,
+		Output:    i.String(),
+		Type:      FailureTypeLint,
+		File:      i.File,
+		Line:      i.Line,
+		Signature: fmt.Sprintf("%s:%s", i.File, i.Rule),
+	}
+}
+
+// licenseHeader is a header template rendered for one comment syntax
+type licenseHeader struct {
+	prefix  string
+	year    string
+	lines   []string
+	pattern *regexp.Regexp
+	// firstLine matches the first template line with any year and holder,
+	// to recognize an outdated header of the same template
+	firstLine *regexp.Regexp
+}
+
+// newLicenseHeader renders the configured template for files commented with prefix
+func newLicenseHeader(cfg LicenseConfig, prefix string) *licenseHeader {
+	year := cfg.Year
+	if year == "" {
+		year = strconv.Itoa(time.Now().Year())
+	}
+
+	var lines, patterns []string
+	var firstLine string
+	for _, line := range strings.Split(strings.TrimRight(cfg.Header, "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line != "" {
+			line = prefix + " " + line
+		} else {
+			line = prefix
+		}
+		lines = append(lines, line)
+
+		holderPattern := ".+"
+		if cfg.Holder != "" {
+			holderPattern = regexp.QuoteMeta(cfg.Holder)
+		}
+		quoted := regexp.QuoteMeta(line)
+		quoted = strings.ReplaceAll(quoted, regexp.QuoteMeta(licensePlaceholderYear), licenseYearPattern)
+		quoted = strings.ReplaceAll(quoted, regexp.QuoteMeta(licensePlaceholderHolder), holderPattern)
+		patterns = append(patterns, quoted)
+
+		if firstLine == "" {
+			firstLine = regexp.QuoteMeta(line)
+			firstLine = strings.ReplaceAll(firstLine, regexp.QuoteMeta(licensePlaceholderYear), `.*`)
+			firstLine = strings.ReplaceAll(firstLine, regexp.QuoteMeta(licensePlaceholderHolder), `.*`)
+		}
+	}
+
+	return &licenseHeader{
+		prefix:    prefix,
+		year:      year,
+		lines:     lines,
+		pattern:   regexp.MustCompile(`\A` + strings.Join(patterns, `\n`) + `(?:\n|\z)`),
+		firstLine: regexp.MustCompile(`\A` + firstLine + `\z`),
+	}
+}
+
+// render returns the header lines with the placeholders filled in
+func (h *licenseHeader) render(year, holder string) []string {
+	rendered := make([]string, 0, len(h.lines))
+	for _, line := range h.lines {
+		line = strings.ReplaceAll(line, licensePlaceholderYear, year)
+		line = strings.ReplaceAll(line, licensePlaceholderHolder, holder)
+		rendered = append(rendered, line)
+	}
+	return rendered
+}
+
+// licenseCommentPrefix returns the line comment prefix for a file, or "" when the file is not checked
+func licenseCommentPrefix(path string, cfg LicenseConfig) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "//"
+	case ".sh":
+		if cfg.IncludeShell {
+			return "#"
+		}
+	case ".yml", ".yaml":
+		if cfg.IncludeYAML {
+			return "#"
+		}
+	}
+	return ""
+}
+
+// License checks license headers in the codebase.
+func (c Check) License() error {
+	return c.LicenseWithArgs()
+}
+
+// LicenseWithArgs checks that every Go file (and optionally shell and YAML files)
+// starts with the header configured in lint.license of .mage.yaml. Generated
+// files are skipped; under ci=true issues are emitted through the CI reporters.
+// Supports:
+//   - fix: Insert missing headers and rewrite mismatched ones in place (default: false)
+//   - dir: Root directory to scan (default: ".")
+//   - ci: Enable CI mode reporting (auto-detected in CI environments)
+//   - ci_format: CI output format (github, json, auto)
+func (c Check) LicenseWithArgs(argsList ...string) error {
+	utils.Header("Checking License Headers")
+	start := time.Now()
+
+	params := utils.ParseParams(argsList)
+	root := utils.GetParam(params, "dir", ".")
+	fix := utils.IsParamTrue(params, "fix")
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	licenseCfg := config.Lint.License
+	if strings.TrimSpace(licenseCfg.Header) == "" {
+		utils.Info("No license header configured (lint.license.header), skipping")
+		return nil
+	}
+	if fix && licenseCfg.Holder == "" && strings.Contains(licenseCfg.Header, licensePlaceholderHolder) {
+		return errLicenseHolderRequired
+	}
+
+	files, err := findLicenseFiles(root, licenseCfg)
+	if err != nil {
+		return err
+	}
+
+	issues, fixed, err := checkLicenseHeaders(files, licenseCfg, fix)
+	if err != nil {
+		return err
+	}
+	for _, file := range fixed {
+		utils.Println("Updated license header: " + file)
+	}
+
+	detector := NewCIDetector()
+	mode := detector.GetConfig(params, config)
+	var platformReporter CIReporter
+	if mode.Enabled {
+		failures := make([]CITestFailure, 0, len(issues))
+		for _, issue := range issues {
+			failures = append(failures, issue.toCIFailure())
+		}
+		platformReporter = newPlatformReporter(detector, mode)
+		if reportErr := reportLintFailures(newLintReporter(platformReporter, mode, licenseReportFile), ciMetadata(detector), len(files), failures, time.Since(start)); reportErr != nil {
+			return reportErr
+		}
+	}
+
+	if platformReporter == nil {
+		for _, issue := range issues {
+			utils.Println(issue.String())
+		}
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %d of %d files (run check:license fix=true to update them)", errLicenseHeaderIssues, len(issues), len(files))
+	}
+
+	if len(fixed) > 0 {
+		utils.Success("Updated license headers in %d of %d files", len(fixed), len(files))
+		return nil
+	}
+	utils.Success("License headers are present in all %d files", len(files))
+	return nil
+}
+
+// findLicenseFiles returns the files under root that take a license header, sorted.
+// vendor, node_modules, testdata and hidden directories other than .github are skipped.
+func findLicenseFiles(root string, cfg LicenseConfig) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && d.Name() != ".github" && shouldSkipScanDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || licenseCommentPrefix(path, cfg) == "" {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+		if !matchesIgnorePattern(filepath.ToSlash(rel), cfg.Ignore) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find source files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// checkLicenseHeaders checks each file and, when fix is set, rewrites the ones with issues.
+// It returns the issues left unfixed and the files that were rewritten.
+func checkLicenseHeaders(files []string, cfg LicenseConfig, fix bool) ([]licenseIssue, []string, error) {
+	headers := make(map[string]*licenseHeader)
+	var issues []licenseIssue
+	var fixed []string
+
+	for _, file := range files {
+		prefix := licenseCommentPrefix(file, cfg)
+		header, ok := headers[prefix]
+		if !ok {
+			header = newLicenseHeader(cfg, prefix)
+			headers[prefix] = header
+		}
+
+		content, err := os.ReadFile(file) // #nosec G304 -- path is a source file inside the scanned tree
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		issue, updated := checkLicenseHeader(string(content), header, cfg.Holder)
+		if issue == nil {
+			continue
+		}
+		issue.File = file
+		if !fix {
+			issues = append(issues, *issue)
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		if err := os.WriteFile(file, []byte(updated), info.Mode().Perm()); err != nil {
+			return nil, nil, fmt.Errorf("failed to update %s: %w", file, err)
+		}
+		fixed = append(fixed, file)
+	}
+
+	return issues, fixed, nil
+}
+
+// checkLicenseHeader checks the leading comment block of a single file.
+// It returns nil when the header is present or the file is generated; otherwise
+// it returns the issue and the content with the header inserted or replaced.
+// Only a block that is an existing license header is replaced; any other leading
+// comment (such as a package doc) is kept below the inserted header.
+func checkLicenseHeader(content string, header *licenseHeader, holder string) (*licenseIssue, string) {
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(content, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	// The header goes after a shebang line, if there is one
+	first := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+		first = 1
+	}
+
+	// Skip generated files: the marker sits in the leading comments, before any code
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if licenseGeneratedPattern.MatchString(trimmed) {
+			return nil, content
+		}
+		if trimmed != "" && !strings.HasPrefix(trimmed, header.prefix) {
+			break
+		}
+	}
+
+	// The leading comment block ends at the first blank line, code or directive
+	end := first
+	for end < len(lines) && strings.HasPrefix(lines[end], header.prefix) && !isCommentDirective(lines[end]) {
+		end++
+	}
+	block := lines[first:end]
+
+	if header.pattern.MatchString(strings.Join(block, "\n")) {
+		return nil, content
+	}
+
+	issue := &licenseIssue{Line: first + 1, Rule: LicenseRuleMissing, Message: "license header is missing"}
+	year := header.year
+	var rest []string
+	if isLicenseHeader(block, header) {
+		issue.Rule = LicenseRuleMismatch
+		issue.Message = "license header does not match the configured template"
+		if existing := licenseYearRegexp.FindString(strings.Join(block, "\n")); existing != "" {
+			year = existing
+		}
+		rest = lines[end:]
+	} else {
+		rest = append([]string{""}, lines[first:]...)
+	}
+
+	updated := make([]string, 0, len(lines)+len(header.lines)+1)
+	updated = append(updated, lines[:first]...)
+	updated = append(updated, header.render(year, holder)...)
+	updated = append(updated, rest...)
+	return issue, strings.Join(updated, newline)
+}
+
+// isCommentDirective reports whether a comment line is a tool directive such as //go:build
+func isCommentDirective(line string) bool {
+	return strings.HasPrefix(line, "//go:") || strings.HasPrefix(line, "// +build") || strings.HasPrefix(line, "//nolint")
+}
+
+// isLicenseHeader reports whether a comment block is an existing license header:
+// it starts with the first line of the configured template (with any year and
+// holder), or with a Copyright or SPDX-License-Identifier line
+func isLicenseHeader(block []string, header *licenseHeader) bool {
+	if len(block) == 0 {
+		return false
+	}
+	if header.firstLine.MatchString(block[0]) {
+		return true
+	}
+	first := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(block[0], header.prefix)))
+	return strings.HasPrefix(first, "copyright") || strings.HasPrefix(first, "spdx-license-identifier:")
+}
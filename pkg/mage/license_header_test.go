@@ -0,0 +1,252 @@
+package mage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLicenseTemplate = "Copyright {{year}} {{holder}}\nSPDX-License-Identifier: MIT\n"
+
+func testLicenseConfig() LicenseConfig {
+	return LicenseConfig{Header: testLicenseTemplate, Holder: "Acme Inc.", Year: "2026"}
+}
+
+func TestCheckLicenseHeader(t *testing.T) {
+	t.Parallel()
+
+	goHeader := newLicenseHeader(testLicenseConfig(), "//")
+	shHeader := newLicenseHeader(testLicenseConfig(), "#")
+
+	tests := []struct {
+		name     string
+		header   *licenseHeader
+		content  string
+		rule     string
+		line     int
+		expected string
+	}{
+		{
+			name:    "present",
+			header:  goHeader,
+			content: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+		},
+		{
+			name:    "older year range still matches",
+			header:  goHeader,
+			content: "// Copyright 2019-2024 Acme Inc.\n// SPDX-License-Identifier: MIT\n\n//go:build linux\n\npackage foo\n",
+		},
+		{
+			name:    "header followed by package docs",
+			header:  goHeader,
+			content: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n// Package foo does things.\npackage foo\n",
+		},
+		{
+			name:    "generated file is skipped",
+			header:  goHeader,
+			content: "// Code generated by mockgen. DO NOT EDIT.\n\npackage foo\n",
+		},
+		{
+			name:    "generated marker after build constraint",
+			header:  goHeader,
+			content: "//go:build tools\n\n// Code generated by stringer; DO NOT EDIT.\n\npackage foo\n",
+		},
+		{
+			name:     "missing",
+			header:   goHeader,
+			content:  "// Package foo does things.\npackage foo\n",
+			rule:     LicenseRuleMissing,
+			line:     1,
+			expected: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\n// Package foo does things.\npackage foo\n",
+		},
+		{
+			name:     "build constraint is not a header",
+			header:   goHeader,
+			content:  "//go:build linux\n\npackage foo\n",
+			rule:     LicenseRuleMissing,
+			line:     1,
+			expected: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\n//go:build linux\n\npackage foo\n",
+		},
+		{
+			name:     "wrong holder keeps the existing year",
+			header:   goHeader,
+			content:  "// Copyright 2020 Someone Else\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+			rule:     LicenseRuleMismatch,
+			line:     1,
+			expected: "// Copyright 2020 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+		},
+		{
+			name:     "package doc mentioning a license is kept",
+			header:   goHeader,
+			content:  "// Package licenses scans dependency licenses and reports copyright notices.\npackage licenses\n",
+			rule:     LicenseRuleMissing,
+			line:     1,
+			expected: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\n// Package licenses scans dependency licenses and reports copyright notices.\npackage licenses\n",
+		},
+		{
+			name:     "leading YAML comment mentioning a license is kept",
+			header:   shHeader,
+			content:  "# Checks the license of every dependency\nname: licenses\n",
+			rule:     LicenseRuleMissing,
+			line:     1,
+			expected: "# Copyright 2026 Acme Inc.\n# SPDX-License-Identifier: MIT\n\n# Checks the license of every dependency\nname: licenses\n",
+		},
+		{
+			name:     "SPDX line at the top is replaced",
+			header:   goHeader,
+			content:  "// SPDX-License-Identifier: Apache-2.0\n\npackage foo\n",
+			rule:     LicenseRuleMismatch,
+			line:     1,
+			expected: "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+		},
+		{
+			name:     "shell script after shebang",
+			header:   shHeader,
+			content:  "#!/usr/bin/env bash\nset -e\n",
+			rule:     LicenseRuleMissing,
+			line:     2,
+			expected: "#!/usr/bin/env bash\n# Copyright 2026 Acme Inc.\n# SPDX-License-Identifier: MIT\n\nset -e\n",
+		},
+		{
+			name:     "windows line endings are preserved",
+			header:   goHeader,
+			content:  "package foo\r\n",
+			rule:     LicenseRuleMissing,
+			line:     1,
+			expected: "// Copyright 2026 Acme Inc.\r\n// SPDX-License-Identifier: MIT\r\n\r\npackage foo\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			issue, updated := checkLicenseHeader(tt.content, tt.header, "Acme Inc.")
+			if tt.rule == "" {
+				assert.Nil(t, issue)
+				assert.Equal(t, tt.content, updated)
+				return
+			}
+			require.NotNil(t, issue)
+			assert.Equal(t, tt.rule, issue.Rule)
+			assert.Equal(t, tt.line, issue.Line)
+			assert.Equal(t, tt.expected, updated)
+
+			again, _ := checkLicenseHeader(updated, tt.header, "Acme Inc.")
+			assert.Nil(t, again, "a fixed file passes the check")
+		})
+	}
+}
+
+func TestCheckLicenseHeader_OutdatedTemplate(t *testing.T) {
+	t.Parallel()
+
+	header := newLicenseHeader(LicenseConfig{Header: "Licensed to {{holder}} under the MIT License.\nSee LICENSE for details.\n", Holder: "Acme Inc."}, "//")
+
+	issue, updated := checkLicenseHeader("// Licensed to Acme Inc. under the MIT License.\n// See COPYING for details.\n\npackage foo\n", header, "Acme Inc.")
+	require.NotNil(t, issue)
+	assert.Equal(t, LicenseRuleMismatch, issue.Rule)
+	assert.Equal(t, "// Licensed to Acme Inc. under the MIT License.\n// See LICENSE for details.\n\npackage foo\n", updated)
+}
+
+func TestNewLicenseHeader_AnyHolder(t *testing.T) {
+	t.Parallel()
+
+	header := newLicenseHeader(LicenseConfig{Header: "Copyright (c) {{year}} {{holder}}"}, "//")
+	assert.True(t, header.pattern.MatchString("// Copyright (c) 2024 Whoever"))
+	assert.False(t, header.pattern.MatchString("// Copyright 2024 Whoever"))
+	assert.Len(t, header.year, 4, "the year defaults to the current year")
+}
+
+func TestFindLicenseFiles(t *testing.T) {
+	root := writeMarkdownTree(t, map[string]string{
+		"main.go":                    "package main\n",
+		"internal/legacy/old.go":     "package legacy\n",
+		"vendor/dep/dep.go":          "package dep\n",
+		"scripts/build.sh":           "echo\n",
+		".github/workflows/ci.yml":   "on: push\n",
+		".git/hooks/pre-commit.yaml": "x: y\n",
+		"README.md":                  "# Readme\n",
+	})
+	rel := func(files []string) []string {
+		out := make([]string, 0, len(files))
+		for _, f := range files {
+			r, err := filepath.Rel(root, f)
+			require.NoError(t, err)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return out
+	}
+
+	files, err := findLicenseFiles(root, LicenseConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal/legacy/old.go", "main.go"}, rel(files))
+
+	files, err = findLicenseFiles(root, LicenseConfig{IncludeShell: true, IncludeYAML: true, Ignore: []string{"internal/legacy/**"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".github/workflows/ci.yml", "main.go", "scripts/build.sh"}, rel(files))
+}
+
+func TestCheckLicenseWithArgs(t *testing.T) {
+	run := func(t *testing.T, cfg LicenseConfig, args ...string) error {
+		t.Helper()
+		config := defaultConfig()
+		config.Lint.License = cfg
+		TestSetConfig(config)
+		defer TestResetConfig()
+		return Check{}.LicenseWithArgs(append(args, "ci=false")...)
+	}
+
+	t.Run("skips when no header is configured", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{"main.go": "package main\n"})
+		require.NoError(t, run(t, LicenseConfig{}, "dir="+root))
+	})
+
+	t.Run("reports and fixes missing headers", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{
+			"good.go": "// Copyright 2025 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+			"bad.go":  "package main\n",
+		})
+		bad := filepath.Join(root, "bad.go")
+		require.NoError(t, os.Chmod(bad, 0o640))
+
+		err := run(t, testLicenseConfig(), "dir="+root)
+		require.ErrorIs(t, err, errLicenseHeaderIssues)
+
+		require.NoError(t, run(t, testLicenseConfig(), "dir="+root, "fix=true"))
+		data, err := os.ReadFile(bad) //nolint:gosec // test file in temp dir
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage main\n", string(data))
+		info, err := os.Stat(bad)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm(), "permissions are kept")
+
+		require.NoError(t, run(t, testLicenseConfig(), "dir="+root))
+	})
+
+	t.Run("fix needs a holder", func(t *testing.T) {
+		cfg := testLicenseConfig()
+		cfg.Holder = ""
+		err := run(t, cfg, "dir="+t.TempDir(), "fix=true")
+		require.ErrorIs(t, err, errLicenseHolderRequired)
+	})
+
+	t.Run("ci mode writes json report", func(t *testing.T) {
+		root := writeMarkdownTree(t, map[string]string{"main.go": "package main\n"})
+		config := defaultConfig()
+		config.Lint.License = testLicenseConfig()
+		config.Test.CIMode.OutputPath = filepath.Join(t.TempDir(), "ci-results.jsonl")
+		TestSetConfig(config)
+		defer TestResetConfig()
+
+		err := Check{}.LicenseWithArgs("dir="+root, "ci=true", "ci_format=json")
+		require.ErrorIs(t, err, errLicenseHeaderIssues)
+		report := filepath.Join(filepath.Dir(config.Test.CIMode.OutputPath), licenseReportFile)
+		data, err := os.ReadFile(report) //nolint:gosec // test file in temp dir
+		require.NoError(t, err)
+		assert.Contains(t, string(data), LicenseRuleMissing)
+	})
+}
@@ -44,9 +44,10 @@ var versionPatterns = []*regexp.Regexp{
 // golangciLintArgs holds the configuration for building golangci-lint arguments.
 // This consolidates the duplicated argument-building logic from Default() and Fix().
 type golangciLintArgs struct {
-	modulePath string  // absolute path to the module directory
-	config     *Config // mage configuration
-	withFix    bool    // whether to include --fix flag
+	modulePath string   // absolute path to the module directory
+	config     *Config  // mage configuration
+	withFix    bool     // whether to include --fix flag
+	targets    []string // packages to lint (default: ./...)
 }
 
 // resolveConfigPath finds the golangci-lint config file, checking module directory first,
@@ -115,7 +116,11 @@ func (g *golangciLintArgs) buildArgs() []string {
 	if g.withFix {
 		args = append(args, "--fix")
 	}
-	args = append(args, "./...")
+	if len(g.targets) > 0 {
+		args = append(args, g.targets...)
+	} else {
+		args = append(args, "./...")
+	}
 
 	// Add config path arguments
 	configPath, configArgs := g.resolveConfigPath()
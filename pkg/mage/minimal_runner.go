@@ -10,6 +10,7 @@ import (
 	"github.com/mrz1836/mage-x/pkg/common/env"
 	"github.com/mrz1836/mage-x/pkg/common/providers"
 	"github.com/mrz1836/mage-x/pkg/exec"
+	"github.com/mrz1836/mage-x/pkg/log"
 	"github.com/mrz1836/mage-x/pkg/mage/runtimectx"
 )
 
@@ -55,13 +56,32 @@ type SecureCommandRunner struct {
 func NewSecureCommandRunner() CommandRunner {
 	// Build a single executor chain with validation
 	// All methods (Execute, ExecuteInDir, etc.) will be validated
-	executor := exec.NewBuilder().
-		WithValidation().
-		Build()
+	builder := exec.NewBuilder().
+		WithValidation()
+
+	// Record every command in the audit log when audit.enabled is set
+	if logger := configuredAuditLogger(); logger != nil {
+		builder = builder.WithAuditLogging(logger)
+	}
 
 	return &SecureCommandRunner{
-		executor: executor,
+		executor: builder.Build(),
+	}
+}
+
+// configuredAuditLogger returns the audit logger from the config, or nil when
+// auditing is disabled or misconfigured (the latter with a warning)
+func configuredAuditLogger() exec.AuditLogger {
+	cfg, err := GetConfig()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	logger, err := newAuditLogger(cfg)
+	if err != nil {
+		log.Warn("audit log disabled: %v", err)
+		return nil
 	}
+	return logger
 }
 
 // RunCmd executes a command and returns an error if it fails
@@ -0,0 +1,223 @@
+package mage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	mageErrors "github.com/mrz1836/mage-x/pkg/common/errors"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for failure notifications
+var (
+	errUnknownNotificationChannel = errors.New("unknown notification channel type")
+	errUnknownWebhookFormat       = errors.New("unknown webhook format")
+	errNotificationURLMissing     = errors.New("notification channel needs a url")
+	errNotificationSMTPMissing    = errors.New("email channel needs smtp_host, from and to")
+	errInvalidMinDuration         = errors.New("invalid notifications.min_duration")
+)
+
+// Notification channel types accepted in notifications.channels
+const (
+	NotificationChannelSlack   = "slack"
+	NotificationChannelWebhook = "webhook"
+	NotificationChannelConsole = "console"
+	NotificationChannelEmail   = "email"
+)
+
+const (
+	// defaultNotifyMinDuration is how long a command must run outside CI before its failure is reported
+	defaultNotifyMinDuration = time.Minute
+
+	// defaultSMTPPort is the submission port used when smtp_port is unset
+	defaultSMTPPort = 587
+
+	// notifyTimeout bounds how long magex waits for all channels
+	notifyTimeout = 15 * time.Second
+)
+
+// NotifyCommandFailure reports a failed magex command to the channels configured under
+// notifications in .mage.yaml. Outside CI only commands that ran for at least
+// notifications.min_duration are reported; in CI every failure is. It does nothing
+// when notifications are disabled or no channel is configured.
+func NotifyCommandFailure(ctx context.Context, command string, duration time.Duration, cmdErr error) error {
+	if cmdErr == nil {
+		return nil
+	}
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	notifications := config.Notifications
+	if !notifications.Enabled || len(notifications.Channels) == 0 {
+		return nil
+	}
+
+	minDuration, err := notifyMinDuration(notifications.MinDuration)
+	if err != nil {
+		return err
+	}
+	detector := NewCIDetector()
+	if !detector.IsCI() && duration < minDuration {
+		return nil
+	}
+
+	notifier, err := newFailureNotifier(notifications.Channels)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	failure := commandFailureError(command, duration, cmdErr, ciMetadata(detector))
+	if err := notifier.NotifyWithContext(ctx, failure); err != nil {
+		return fmt.Errorf("failed to send failure notification: %w", err)
+	}
+	return nil
+}
+
+// notifyMinDuration parses notifications.min_duration, defaulting to one minute
+func notifyMinDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultNotifyMinDuration, nil
+	}
+	if value == "0" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidMinDuration, value)
+	}
+	return duration, nil
+}
+
+// newFailureNotifier builds a notifier with one channel per configured entry
+func newFailureNotifier(channels []NotificationChannelConfig) (mageErrors.ErrorNotifier, error) {
+	notifier := mageErrors.NewErrorNotifier()
+	for i, cfg := range channels {
+		channel, err := newNotificationChannel(i, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := notifier.AddChannel(channel); err != nil {
+			return nil, fmt.Errorf("failed to add notification channel %s: %w", channel.Name(), err)
+		}
+	}
+	return notifier, nil
+}
+
+// newNotificationChannel builds the channel for one notifications.channels entry
+func newNotificationChannel(index int, cfg NotificationChannelConfig) (mageErrors.NotificationChannel, error) {
+	kind := strings.ToLower(cfg.Type)
+	name := cfg.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%d", kind, index+1)
+	}
+
+	switch kind {
+	case NotificationChannelSlack, NotificationChannelWebhook:
+		url := os.ExpandEnv(cfg.URL)
+		if url == "" {
+			return nil, fmt.Errorf("%w: %s", errNotificationURLMissing, name)
+		}
+		format := mageErrors.WebhookFormat(strings.ToLower(cfg.Format))
+		switch {
+		case kind == NotificationChannelSlack:
+			format = mageErrors.WebhookFormatSlack
+		case format == "":
+			format = mageErrors.WebhookFormatJSON
+		case format != mageErrors.WebhookFormatJSON && format != mageErrors.WebhookFormatSlack:
+			return nil, fmt.Errorf("%w %q for %s (use json or slack)", errUnknownWebhookFormat, cfg.Format, name)
+		}
+		headers := make(map[string]string, len(cfg.Headers))
+		for key, value := range cfg.Headers {
+			headers[key] = os.ExpandEnv(value)
+		}
+		channel := mageErrors.NewWebhookChannel(name, url, headers)
+		channel.SetFormat(format)
+		return channel, nil
+
+	case NotificationChannelConsole:
+		return mageErrors.NewConsoleChannel(name), nil
+
+	case NotificationChannelEmail:
+		if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("%w: %s", errNotificationSMTPMissing, name)
+		}
+		port := cfg.SMTPPort
+		if port == 0 {
+			port = defaultSMTPPort
+		}
+		password := ""
+		if cfg.PasswordEnv != "" {
+			password = os.Getenv(cfg.PasswordEnv)
+		}
+		return mageErrors.NewEmailChannel(name, cfg.SMTPHost, port, cfg.Username, password, cfg.From, cfg.To), nil
+
+	default:
+		return nil, fmt.Errorf("%w %q for %s (use slack, webhook, console or email)", errUnknownNotificationChannel, cfg.Type, name)
+	}
+}
+
+// commandFailureError describes a failed command as a MageError carrying the command,
+// its duration, the error chain and the CI metadata as context fields
+func commandFailureError(command string, duration time.Duration, cmdErr error, metadata CIMetadata) mageErrors.MageError {
+	fields := map[string]any{
+		"command":     command,
+		"duration":    utils.FormatDuration(duration),
+		"duration_ms": duration.Milliseconds(),
+		"error_chain": errorChain(cmdErr),
+	}
+	for key, value := range map[string]string{
+		"branch":     metadata.Branch,
+		"commit":     metadata.Commit,
+		"run_id":     metadata.RunID,
+		"workflow":   metadata.Workflow,
+		"platform":   metadata.Platform,
+		"go_version": metadata.GoVersion,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	return mageErrors.NewErrorBuilder().
+		WithMessage("magex %s failed after %s", command, utils.FormatDuration(duration)).
+		WithCode(mageErrors.ErrCommandFailed).
+		WithSeverity(mageErrors.SeverityError).
+		WithOperation(command).
+		WithFields(fields).
+		WithCause(cmdErr).
+		Build()
+}
+
+// errorChain lists each layer of a wrapped error from the outermost in, with the
+// text each layer adds; joined errors contribute every branch
+func errorChain(err error) []string {
+	var chain []string
+	for err != nil {
+		message := err.Error()
+		var next error
+		switch wrapped := err.(type) { //nolint:errorlint // walking the chain one layer at a time
+		case interface{ Unwrap() []error }:
+			for _, branch := range wrapped.Unwrap() {
+				chain = append(chain, errorChain(branch)...)
+			}
+			if len(wrapped.Unwrap()) > 0 {
+				return chain
+			}
+		case interface{ Unwrap() error }:
+			next = wrapped.Unwrap()
+		}
+		if next != nil {
+			message = strings.TrimSuffix(message, ": "+next.Error())
+		}
+		chain = append(chain, message)
+		err = next
+	}
+	return chain
+}
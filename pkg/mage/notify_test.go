@@ -0,0 +1,190 @@
+package mage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mageErrors "github.com/mrz1836/mage-x/pkg/common/errors"
+)
+
+// notificationStandIn is a local HTTP server that records every webhook body it receives
+type notificationStandIn struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+	server *httptest.Server
+}
+
+func newNotificationStandIn(t *testing.T) *notificationStandIn {
+	t.Helper()
+	s := &notificationStandIn{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(data, &body))
+		s.mu.Lock()
+		s.bodies = append(s.bodies, body)
+		s.mu.Unlock()
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *notificationStandIn) received() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any(nil), s.bodies...)
+}
+
+// useNotificationsConfig installs a config with the given notifications section
+func useNotificationsConfig(t *testing.T, notifications NotificationsConfig) {
+	t.Helper()
+	config := defaultConfig()
+	config.Notifications = notifications
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+}
+
+// notifyOutsideCI blanks the variables the CI detector reads so failures are judged
+// by duration, whatever CI the tests themselves run in
+func notifyOutsideCI(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"CI", "GITHUB_ACTIONS", "GITLAB_CI", "CIRCLECI", "TRAVIS", "JENKINS_URL", "TF_BUILD",
+		"BUILDKITE", "DRONE", "CODEBUILD_CI", "TEAMCITY_VERSION", "BITBUCKET_BUILD_NUMBER",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNotifyCommandFailure(t *testing.T) {
+	errTestsFailed := errors.New("tests failed") //nolint:err113 // test error
+	cmdErr := fmt.Errorf("test:unit: %w", errTestsFailed)
+
+	t.Run("long-running failure is posted to every channel", func(t *testing.T) {
+		notifyOutsideCI(t)
+		slack := newNotificationStandIn(t)
+		hook := newNotificationStandIn(t)
+		t.Setenv("TEST_NOTIFY_URL", hook.server.URL)
+		useNotificationsConfig(t, NotificationsConfig{
+			Enabled:     true,
+			MinDuration: "30s",
+			Channels: []NotificationChannelConfig{
+				{Type: NotificationChannelSlack, URL: slack.server.URL},
+				{Type: NotificationChannelWebhook, URL: "${TEST_NOTIFY_URL}"},
+			},
+		})
+
+		require.NoError(t, NotifyCommandFailure(context.Background(), "test:unit", 2*time.Minute, cmdErr))
+
+		require.Len(t, slack.received(), 1)
+		assert.Contains(t, slack.received()[0]["text"], "test:unit failed")
+
+		require.Len(t, hook.received(), 1)
+		payload := hook.received()[0]
+		assert.Equal(t, "COMMAND_FAILED", payload["error_code"])
+		assert.Equal(t, "test:unit", payload["operation"])
+		fields, ok := payload["fields"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "test:unit", fields["command"])
+		assert.InDelta(t, 120000, fields["duration_ms"], 0)
+		assert.Equal(t, []any{"test:unit", "tests failed"}, fields["error_chain"])
+	})
+
+	t.Run("short failure outside CI is not posted", func(t *testing.T) {
+		notifyOutsideCI(t)
+		hook := newNotificationStandIn(t)
+		useNotificationsConfig(t, NotificationsConfig{
+			Enabled:  true,
+			Channels: []NotificationChannelConfig{{Type: NotificationChannelWebhook, URL: hook.server.URL}},
+		})
+
+		require.NoError(t, NotifyCommandFailure(context.Background(), "lint", 5*time.Second, cmdErr))
+		assert.Empty(t, hook.received())
+	})
+
+	t.Run("every CI failure is posted with CI metadata", func(t *testing.T) {
+		notifyOutsideCI(t)
+		t.Setenv("GITHUB_ACTIONS", "true")
+		t.Setenv("GITHUB_REF_NAME", "main")
+		hook := newNotificationStandIn(t)
+		useNotificationsConfig(t, NotificationsConfig{
+			Enabled:  true,
+			Channels: []NotificationChannelConfig{{Type: NotificationChannelWebhook, URL: hook.server.URL}},
+		})
+
+		require.NoError(t, NotifyCommandFailure(context.Background(), "lint", time.Second, cmdErr))
+		require.Len(t, hook.received(), 1)
+		fields, ok := hook.received()[0]["fields"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "main", fields["branch"])
+		assert.Equal(t, "github", fields["platform"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		hook := newNotificationStandIn(t)
+		useNotificationsConfig(t, NotificationsConfig{
+			Channels: []NotificationChannelConfig{{Type: NotificationChannelWebhook, URL: hook.server.URL}},
+		})
+
+		require.NoError(t, NotifyCommandFailure(context.Background(), "lint", time.Hour, cmdErr))
+		assert.Empty(t, hook.received())
+	})
+
+	t.Run("invalid min_duration", func(t *testing.T) {
+		useNotificationsConfig(t, NotificationsConfig{
+			Enabled:     true,
+			MinDuration: "soon",
+			Channels:    []NotificationChannelConfig{{Type: NotificationChannelConsole}},
+		})
+
+		require.ErrorIs(t, NotifyCommandFailure(context.Background(), "lint", time.Hour, cmdErr), errInvalidMinDuration)
+	})
+}
+
+func TestNewNotificationChannel(t *testing.T) {
+	t.Parallel()
+
+	channel, err := newNotificationChannel(0, NotificationChannelConfig{Type: "Slack", URL: "https://hooks.example.com/x"})
+	require.NoError(t, err)
+	assert.Equal(t, "slack-1", channel.Name())
+	webhook, ok := channel.(*mageErrors.WebhookChannel)
+	require.True(t, ok)
+	assert.Equal(t, mageErrors.WebhookFormatSlack, webhook.Format())
+
+	channel, err = newNotificationChannel(1, NotificationChannelConfig{Name: "ops", Type: "email", SMTPHost: "smtp.example.com", From: "ci@example.com", To: []string{"ops@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "ops", channel.Name())
+
+	_, err = newNotificationChannel(0, NotificationChannelConfig{Type: "webhook"})
+	require.ErrorIs(t, err, errNotificationURLMissing)
+	_, err = newNotificationChannel(0, NotificationChannelConfig{Type: "webhook", URL: "https://x", Format: "xml"})
+	require.ErrorIs(t, err, errUnknownWebhookFormat)
+	_, err = newNotificationChannel(0, NotificationChannelConfig{Type: "email"})
+	require.ErrorIs(t, err, errNotificationSMTPMissing)
+	_, err = newNotificationChannel(0, NotificationChannelConfig{Type: "pager"})
+	require.ErrorIs(t, err, errUnknownNotificationChannel)
+}
+
+func TestErrorChain(t *testing.T) {
+	t.Parallel()
+
+	errRoot := errors.New("exit status 1")   //nolint:err113 // test error
+	errOther := errors.New("lint timed out") //nolint:err113 // test error
+
+	assert.Nil(t, errorChain(nil))
+	assert.Equal(t, []string{"build failed", "go build", "exit status 1"},
+		errorChain(fmt.Errorf("build failed: %w", fmt.Errorf("go build: %w", errRoot))))
+	assert.Equal(t, []string{"exit status 1", "lint timed out"}, errorChain(errors.Join(errRoot, errOther)))
+}
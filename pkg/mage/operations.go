@@ -40,12 +40,6 @@ type Serve struct{}
 // Common provides common operations
 type Common struct{}
 
-// All runs all available checks
-func (c Check) All() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Running all checks")
-}
-
 // Format checks code formatting
 func (c Check) Format() error {
 	runner := GetRunner()
@@ -58,12 +52,6 @@ func (c Check) Imports() error {
 	return runner.RunCmd("goimports", "-l", ".")
 }
 
-// License checks license headers in the codebase.
-func (c Check) License() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Checking license headers")
-}
-
 // Security runs security checks using gosec.
 func (c Check) Security() error {
 	runner := GetRunner()
@@ -76,18 +64,6 @@ func (c Check) Dependencies() error {
 	return runner.RunCmd("go", "mod", "verify")
 }
 
-// Tidy runs go mod tidy to clean up dependencies.
-func (c Check) Tidy() error {
-	runner := GetRunner()
-	return runner.RunCmd("go", "mod", "tidy")
-}
-
-// Generate runs go generate on all packages.
-func (c Check) Generate() error {
-	runner := GetRunner()
-	return runner.RunCmd("go", "generate", "./...")
-}
-
 // Spelling checks for spelling errors using misspell.
 func (c Check) Spelling() error {
 	runner := GetRunner()
@@ -252,66 +228,6 @@ func (m Monitor) Export(format, timeRange string) error {
 	return runner.RunCmd("echo", "Exporting metrics as", format, "for", timeRange)
 }
 
-// Migrate runs database migrations in the specified direction.
-func (db Database) Migrate(direction string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Running database migration:", direction)
-}
-
-// Seed seeds the database with test data.
-func (db Database) Seed(seedName string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Seeding database:", seedName)
-}
-
-// Reset resets the database to a clean state.
-func (db Database) Reset() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Resetting database")
-}
-
-// Backup creates a backup of the database.
-func (db Database) Backup(backupFile string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Creating database backup:", backupFile)
-}
-
-// Restore restores the database from a backup file.
-func (db Database) Restore(backupFile string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Restoring database from:", backupFile)
-}
-
-// Status checks the database status.
-func (db Database) Status() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Checking database status")
-}
-
-// Create creates a new database with the given name.
-func (db Database) Create(dbName string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Creating database:", dbName)
-}
-
-// Drop drops a database with the given name.
-func (db Database) Drop(dbName string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Dropping database:", dbName)
-}
-
-// Console opens a database console.
-func (db Database) Console() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Opening database console")
-}
-
-// Query executes a database query.
-func (db Database) Query(query string) error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Executing query:", query)
-}
-
 // Local deploys the application locally.
 func (d Deploy) Local() error {
 	runner := GetRunner()
@@ -457,12 +373,6 @@ func (r Run) Prod() error {
 	return runner.RunCmd("echo", "Running in prod mode")
 }
 
-// Watch runs the application with file watching enabled
-func (r Run) Watch() error {
-	runner := GetRunner()
-	return runner.RunCmd("echo", "Running with watch")
-}
-
 // Debug runs the application in debug mode
 func (r Run) Debug(_ ...any) error {
 	runner := GetRunner()
@@ -286,78 +286,6 @@ func (ts *OperationsCoverageTestSuite) TestMonitorExportSuccess() {
 	ts.Require().NoError(err)
 }
 
-// ================== Database Namespace Tests ==================
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseMigrateSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Migrate("up")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseSeedSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Seed("test-data")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseResetSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Reset()
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseBackupSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Backup("backup.sql")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseRestoreSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Restore("backup.sql")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseStatusSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Status()
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseCreateSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Create("testdb")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseDropSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Drop("testdb")
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseConsoleSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Console()
-	})
-	ts.Require().NoError(err)
-}
-
-func (ts *OperationsCoverageTestSuite) TestDatabaseQuerySuccess() {
-	err := ts.withMockRunner(func() error {
-		return Database{}.Query("SELECT 1")
-	})
-	ts.Require().NoError(err)
-}
-
 // ================== Deploy Namespace Tests ==================
 
 func (ts *OperationsCoverageTestSuite) TestDeployLocalSuccess() {
@@ -497,13 +425,6 @@ func (ts *OperationsCoverageTestSuite) TestRunProdSuccess() {
 	ts.Require().NoError(err)
 }
 
-func (ts *OperationsCoverageTestSuite) TestRunWatchSuccess() {
-	err := ts.withMockRunner(func() error {
-		return Run{}.Watch()
-	})
-	ts.Require().NoError(err)
-}
-
 func (ts *OperationsCoverageTestSuite) TestRunDebugSuccess() {
 	err := ts.withMockRunner(func() error {
 		return Run{}.Debug()
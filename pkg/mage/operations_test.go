@@ -4,6 +4,8 @@
 package mage
 
 import (
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,12 +14,15 @@ import (
 
 // Mock runner for testing operations
 type MockRunner struct {
+	mu          sync.Mutex // check:all runs commands concurrently
 	commands    [][]string
 	shouldError bool
 }
 
 func (m *MockRunner) RunCmd(cmd string, args ...string) error {
 	fullCmd := append([]string{cmd}, args...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.commands = append(m.commands, fullCmd)
 	if m.shouldError {
 		return assert.AnError
@@ -27,6 +32,8 @@ func (m *MockRunner) RunCmd(cmd string, args ...string) error {
 
 func (m *MockRunner) RunCmdOutput(cmd string, args ...string) (string, error) {
 	fullCmd := append([]string{cmd}, args...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.commands = append(m.commands, fullCmd)
 	if m.shouldError {
 		return "", assert.AnError
@@ -90,13 +97,10 @@ func TestCheck_Operations(t *testing.T) {
 	t.Run("All", func(t *testing.T) {
 		mockRunner := helper.GetMockRunner()
 		mockRunner.Reset()
-		err := check.All()
+		err := check.AllWithArgs("only=generate", "output="+filepath.Join(t.TempDir(), "checks.json"))
 		require.NoError(t, err)
 
-		lastCmd := mockRunner.GetLastCommand()
-		require.NotNil(t, lastCmd)
-		assert.Equal(t, "echo", lastCmd[0])
-		assert.Contains(t, lastCmd[1], "Running all checks")
+		assert.Contains(t, mockRunner.GetAllCommands(), []string{"go", "generate", "./..."})
 	})
 
 	t.Run("Format", func(t *testing.T) {
@@ -127,6 +131,7 @@ func TestCheck_Operations(t *testing.T) {
 	t.Run("Tidy", func(t *testing.T) {
 		mockRunner := helper.GetMockRunner()
 		mockRunner.Reset()
+		chdirTempModule(t)
 		err := check.Tidy()
 		require.NoError(t, err)
 
@@ -261,69 +266,6 @@ func TestMonitor_Operations(t *testing.T) {
 	})
 }
 
-func TestDatabase_Operations(t *testing.T) {
-	helper := NewOperationsTestHelper()
-	helper.SetupMockRunner(t)
-	defer helper.TeardownMockRunner()
-
-	db := Database{}
-
-	t.Run("Migrate", func(t *testing.T) {
-		mockRunner := helper.GetMockRunner()
-		mockRunner.Reset()
-		err := db.Migrate("up")
-		require.NoError(t, err)
-
-		lastCmd := mockRunner.GetLastCommand()
-		require.NotNil(t, lastCmd)
-		require.Len(t, lastCmd, 3) // ["echo", "Running database migration:", "up"]
-		assert.Equal(t, "echo", lastCmd[0])
-		assert.Equal(t, "Running database migration:", lastCmd[1])
-		assert.Equal(t, "up", lastCmd[2])
-	})
-
-	t.Run("Seed", func(t *testing.T) {
-		mockRunner := helper.GetMockRunner()
-		mockRunner.Reset()
-		err := db.Seed("test_data")
-		require.NoError(t, err)
-
-		lastCmd := mockRunner.GetLastCommand()
-		require.NotNil(t, lastCmd)
-		require.Len(t, lastCmd, 3) // ["echo", "Seeding database:", "test_data"]
-		assert.Equal(t, "echo", lastCmd[0])
-		assert.Equal(t, "Seeding database:", lastCmd[1])
-		assert.Equal(t, "test_data", lastCmd[2])
-	})
-
-	t.Run("Reset", func(t *testing.T) {
-		mockRunner := helper.GetMockRunner()
-		mockRunner.Reset()
-		err := db.Reset()
-		require.NoError(t, err)
-
-		commands := mockRunner.GetAllCommands()
-		require.GreaterOrEqual(t, len(commands), 1)
-
-		// Should run multiple commands for reset
-		assert.GreaterOrEqual(t, len(commands), 1)
-	})
-
-	t.Run("Backup", func(t *testing.T) {
-		mockRunner := helper.GetMockRunner()
-		mockRunner.Reset()
-		err := db.Backup("backup.sql")
-		require.NoError(t, err)
-
-		lastCmd := mockRunner.GetLastCommand()
-		require.NotNil(t, lastCmd)
-		require.Len(t, lastCmd, 3) // ["echo", "Creating database backup:", "backup.sql"]
-		assert.Equal(t, "echo", lastCmd[0])
-		assert.Equal(t, "Creating database backup:", lastCmd[1])
-		assert.Equal(t, "backup.sql", lastCmd[2])
-	})
-}
-
 func TestDeploy_Operations(t *testing.T) {
 	helper := NewOperationsTestHelper()
 	helper.SetupMockRunner(t)
@@ -467,19 +409,6 @@ func TestRun_Operations(t *testing.T) {
 		assert.Contains(t, lastCmd[1], "dev")
 	})
 
-	t.Run("Watch", func(t *testing.T) {
-		mockRunner := helper.GetMockRunner()
-		mockRunner.Reset()
-		err := run.Watch()
-		require.NoError(t, err)
-
-		lastCmd := mockRunner.GetLastCommand()
-		require.NotNil(t, lastCmd)
-		assert.Equal(t, "echo", lastCmd[0])
-		assert.Contains(t, lastCmd[1], "Running")
-		assert.Contains(t, lastCmd[1], "watch")
-	})
-
 	t.Run("Debug", func(t *testing.T) {
 		mockRunner := helper.GetMockRunner()
 		mockRunner.Reset()
@@ -1,6 +1,7 @@
 package mage
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -48,9 +49,10 @@ func (h *operationsTestHelper) expectCmdError(cmd string) {
 
 // TestCheckAllSuccess tests Check.All success path
 func TestCheckAllSuccess(t *testing.T) {
+	t.Chdir(t.TempDir())
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmd("echo")
+	h.mockRunner.On("RunCmdOutput", mock.Anything, mock.Anything).Return("", nil)
 
 	err := Check{}.All()
 	require.NoError(t, err)
@@ -58,9 +60,10 @@ func TestCheckAllSuccess(t *testing.T) {
 
 // TestCheckAllError tests Check.All error path
 func TestCheckAllError(t *testing.T) {
+	t.Chdir(t.TempDir())
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmdError("echo")
+	h.mockRunner.On("RunCmdOutput", mock.Anything, mock.Anything).Return("", assert.AnError)
 
 	err := Check{}.All()
 	require.Error(t, err)
@@ -110,8 +113,8 @@ func TestCheckImportsError(t *testing.T) {
 func TestCheckLicenseSuccess(t *testing.T) {
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmd("echo")
 
+	// No header is configured, so the check is skipped
 	err := Check{}.License()
 	require.NoError(t, err)
 }
@@ -156,11 +159,19 @@ func TestCheckDependenciesError(t *testing.T) {
 	require.Error(t, err)
 }
 
+// chdirTempModule switches into a temp directory holding a minimal go.mod
+func chdirTempModule(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("go.mod", []byte(testGoMod), 0o600))
+}
+
 // TestCheckTidySuccess tests Check.Tidy success path
 func TestCheckTidySuccess(t *testing.T) {
+	chdirTempModule(t)
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmd("go")
+	h.mockRunner.On("RunCmdOutput", "go", mock.Anything).Return("", nil)
 
 	err := Check{}.Tidy()
 	require.NoError(t, err)
@@ -168,9 +179,10 @@ func TestCheckTidySuccess(t *testing.T) {
 
 // TestCheckTidyError tests Check.Tidy error path
 func TestCheckTidyError(t *testing.T) {
+	chdirTempModule(t)
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmdError("go")
+	h.mockRunner.On("RunCmdOutput", "go", mock.Anything).Return("", assert.AnError)
 
 	err := Check{}.Tidy()
 	require.Error(t, err)
@@ -178,9 +190,10 @@ func TestCheckTidyError(t *testing.T) {
 
 // TestCheckGenerateSuccess tests Check.Generate success path
 func TestCheckGenerateSuccess(t *testing.T) {
+	chdirTempModule(t)
 	h := newOperationsTestHelper(t)
 	defer h.teardown(t)
-	h.expectCmd("go")
+	h.mockRunner.On("RunCmdOutput", "go", mock.Anything).Return("", nil)
 
 	err := Check{}.Generate()
 	require.NoError(t, err)
@@ -473,106 +486,6 @@ func TestMonitorExportSuccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
-// TestDatabaseMigrateSuccess tests Database.Migrate success path
-func TestDatabaseMigrateSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Migrate("up")
-	require.NoError(t, err)
-}
-
-// TestDatabaseSeedSuccess tests Database.Seed success path
-func TestDatabaseSeedSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Seed("test_data")
-	require.NoError(t, err)
-}
-
-// TestDatabaseResetSuccess tests Database.Reset success path
-func TestDatabaseResetSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Reset()
-	require.NoError(t, err)
-}
-
-// TestDatabaseBackupSuccess tests Database.Backup success path
-func TestDatabaseBackupSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Backup("backup.sql")
-	require.NoError(t, err)
-}
-
-// TestDatabaseRestoreSuccess tests Database.Restore success path
-func TestDatabaseRestoreSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Restore("backup.sql")
-	require.NoError(t, err)
-}
-
-// TestDatabaseStatusSuccess tests Database.Status success path
-func TestDatabaseStatusSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Status()
-	require.NoError(t, err)
-}
-
-// TestDatabaseCreateSuccess tests Database.Create success path
-func TestDatabaseCreateSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Create("mydb")
-	require.NoError(t, err)
-}
-
-// TestDatabaseDropSuccess tests Database.Drop success path
-func TestDatabaseDropSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Drop("mydb")
-	require.NoError(t, err)
-}
-
-// TestDatabaseConsoleSuccess tests Database.Console success path
-func TestDatabaseConsoleSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Console()
-	require.NoError(t, err)
-}
-
-// TestDatabaseQuerySuccess tests Database.Query success path
-func TestDatabaseQuerySuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Database{}.Query("SELECT * FROM users")
-	require.NoError(t, err)
-}
-
 // TestDeployLocalSuccess tests Deploy.Local success path
 func TestDeployLocalSuccess(t *testing.T) {
 	h := newOperationsTestHelper(t)
@@ -823,16 +736,6 @@ func TestRunProdSuccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
-// TestRunWatchSuccess tests Run.Watch success path
-func TestRunWatchSuccess(t *testing.T) {
-	h := newOperationsTestHelper(t)
-	defer h.teardown(t)
-	h.expectCmd("echo")
-
-	err := Run{}.Watch()
-	require.NoError(t, err)
-}
-
 // TestRunDebugSuccess tests Run.Debug success path
 func TestRunDebugSuccess(t *testing.T) {
 	h := newOperationsTestHelper(t)
@@ -1040,10 +943,8 @@ func TestOperationsErrorPropagation(t *testing.T) {
 		fn   func() error
 		cmd  string
 	}{
-		{"Check.All", func() error { return Check{}.All() }, "echo"},
 		{"CI.Validate", func() error { return CI{}.Validate() }, "echo"},
 		{"Monitor.Start", func() error { return Monitor{}.Start() }, "echo"},
-		{"Database.Reset", func() error { return Database{}.Reset() }, "echo"},
 		{"Deploy.Local", func() error { return Deploy{}.Local() }, "echo"},
 		{"Run.Dev", func() error { return Run{}.Dev() }, "echo"},
 		{"Serve.HTTP", func() error { return Serve{}.HTTP() }, "echo"},
@@ -0,0 +1,233 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Static errors for dependency resolution
+var (
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrUnknownDependency = errors.New("unknown dependency")
+)
+
+// PlanStep is a single command in an execution plan
+type PlanStep struct {
+	// Name is the canonical command name
+	Name string
+
+	// Dependencies are the canonical names of the direct dependencies
+	Dependencies []string
+
+	// Level is the longest dependency chain below this step (0 = no dependencies).
+	// Steps on the same level never depend on each other and may run concurrently.
+	Level int
+}
+
+// ExecutionPlan is the resolved dependency graph for a command
+type ExecutionPlan struct {
+	// Root is the canonical name of the requested command
+	Root string
+
+	// Steps lists every command exactly once in topological order; the root is last
+	Steps []PlanStep
+}
+
+// Plan resolves the dependency graph of a command into a topologically ordered plan.
+// Each dependency appears once no matter how many commands share it, and cycles are
+// reported with the full loop (e.g. "a -> b -> a").
+func (r *Registry) Plan(name string) (*ExecutionPlan, error) {
+	root, exists := r.Get(name)
+	if !exists {
+		return nil, r.unknownCommandError(name)
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	plan := &ExecutionPlan{Root: root.FullName()}
+	state := make(map[string]int)
+	levels := make(map[string]int)
+	var stack []string
+
+	var visit func(cmd *Command) error
+	visit = func(cmd *Command) error {
+		cmdName := cmd.FullName()
+		switch state[cmdName] {
+		case visiting:
+			loop := append(slices.Clone(stack[slices.Index(stack, cmdName):]), cmdName)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(loop, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[cmdName] = visiting
+		stack = append(stack, cmdName)
+
+		step := PlanStep{Name: cmdName}
+		for _, depName := range cmd.Dependencies {
+			dep, ok := r.Get(depName)
+			if !ok {
+				return fmt.Errorf("%w '%s' required by %s", ErrUnknownDependency, depName, cmdName)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+			depFull := dep.FullName()
+			if !slices.Contains(step.Dependencies, depFull) {
+				step.Dependencies = append(step.Dependencies, depFull)
+			}
+			step.Level = max(step.Level, levels[depFull]+1)
+		}
+
+		stack = stack[:len(stack)-1]
+		state[cmdName] = visited
+		levels[cmdName] = step.Level
+		plan.Steps = append(plan.Steps, step)
+		return nil
+	}
+
+	if err := visit(root); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// SetParallelism sets how many independent dependencies Execute may run at once (minimum 1)
+func (r *Registry) SetParallelism(jobs int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = max(jobs, 1)
+}
+
+// Parallelism returns how many independent dependencies Execute may run at once
+func (r *Registry) Parallelism() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return max(r.jobs, 1)
+}
+
+// runSteps executes plan steps once each, starting a step only after all of its
+// dependencies succeeded. With more than one job, independent steps run concurrently.
+// After the first failure no new steps are started and that failure is returned.
+func (r *Registry) runSteps(steps []PlanStep, jobs int) error {
+	if jobs <= 1 {
+		for _, step := range steps {
+			if err := r.runStep(step); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	done := make(map[string]chan struct{}, len(steps))
+	for _, step := range steps {
+		done[step.Name] = make(chan struct{})
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+		sem    = make(chan struct{}, jobs)
+		errs   = make([]error, len(steps))
+	)
+	for i, step := range steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[step.Name])
+
+			for _, dep := range step.Dependencies {
+				<-done[dep]
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if failed.Load() {
+				return
+			}
+			if err := r.runStep(step); err != nil {
+				errs[i] = err
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// runStep executes a single dependency without arguments
+func (r *Registry) runStep(step PlanStep) error {
+	cmd, exists := r.Get(step.Name)
+	if !exists {
+		return fmt.Errorf("%w '%s'", ErrUnknownDependency, step.Name)
+	}
+	if err := cmd.Execute(); err != nil {
+		return fmt.Errorf("dependency '%s' failed: %w", step.Name, err)
+	}
+	return nil
+}
+
+// Text renders the plan as stages of commands that can run concurrently
+func (p *ExecutionPlan) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Execution plan for %s (%d step", p.Root, len(p.Steps))
+	if len(p.Steps) != 1 {
+		sb.WriteString("s")
+	}
+	sb.WriteString("):\n")
+
+	for level, steps := range p.levels() {
+		fmt.Fprintf(&sb, "  Stage %d:\n", level+1)
+		for _, step := range steps {
+			if len(step.Dependencies) == 0 {
+				fmt.Fprintf(&sb, "    - %s\n", step.Name)
+				continue
+			}
+			fmt.Fprintf(&sb, "    - %s (after %s)\n", step.Name, strings.Join(step.Dependencies, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// DOT renders the plan as a Graphviz digraph with edges pointing from a dependency to its dependent
+func (p *ExecutionPlan) DOT() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", p.Root)
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, step := range p.Steps {
+		if step.Name == p.Root {
+			fmt.Fprintf(&sb, "  %q [style=bold];\n", step.Name)
+		} else {
+			fmt.Fprintf(&sb, "  %q;\n", step.Name)
+		}
+	}
+	for _, step := range p.Steps {
+		for _, dep := range step.Dependencies {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", dep, step.Name)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// levels groups plan steps by level, preserving topological order within each level
+func (p *ExecutionPlan) levels() [][]PlanStep {
+	var levels [][]PlanStep
+	for _, step := range p.Steps {
+		for len(levels) <= step.Level {
+			levels = append(levels, nil)
+		}
+		levels[step.Level] = append(levels[step.Level], step)
+	}
+	return levels
+}
@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// graphRegistry registers one command per entry in deps (name -> dependencies);
+// every command appends its name to the returned log when it runs
+func graphRegistry(t *testing.T, deps map[string][]string) (*Registry, func() []string) {
+	t.Helper()
+
+	r := NewRegistry()
+	var (
+		mu  sync.Mutex
+		log []string
+	)
+	for name, d := range deps {
+		r.MustRegister(&Command{
+			Name:         name,
+			Description:  name,
+			Dependencies: d,
+			Func: func() error {
+				mu.Lock()
+				log = append(log, name)
+				mu.Unlock()
+				return nil
+			},
+		})
+	}
+	return r, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(log)
+	}
+}
+
+func planNames(plan *ExecutionPlan) []string {
+	names := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+func TestRegistry_Plan(t *testing.T) {
+	r, _ := graphRegistry(t, map[string][]string{
+		"release":  {"test", "lint"},
+		"test":     {"build", "generate"},
+		"lint":     {"generate"},
+		"build":    {"generate"},
+		"generate": nil,
+	})
+
+	plan, err := r.Plan("release")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []string{"generate", "build", "test", "lint", "release"}
+	if got := planNames(plan); !slices.Equal(got, want) {
+		t.Errorf("Plan() order = %v, want %v", got, want)
+	}
+
+	levels := map[string]int{}
+	for _, step := range plan.Steps {
+		levels[step.Name] = step.Level
+	}
+	wantLevels := map[string]int{"generate": 0, "build": 1, "lint": 1, "test": 2, "release": 3}
+	for name, level := range wantLevels {
+		if levels[name] != level {
+			t.Errorf("level of %s = %d, want %d", name, levels[name], level)
+		}
+	}
+}
+
+func TestRegistry_PlanErrors(t *testing.T) {
+	t.Run("cycle names the loop", func(t *testing.T) {
+		r, _ := graphRegistry(t, map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"b"},
+		})
+		_, err := r.Plan("a")
+		if !errors.Is(err, ErrDependencyCycle) {
+			t.Fatalf("Plan() error = %v, want ErrDependencyCycle", err)
+		}
+		if !strings.Contains(err.Error(), "b -> c -> b") {
+			t.Errorf("cycle error = %q, want loop b -> c -> b", err)
+		}
+	})
+
+	t.Run("self dependency", func(t *testing.T) {
+		r, _ := graphRegistry(t, map[string][]string{"a": {"a"}})
+		_, err := r.Plan("a")
+		if !errors.Is(err, ErrDependencyCycle) || !strings.Contains(err.Error(), "a -> a") {
+			t.Errorf("Plan() error = %v, want cycle a -> a", err)
+		}
+	})
+
+	t.Run("unknown dependency is not an unknown command", func(t *testing.T) {
+		r, _ := graphRegistry(t, map[string][]string{"a": {"missing"}})
+		err := r.Execute("a")
+		if !errors.Is(err, ErrUnknownDependency) {
+			t.Fatalf("Execute() error = %v, want ErrUnknownDependency", err)
+		}
+		if errors.Is(err, ErrUnknownCommand) {
+			t.Error("a missing dependency must not look like an unknown top-level command")
+		}
+	})
+
+	t.Run("unknown root", func(t *testing.T) {
+		r, _ := graphRegistry(t, nil)
+		if _, err := r.Plan("nope"); !errors.Is(err, ErrUnknownCommand) {
+			t.Errorf("Plan() error = %v, want ErrUnknownCommand", err)
+		}
+	})
+}
+
+func TestRegistry_ExecuteRunsSharedDependencyOnce(t *testing.T) {
+	r, log := graphRegistry(t, map[string][]string{
+		"all":   {"a", "b"},
+		"a":     {"setup"},
+		"b":     {"setup"},
+		"setup": nil,
+	})
+
+	if err := r.Execute("all"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := log(), []string{"setup", "a", "b", "all"}; !slices.Equal(got, want) {
+		t.Errorf("execution order = %v, want %v", got, want)
+	}
+}
+
+func TestRegistry_ExecuteParallel(t *testing.T) {
+	r := NewRegistry()
+	r.SetParallelism(2)
+
+	var running, peak atomic.Int32
+	slow := func() error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		r.MustRegister(&Command{Name: name, Description: name, Func: slow})
+	}
+
+	var rootRan bool
+	r.MustRegister(&Command{
+		Name:         "root",
+		Description:  "root",
+		Dependencies: []string{"a", "b", "c"},
+		Func: func() error {
+			rootRan = true
+			if running.Load() != 0 {
+				return fmt.Errorf("root started while %d dependencies still running", running.Load()) //nolint:err113 // test-only error
+			}
+			return nil
+		},
+	})
+
+	if err := r.Execute("root"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !rootRan {
+		t.Error("root command did not run")
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want the -j limit of 2", got)
+	}
+}
+
+func TestRegistry_ExecuteStopsAfterFailure(t *testing.T) {
+	for _, jobs := range []int{1, 4} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			r := NewRegistry()
+			r.SetParallelism(jobs)
+
+			var ranAfter, ranRoot atomic.Bool
+			r.MustRegister(&Command{Name: "broken", Description: "broken", Func: func() error { return errTestExecutionFailed }})
+			r.MustRegister(&Command{
+				Name: "after", Description: "after", Dependencies: []string{"broken"},
+				Func: func() error { ranAfter.Store(true); return nil },
+			})
+			r.MustRegister(&Command{
+				Name: "root", Description: "root", Dependencies: []string{"after"},
+				Func: func() error { ranRoot.Store(true); return nil },
+			})
+
+			err := r.Execute("root")
+			if !errors.Is(err, errTestExecutionFailed) {
+				t.Fatalf("Execute() error = %v, want wrapped errTestExecutionFailed", err)
+			}
+			if !strings.Contains(err.Error(), "dependency 'broken' failed") {
+				t.Errorf("error = %q, want the failing dependency named", err)
+			}
+			if ranAfter.Load() || ranRoot.Load() {
+				t.Error("commands depending on a failed dependency must not run")
+			}
+		})
+	}
+}
+
+func TestExecutionPlan_Render(t *testing.T) {
+	r, _ := graphRegistry(t, map[string][]string{
+		"ci":    {"lint", "test"},
+		"lint":  nil,
+		"test":  {"build"},
+		"build": nil,
+	})
+	plan, err := r.Plan("ci")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	text := plan.Text()
+	for _, want := range []string{
+		"Execution plan for ci (4 steps):",
+		"Stage 1:\n    - lint\n    - build\n",
+		"Stage 2:\n    - test (after build)\n",
+		"Stage 3:\n    - ci (after lint, test)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() missing %q:\n%s", want, text)
+		}
+	}
+
+	dot := plan.DOT()
+	for _, want := range []string{`digraph "ci" {`, `"ci" [style=bold];`, `"build" -> "test";`, `"lint" -> "ci";`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT() missing %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestRegistry_SetParallelism(t *testing.T) {
+	r := NewRegistry()
+	if got := r.Parallelism(); got != 1 {
+		t.Errorf("default Parallelism() = %d, want 1", got)
+	}
+	r.SetParallelism(0)
+	if got := r.Parallelism(); got != 1 {
+		t.Errorf("Parallelism() after SetParallelism(0) = %d, want 1", got)
+	}
+	r.SetParallelism(8)
+	if got := r.Parallelism(); got != 8 {
+		t.Errorf("Parallelism() = %d, want 8", got)
+	}
+}
@@ -25,6 +25,7 @@ type Registry struct {
 	commands   map[string]*Command
 	aliases    map[string]string // alias -> command name mapping
 	registered bool              // tracks if commands have been registered
+	jobs       int               // max concurrent dependencies during Execute (default 1)
 
 	// Metadata about the registry
 	metadata CommandMetadata
@@ -344,37 +345,43 @@ func (r *Registry) searchTags(tags []string, query string) bool {
 	return false
 }
 
-// Execute runs a command by name with optional arguments
+// Execute runs a command by name with optional arguments.
+// Dependencies are resolved into an execution plan first: each runs once, in
+// dependency order, with up to Parallelism() independent dependencies at a time.
 func (r *Registry) Execute(name string, args ...string) error {
-	cmd, exists := r.Get(name)
-	if !exists {
-		// Use comprehensive search for better suggestions
-		suggestions := r.Search(name)
-		if len(suggestions) > 0 {
-			var suggestionNames []string
-			for i, suggestion := range suggestions {
-				if i >= 5 { // Limit to 5 suggestions
-					break
-				}
-				suggestionNames = append(suggestionNames, suggestion.FullName())
-			}
-			return fmt.Errorf("%w '%s'. Did you mean: %s?",
-				ErrUnknownCommand, name, strings.Join(suggestionNames, ", "))
-		}
-		return fmt.Errorf("%w: %s", ErrUnknownCommand, name)
+	plan, err := r.Plan(name)
+	if err != nil {
+		return err
 	}
 
-	// Execute dependencies first
-	for _, dep := range cmd.Dependencies {
-		if err := r.Execute(dep); err != nil {
-			return fmt.Errorf("dependency '%s' failed: %w", dep, err)
-		}
+	// Execute dependencies first (every step except the root, which is last)
+	if err := r.runSteps(plan.Steps[:len(plan.Steps)-1], r.Parallelism()); err != nil {
+		return err
 	}
 
 	// Execute the command
+	cmd, _ := r.Get(plan.Root)
 	return cmd.Execute(args...)
 }
 
+// unknownCommandError builds an ErrUnknownCommand error with up to 5 suggestions
+func (r *Registry) unknownCommandError(name string) error {
+	// Use comprehensive search for better suggestions
+	suggestions := r.Search(name)
+	if len(suggestions) > 0 {
+		var suggestionNames []string
+		for i, suggestion := range suggestions {
+			if i >= 5 { // Limit to 5 suggestions
+				break
+			}
+			suggestionNames = append(suggestionNames, suggestion.FullName())
+		}
+		return fmt.Errorf("%w '%s'. Did you mean: %s?",
+			ErrUnknownCommand, name, strings.Join(suggestionNames, ", "))
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownCommand, name)
+}
+
 // Metadata returns registry metadata with deep-copied maps to prevent race conditions
 func (r *Registry) Metadata() CommandMetadata {
 	r.mu.RLock()
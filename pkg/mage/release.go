@@ -364,38 +364,7 @@ func (Release) Init() error {
 // Changelog generates a changelog for the next release
 func (Release) Changelog() error {
 	utils.Header("Generating Changelog")
-
-	// Get the latest tag
-	latestTag, err := GetRunner().RunCmdOutput("git", "describe", "--tags", "--abbrev=0")
-	if err != nil {
-		utils.Warn("No previous tags found, generating full changelog")
-		latestTag = ""
-	} else {
-		latestTag = strings.TrimSpace(latestTag)
-		utils.Info("Generating changelog since %s", latestTag)
-	}
-
-	// Generate changelog using git log
-	var args []string
-	if latestTag != "" {
-		args = []string{"log", "--pretty=format:- %s", fmt.Sprintf("%s..HEAD", latestTag)}
-	} else {
-		args = []string{"log", "--pretty=format:- %s"}
-	}
-
-	output, err := GetRunner().RunCmdOutput("git", args...)
-	if err != nil {
-		return fmt.Errorf("failed to generate changelog: %w", err)
-	}
-
-	if strings.TrimSpace(output) == "" {
-		utils.Info("No changes since last release")
-	} else {
-		utils.Info("Changelog:")
-		utils.Info("%s", output)
-	}
-
-	return nil
+	return runChangelog(nil)
 }
 
 // Helper functions
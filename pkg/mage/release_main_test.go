@@ -276,11 +276,13 @@ func (ts *ReleaseMainTestSuite) TestClean_Success() {
 
 // TestChangelog_NoTags tests Changelog with no previous tags
 func (ts *ReleaseMainTestSuite) TestChangelog_NoTags() {
-	// Mock git describe (no tags)
-	ts.env.Runner.On("RunCmdOutput", "git", []string{"describe", "--tags", "--abbrev=0"}).Return("", errReleaseGitError)
+	// Mock git tag (no tags)
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"tag", "-l", "v*", "--sort=-version:refname"}).Return("", errReleaseGitError)
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"tag", "-l", "v*", "--sort=-version:refname", "--points-at", "HEAD"}).Return("", errReleaseGitError)
 
 	// Mock git log (full history)
-	ts.env.Runner.On("RunCmdOutput", "git", []string{"log", "--pretty=format:- %s"}).Return("- Initial commit\n- Add feature\n", nil)
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"log", "--no-merges", changelogLogFormat, "HEAD"}).
+		Return("abc\x1fabc\x1f2024-01-02\x1fdev\x1fInitial commit\x1e\nadd\x1fadd\x1f2024-01-03\x1fdev\x1ffeat: add feature\x1e", nil)
 
 	err := ts.env.WithMockRunner(
 		setTestCommandRunner,
@@ -295,11 +297,13 @@ func (ts *ReleaseMainTestSuite) TestChangelog_NoTags() {
 
 // TestChangelog_WithTag tests Changelog since last tag
 func (ts *ReleaseMainTestSuite) TestChangelog_WithTag() {
-	// Mock git describe
-	ts.env.Runner.On("RunCmdOutput", "git", []string{"describe", "--tags", "--abbrev=0"}).Return("v1.0.0", nil)
+	// Mock git tag
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"tag", "-l", "v*", "--sort=-version:refname"}).Return("v1.0.0\n", nil)
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"tag", "-l", "v*", "--sort=-version:refname", "--points-at", "HEAD"}).Return("", nil)
 
 	// Mock git log since tag
-	ts.env.Runner.On("RunCmdOutput", "git", []string{"log", "--pretty=format:- %s", "v1.0.0..HEAD"}).Return("- Fix bug\n- Add feature\n", nil)
+	ts.env.Runner.On("RunCmdOutput", "git", []string{"log", "--no-merges", changelogLogFormat, "v1.0.0..HEAD"}).
+		Return("abc\x1fabc\x1f2024-01-02\x1fdev\x1ffix: bug\x1e", nil)
 
 	err := ts.env.WithMockRunner(
 		setTestCommandRunner,
@@ -0,0 +1,481 @@
+package mage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for native release packaging
+var (
+	errUnsupportedArchiveFormat = errors.New("unsupported release format")
+	errReleaseBinaryMissing     = errors.New("release binary not found, run build:all first")
+	errNoReleasePlatforms       = errors.New("no build platforms configured")
+	errInvalidReleaseName       = errors.New("release name template produced an empty name")
+	errReleaseNameCollision     = errors.New("release artifacts would overwrite each other, include .Os and .Arch in release.name_template")
+)
+
+const (
+	// releaseDistDir is where release:package writes its artifacts
+	releaseDistDir = "dist"
+
+	// releaseFormatTarGz packages a platform as a gzipped tarball
+	releaseFormatTarGz = "tar.gz"
+
+	// releaseFormatZip packages a platform as a zip archive
+	releaseFormatZip = "zip"
+
+	// releaseFormatBinary ships the bare binary
+	releaseFormatBinary = "binary"
+
+	// defaultReleaseNameTmpl names archives when release.name_template is not set
+	defaultReleaseNameTmpl = "{{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}"
+
+	// releaseManifestFile lists the packaged artifacts
+	releaseManifestFile = "manifest.json"
+)
+
+// releaseNameData holds the fields release.name_template can use
+type releaseNameData struct {
+	ProjectName string
+	Binary      string
+	Version     string // Tag without the leading "v"
+	Tag         string
+	Os          string
+	Arch        string
+}
+
+// releaseArtifact is one file written by release:package
+type releaseArtifact struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	OS     string `json:"goos,omitempty"`
+	Arch   string `json:"goarch,omitempty"`
+	Format string `json:"format"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// plannedReleaseArtifact is an artifact release:package is about to write
+type plannedReleaseArtifact struct {
+	path   string
+	format string
+	os     string
+	arch   string
+	files  []archiveFile
+}
+
+// releaseManifest describes a packaged release
+type releaseManifest struct {
+	ProjectName string            `json:"project_name"`
+	Version     string            `json:"version"`
+	Tag         string            `json:"tag"`
+	Commit      string            `json:"commit"`
+	Date        time.Time         `json:"date"`
+	Artifacts   []releaseArtifact `json:"artifacts"`
+}
+
+// archiveFile is a file placed at the root of a release archive
+type archiveFile struct {
+	source string
+	name   string
+	mode   fs.FileMode
+}
+
+// Package builds every platform in build.platforms with build:all and packages
+// the binaries into dist/ without goreleaser: one archive per platform and
+// release.formats entry (tar.gz, zip or binary), named by release.name_template
+// and holding the binary with the README and LICENSE files, plus a SHA256
+// checksums file and a manifest.json listing every artifact.
+// Supports:
+//   - version: Tag the binaries are built and named with (default: from git)
+//   - skip-build: Package the binaries already in build.output (default: false)
+func (Release) Package(args ...string) error {
+	utils.Header("Packaging Release")
+
+	params := utils.ParseParams(args)
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	tag := utils.GetParam(params, "version", "")
+	if tag != "" {
+		// Build.All embeds the release version through getVersion
+		restore, setErr := setReleaseVersionEnv(tag)
+		if setErr != nil {
+			return setErr
+		}
+		defer restore()
+	} else {
+		tag = getVersion()
+	}
+
+	if !utils.IsParamTrue(params, "skip-build") {
+		if err := (Build{}).All(); err != nil {
+			return fmt.Errorf("failed to build release binaries: %w", err)
+		}
+	}
+
+	manifest, err := packageRelease(config, tag, getCommit(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range manifest.Artifacts {
+		utils.Info("%s", artifact.Path)
+	}
+	utils.Success("Packaged %s %s into %s/", manifest.ProjectName, tag, releaseDistDir)
+	return nil
+}
+
+// setReleaseVersionEnv sets MAGE_X_RELEASE_VERSION and returns a function that
+// restores the previous value
+func setReleaseVersionEnv(version string) (func(), error) {
+	const key = "MAGE_X_RELEASE_VERSION"
+	previous, had := os.LookupEnv(key)
+	if err := os.Setenv(key, version); err != nil {
+		return nil, fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return func() {
+		if had {
+			_ = os.Setenv(key, previous) //nolint:errcheck // best-effort restore
+		} else {
+			_ = os.Unsetenv(key) //nolint:errcheck // best-effort restore
+		}
+	}, nil
+}
+
+// packageRelease writes the archives, checksums file and manifest for the binaries
+// build:all produced and returns the manifest
+func packageRelease(config *Config, tag, commit string, date time.Time) (*releaseManifest, error) {
+	if len(config.Build.Platforms) == 0 {
+		return nil, errNoReleasePlatforms
+	}
+	formats, err := releaseFormats(config.Release.Formats)
+	if err != nil {
+		return nil, err
+	}
+	nameTmpl := config.Release.NameTmpl
+	if nameTmpl == "" {
+		nameTmpl = defaultReleaseNameTmpl
+	}
+	tmpl, err := template.New("name_template").Option("missingkey=error").Parse(nameTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release.name_template: %w", err)
+	}
+	extras, err := releaseExtraFiles(".")
+	if err != nil {
+		return nil, err
+	}
+
+	projectName := config.Project.Name
+	if projectName == "" {
+		projectName = config.Project.Binary
+	}
+	manifest := &releaseManifest{
+		ProjectName: projectName,
+		Version:     strings.TrimPrefix(tag, "v"),
+		Tag:         tag,
+		Commit:      commit,
+		Date:        date,
+	}
+
+	// Render every artifact path up front so a name template that leaves out the
+	// platform fails before anything is written
+	checksumsPath := filepath.Join(releaseDistDir, fmt.Sprintf("%s_%s_checksums.txt", projectName, manifest.Version))
+	owners := map[string]string{
+		checksumsPath: "checksums",
+		filepath.Join(releaseDistDir, releaseManifestFile): "manifest",
+	}
+	var planned []plannedReleaseArtifact
+	for _, platform := range config.Build.Platforms {
+		p, err := utils.ParsePlatform(platform)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", platform, err)
+		}
+		ext := utils.GetBinaryExt(p)
+		binaryPath := filepath.Join(config.Build.Output, fmt.Sprintf("%s-%s-%s%s", config.Project.Binary, p.OS, p.Arch, ext))
+		if !utils.FileExists(binaryPath) {
+			return nil, fmt.Errorf("%w: %s", errReleaseBinaryMissing, binaryPath)
+		}
+
+		name, err := renderReleaseName(tmpl, releaseNameData{
+			ProjectName: projectName,
+			Binary:      config.Project.Binary,
+			Version:     manifest.Version,
+			Tag:         tag,
+			Os:          p.OS,
+			Arch:        p.Arch,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		files := append([]archiveFile{{source: binaryPath, name: config.Project.Binary + ext, mode: fileops.PermFileExecutable}}, extras...)
+		for _, format := range formats {
+			artifactPath := filepath.Join(releaseDistDir, releaseArtifactName(name, format, ext))
+			owner := platform + " " + format
+			if previous, taken := owners[artifactPath]; taken {
+				return nil, fmt.Errorf("%w: %s is produced by both %s and %s", errReleaseNameCollision, artifactPath, previous, owner)
+			}
+			owners[artifactPath] = owner
+			planned = append(planned, plannedReleaseArtifact{path: artifactPath, format: format, os: p.OS, arch: p.Arch, files: files})
+		}
+	}
+
+	if err := os.MkdirAll(releaseDistDir, fileops.PermDir); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", releaseDistDir, err)
+	}
+
+	for _, item := range planned {
+		if err := writeReleaseArtifact(item.path, item.format, item.files); err != nil {
+			return nil, err
+		}
+		artifact, err := describeReleaseArtifact(item.path, item.format)
+		if err != nil {
+			return nil, err
+		}
+		artifact.OS, artifact.Arch = item.os, item.arch
+		manifest.Artifacts = append(manifest.Artifacts, artifact)
+	}
+
+	if err := writeReleaseChecksums(checksumsPath, manifest.Artifacts); err != nil {
+		return nil, err
+	}
+	checksums, err := describeReleaseArtifact(checksumsPath, "checksums")
+	if err != nil {
+		return nil, err
+	}
+	checksums.SHA256 = ""
+	manifest.Artifacts = append(manifest.Artifacts, checksums)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode release manifest: %w", err)
+	}
+	manifestPath := filepath.Join(releaseDistDir, releaseManifestFile)
+	if err := os.WriteFile(manifestPath, append(data, '\n'), fileops.PermFile); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+	return manifest, nil
+}
+
+// releaseFormats validates release.formats, defaulting to tar.gz
+func releaseFormats(configured []string) ([]string, error) {
+	var formats []string
+	for _, format := range configured {
+		format = strings.ToLower(strings.TrimSpace(format))
+		switch format {
+		case "":
+			continue
+		case "tgz":
+			format = releaseFormatTarGz
+		case releaseFormatTarGz, releaseFormatZip, releaseFormatBinary:
+		default:
+			return nil, fmt.Errorf("%w: %q (use %s, %s or %s)", errUnsupportedArchiveFormat, format,
+				releaseFormatTarGz, releaseFormatZip, releaseFormatBinary)
+		}
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	if len(formats) == 0 {
+		formats = []string{releaseFormatTarGz}
+	}
+	return formats, nil
+}
+
+// renderReleaseName executes the name template for one platform
+func renderReleaseName(tmpl *template.Template, data releaseNameData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("invalid release.name_template: %w", err)
+	}
+	name := strings.TrimSpace(sb.String())
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: %q", errInvalidReleaseName, name)
+	}
+	return name, nil
+}
+
+// releaseArtifactName returns the file name of an artifact in a given format
+func releaseArtifactName(name, format, ext string) string {
+	if format == releaseFormatBinary {
+		return name + ext
+	}
+	return name + "." + format
+}
+
+// releaseExtraFiles returns the README and LICENSE files of dir that go into
+// every archive
+func releaseExtraFiles(dir string) ([]archiveFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var files []archiveFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		lower := strings.ToLower(entry.Name())
+		if strings.HasPrefix(lower, "readme") || strings.HasPrefix(lower, "license") || strings.HasPrefix(lower, "licence") {
+			files = append(files, archiveFile{source: filepath.Join(dir, entry.Name()), name: entry.Name(), mode: fileops.PermFile})
+		}
+	}
+	return files, nil
+}
+
+// writeReleaseArtifact writes files into an archive, or copies the binary for the
+// binary format
+func writeReleaseArtifact(path, format string, files []archiveFile) (err error) {
+	out, err := os.Create(path) // #nosec G304 -- path is inside the dist directory
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to write %s: %w", path, closeErr)
+		}
+	}()
+
+	switch format {
+	case releaseFormatTarGz:
+		err = writeTarGz(out, files)
+	case releaseFormatZip:
+		err = writeZip(out, files)
+	case releaseFormatBinary:
+		err = copyFileTo(out, files[0].source)
+		if err == nil {
+			err = out.Chmod(fileops.PermFileExecutable)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeTarGz writes files to w as a gzipped tarball
+func writeTarGz(w io.Writer, files []archiveFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		info, err := os.Stat(file.source)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file.source, err)
+		}
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    int64(file.mode),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if err := copyFileTo(tw, file.source); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish tarball: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish gzip stream: %w", err)
+	}
+	return nil
+}
+
+// writeZip writes files to w as a zip archive
+func writeZip(w io.Writer, files []archiveFile) error {
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		info, err := os.Stat(file.source)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file.source, err)
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		header.Name = file.name
+		header.Method = zip.Deflate
+		header.SetMode(file.mode)
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if err := copyFileTo(entry, file.source); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %w", err)
+	}
+	return nil
+}
+
+// copyFileTo copies the contents of source to w
+func copyFileTo(w io.Writer, source string) error {
+	in, err := os.Open(source) // #nosec G304 -- source is a release binary or project file
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", source, err)
+	}
+	defer func() { _ = in.Close() }() //nolint:errcheck // read-only file
+
+	if _, err := io.Copy(w, in); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", source, err)
+	}
+	return nil
+}
+
+// describeReleaseArtifact returns the manifest entry of a written artifact
+func describeReleaseArtifact(path, format string) (releaseArtifact, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return releaseArtifact{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return releaseArtifact{}, err
+	}
+	return releaseArtifact{
+		Name:   filepath.Base(path),
+		Path:   filepath.ToSlash(path),
+		Format: format,
+		Size:   info.Size(),
+		SHA256: checksum,
+	}, nil
+}
+
+// writeReleaseChecksums writes a sha256sum-compatible checksums file
+func writeReleaseChecksums(path string, artifacts []releaseArtifact) error {
+	sorted := slices.Clone(artifacts)
+	slices.SortFunc(sorted, func(a, b releaseArtifact) int { return strings.Compare(a.Name, b.Name) })
+
+	var sb strings.Builder
+	for _, artifact := range sorted {
+		sb.WriteString(artifact.SHA256 + "  " + artifact.Name + "\n")
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
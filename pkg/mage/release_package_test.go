@@ -0,0 +1,206 @@
+package mage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useReleaseProject runs the test in a temp project holding fake build:all
+// binaries for the given platforms
+func useReleaseProject(t *testing.T, release ReleaseConfig, platforms ...string) *Config {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	config := defaultConfig()
+	config.Project.Name = "demo"
+	config.Project.Binary = "demo"
+	config.Build.Output = "bin"
+	config.Build.Platforms = platforms
+	config.Release = release
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+
+	files := map[string]string{
+		"README.md":  "# demo\n",
+		"LICENSE":    "MIT\n",
+		"go.mod":     "module example.com/demo\n",
+		"CHANGES.md": "not packaged\n",
+	}
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		name := "demo-" + goos + "-" + goarch
+		if goos == "windows" {
+			name += ".exe"
+		}
+		files[filepath.Join("bin", name)] = "binary " + platform
+	}
+	writeTestFiles(t, files)
+	return config
+}
+
+// tarGzEntries returns the names and modes of the files in a tarball
+func tarGzEntries(t *testing.T, path string) map[string]int64 {
+	t.Helper()
+	file, err := os.Open(path) //nolint:gosec // test artifact
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	entries := make(map[string]int64)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		require.NoError(t, err)
+		entries[header.Name] = header.Mode
+	}
+}
+
+func TestReleasePackage_ArchivesChecksumsAndManifest(t *testing.T) {
+	useReleaseProject(t, ReleaseConfig{Formats: []string{"tar.gz", "zip"}}, "linux/amd64", "windows/amd64")
+
+	require.NoError(t, Release{}.Package("version=v1.2.3", "skip-build=true"))
+
+	entries := tarGzEntries(t, filepath.Join("dist", "demo_1.2.3_linux_amd64.tar.gz"))
+	assert.Equal(t, map[string]int64{"demo": 0o755, "README.md": 0o644, "LICENSE": 0o644}, entries)
+
+	zr, err := zip.OpenReader(filepath.Join("dist", "demo_1.2.3_windows_amd64.zip"))
+	require.NoError(t, err)
+	defer func() { _ = zr.Close() }()
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"LICENSE", "README.md", "demo.exe"}, names)
+
+	checksums, err := os.ReadFile(filepath.Join("dist", "demo_1.2.3_checksums.txt"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(checksums)), "\n")
+	require.Len(t, lines, 4)
+	sum, err := fileChecksum(filepath.Join("dist", "demo_1.2.3_linux_amd64.tar.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, sum+"  demo_1.2.3_linux_amd64.tar.gz", lines[0])
+
+	data, err := os.ReadFile(filepath.Join("dist", releaseManifestFile))
+	require.NoError(t, err)
+	var manifest releaseManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, "demo", manifest.ProjectName)
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.Equal(t, "v1.2.3", manifest.Tag)
+	require.Len(t, manifest.Artifacts, 5)
+	assert.Equal(t, releaseArtifact{
+		Name:   "demo_1.2.3_linux_amd64.tar.gz",
+		Path:   "dist/demo_1.2.3_linux_amd64.tar.gz",
+		OS:     "linux",
+		Arch:   "amd64",
+		Format: releaseFormatTarGz,
+		Size:   manifest.Artifacts[0].Size,
+		SHA256: sum,
+	}, manifest.Artifacts[0])
+	assert.Equal(t, "checksums", manifest.Artifacts[4].Format)
+
+	_, hadVersion := os.LookupEnv("MAGE_X_RELEASE_VERSION")
+	assert.False(t, hadVersion, "the version override is restored")
+}
+
+func TestReleasePackage_NameTemplateAndBinaryFormat(t *testing.T) {
+	config := useReleaseProject(t, ReleaseConfig{
+		Formats:  []string{"binary"},
+		NameTmpl: "{{ .Binary }}-{{ .Tag }}-{{ .Os }}-{{ .Arch }}",
+	}, "darwin/arm64", "windows/amd64")
+
+	manifest, err := packageRelease(config, "v2.0.0", "abc123", time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc123", manifest.Commit)
+	assert.FileExists(t, filepath.Join("dist", "demo-v2.0.0-darwin-arm64"))
+	assert.FileExists(t, filepath.Join("dist", "demo-v2.0.0-windows-amd64.exe"))
+	info, err := os.Stat(filepath.Join("dist", "demo-v2.0.0-darwin-arm64"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+}
+
+func TestReleasePackage_Errors(t *testing.T) {
+	t.Run("missing binary", func(t *testing.T) {
+		config := useReleaseProject(t, ReleaseConfig{}, "linux/amd64")
+		config.Build.Platforms = append(config.Build.Platforms, "linux/arm64")
+		_, err := packageRelease(config, "v1.0.0", "", time.Now())
+		require.ErrorIs(t, err, errReleaseBinaryMissing)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		useReleaseProject(t, ReleaseConfig{Formats: []string{"rar"}}, "linux/amd64")
+		require.ErrorIs(t, Release{}.Package("skip-build=true"), errUnsupportedArchiveFormat)
+	})
+
+	t.Run("no platforms", func(t *testing.T) {
+		config := useReleaseProject(t, ReleaseConfig{})
+		_, err := packageRelease(config, "v1.0.0", "", time.Now())
+		require.ErrorIs(t, err, errNoReleasePlatforms)
+	})
+
+	t.Run("name with a path", func(t *testing.T) {
+		config := useReleaseProject(t, ReleaseConfig{NameTmpl: "{{ .Os }}/{{ .Arch }}"}, "linux/amd64")
+		_, err := packageRelease(config, "v1.0.0", "", time.Now())
+		require.ErrorIs(t, err, errInvalidReleaseName)
+	})
+
+	t.Run("name without the platform", func(t *testing.T) {
+		config := useReleaseProject(t, ReleaseConfig{NameTmpl: "{{ .ProjectName }}_{{ .Version }}"}, "linux/amd64", "darwin/arm64")
+		_, err := packageRelease(config, "v1.0.0", "", time.Now())
+		require.ErrorIs(t, err, errReleaseNameCollision)
+		assert.Contains(t, err.Error(), "linux/amd64 tar.gz")
+		assert.NoDirExists(t, "dist", "nothing is written when names collide")
+	})
+
+	t.Run("name of the checksums file", func(t *testing.T) {
+		config := useReleaseProject(t, ReleaseConfig{
+			Formats:  []string{"binary"},
+			NameTmpl: "{{ .ProjectName }}_{{ .Version }}_checksums.txt",
+		}, "linux/amd64")
+		_, err := packageRelease(config, "v1.0.0", "", time.Now())
+		require.ErrorIs(t, err, errReleaseNameCollision)
+	})
+}
+
+func TestReleaseFormats(t *testing.T) {
+	t.Parallel()
+
+	formats, err := releaseFormats(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{releaseFormatTarGz}, formats)
+
+	formats, err = releaseFormats([]string{"ZIP", "tgz", "tar.gz", " binary "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{releaseFormatZip, releaseFormatTarGz, releaseFormatBinary}, formats)
+}
+
+func TestRenderReleaseName(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("name").Option("missingkey=error").Parse(defaultReleaseNameTmpl))
+	name, err := renderReleaseName(tmpl, releaseNameData{ProjectName: "app", Version: "1.0.0", Os: "linux", Arch: "arm64"})
+	require.NoError(t, err)
+	assert.Equal(t, "app_1.0.0_linux_arm64", name)
+
+	bad := template.Must(template.New("name").Parse("{{ .Missing }}"))
+	_, err = renderReleaseName(bad, releaseNameData{})
+	require.Error(t, err)
+}
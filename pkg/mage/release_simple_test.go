@@ -246,8 +246,9 @@ func (suite *ReleaseSimpleTestSuite) TestReleaseInitFailsWhenConfigExists() {
 // TestReleaseChangelog tests changelog generation
 func (suite *ReleaseSimpleTestSuite) TestReleaseChangelog() {
 	// Mock git commands
-	suite.mockRunner.SetOutput("git", []string{"describe", "--tags", "--abbrev=0"}, "v1.0.0")
-	suite.mockRunner.SetOutput("git", []string{"log", "--pretty=format:- %s", "v1.0.0..HEAD"}, "- feat: new feature\n- fix: bug fix")
+	suite.mockRunner.SetOutput("git", []string{"tag", "-l", "v*", "--sort=-version:refname"}, "v1.0.0")
+	suite.mockRunner.SetOutput("git", []string{"log", "--no-merges", changelogLogFormat, "v1.0.0..HEAD"},
+		"a1\x1fa1\x1f2024-01-02\x1fdev\x1ffeat: new feature\x1e\nb2\x1fb2\x1f2024-01-01\x1fdev\x1ffix: bug fix\x1e")
 
 	originalRunner := GetRunner()
 	if err := SetRunner(suite.mockRunner); err != nil {
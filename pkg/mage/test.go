@@ -69,7 +69,7 @@ type fuzzOptions struct {
 	successMessage string // message to display on success
 }
 
-// getCIParams extracts CI-related parameters from args and returns the remaining args
+// getCIParams extracts CI-related parameters (ci, ci_format, flaky_retries) from args and returns the remaining args
 func getCIParams(args []string) (params map[string]string, remainingArgs []string) {
 	params = make(map[string]string)
 	for _, arg := range args {
@@ -78,6 +78,10 @@ func getCIParams(args []string) (params map[string]string, remainingArgs []strin
 			params["ci"] = strings.TrimPrefix(arg, "ci=")
 		} else if arg == "ci" {
 			params["ci"] = trueValue
+		} else if strings.HasPrefix(arg, "ci_format=") {
+			params["ci_format"] = strings.TrimPrefix(arg, "ci_format=")
+		} else if strings.HasPrefix(arg, "flaky_retries=") {
+			params["flaky_retries"] = strings.TrimPrefix(arg, "flaky_retries=")
 		} else {
 			remainingArgs = append(remainingArgs, arg)
 		}
@@ -1264,6 +1268,13 @@ func runTestsForModulesWithRunner(config *Config, modules []ModuleInfo, race, co
 		return nil
 	}
 
+	// CI reporters parse live go test output, which replayed results cannot provide
+	var resultCache *testResultCache
+	if _, isCI := runner.(CIRunner); !isCI && !cover {
+		resultCache = newTestResultCache(config, runner)
+	}
+	defer resultCache.report()
+
 	for _, module := range filteredModules {
 		if err := runtimectx.CheckCanceled(); err != nil {
 			return fmt.Errorf("tests canceled: %w", err)
@@ -1290,10 +1301,15 @@ func runTestsForModulesWithRunner(config *Config, modules []ModuleInfo, race, co
 		if testType == "unit" || testType == "short" {
 			testArgs = append(testArgs, "-short")
 		}
-		testArgs = append(testArgs, "./...")
 
-		// Run tests in module directory using provided runner
-		err := runCommandInModuleWithRunner(module, runner, "go", testArgs...)
+		// Run tests in module directory using provided runner, replaying
+		// recorded passes when the test result cache is enabled
+		var err error
+		if resultCache.cacheable(testArgs) {
+			err = resultCache.run(module, runner, testArgs)
+		} else {
+			err = runCommandInModuleWithRunner(module, runner, "go", append(testArgs, "./...")...)
+		}
 
 		tagInfo := getTagInfo(buildTag)
 		if err != nil {
@@ -1333,6 +1349,7 @@ func parseSafeTestArgs(args []string) ([]string, error) {
 		"-failfast":  true,
 		"-vet":       true,
 		"-run":       true,
+		"-skip":      true,
 		"-bench":     true,
 		"-benchmem":  true,
 		"-benchtime": true,
@@ -1362,7 +1379,7 @@ func parseSafeTestArgs(args []string) ([]string, error) {
 			// Check if this flag expects a value (next argument)
 			flagsWithValues := map[string]bool{
 				"-count": true, "-cpu": true, "-parallel": true, "-vet": true,
-				"-run": true, "-bench": true, "-benchtime": true, "-timeout": true,
+				"-run": true, "-skip": true, "-bench": true, "-benchtime": true, "-timeout": true,
 				"-covermode": true, "-coverpkg": true, "-tags": true,
 			}
 
@@ -1426,7 +1443,8 @@ func buildTestArgs(cfg *Config, race, cover bool, additionalArgs ...string) []st
 		}
 	}
 
-	return args
+	// Quarantined tests are skipped alongside any -skip the caller passed
+	return appendQuarantineSkip(args, cfg.Test.Quarantine)
 }
 
 // buildTestArgsWithOverrides builds test arguments with explicit overrides for race and cover
@@ -1485,7 +1503,8 @@ func buildTestArgsWithOverrides(cfg *Config, raceOverride, coverOverride *bool,
 		}
 	}
 
-	return args
+	// Quarantined tests are skipped alongside any -skip the caller passed
+	return appendQuarantineSkip(args, cfg.Test.Quarantine)
 }
 
 // findFuzzPackages finds packages containing fuzz tests in the current directory.
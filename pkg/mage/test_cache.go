@@ -0,0 +1,393 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/cache"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// errTestCacheNoPackages is returned when go list reports no packages for a module
+var errTestCacheNoPackages = errors.New("no packages listed")
+
+// testCacheBypassFlags are go test flags whose output cannot be replayed from a recorded pass.
+// -count is also Go's own idiom for forcing tests to run.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var testCacheBypassFlags = []string{"-count", "-json", "-cover", "-covermode", "-coverpkg", "-coverprofile", "-bench", "-fuzz"}
+
+// testCacheIgnoredEnv are Go environment variables that locate files on this machine
+// without affecting test results, so they stay out of the cache key
+//
+//nolint:gochecknoglobals // read-only lookup table
+var testCacheIgnoredEnv = []string{"GOCACHE", "GOENV", "GOMODCACHE", "GOPATH", "GOROOT", "GOTMPDIR"}
+
+// goTestSummaryRegex matches the line go test prints when it finishes a package
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var goTestSummaryRegex = regexp.MustCompile(`^(?:ok  |\?   |FAIL)\t(\S+)`)
+
+// testResultCache skips packages whose tests already passed with identical inputs.
+// Entries are keyed on the content of each package, its module-local dependencies and
+// test data, plus go.mod/go.sum, the go test flags, the Go version and Go environment.
+// The cache lives outside Go's build cache, so it survives `go clean -testcache`, and
+// keys use paths relative to the project root, so worktrees share entries.
+type testResultCache struct {
+	manager *cache.Manager
+	root    string
+	keyEnv  []string
+}
+
+// goListTestPackage is the subset of go list output needed to key a package's tests
+type goListTestPackage struct {
+	ImportPath      string
+	Dir             string
+	GoFiles         []string
+	CgoFiles        []string
+	CFiles          []string
+	CXXFiles        []string
+	HFiles          []string
+	SFiles          []string
+	SysoFiles       []string
+	EmbedFiles      []string
+	TestGoFiles     []string
+	XTestGoFiles    []string
+	TestEmbedFiles  []string
+	XTestEmbedFiles []string
+	Deps            []string
+	TestImports     []string
+	XTestImports    []string
+}
+
+// testCachePlan splits a module's packages into recorded passes and packages to run
+type testCachePlan struct {
+	hits   []*cache.TestResult
+	misses []string
+	keys   map[string]string
+}
+
+// newTestResultCache opens the test result cache, returning nil when it is disabled
+// or cannot be used
+func newTestResultCache(config *Config, runner CommandRunner) *testResultCache {
+	if config == nil || !config.Test.ResultCache {
+		return nil
+	}
+
+	dir, err := testResultCacheDir(config)
+	if err != nil {
+		utils.Warn("Test result cache disabled: %v", err)
+		return nil
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		utils.Warn("Test result cache disabled: %v", err)
+		return nil
+	}
+
+	goVersion, err := runner.RunCmdOutput("go", "env", "GOVERSION")
+	if err != nil {
+		utils.Warn("Test result cache disabled: failed to read Go version: %v", err)
+		return nil
+	}
+
+	cacheConfig := cache.DefaultConfig()
+	cacheConfig.Directory = dir
+	applyRemoteCacheConfig(cacheConfig, config.Cache.Remote)
+	manager := cache.NewManager(cacheConfig)
+	if err := manager.Init(); err != nil {
+		utils.Warn("Test result cache disabled: %v", err)
+		return nil
+	}
+
+	return &testResultCache{
+		manager: manager,
+		root:    root,
+		keyEnv:  append([]string{"go=" + strings.TrimSpace(goVersion)}, testCacheEnv(os.Environ())...),
+	}
+}
+
+// testResultCacheDir resolves the configured cache directory, defaulting to a
+// per-user directory shared by every checkout
+func testResultCacheDir(config *Config) (string, error) {
+	if config.Test.ResultCacheDir != "" {
+		return filepath.Abs(config.Test.ResultCacheDir)
+	}
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(userCache, DefaultTestResultCacheDir), nil
+}
+
+// testCacheEnv returns the sorted GO* and CGO_* variables that can change test results
+func testCacheEnv(environ []string) []string {
+	var keyEnv []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, "GO") && !strings.HasPrefix(name, "CGO_") {
+			continue
+		}
+		if slices.Contains(testCacheIgnoredEnv, name) {
+			continue
+		}
+		keyEnv = append(keyEnv, kv)
+	}
+	sort.Strings(keyEnv)
+	return keyEnv
+}
+
+// cacheable reports whether go test output for these arguments can be replayed
+func (c *testResultCache) cacheable(testArgs []string) bool {
+	if c == nil {
+		return false
+	}
+	for _, arg := range testArgs {
+		name, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(testCacheBypassFlags, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// run tests a module, replaying recorded passes and running only the remaining
+// packages. The output of the remaining packages is captured so it can be replayed
+// later, and is printed once go test finishes, whether it passed or failed. Packages
+// go test reports as passed are recorded even when others fail. When the cache cannot
+// be consulted the whole module is tested as usual.
+func (c *testResultCache) run(module ModuleInfo, runner CommandRunner, testArgs []string) error {
+	plan, err := c.plan(module, runner, testArgs)
+	if err != nil {
+		utils.Warn("Test result cache skipped for %s: %v", module.Relative, err)
+		return runCommandInModuleWithRunner(module, runner, "go", append(slices.Clone(testArgs), "./...")...)
+	}
+
+	for _, hit := range plan.hits {
+		utils.Print("%s\t(cached pass from %s)\n", hit.Output, hit.Timestamp.Local().Format(time.DateTime))
+	}
+	if len(plan.misses) == 0 {
+		utils.Info("All %d packages replayed from the test result cache", len(plan.hits))
+		return nil
+	}
+
+	start := time.Now()
+	output, err := runCommandInModuleOutputWithRunner(module, runner, "go", append(slices.Clone(testArgs), plan.misses...)...)
+	// Print the output before checking the error, so failing tests are shown
+	if output != "" {
+		utils.Println(strings.TrimRight(output, "\n"))
+	}
+	c.record(plan, output, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("failed to run command 'go' in module %s: %w", module.Relative, err)
+	}
+	return nil
+}
+
+// record stores the output of every planned package that go test reported as passed
+func (c *testResultCache) record(plan *testCachePlan, output string, elapsed time.Duration) {
+	outputs := splitGoTestOutput(output)
+	for _, pkg := range plan.misses {
+		pkgOutput, ok := outputs[pkg]
+		if !ok {
+			utils.Debug("no go test output for %s, not recording it", pkg)
+			continue
+		}
+		if !goTestPassed(pkgOutput) {
+			continue
+		}
+		result := &cache.TestResult{
+			Package:  pkg,
+			Success:  true,
+			Output:   pkgOutput,
+			Duration: elapsed,
+		}
+		if err := c.manager.GetBuildCache().StoreTestResult(plan.keys[pkg], result); err != nil {
+			utils.Warn("Failed to record test result for %s: %v", pkg, err)
+		}
+	}
+}
+
+// plan lists a module's packages and looks up each one in the cache
+func (c *testResultCache) plan(module ModuleInfo, runner CommandRunner, testArgs []string) (*testCachePlan, error) {
+	listArgs := []string{"list", "-e", "-json"}
+	if tags := testArgValue(testArgs, "-tags"); tags != "" {
+		listArgs = append(listArgs, "-tags", tags)
+	}
+	listArgs = append(listArgs, "./...")
+
+	output, err := runCommandInModuleOutputWithRunner(module, runner, "go", listArgs...)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := decodeGoListPackages(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, errTestCacheNoPackages
+	}
+
+	flags := slices.Concat(testArgs, c.keyEnv)
+	// The root go.mod and go.sum are always part of the key; nested modules add their own
+	var moduleFiles []string
+	if !module.IsRoot {
+		moduleFiles = []string{c.relPath(filepath.Join(module.Path, "go.mod")), c.relPath(filepath.Join(module.Path, "go.sum"))}
+	}
+
+	plan := &testCachePlan{keys: make(map[string]string, len(pkgs))}
+	hashOpts := cache.TestHashOptions{Root: c.root, ContentOnly: true}
+	for _, pkg := range pkgs {
+		files := slices.Concat(moduleFiles, c.testInputFiles(pkg, pkgs))
+		key, err := c.manager.GenerateTestHashWithOptions(pkg.ImportPath, files, flags, hashOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", pkg.ImportPath, err)
+		}
+		plan.keys[pkg.ImportPath] = key
+
+		if result, found := c.manager.GetBuildCache().GetTestResult(key); found && result.Success {
+			plan.hits = append(plan.hits, result)
+			continue
+		}
+		plan.misses = append(plan.misses, pkg.ImportPath)
+	}
+	return plan, nil
+}
+
+// testInputFiles returns every file that can influence the tests of pkg: its own sources,
+// tests, embedded files and testdata, plus the sources of module-local packages it or its
+// tests depend on. Paths are relative to the project root.
+func (c *testResultCache) testInputFiles(pkg *goListTestPackage, all map[string]*goListTestPackage) []string {
+	local := map[string]bool{pkg.ImportPath: true}
+	var addDeps func(importPaths []string)
+	addDeps = func(importPaths []string) {
+		for _, importPath := range importPaths {
+			dep, ok := all[importPath]
+			if !ok || local[importPath] {
+				continue
+			}
+			local[importPath] = true
+			addDeps(dep.Deps)
+		}
+	}
+	addDeps(pkg.Deps)
+	addDeps(pkg.TestImports)
+	addDeps(pkg.XTestImports)
+
+	var files []string
+	for _, importPath := range slices.Sorted(maps.Keys(local)) {
+		p := all[importPath]
+		for _, name := range slices.Concat(p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.HFiles, p.SFiles, p.SysoFiles, p.EmbedFiles) {
+			files = append(files, c.relPath(filepath.Join(p.Dir, name)))
+		}
+	}
+	for _, name := range slices.Concat(pkg.TestGoFiles, pkg.XTestGoFiles, pkg.TestEmbedFiles, pkg.XTestEmbedFiles) {
+		files = append(files, c.relPath(filepath.Join(pkg.Dir, name)))
+	}
+
+	testdata := filepath.Join(pkg.Dir, "testdata")
+	walkErr := filepath.WalkDir(testdata, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // a missing or unreadable testdata directory adds no inputs
+		}
+		if !d.IsDir() {
+			files = append(files, c.relPath(path))
+		}
+		return nil
+	})
+	if walkErr != nil {
+		utils.Debug("failed to walk %s: %v", testdata, walkErr)
+	}
+	return files
+}
+
+// relPath makes path relative to the project root so keys are identical across checkouts
+func (c *testResultCache) relPath(path string) string {
+	if rel, err := filepath.Rel(c.root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// report prints the hit and miss counts of this invocation
+func (c *testResultCache) report() {
+	if c == nil {
+		return
+	}
+	stats, err := c.manager.GetStats()
+	if err != nil || stats.Hits+stats.Misses == 0 {
+		return
+	}
+	utils.Info("Test result cache: %d hits, %d misses (%.0f%% hit rate)", stats.Hits, stats.Misses, stats.HitRate*100)
+}
+
+// splitGoTestOutput splits go test output into the output of each package, ending
+// with the package's summary line, keyed by import path. go test prints the output of
+// a package in one piece once it finishes, so packages never interleave.
+func splitGoTestOutput(output string) map[string]string {
+	outputs := make(map[string]string)
+	var block strings.Builder
+	for _, line := range strings.Split(output, "\n") {
+		block.WriteString(line)
+		if match := goTestSummaryRegex.FindStringSubmatch(line); match != nil {
+			outputs[match[1]] = block.String()
+			block.Reset()
+			continue
+		}
+		block.WriteString("\n")
+	}
+	return outputs
+}
+
+// goTestPassed reports whether the output of a package ends with a passing summary line
+func goTestPassed(pkgOutput string) bool {
+	summary := pkgOutput[strings.LastIndex(pkgOutput, "\n")+1:]
+	return !strings.HasPrefix(summary, "FAIL")
+}
+
+// decodeGoListPackages decodes the JSON stream of go list -json, keyed by import path.
+// Lines before the first object (e.g. module download notices) are skipped.
+func decodeGoListPackages(output string) (map[string]*goListTestPackage, error) {
+	if start := strings.Index(output, "{"); start > 0 {
+		output = output[start:]
+	}
+
+	pkgs := make(map[string]*goListTestPackage)
+	decoder := json.NewDecoder(strings.NewReader(output))
+	for {
+		var pkg goListTestPackage
+		err := decoder.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			return pkgs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse go list output: %w", err)
+		}
+		pkgs[pkg.ImportPath] = &pkg
+	}
+}
+
+// testArgValue returns the value of a flag given as "-flag value" or "-flag=value"
+func testArgValue(args []string, flagName string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, flagName+"="); ok {
+			return value
+		}
+		if arg == flagName && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,284 @@
+package mage
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/common/cache"
+	"github.com/mrz1836/mage-x/pkg/mage/testutil"
+)
+
+var errTestCacheRunFailed = errors.New("tests failed")
+
+// fakeGoTestCache answers go list with fixed packages and go test with a summary line per
+// package, failing the packages in failing
+func fakeGoTestCache(packages []goListTestPackage, failing map[string]bool) func(cmd string) (string, error) {
+	return func(cmd string) (string, error) {
+		args := strings.Fields(cmd)
+		if len(args) < 2 || args[0] != "go" {
+			return "", nil
+		}
+		var sb strings.Builder
+		switch args[1] {
+		case "test":
+			// go test output of the listed packages, each ending with its summary line
+			var err error
+			for _, pkg := range args[2:] {
+				switch {
+				case strings.HasPrefix(pkg, "-"):
+					continue
+				case failing[pkg]:
+					sb.WriteString("--- FAIL: TestX (0.00s)\n    a_test.go:12: expected 1, got 2\nFAIL\nFAIL\t" + pkg + "\t0.012s\n")
+					err = errTestCacheRunFailed
+				case pkg == "example.com/app/b":
+					sb.WriteString("?   \t" + pkg + "\t[no test files]\n")
+				default:
+					sb.WriteString("=== RUN   TestX\n--- PASS: TestX (0.00s)\nPASS\nok  \t" + pkg + "\t0.012s\n")
+				}
+			}
+			return sb.String(), err
+		case "list":
+			sb.WriteString("go: downloading example.com/dep v1.0.0\n")
+			for _, pkg := range packages {
+				data, err := json.Marshal(pkg)
+				if err != nil {
+					return "", err
+				}
+				sb.Write(data)
+				sb.WriteString("\n")
+			}
+			return sb.String(), nil
+		}
+		return "", nil
+	}
+}
+
+// goTestRuns returns the arguments of every go test the runner ran
+func goTestRuns(runner *testutil.FakeRunner) [][]string {
+	var runs [][]string
+	for _, cmd := range runner.Commands() {
+		if args := strings.Fields(cmd); len(args) > 1 && args[0] == "go" && args[1] == "test" {
+			runs = append(runs, args[1:])
+		}
+	}
+	return runs
+}
+
+// newTestCacheFixture writes a module with a tested package depending on an untested one.
+// go test fails the packages in failing, which may change between runs.
+func newTestCacheFixture(t *testing.T, failing map[string]bool) (*testResultCache, *testutil.FakeRunner, ModuleInfo) {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.24\n",
+		"a/a.go":          "package a\n",
+		"a/a_test.go":     "package a\n",
+		"a/testdata/in":   "fixture\n",
+		"b/b.go":          "package b\n",
+		"c/c.go":          "package c\n",
+		"c/c_test.go":     "package c\n",
+		"c/testdata/skip": "unrelated\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	cacheConfig := cache.DefaultConfig()
+	cacheConfig.Directory = filepath.Join(root, ".cache")
+	manager := cache.NewManager(cacheConfig)
+	require.NoError(t, manager.Init())
+
+	packages := []goListTestPackage{
+		{
+			ImportPath:  "example.com/app/a",
+			Dir:         filepath.Join(root, "a"),
+			GoFiles:     []string{"a.go"},
+			TestGoFiles: []string{"a_test.go"},
+			Deps:        []string{"example.com/app/b", "fmt"},
+		},
+		{ImportPath: "example.com/app/b", Dir: filepath.Join(root, "b"), GoFiles: []string{"b.go"}},
+		{
+			ImportPath:  "example.com/app/c",
+			Dir:         filepath.Join(root, "c"),
+			GoFiles:     []string{"c.go"},
+			TestGoFiles: []string{"c_test.go"},
+		},
+	}
+	runner := testutil.NewFakeRunner(fakeGoTestCache(packages, failing))
+
+	c := &testResultCache{manager: manager, root: root, keyEnv: []string{"go=go1.24.0"}}
+	module := ModuleInfo{Path: root, Module: "example.com/app", Relative: ".", IsRoot: true, Name: "app"}
+	return c, runner, module
+}
+
+func TestTestResultCache_Cacheable(t *testing.T) {
+	t.Parallel()
+
+	c := &testResultCache{}
+	assert.True(t, c.cacheable([]string{"test", "-race", "-timeout", "10m", "-tags", "integration"}))
+	for _, arg := range []string{"-count=1", "-json", "-cover", "-coverprofile=c.out", "-bench=.", "-fuzz=FuzzX"} {
+		assert.False(t, c.cacheable([]string{"test", arg}), arg)
+	}
+
+	var disabled *testResultCache
+	assert.False(t, disabled.cacheable([]string{"test"}), "a nil cache is never used")
+}
+
+func TestTestCacheEnv(t *testing.T) {
+	t.Parallel()
+
+	env := testCacheEnv([]string{"PATH=/bin", "GOPATH=/home/go", "GOOS=linux", "CGO_ENABLED=0", "GOARCH=amd64", "GOCACHE=/tmp/c"})
+	assert.Equal(t, []string{"CGO_ENABLED=0", "GOARCH=amd64", "GOOS=linux"}, env)
+}
+
+func TestTestArgValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "integration", testArgValue([]string{"test", "-tags", "integration"}, "-tags"))
+	assert.Equal(t, "a,b", testArgValue([]string{"test", "-tags=a,b"}, "-tags"))
+	assert.Empty(t, testArgValue([]string{"test", "-tags"}, "-tags"))
+}
+
+func TestTestResultCacheDir(t *testing.T) {
+	t.Parallel()
+
+	dir, err := testResultCacheDir(&Config{Test: TestConfig{ResultCacheDir: "rel/cache"}})
+	require.NoError(t, err)
+	assert.True(t, filepath.IsAbs(dir))
+	assert.Equal(t, filepath.Join("rel", "cache"), filepath.Join(filepath.Base(filepath.Dir(dir)), filepath.Base(dir)))
+}
+
+func TestTestResultCache_Run(t *testing.T) {
+	c, runner, module := newTestCacheFixture(t, nil)
+	testArgs := []string{"test", "-race"}
+
+	require.NoError(t, c.run(module, runner, testArgs))
+	require.Len(t, goTestRuns(runner), 1)
+	assert.ElementsMatch(t, []string{"example.com/app/a", "example.com/app/b", "example.com/app/c"}, goTestRuns(runner)[0][2:],
+		"every package runs on a cold cache")
+
+	require.NoError(t, c.run(module, runner, testArgs))
+	assert.Len(t, goTestRuns(runner), 1, "a warm cache replays every package")
+
+	// Changing a dependency invalidates its dependents only
+	require.NoError(t, os.WriteFile(filepath.Join(c.root, "b", "b.go"), []byte("package b\n\nconst X = 1\n"), 0o600))
+	require.NoError(t, c.run(module, runner, testArgs))
+	require.Len(t, goTestRuns(runner), 2)
+	assert.ElementsMatch(t, []string{"example.com/app/a", "example.com/app/b"}, goTestRuns(runner)[1][2:])
+
+	// Test data belongs to its package
+	require.NoError(t, os.WriteFile(filepath.Join(c.root, "a", "testdata", "in"), []byte("changed\n"), 0o600))
+	require.NoError(t, c.run(module, runner, testArgs))
+	require.Len(t, goTestRuns(runner), 3)
+	assert.Equal(t, []string{"example.com/app/a"}, goTestRuns(runner)[2][2:])
+
+	// Different flags are a different key
+	require.NoError(t, c.run(module, runner, []string{"test"}))
+	assert.Len(t, goTestRuns(runner), 4)
+
+	stats, err := c.manager.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stats.Hits)
+	assert.Equal(t, int64(9), stats.Misses)
+}
+
+// runTestCacheCapturingOutput runs the module's tests and returns what they printed, so
+// failing go test output does not reach the test binary's own output
+func runTestCacheCapturingOutput(t *testing.T, c *testResultCache, runner *testutil.FakeRunner, module ModuleInfo) (string, error) {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	runErr := c.run(module, runner, []string{"test"})
+	os.Stdout = old
+	require.NoError(t, w.Close())
+	output, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(output), runErr
+}
+
+func TestTestResultCache_RunFailureRecordsPasses(t *testing.T) {
+	failing := map[string]bool{"example.com/app/a": true}
+	c, runner, module := newTestCacheFixture(t, failing)
+
+	_, err := runTestCacheCapturingOutput(t, c, runner, module)
+	require.ErrorIs(t, err, errTestCacheRunFailed)
+
+	delete(failing, "example.com/app/a")
+	require.NoError(t, c.run(module, runner, []string{"test"}))
+	runs := goTestRuns(runner)
+	require.Len(t, runs, 2)
+	assert.Equal(t, []string{"example.com/app/a"}, runs[1][1:], "only the failed package is tested again")
+}
+
+func TestTestResultCache_RunFailurePrintsOutput(t *testing.T) {
+	c, runner, module := newTestCacheFixture(t, map[string]bool{"example.com/app/a": true})
+
+	output, runErr := runTestCacheCapturingOutput(t, c, runner, module)
+	require.ErrorIs(t, runErr, errTestCacheRunFailed)
+	assert.Contains(t, output, "a_test.go:12: expected 1, got 2")
+	assert.Contains(t, output, "FAIL\texample.com/app/a")
+}
+
+func TestTestResultCache_ReplaysRecordedOutput(t *testing.T) {
+	c, runner, module := newTestCacheFixture(t, nil)
+	require.NoError(t, c.run(module, runner, []string{"test", "-v"}))
+
+	plan, err := c.plan(module, runner, []string{"test", "-v"})
+	require.NoError(t, err)
+	outputs := make(map[string]string, len(plan.hits))
+	for _, hit := range plan.hits {
+		outputs[hit.Package] = hit.Output
+	}
+	assert.Equal(t, "=== RUN   TestX\n--- PASS: TestX (0.00s)\nPASS\nok  \texample.com/app/a\t0.012s", outputs["example.com/app/a"])
+	assert.Equal(t, "?   \texample.com/app/b\t[no test files]", outputs["example.com/app/b"])
+}
+
+func TestSplitGoTestOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "?   \texample.com/a\t[no test files]\n--- PASS: TestB (0.00s)\nok  \texample.com/b\t0.1s\tcoverage: 50.0% of statements\n" +
+		"--- FAIL: TestC (0.00s)\nFAIL\texample.com/c\t0.2s\n"
+	assert.Equal(t, map[string]string{
+		"example.com/a": "?   \texample.com/a\t[no test files]",
+		"example.com/b": "--- PASS: TestB (0.00s)\nok  \texample.com/b\t0.1s\tcoverage: 50.0% of statements",
+		"example.com/c": "--- FAIL: TestC (0.00s)\nFAIL\texample.com/c\t0.2s",
+	}, splitGoTestOutput(output))
+}
+
+func TestTestResultCache_IgnoresModTimeAndCheckout(t *testing.T) {
+	c, runner, module := newTestCacheFixture(t, nil)
+	require.NoError(t, c.run(module, runner, []string{"test"}))
+
+	plan, err := c.plan(module, runner, []string{"test"})
+	require.NoError(t, err)
+	assert.Empty(t, plan.misses)
+
+	// A second checkout of the same sources hits the same entries
+	clone, cloneRunner, cloneModule := newTestCacheFixture(t, nil)
+	clone.manager = c.manager
+	plan, err = clone.plan(cloneModule, cloneRunner, []string{"test"})
+	require.NoError(t, err)
+	assert.Empty(t, plan.misses)
+	assert.Len(t, plan.hits, 3)
+}
+
+func TestGoTestPassed(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, goTestPassed("--- PASS: TestB (0.00s)\nok  \texample.com/b\t0.1s"))
+	assert.True(t, goTestPassed("?   \texample.com/a\t[no test files]"))
+	assert.False(t, goTestPassed("--- FAIL: TestC (0.00s)\nFAIL\texample.com/c\t0.2s"))
+}
@@ -0,0 +1,403 @@
+package mage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// errInvalidFlakyMin is returned when test:flaky gets a non-positive min parameter
+var errInvalidFlakyMin = errors.New("min must be a positive integer")
+
+// testNamePattern matches a top-level Go test function name
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var testNamePattern = regexp.MustCompile(`^(Test|Example|Fuzz)\w*$`)
+
+// flakyRerunValueFlags are go test flags whose value is a separate argument
+//
+//nolint:gochecknoglobals // read-only lookup table
+var flakyRerunValueFlags = map[string]bool{
+	"-p": true, "-timeout": true, "-tags": true, "-run": true, "-skip": true, "-count": true,
+	"-cpu": true, "-parallel": true, "-bench": true, "-benchtime": true, "-covermode": true,
+	"-coverpkg": true, "-coverprofile": true, "-vet": true, "-shuffle": true, "-fuzz": true,
+	"-fuzztime": true, "-outputdir": true,
+}
+
+// flakyRerunDroppedFlags are go test flags replaced or made meaningless by a rerun
+//
+//nolint:gochecknoglobals // read-only lookup table
+var flakyRerunDroppedFlags = map[string]bool{
+	"-run": true, "-count": true, "-json": true, "-cover": true, "-covermode": true,
+	"-coverpkg": true, "-coverprofile": true, "-bench": true, "-benchtime": true,
+	"-benchmem": true, "-fuzz": true, "-fuzztime": true, "-failfast": true,
+}
+
+// flakyHistory records how often each test failed, and whether reruns passed
+type flakyHistory struct {
+	Updated time.Time               `json:"updated"`
+	Tests   map[string]*flakyRecord `json:"tests"`
+}
+
+// flakyRecord is the failure history of a single test
+type flakyRecord struct {
+	Package    string    `json:"package"`
+	Test       string    `json:"test"`
+	Flaky      int       `json:"flaky"`      // Failures that passed on a rerun
+	Consistent int       `json:"consistent"` // Failures that failed every rerun
+	LastSeen   time.Time `json:"last_seen"`
+	LastCommit string    `json:"last_commit,omitempty"`
+}
+
+// flakyHistoryPath returns the history file kept next to the CI output file
+func flakyHistoryPath(mode CIMode) string {
+	outputPath := mode.OutputPath
+	if outputPath == "" {
+		outputPath = DefaultCIMode().OutputPath
+	}
+	return filepath.Join(filepath.Dir(outputPath), DefaultFlakyHistoryFile)
+}
+
+// loadFlakyHistory reads the history file; a missing file is an empty history
+func loadFlakyHistory(path string) (*flakyHistory, error) {
+	history := &flakyHistory{Tests: make(map[string]*flakyRecord)}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is derived from the CI output configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flaky test history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse flaky test history %s: %w", path, err)
+	}
+	if history.Tests == nil {
+		history.Tests = make(map[string]*flakyRecord)
+	}
+	return history, nil
+}
+
+// save writes the history file, creating its directory when needed
+func (h *flakyHistory) save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode flaky test history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), fileops.PermDirSensitive); err != nil {
+		return fmt.Errorf("failed to create flaky test history directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to write flaky test history: %w", err)
+	}
+	return nil
+}
+
+// record adds classified failures to the history
+func (h *flakyHistory) record(failures []CITestFailure, now time.Time, commit string) {
+	for _, f := range failures {
+		if f.Reruns == 0 {
+			continue
+		}
+		key := f.Package + " " + f.Test
+		rec, ok := h.Tests[key]
+		if !ok {
+			rec = &flakyRecord{Package: f.Package, Test: f.Test}
+			h.Tests[key] = rec
+		}
+		if f.Flaky {
+			rec.Flaky++
+		} else {
+			rec.Consistent++
+		}
+		rec.LastSeen = now
+		rec.LastCommit = commit
+	}
+	h.Updated = now
+}
+
+// ranked returns tests that were flaky at least minFlaky times, most flaky first
+func (h *flakyHistory) ranked(minFlaky int) []*flakyRecord {
+	var records []*flakyRecord
+	for _, rec := range h.Tests {
+		if rec.Flaky >= minFlaky {
+			records = append(records, rec)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Flaky != records[j].Flaky {
+			return records[i].Flaky > records[j].Flaky
+		}
+		if records[i].Package != records[j].Package {
+			return records[i].Package < records[j].Package
+		}
+		return records[i].Test < records[j].Test
+	})
+	return records
+}
+
+// rerunnable reports whether a failure belongs to a single test that can be rerun on its own
+func rerunnable(f CITestFailure) bool {
+	return f.Test != "" && f.Package != "" && (f.Type == FailureTypeTest || f.Type == FailureTypeRace)
+}
+
+// retryFailures reruns each failed test up to FlakyRetries times. Failures that pass on
+// a rerun are flaky: they move from Failures to Flaky, so reporters show them as passed
+// on a retry rather than as failures. It returns true when every failure of the run was
+// flaky, in which case the run counts as passed.
+func (r *ciRunner) retryFailures(ctx context.Context, args []string) bool {
+	r.mu.Lock()
+	failures := make([]CITestFailure, len(r.results.Failures))
+	copy(failures, r.results.Failures)
+	r.mu.Unlock()
+
+	baseArgs := flakyRerunArgs(args)
+	rerun := 0
+	for i := range failures {
+		if !rerunnable(failures[i]) || rerun >= MaxFlakyRerunFailures {
+			continue
+		}
+		rerun++
+
+		pattern := testRunPattern(failures[i].Test)
+		for attempt := 1; attempt <= r.mode.FlakyRetries; attempt++ {
+			if ctx.Err() != nil {
+				break
+			}
+			failures[i].Reruns = attempt
+			rerunArgs := append(append([]string{}, baseArgs...), "-run", pattern, failures[i].Package)
+			output, _ := r.base.RunCmdOutput("go", rerunArgs...) //nolint:errcheck // the outcome is read from the JSON events
+			if testPassedInOutput(output, failures[i].Package, failures[i].Test) {
+				failures[i].Flaky = true
+				break
+			}
+		}
+	}
+
+	var remaining, flaky []CITestFailure
+	for _, f := range failures {
+		if f.Flaky {
+			flaky = append(flaky, f)
+			utils.Warn("Flaky test: %s (%s) passed on rerun %d/%d", f.Test, f.Package, f.Reruns, r.mode.FlakyRetries)
+		} else {
+			remaining = append(remaining, f)
+		}
+	}
+	allFlaky := len(flaky) > 0 && len(remaining) == 0
+
+	r.mu.Lock()
+	r.flaky = append(r.flaky, flaky...)
+	r.results.Failures = remaining
+	r.results.Flaky = append([]CITestFailure(nil), r.flaky...)
+	r.results.Summary.Flaky = len(r.flaky)
+	r.results.Summary.Failed = len(remaining)
+	if allFlaky {
+		r.results.Summary.Status = TestStatusPassed
+		r.results.Summary.ExitCode = 0
+	}
+	commit := r.results.Metadata.Commit
+	r.mu.Unlock()
+
+	r.recordFlakyHistory(failures, commit)
+	return allFlaky
+}
+
+// withoutFlaky drops failures of tests already found flaky, which the parser reports
+// again when the runner is reused for another go test run (e.g. per build tag).
+// Caller MUST hold r.mu.
+func (r *ciRunner) withoutFlaky(failures []CITestFailure) []CITestFailure {
+	if len(r.flaky) == 0 {
+		return failures
+	}
+	known := make(map[string]bool, len(r.flaky))
+	for _, f := range r.flaky {
+		known[f.Package+":"+f.Test] = true
+	}
+	remaining := failures[:0:0]
+	for _, f := range failures {
+		if !known[f.Package+":"+f.Test] {
+			remaining = append(remaining, f)
+		}
+	}
+	return remaining
+}
+
+// recordFlakyHistory adds classified failures to the history file
+func (r *ciRunner) recordFlakyHistory(failures []CITestFailure, commit string) {
+	path := flakyHistoryPath(r.mode)
+	history, err := loadFlakyHistory(path)
+	if err != nil {
+		utils.Warn("Failed to load flaky test history: %v", err)
+		return
+	}
+	history.record(failures, time.Now(), commit)
+	if err := history.save(path); err != nil {
+		utils.Warn("%v", err)
+	}
+}
+
+// flakyRerunArgs derives the arguments for rerunning single tests from the original
+// go test arguments: package patterns and flags that select, repeat or measure tests
+// are dropped, and JSON output with a single, uncached run is forced.
+func flakyRerunArgs(args []string) []string {
+	rerun := []string{"test", "-json", "-count=1"}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue // package pattern
+		}
+		name, _, hasValue := strings.Cut(arg, "=")
+		takesNext := !hasValue && flakyRerunValueFlags[name] && i+1 < len(args)
+		if flakyRerunDroppedFlags[name] {
+			if takesNext {
+				i++
+			}
+			continue
+		}
+		rerun = append(rerun, arg)
+		if takesNext {
+			i++
+			rerun = append(rerun, args[i])
+		}
+	}
+	return rerun
+}
+
+// testRunPattern builds a -run pattern matching exactly one test or subtest
+func testRunPattern(test string) string {
+	parts := strings.Split(test, "/")
+	for i, part := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(part) + "$"
+	}
+	return strings.Join(parts, "/")
+}
+
+// testPassedInOutput reports whether go test -json output contains a pass event for the test
+func testPassedInOutput(output, pkg, test string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var event TestEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		if event.Package == pkg && event.Test == test && event.Action == "pass" {
+			return true
+		}
+	}
+	return false
+}
+
+// appendQuarantineSkip adds a -skip flag for quarantined tests. Entries that are not
+// top-level test names are ignored with a warning, since -skip applies per name level.
+// A -skip already in args is merged into the same flag: go test treats top-level "|"
+// in -skip as alternatives, each split into levels on "/", so both keep their meaning.
+func appendQuarantineSkip(args, quarantine []string) []string {
+	var names []string
+	for _, name := range quarantine {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !testNamePattern.MatchString(name) {
+			utils.Warn("Ignoring quarantine entry %q: expected a top-level test name such as TestFoo", name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return args
+	}
+	sort.Strings(names)
+
+	var patterns []string
+	merged := make([]string, 0, len(args)+2)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-skip" && i+1 < len(args):
+			i++
+			patterns = append(patterns, args[i])
+		case strings.HasPrefix(args[i], "-skip="):
+			patterns = append(patterns, strings.TrimPrefix(args[i], "-skip="))
+		default:
+			merged = append(merged, args[i])
+		}
+	}
+	patterns = append(patterns, "^("+strings.Join(names, "|")+")$")
+	return append(merged, "-skip", strings.Join(patterns, "|"))
+}
+
+// Flaky lists tests that failed and then passed on a rerun in CI mode, most flaky first.
+// Parameters: min=<n> hides tests flaky fewer than n times (default 1).
+func (Test) Flaky(args ...string) error {
+	params := utils.ParseParams(args)
+	minFlaky := 1
+	if v := utils.GetParam(params, "min", ""); v != "" {
+		if _, err := fmt.Sscanf(v, "%d", &minFlaky); err != nil || minFlaky < 1 {
+			return fmt.Errorf("%w: min=%s", errInvalidFlakyMin, v)
+		}
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	mode := NewCIDetector().GetConfig(nil, config)
+	path := flakyHistoryPath(mode)
+	history, err := loadFlakyHistory(path)
+	if err != nil {
+		return err
+	}
+
+	records := history.ranked(minFlaky)
+	if len(records) == 0 {
+		utils.Info("No flaky tests recorded in %s", path)
+		utils.Info("Enable detection with ci_mode.flaky_retries in .mage.yaml or flaky_retries=<n>")
+		return nil
+	}
+
+	quarantined := make(map[string]bool, len(config.Test.Quarantine))
+	for _, name := range config.Test.Quarantine {
+		quarantined[strings.TrimSpace(name)] = true
+	}
+
+	utils.Header("Flaky Tests")
+	fmt.Printf("%-6s %-10s %-12s %-40s %s\n", "FLAKY", "CONSISTENT", "LAST SEEN", "TEST", "PACKAGE")
+	var suggestions []string
+	for _, rec := range records {
+		name := rec.Test
+		top, _, _ := strings.Cut(rec.Test, "/")
+		if quarantined[top] {
+			name += " (quarantined)"
+		} else if !contains(suggestions, top) {
+			suggestions = append(suggestions, top)
+		}
+		fmt.Printf("%-6d %-10d %-12s %-40s %s\n", rec.Flaky, rec.Consistent, rec.LastSeen.Format(time.DateOnly), name, rec.Package)
+	}
+
+	if len(suggestions) > 0 {
+		fmt.Println()
+		utils.Info("To quarantine these tests, add them to .mage.yaml:")
+		fmt.Println("test:\n  quarantine:")
+		for _, name := range suggestions {
+			fmt.Printf("    - %s\n", name)
+		}
+	}
+	return nil
+}
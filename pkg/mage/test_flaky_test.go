@@ -0,0 +1,225 @@
+package mage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyRerunRunner answers test reruns from a script of per-test outcomes
+type flakyRerunRunner struct {
+	outcomes map[string][]string // -run pattern -> action per attempt
+	calls    [][]string
+}
+
+func (m *flakyRerunRunner) RunCmd(_ string, _ ...string) error {
+	return nil
+}
+
+func (m *flakyRerunRunner) RunCmdOutput(_ string, args ...string) (string, error) {
+	m.calls = append(m.calls, args)
+	pattern := testArgValue(args, "-run")
+	pkg := args[len(args)-1]
+
+	attempt := 0
+	for _, call := range m.calls {
+		if testArgValue(call, "-run") == pattern {
+			attempt++
+		}
+	}
+	actions := m.outcomes[pattern]
+	action := "fail"
+	if attempt <= len(actions) {
+		action = actions[attempt-1]
+	}
+
+	test := strings.NewReplacer("^", "", "$", "", `\`, "").Replace(pattern)
+	return "go: downloading example.com/x v1.0.0\n" +
+		`{"Action":"run","Package":"` + pkg + `","Test":"` + test + `"}` + "\n" +
+		`{"Action":"` + action + `","Package":"` + pkg + `","Test":"` + test + `"}`, nil
+}
+
+func TestFlakyRerunArgs(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"test", "-json", "-p", "4", "-timeout", "10m", "-tags", "integration", "-race",
+		"-run", "TestOld", "-count=3", "-coverprofile", "c.out", "-skip", "^(TestQ)$", "./...", "./cmd"}
+	assert.Equal(t,
+		[]string{"test", "-json", "-count=1", "-p", "4", "-timeout", "10m", "-tags", "integration", "-race", "-skip", "^(TestQ)$"},
+		flakyRerunArgs(args))
+}
+
+func TestTestRunPattern(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "^TestFoo$", testRunPattern("TestFoo"))
+	assert.Equal(t, `^TestFoo$/^case_1\.5$`, testRunPattern("TestFoo/case_1.5"))
+}
+
+func TestTestPassedInOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "noise\n" +
+		`{"Action":"pass","Package":"ex/a","Test":"TestA/sub"}` + "\n" +
+		`{"Action":"fail","Package":"ex/a","Test":"TestA"}` + "\n" +
+		`{"Action":"pass","Package":"ex/b","Test":"TestB"}`
+	assert.True(t, testPassedInOutput(output, "ex/a", "TestA/sub"))
+	assert.False(t, testPassedInOutput(output, "ex/a", "TestA"))
+	assert.False(t, testPassedInOutput(output, "ex/a", "TestB"), "a pass in another package does not count")
+}
+
+func TestAppendQuarantineSkip(t *testing.T) {
+	t.Parallel()
+
+	args := appendQuarantineSkip([]string{"test"}, []string{"TestZeta", " TestAlpha ", "", "TestA/sub", "not-a-test"})
+	assert.Equal(t, []string{"test", "-skip", "^(TestAlpha|TestZeta)$"}, args)
+	assert.Equal(t, []string{"test"}, appendQuarantineSkip([]string{"test"}, nil))
+
+	cfg := &Config{Test: TestConfig{Quarantine: []string{"TestFlaky"}}}
+	assert.Equal(t, []string{"test", "-skip", "^(TestFlaky)$"}, buildTestArgs(cfg, false, false))
+
+	// A user -skip is merged into the quarantine pattern instead of overriding it
+	assert.Equal(t, []string{"test", "-v", "-skip", "TestSlow/large|^(TestFlaky)$"},
+		buildTestArgs(cfg, false, false, "-skip", "TestSlow/large", "-v"))
+	assert.Equal(t, []string{"test", "-skip", "TestA|TestB|^(TestFlaky)$"},
+		appendQuarantineSkip([]string{"test", "-skip=TestA", "-skip", "TestB"}, cfg.Test.Quarantine))
+}
+
+func TestFlakyHistory(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".mage-x", DefaultFlakyHistoryFile)
+	history, err := loadFlakyHistory(path)
+	require.NoError(t, err)
+	assert.Empty(t, history.Tests)
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	failures := []CITestFailure{
+		{Package: "ex/a", Test: "TestA", Flaky: true, Reruns: 1},
+		{Package: "ex/a", Test: "TestB", Reruns: 3},
+		{Package: "ex/a", Test: "TestBuild"}, // never rerun
+	}
+	history.record(failures, now, "abc123")
+	history.record(failures[:1], now, "def456")
+	require.NoError(t, history.save(path))
+
+	loaded, err := loadFlakyHistory(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Tests, 2)
+	a := loaded.Tests["ex/a TestA"]
+	require.NotNil(t, a)
+	assert.Equal(t, 2, a.Flaky)
+	assert.Equal(t, "def456", a.LastCommit)
+	assert.Equal(t, 1, loaded.Tests["ex/a TestB"].Consistent)
+
+	ranked := loaded.ranked(1)
+	require.Len(t, ranked, 1)
+	assert.Equal(t, "TestA", ranked[0].Test)
+	assert.Empty(t, loaded.ranked(3))
+}
+
+func TestCIRunner_RetryFailures(t *testing.T) {
+	t.Parallel()
+
+	base := &flakyRerunRunner{outcomes: map[string][]string{
+		"^TestFlaky$":           {"fail", "pass"},
+		"^TestBroken$":          {"fail", "fail"},
+		`^TestSub$/^case_1\.5$`: {"pass"},
+	}}
+	outputPath := filepath.Join(t.TempDir(), "ci-results.jsonl")
+	r := &ciRunner{
+		base: base,
+		mode: CIMode{FlakyRetries: 2, OutputPath: outputPath},
+		results: &CIResult{
+			Summary: CISummary{Status: TestStatusFailed, Failed: 4},
+			Failures: []CITestFailure{
+				{Package: "ex/a", Test: "TestFlaky", Type: FailureTypeTest},
+				{Package: "ex/a", Test: "TestBroken", Type: FailureTypeTest},
+				{Package: "ex/b", Test: "TestSub/case_1.5", Type: FailureTypeRace},
+				{Package: "ex/c", Type: FailureTypeBuild},
+			},
+		},
+	}
+
+	allFlaky := r.retryFailures(context.Background(), []string{"test", "-json", "-race", "./..."})
+	assert.False(t, allFlaky, "consistent and build failures keep the run failed")
+
+	results := r.GetResults()
+	require.Len(t, results.Flaky, 2, "flaky failures move out of Failures")
+	assert.Equal(t, "TestFlaky", results.Flaky[0].Test)
+	assert.Equal(t, 2, results.Flaky[0].Reruns)
+	assert.Equal(t, "TestSub/case_1.5", results.Flaky[1].Test)
+	assert.Equal(t, 1, results.Flaky[1].Reruns)
+	require.Len(t, results.Failures, 2)
+	assert.Equal(t, "TestBroken", results.Failures[0].Test)
+	assert.Equal(t, 2, results.Failures[0].Reruns)
+	assert.Zero(t, results.Failures[1].Reruns, "build failures are not rerun")
+	assert.Equal(t, 2, results.Summary.Flaky)
+	assert.Equal(t, 2, results.Summary.Failed)
+	assert.Equal(t, TestStatusFailed, results.Summary.Status)
+
+	require.Len(t, base.calls, 5)
+	assert.Equal(t, []string{"test", "-json", "-count=1", "-race", "-run", "^TestFlaky$", "ex/a"}, base.calls[0])
+
+	history, err := loadFlakyHistory(flakyHistoryPath(r.mode))
+	require.NoError(t, err)
+	assert.Len(t, history.Tests, 3)
+	assert.Equal(t, 1, history.Tests["ex/b TestSub/case_1.5"].Flaky)
+}
+
+func TestCIRunner_RetryFailuresAllFlaky(t *testing.T) {
+	t.Parallel()
+
+	r := &ciRunner{
+		base: &flakyRerunRunner{outcomes: map[string][]string{"^TestFlaky$": {"pass"}}},
+		mode: CIMode{FlakyRetries: 3, OutputPath: filepath.Join(t.TempDir(), "ci-results.jsonl")},
+		results: &CIResult{
+			Summary:  CISummary{Status: TestStatusFailed, Failed: 1, ExitCode: 1},
+			Failures: []CITestFailure{{Package: "ex/a", Test: "TestFlaky", Type: FailureTypeTest}},
+		},
+	}
+
+	assert.True(t, r.retryFailures(context.Background(), []string{"test", "./..."}))
+	results := r.GetResults()
+	assert.Equal(t, TestStatusPassed, results.Summary.Status)
+	assert.Zero(t, results.Summary.Failed)
+	assert.Zero(t, results.Summary.ExitCode, "a run passing on reruns exits 0")
+	assert.Empty(t, results.Failures, "flaky failures are not reported as failures")
+	assert.Len(t, results.Flaky, 1)
+}
+
+func TestJUnitReporter_FlakyTestPasses(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	reporter := NewJUnitReporter(path)
+	observer, ok := reporter.(TestEventObserver)
+	require.True(t, ok)
+	observer.ObserveEvent(TestEvent{Action: "run", Package: "ex/a", Test: "TestFlaky"})
+	observer.ObserveEvent(TestEvent{Action: "fail", Package: "ex/a", Test: "TestFlaky"})
+	flaky := CITestFailure{Package: "ex/a", Test: "TestFlaky", Flaky: true, Reruns: 1}
+	require.NoError(t, reporter.WriteSummary(&CIResult{Flaky: []CITestFailure{flaky}}))
+	require.NoError(t, reporter.Close())
+
+	data, err := os.ReadFile(path) //nolint:gosec // test-controlled path
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `failures="0"`)
+	assert.Contains(t, string(data), "Flaky: failed, then passed on rerun 1")
+	assert.NotContains(t, string(data), "<failure")
+}
+
+func TestCIMode_ValidateFlakyRetries(t *testing.T) {
+	t.Parallel()
+
+	mode := DefaultCIMode()
+	mode.FlakyRetries = MaxFlakyRetries
+	require.NoError(t, mode.Validate())
+	mode.FlakyRetries = MaxFlakyRetries + 1
+	require.ErrorIs(t, mode.Validate(), ErrCIFlakyRetriesOutOfRange)
+}
@@ -30,6 +30,16 @@
 //	// Verify expectations
 //	runner.Verify(t)
 //
+// # Fake Runner
+//
+// Record commands and answer them from canned outputs without expectations:
+//
+//	runner := testutil.NewFakeRunner(nil)
+//	runner.Outputs["git status --porcelain"] = ""
+//	runner.Errors["go vet ./..."] = errVet
+//
+//	// Run code under test, then inspect runner.Commands()
+//
 // # Command Matcher
 //
 // Match and verify command invocations:
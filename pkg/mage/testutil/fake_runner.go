@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"strings"
+	"sync"
+)
+
+// FakeRunner is a lightweight command runner that records every command and
+// answers from canned outputs, for tests that do not need mock expectations.
+// Commands are keyed by their full command line, e.g. "git status --porcelain".
+type FakeRunner struct {
+	// Outputs holds the RunCmdOutput result per command
+	Outputs map[string]string
+
+	// Errors holds the error returned by RunCmd and RunCmdOutput per command
+	Errors map[string]error
+
+	// Output answers RunCmdOutput instead of Outputs and Errors when set
+	Output func(cmd string) (string, error)
+
+	mu       sync.Mutex
+	commands []string
+}
+
+// NewFakeRunner creates a fake runner that answers RunCmdOutput through output,
+// or with empty output when output is nil
+func NewFakeRunner(output func(cmd string) (string, error)) *FakeRunner {
+	return &FakeRunner{
+		Outputs: make(map[string]string),
+		Errors:  make(map[string]error),
+		Output:  output,
+	}
+}
+
+// RunCmd records the command and returns its configured error
+func (r *FakeRunner) RunCmd(name string, args ...string) error {
+	cmd := r.record(name, args)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Errors[cmd]
+}
+
+// RunCmdOutput records the command and returns its configured output
+func (r *FakeRunner) RunCmdOutput(name string, args ...string) (string, error) {
+	cmd := r.record(name, args)
+	if r.Output != nil {
+		return r.Output(cmd)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Outputs[cmd], r.Errors[cmd]
+}
+
+// Commands returns the commands run so far, in order
+func (r *FakeRunner) Commands() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.commands...)
+}
+
+// Reset forgets the recorded commands
+func (r *FakeRunner) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = nil
+}
+
+// record appends a command line to the history and returns it
+func (r *FakeRunner) record(name string, args []string) string {
+	cmd := strings.TrimSpace(name + " " + strings.Join(args, " "))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, cmd)
+	return cmd
+}
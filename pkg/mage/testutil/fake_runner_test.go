@@ -0,0 +1,38 @@
+package testutil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/mage/testutil"
+)
+
+var errFakeRunnerTest = errors.New("fake failure")
+
+func TestFakeRunner(t *testing.T) {
+	runner := testutil.NewFakeRunner(nil)
+	runner.Outputs["git status --porcelain"] = " M a.go\n"
+	runner.Errors["go vet ./..."] = errFakeRunnerTest
+
+	output, err := runner.RunCmdOutput("git", "status", "--porcelain")
+	require.NoError(t, err)
+	assert.Equal(t, " M a.go\n", output)
+	require.ErrorIs(t, runner.RunCmd("go", "vet", "./..."), errFakeRunnerTest)
+	require.NoError(t, runner.RunCmd("gofmt"))
+	assert.Equal(t, []string{"git status --porcelain", "go vet ./...", "gofmt"}, runner.Commands())
+
+	runner.Reset()
+	assert.Empty(t, runner.Commands())
+}
+
+func TestFakeRunner_OutputCallback(t *testing.T) {
+	runner := testutil.NewFakeRunner(func(cmd string) (string, error) {
+		return "ran " + cmd, nil
+	})
+	output, err := runner.RunCmdOutput("swag", "--version")
+	require.NoError(t, err)
+	assert.Equal(t, "ran swag --version", output)
+}
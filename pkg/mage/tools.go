@@ -36,7 +36,8 @@ type ToolDefinition struct {
 	Check   string // Command to check if installed
 }
 
-// Install installs all required development tools
+// Install installs all required development tools. When the project has a
+// .mage-tools.lock, the locked versions are installed into the tools directory instead.
 func (Tools) Install() error {
 	utils.Header("Installing Development Tools")
 
@@ -45,6 +46,18 @@ func (Tools) Install() error {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
+	lock, err := readToolsLock(ToolsLockFile)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		binDir, binErr := toolsBinDir(config)
+		if binErr != nil {
+			return binErr
+		}
+		return installLockedTools(lock, binDir)
+	}
+
 	tools := getRequiredTools(config)
 
 	for _, tool := range tools {
@@ -143,7 +156,8 @@ func (Tools) Update() error {
 	return nil
 }
 
-// Verify checks that all required tools are installed
+// Verify checks that all required tools are installed. When the project has a
+// .mage-tools.lock, every tool binary must match its locked version and checksum.
 func (Tools) Verify() error {
 	utils.Header("Verifying Development Tools")
 
@@ -152,6 +166,18 @@ func (Tools) Verify() error {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
+	lock, err := readToolsLock(ToolsLockFile)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		binDir, binErr := toolsBinDir(config)
+		if binErr != nil {
+			return binErr
+		}
+		return verifyLockedTools(lock, binDir)
+	}
+
 	tools := getRequiredTools(config)
 	allGood := true
 
@@ -0,0 +1,467 @@
+package mage
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/mage-x/pkg/common/fileops"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// Static errors for the tool lockfile
+var (
+	errToolsLockMismatch    = errors.New("installed tools do not match " + ToolsLockFile + ". Run 'magex tools:install' to reinstall them")
+	errToolChecksumMismatch = errors.New("tool binary checksum does not match the lock")
+	errToolVersionMismatch  = errors.New("tool binary version does not match the lock")
+	errToolsEnvRunner       = errors.New("installing into the tools directory needs a runner that supports environment variables")
+)
+
+const (
+	// golangciLintPackage installs golangci-lint v2 when it is locked
+	golangciLintPackage = "github.com/golangci/golangci-lint/v2/cmd/golangci-lint"
+
+	// golangciLintV1Package installs golangci-lint when a v1 version is configured
+	golangciLintV1Package = "github.com/golangci/golangci-lint/cmd/golangci-lint"
+)
+
+// majorVersionElement matches a /vN path element, which go install skips when naming binaries
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var majorVersionElement = regexp.MustCompile(`^v[0-9]+$`)
+
+// goReleaseVersion matches a released Go toolchain name such as go1.25.3 or go1.26rc1
+//
+//nolint:gochecknoglobals // compiled once, read-only
+var goReleaseVersion = regexp.MustCompile(`^go1\.[0-9]+(\.[0-9]+|rc[0-9]+)?$`)
+
+// ToolsLock is the content of .mage-tools.lock: the exact build of every tool, so
+// all developers and CI runners use identical binaries
+type ToolsLock struct {
+	Tools []LockedTool `json:"tools"`
+}
+
+// LockedTool records one tool as installed by "magex tools:lock"
+type LockedTool struct {
+	Name      string            `json:"name"`      // Binary name
+	Package   string            `json:"package"`   // Package passed to go install
+	Module    string            `json:"module"`    // Module the package belongs to
+	Version   string            `json:"version"`   // Resolved module version
+	GoVersion string            `json:"go"`        // Toolchain the checksums were built with
+	Checksums map[string]string `json:"checksums"` // SHA-256 of the binary by GOOS/GOARCH
+}
+
+// toolBinary describes an installed tool binary
+type toolBinary struct {
+	Module    string
+	Version   string
+	GoVersion string
+	Checksum  string
+}
+
+// Lock installs every configured tool into the project tools directory (.mage/bin)
+// and records its module, resolved version and binary checksum in .mage-tools.lock.
+// Checksums recorded for other platforms are kept while a tool's version is unchanged.
+func (Tools) Lock() error {
+	utils.Header("Locking Development Tools")
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	binDir, err := toolsBinDir(config)
+	if err != nil {
+		return err
+	}
+	previous, err := readToolsLock(ToolsLockFile)
+	if err != nil {
+		return err
+	}
+
+	lock := &ToolsLock{}
+	for _, tool := range getRequiredTools(config) {
+		pkg := lockPackage(tool)
+		if pkg == "" {
+			continue
+		}
+		version := tool.Version
+		if version == "" || version == VersionLatest {
+			if resolved := getToolVersionFromEnv(tool.Name); resolved != "" {
+				version = resolved
+			} else {
+				version = VersionLatest
+			}
+		}
+
+		utils.Info("Installing %s@%s into %s...", pkg, version, binDir)
+		if err := goInstallTool(binDir, pkg, version, ""); err != nil {
+			return fmt.Errorf("failed to install %s: %w", tool.Name, err)
+		}
+		name := toolBinaryName(pkg)
+		binary, err := inspectToolBinary(toolBinaryPath(binDir, name))
+		if err != nil {
+			return err
+		}
+
+		locked := LockedTool{
+			Name:      name,
+			Package:   pkg,
+			Module:    binary.Module,
+			Version:   binary.Version,
+			GoVersion: binary.GoVersion,
+			Checksums: map[string]string{},
+		}
+		if old := previous.find(name); old != nil && old.Version == locked.Version && old.GoVersion == locked.GoVersion {
+			for platform, checksum := range old.Checksums {
+				locked.Checksums[platform] = checksum
+			}
+		}
+		locked.Checksums[toolPlatform()] = binary.Checksum
+		lock.Tools = append(lock.Tools, locked)
+		utils.Success("%s %s", name, locked.Version)
+	}
+
+	if err := writeToolsLock(ToolsLockFile, lock); err != nil {
+		return err
+	}
+	utils.Success("Locked %d tool(s) in %s", len(lock.Tools), ToolsLockFile)
+	return nil
+}
+
+// installLockedTools installs every tool in the lock into binDir, skipping binaries
+// that already match. A checksum missing for this platform is added to the lock.
+func installLockedTools(lock *ToolsLock, binDir string) error {
+	recorded := 0
+	for i := range lock.Tools {
+		added, err := installLockedTool(&lock.Tools[i], binDir)
+		if err != nil {
+			return err
+		}
+		if added {
+			recorded++
+		}
+	}
+
+	if recorded > 0 {
+		if err := writeToolsLock(ToolsLockFile, lock); err != nil {
+			return err
+		}
+		utils.Info("Recorded %s checksums for %d tool(s); commit %s", toolPlatform(), recorded, ToolsLockFile)
+	}
+	utils.Success("All locked tools installed in %s", binDir)
+	return nil
+}
+
+// installLockedTool installs one locked tool into binDir unless its binary already
+// matches, and reports whether a checksum for this platform was added to the tool
+func installLockedTool(tool *LockedTool, binDir string) (bool, error) {
+	binaryPath := toolBinaryPath(binDir, tool.Name)
+	if binary, err := inspectToolBinary(binaryPath); err == nil && tool.matches(binary) == nil {
+		utils.Info("%s %s is already installed", tool.Name, tool.Version)
+		return false, nil
+	}
+
+	utils.Info("Installing %s@%s into %s...", tool.Package, tool.Version, binDir)
+	if err := goInstallTool(binDir, tool.Package, tool.Version, tool.GoVersion); err != nil {
+		return false, fmt.Errorf("failed to install %s: %w", tool.Name, err)
+	}
+	binary, err := inspectToolBinary(binaryPath)
+	if err != nil {
+		return false, err
+	}
+	if err := tool.matches(binary); err != nil {
+		return false, fmt.Errorf("%s: %w", tool.Name, err)
+	}
+	utils.Success("%s %s installed", tool.Name, tool.Version)
+
+	platform := toolPlatform()
+	if _, ok := tool.Checksums[platform]; ok {
+		return false, nil
+	}
+	if tool.Checksums == nil {
+		tool.Checksums = map[string]string{}
+	}
+	tool.Checksums[platform] = binary.Checksum
+	return true, nil
+}
+
+// installProjectTool installs a tool that a magex task runs into the project tools
+// directory, never the global GOBIN, and puts that directory first on PATH. A tool
+// in .mage-tools.lock is installed at its locked version and must match its
+// checksum; any other tool is installed as pkg@version unless current reports that
+// the binary already on PATH will do.
+func installProjectTool(config *Config, name, pkg, version string, current func() bool) error {
+	binDir, err := toolsBinDir(config)
+	if err != nil {
+		return err
+	}
+	lock, err := readToolsLock(ToolsLockFile)
+	if err != nil {
+		return err
+	}
+
+	tool := lock.find(name)
+	switch {
+	case tool != nil:
+		recorded, installErr := installLockedTool(tool, binDir)
+		if installErr != nil {
+			return installErr
+		}
+		if recorded {
+			if err := writeToolsLock(ToolsLockFile, lock); err != nil {
+				return err
+			}
+			utils.Info("Recorded the %s checksum of %s; commit %s", toolPlatform(), name, ToolsLockFile)
+		}
+	case current != nil && current():
+		return nil
+	default:
+		utils.Info("Installing %s@%s into %s...", pkg, version, binDir)
+		if err := goInstallTool(binDir, pkg, version, ""); err != nil {
+			return fmt.Errorf("failed to install %s: %w", name, err)
+		}
+	}
+	return prependPath(binDir)
+}
+
+// verifyLockedTools reports every locked tool whose binary is missing or differs from the lock
+func verifyLockedTools(lock *ToolsLock, binDir string) error {
+	allGood := true
+	for i := range lock.Tools {
+		tool := &lock.Tools[i]
+		binary, err := inspectToolBinary(toolBinaryPath(binDir, tool.Name))
+		if err != nil {
+			utils.Error("%s: not installed in %s", tool.Name, binDir)
+			allGood = false
+			continue
+		}
+		if err := tool.matches(binary); err != nil {
+			utils.Error("%s: %v", tool.Name, err)
+			allGood = false
+			continue
+		}
+		if _, ok := tool.Checksums[toolPlatform()]; !ok {
+			utils.Warn("%s: %s (no checksum recorded for %s)", tool.Name, tool.Version, toolPlatform())
+			continue
+		}
+		utils.Success("%s: %s", tool.Name, tool.Version)
+	}
+
+	if !allGood {
+		return errToolsLockMismatch
+	}
+	utils.Success("All tools match %s", ToolsLockFile)
+	return nil
+}
+
+// matches checks an installed binary against the lock: same module version and,
+// when one is recorded for this platform, the same checksum
+func (t *LockedTool) matches(binary *toolBinary) error {
+	if binary.Version != t.Version {
+		return fmt.Errorf("%w: installed %s, locked %s", errToolVersionMismatch, binary.Version, t.Version)
+	}
+	expected, ok := t.Checksums[toolPlatform()]
+	if !ok || expected == binary.Checksum {
+		return nil
+	}
+	if binary.GoVersion != t.GoVersion {
+		return fmt.Errorf("%w (built with %s, locked with %s)", errToolChecksumMismatch, binary.GoVersion, t.GoVersion)
+	}
+	return errToolChecksumMismatch
+}
+
+// find returns the locked tool with the given binary name
+func (l *ToolsLock) find(name string) *LockedTool {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Tools {
+		if l.Tools[i].Name == name {
+			return &l.Tools[i]
+		}
+	}
+	return nil
+}
+
+// readToolsLock reads the lockfile; a missing file yields a nil lock
+func readToolsLock(path string) (*ToolsLock, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the project lockfile
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil //nolint:nilnil // a nil lock means the project does not lock its tools
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var lock ToolsLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// writeToolsLock writes the lockfile with tools sorted by name, so it diffs cleanly
+func writeToolsLock(path string, lock *ToolsLock) error {
+	sort.Slice(lock.Tools, func(i, j int) bool { return lock.Tools[i].Name < lock.Tools[j].Name })
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), fileops.PermFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// toolsBinDir returns the absolute project tools directory (tools.bin_dir, default .mage/bin)
+func toolsBinDir(config *Config) (string, error) {
+	dir := config.Tools.BinDir
+	if dir == "" {
+		dir = DefaultToolsBinDir
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tools directory: %w", err)
+	}
+	return absDir, nil
+}
+
+// PrependToolsBinToPath puts the project tools directory first on PATH when it
+// exists, so commands run by magex use the locked tool binaries
+func PrependToolsBinToPath() error {
+	config, err := GetConfig()
+	if err != nil {
+		config = defaultConfig()
+	}
+	binDir, err := toolsBinDir(config)
+	if err != nil {
+		return err
+	}
+	if info, statErr := os.Stat(binDir); statErr != nil || !info.IsDir() {
+		return nil
+	}
+	return prependPath(binDir)
+}
+
+// prependPath puts dir first on PATH unless it is already listed
+func prependPath(dir string) error {
+	current := os.Getenv("PATH")
+	for _, entry := range filepath.SplitList(current) {
+		if entry == dir {
+			return nil
+		}
+	}
+	if current != "" {
+		dir += string(os.PathListSeparator) + current
+	}
+	if err := os.Setenv("PATH", dir); err != nil {
+		return fmt.Errorf("failed to set PATH: %w", err)
+	}
+	return nil
+}
+
+// goInstallTool runs "go install -trimpath pkg@version" with GOBIN set to binDir.
+// -trimpath keeps the binary independent of where the module cache lives, and a
+// goVersion from the lock pins GOTOOLCHAIN, so the same version produces the same
+// checksum on every machine whatever Go release is installed locally.
+//
+//nolint:gochecknoglobals // seam replaced in tests to avoid real go install calls
+var goInstallTool = func(binDir, pkg, version, goVersion string) error {
+	if err := os.MkdirAll(binDir, fileops.PermDir); err != nil {
+		return fmt.Errorf("failed to create %s: %w", binDir, err)
+	}
+	envRunner, ok := GetRunner().(EnvCommandRunner)
+	if !ok {
+		return errToolsEnvRunner
+	}
+	return envRunner.RunCmdWithEnv(goInstallEnv(binDir, goVersion), CmdGo, CmdGoInstall, "-trimpath", pkg+"@"+version)
+}
+
+// goInstallEnv returns the environment of go install: GOBIN, and GOTOOLCHAIN when
+// goVersion names a Go release (build info may add experiments after a space, and
+// development toolchains cannot be downloaded, so they are not pinned)
+func goInstallEnv(binDir, goVersion string) []string {
+	env := []string{"GOBIN=" + binDir}
+	toolchain, _, _ := strings.Cut(goVersion, " ")
+	if goReleaseVersion.MatchString(toolchain) {
+		env = append(env, "GOTOOLCHAIN="+toolchain)
+	}
+	return env
+}
+
+// inspectToolBinary reads the module build info and checksum of a Go binary
+func inspectToolBinary(binaryPath string) (*toolBinary, error) {
+	info, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build info of %s: %w", binaryPath, err)
+	}
+	checksum, err := fileChecksum(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	return &toolBinary{
+		Module:    info.Main.Path,
+		Version:   info.Main.Version,
+		GoVersion: info.GoVersion,
+		Checksum:  checksum,
+	}, nil
+}
+
+// fileChecksum returns the hex SHA-256 of a file
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath) // #nosec G304 -- path is a tool binary or release artifact
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer func() { _ = file.Close() }() //nolint:errcheck // read-only file
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", filePath, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// lockPackage returns the package go install builds for a tool
+func lockPackage(tool ToolDefinition) string {
+	if tool.Name == CmdGolangciLint && tool.Module == "" {
+		if strings.HasPrefix(strings.TrimPrefix(tool.Version, "v"), "1.") {
+			return golangciLintV1Package
+		}
+		return golangciLintPackage
+	}
+	return tool.Module
+}
+
+// toolBinaryName returns the name go install gives the binary of pkg: its last path
+// element, skipping a trailing major version element such as /v2
+func toolBinaryName(pkg string) string {
+	name := path.Base(pkg)
+	if majorVersionElement.MatchString(name) && strings.Count(pkg, "/") > 1 {
+		name = path.Base(path.Dir(pkg))
+	}
+	return name
+}
+
+// toolBinaryPath returns where go install puts the binary of a tool in binDir
+func toolBinaryPath(binDir, name string) string {
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(binDir, name)
+}
+
+// toolPlatform is the key checksums are recorded under
+func toolPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
@@ -0,0 +1,271 @@
+package mage
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useFakeGoInstall replaces go install with a copy of the test binary, which
+// carries real build info, and returns the installs it was asked for. PATH is
+// restored afterwards, since installing prepends the tools directory.
+func useFakeGoInstall(t *testing.T) *[]string {
+	t.Helper()
+	t.Setenv("PATH", os.Getenv("PATH"))
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	data, err := os.ReadFile(exe) //nolint:gosec // the running test binary
+	require.NoError(t, err)
+
+	var installs []string
+	original := goInstallTool
+	goInstallTool = func(binDir, pkg, version, _ string) error {
+		installs = append(installs, pkg+"@"+version)
+		require.NoError(t, os.MkdirAll(binDir, 0o750))
+		return os.WriteFile(toolBinaryPath(binDir, toolBinaryName(pkg)), data, 0o700) //nolint:gosec // test executable
+	}
+	t.Cleanup(func() { goInstallTool = original })
+	return &installs
+}
+
+// testBinaryVersion is the module version the fake go install produces
+func testBinaryVersion(t *testing.T) string {
+	t.Helper()
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+	return info.Main.Version
+}
+
+// runtimeGoVersion returns the toolchain recorded in the test binary
+func runtimeGoVersion(t *testing.T) string {
+	t.Helper()
+	info, ok := debug.ReadBuildInfo()
+	require.True(t, ok)
+	return info.GoVersion
+}
+
+func TestToolsLock(t *testing.T) {
+	chdirTempModule(t)
+	installs := useFakeGoInstall(t)
+	config := defaultConfig()
+	config.Tools.Mockgen = ""
+	config.Tools.Swag = ""
+	config.Tools.GoVulnCheck = "v1.1.4"
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+
+	// A checksum recorded elsewhere survives while the version is unchanged
+	require.NoError(t, writeToolsLock(ToolsLockFile, &ToolsLock{Tools: []LockedTool{{
+		Name:      "gofumpt",
+		Version:   testBinaryVersion(t),
+		GoVersion: runtimeGoVersion(t),
+		Checksums: map[string]string{"plan9/386": "abc"},
+	}}}))
+
+	require.NoError(t, Tools{}.Lock())
+
+	assert.Contains(t, *installs, golangciLintPackage+"@latest")
+	assert.Contains(t, *installs, "golang.org/x/vuln/cmd/govulncheck@v1.1.4")
+
+	lock, err := readToolsLock(ToolsLockFile)
+	require.NoError(t, err)
+	require.Len(t, lock.Tools, 3)
+	names := []string{lock.Tools[0].Name, lock.Tools[1].Name, lock.Tools[2].Name}
+	assert.Equal(t, []string{"gofumpt", "golangci-lint", "govulncheck"}, names, "tools are sorted by name")
+
+	gofumpt := lock.find("gofumpt")
+	require.NotNil(t, gofumpt)
+	assert.Equal(t, "mvdan.cc/gofumpt", gofumpt.Package)
+	assert.NotEmpty(t, gofumpt.Module)
+	assert.Equal(t, testBinaryVersion(t), gofumpt.Version)
+	assert.Len(t, gofumpt.Checksums[toolPlatform()], 64)
+	assert.Equal(t, "abc", gofumpt.Checksums["plan9/386"])
+
+	binDir, err := toolsBinDir(config)
+	require.NoError(t, err)
+	assert.FileExists(t, toolBinaryPath(binDir, "golangci-lint"))
+}
+
+// lockInstalledTool installs a fake tool and returns the lock entry that matches it
+func lockInstalledTool(t *testing.T, binDir, pkg string) LockedTool {
+	t.Helper()
+	require.NoError(t, goInstallTool(binDir, pkg, "v1.0.0", ""))
+	name := toolBinaryName(pkg)
+	binary, err := inspectToolBinary(toolBinaryPath(binDir, name))
+	require.NoError(t, err)
+	return LockedTool{
+		Name:      name,
+		Package:   pkg,
+		Module:    binary.Module,
+		Version:   binary.Version,
+		GoVersion: binary.GoVersion,
+		Checksums: map[string]string{toolPlatform(): binary.Checksum},
+	}
+}
+
+func TestInstallLockedTools(t *testing.T) {
+	t.Run("skips matching binaries and reinstalls missing ones", func(t *testing.T) {
+		chdirTempModule(t)
+		installs := useFakeGoInstall(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		gofumpt := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+		govulncheck := lockInstalledTool(t, binDir, "golang.org/x/vuln/cmd/govulncheck")
+		require.NoError(t, os.Remove(toolBinaryPath(binDir, "govulncheck")))
+		*installs = nil
+
+		require.NoError(t, installLockedTools(&ToolsLock{Tools: []LockedTool{gofumpt, govulncheck}}, binDir))
+		assert.Equal(t, []string{"golang.org/x/vuln/cmd/govulncheck@" + govulncheck.Version}, *installs)
+		assert.NoFileExists(t, ToolsLockFile, "the lock is unchanged")
+	})
+
+	t.Run("records a checksum for a new platform", func(t *testing.T) {
+		chdirTempModule(t)
+		useFakeGoInstall(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		tool := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+		checksum := tool.Checksums[toolPlatform()]
+		tool.Checksums = map[string]string{"plan9/386": "abc"}
+		require.NoError(t, os.Remove(toolBinaryPath(binDir, "gofumpt")))
+
+		require.NoError(t, installLockedTools(&ToolsLock{Tools: []LockedTool{tool}}, binDir))
+
+		lock, err := readToolsLock(ToolsLockFile)
+		require.NoError(t, err)
+		require.NotNil(t, lock)
+		assert.Equal(t, map[string]string{"plan9/386": "abc", toolPlatform(): checksum}, lock.Tools[0].Checksums)
+	})
+
+	t.Run("fails when the installed binary does not match", func(t *testing.T) {
+		chdirTempModule(t)
+		useFakeGoInstall(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		tool := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+		tool.Checksums[toolPlatform()] = strings.Repeat("0", 64)
+
+		err := installLockedTools(&ToolsLock{Tools: []LockedTool{tool}}, binDir)
+		require.ErrorIs(t, err, errToolChecksumMismatch)
+	})
+
+	t.Run("installs with the locked toolchain", func(t *testing.T) {
+		chdirTempModule(t)
+		useFakeGoInstall(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		tool := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+		require.NoError(t, os.Remove(toolBinaryPath(binDir, "gofumpt")))
+
+		var goVersions []string
+		fake := goInstallTool
+		goInstallTool = func(binDir, pkg, version, goVersion string) error {
+			goVersions = append(goVersions, goVersion)
+			return fake(binDir, pkg, version, goVersion)
+		}
+
+		require.NoError(t, installLockedTools(&ToolsLock{Tools: []LockedTool{tool}}, binDir))
+		assert.Equal(t, []string{tool.GoVersion}, goVersions)
+	})
+}
+
+func TestGoInstallEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		goVersion string
+		expected  []string
+	}{
+		{"", []string{"GOBIN=/bin"}},
+		{"go1.25.3", []string{"GOBIN=/bin", "GOTOOLCHAIN=go1.25.3"}},
+		{"go1.26rc1", []string{"GOBIN=/bin", "GOTOOLCHAIN=go1.26rc1"}},
+		{"go1.25.3 X:nocoverageredesign", []string{"GOBIN=/bin", "GOTOOLCHAIN=go1.25.3"}},
+		{"devel go1.26-abcdef", []string{"GOBIN=/bin"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, goInstallEnv("/bin", tt.goVersion), tt.goVersion)
+	}
+}
+
+func TestVerifyLockedTools(t *testing.T) {
+	useFakeGoInstall(t)
+	binDir := filepath.Join(t.TempDir(), "bin")
+	tool := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+
+	require.NoError(t, verifyLockedTools(&ToolsLock{Tools: []LockedTool{tool}}, binDir))
+
+	tampered := tool
+	tampered.Checksums = map[string]string{toolPlatform(): strings.Repeat("0", 64)}
+	require.ErrorIs(t, verifyLockedTools(&ToolsLock{Tools: []LockedTool{tampered}}, binDir), errToolsLockMismatch)
+
+	upgraded := tool
+	upgraded.Version = "v9.9.9"
+	require.ErrorIs(t, verifyLockedTools(&ToolsLock{Tools: []LockedTool{upgraded}}, binDir), errToolsLockMismatch)
+
+	missing := tool
+	missing.Name = "staticcheck"
+	require.ErrorIs(t, verifyLockedTools(&ToolsLock{Tools: []LockedTool{missing}}, binDir), errToolsLockMismatch)
+
+	otherPlatform := tool
+	otherPlatform.Checksums = map[string]string{"plan9/386": "abc"}
+	require.NoError(t, verifyLockedTools(&ToolsLock{Tools: []LockedTool{otherPlatform}}, binDir), "the version still matches")
+}
+
+func TestToolsVerify_UsesLock(t *testing.T) {
+	chdirTempModule(t)
+	useFakeGoInstall(t)
+	config := defaultConfig()
+	TestSetConfig(config)
+	t.Cleanup(TestResetConfig)
+	binDir, err := toolsBinDir(config)
+	require.NoError(t, err)
+
+	tool := lockInstalledTool(t, binDir, "mvdan.cc/gofumpt")
+	require.NoError(t, writeToolsLock(ToolsLockFile, &ToolsLock{Tools: []LockedTool{tool}}))
+	require.NoError(t, Tools{}.Verify())
+
+	require.NoError(t, os.WriteFile(toolBinaryPath(binDir, "gofumpt"), []byte("not a go binary"), 0o600))
+	require.ErrorIs(t, Tools{}.Verify(), errToolsLockMismatch)
+}
+
+func TestToolBinaryName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "gofumpt", toolBinaryName("mvdan.cc/gofumpt"))
+	assert.Equal(t, "golangci-lint", toolBinaryName(golangciLintPackage))
+	assert.Equal(t, "gqlgen", toolBinaryName("github.com/99designs/gqlgen"))
+	assert.Equal(t, "oapi-codegen", toolBinaryName("github.com/oapi-codegen/oapi-codegen/v2"))
+}
+
+func TestLockPackage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, golangciLintPackage, lockPackage(ToolDefinition{Name: CmdGolangciLint, Version: "v2.1.6"}))
+	assert.Equal(t, golangciLintV1Package, lockPackage(ToolDefinition{Name: CmdGolangciLint, Version: "v1.64.8"}))
+	assert.Equal(t, "mvdan.cc/gofumpt", lockPackage(ToolDefinition{Name: "gofumpt", Module: "mvdan.cc/gofumpt"}))
+}
+
+func TestReadToolsLock_Missing(t *testing.T) {
+	lock, err := readToolsLock(filepath.Join(t.TempDir(), ToolsLockFile))
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+}
+
+func TestPrependToolsBinToPath(t *testing.T) {
+	chdirTempModule(t)
+	TestSetConfig(defaultConfig())
+	t.Cleanup(TestResetConfig)
+	t.Setenv("PATH", "/usr/bin")
+
+	require.NoError(t, PrependToolsBinToPath())
+	assert.Equal(t, "/usr/bin", os.Getenv("PATH"), "nothing is added without a tools directory")
+
+	require.NoError(t, os.MkdirAll(DefaultToolsBinDir, 0o750))
+	binDir, err := filepath.Abs(DefaultToolsBinDir)
+	require.NoError(t, err)
+	require.NoError(t, PrependToolsBinToPath())
+	require.NoError(t, PrependToolsBinToPath())
+	assert.Equal(t, binDir+string(os.PathListSeparator)+"/usr/bin", os.Getenv("PATH"))
+}
@@ -0,0 +1,213 @@
+package mage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// diffMaxEdits bounds the Myers search (and its quadratic trace); larger rewrites are shown as one replacement hunk
+const diffMaxEdits = 1000
+
+// diffOp is one line of an edit script: ' ' keeps, '-' removes and '+' adds a line
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns a unified diff between two versions of a file, or "" when they are equal.
+// An empty before or after with exists=false is rendered as /dev/null.
+func unifiedDiff(name string, before, after []byte, beforeExists, afterExists bool) string {
+	if beforeExists == afterExists && bytes.Equal(before, after) {
+		return ""
+	}
+
+	oldName, newName := "a/"+name, "b/"+name
+	if !beforeExists {
+		oldName = "/dev/null"
+	}
+	if !afterExists {
+		newName = "/dev/null"
+	}
+	if bytes.IndexByte(before, 0) >= 0 || bytes.IndexByte(after, 0) >= 0 {
+		return fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	writeDiffHunks(&sb, diffLines(splitDiffLines(before), splitDiffLines(after)))
+	return sb.String()
+}
+
+// splitDiffLines splits content into lines without their terminators
+func splitDiffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// diffLines computes a shortest edit script from a to b (Myers' algorithm)
+func diffLines(a, b []string) []diffOp {
+	// Common prefix and suffix never take part in the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// myersDiff runs the greedy Myers search and backtracks through the saved frontiers
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	limit := min(n+m, diffMaxEdits)
+
+	offset := n + m + 1
+	v := make([]int, 2*(n+m)+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		// Round d only reads diagonals -d-1..d+1, so that window is all backtracking needs
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(a, b, trace)
+			}
+		}
+	}
+	return replaceAllOps(a, b)
+}
+
+// backtrackDiff walks the saved frontiers from the end back to the start
+func backtrackDiff(a, b []string, trace [][]int) []diffOp {
+	var reversed []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, diffOp{'+', b[y-1]})
+			y--
+		} else {
+			reversed = append(reversed, diffOp{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// replaceAllOps removes every line of a and adds every line of b
+func replaceAllOps(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{'-', line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{'+', line})
+	}
+	return ops
+}
+
+// writeDiffHunks groups changes that are close together into @@ hunks with context
+func writeDiffHunks(sb *strings.Builder, ops []diffOp) {
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	for i := 0; i < len(changes); {
+		// Extend the hunk while the next change is within two contexts of the last one
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContextLines {
+			j++
+		}
+		start := max(0, changes[i]-diffContextLines)
+		end := min(len(ops), changes[j]+diffContextLines+1)
+
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		// An empty side points at the line before the hunk
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = j + 1
+	}
+}
@@ -0,0 +1,97 @@
+package mage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		before       string
+		after        string
+		beforeExists bool
+		afterExists  bool
+		expected     string
+	}{
+		{
+			name:         "equal",
+			before:       "a\nb\n",
+			after:        "a\nb\n",
+			beforeExists: true,
+			afterExists:  true,
+		},
+		{
+			name:         "changed line with context",
+			before:       "1\n2\n3\n4\n5\n6\n7\n8\n",
+			after:        "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			beforeExists: true,
+			afterExists:  true,
+			expected:     "--- a/f.go\n+++ b/f.go\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:         "added file",
+			after:        "x\ny\n",
+			beforeExists: false,
+			afterExists:  true,
+			expected:     "--- /dev/null\n+++ b/f.go\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name:         "deleted file",
+			before:       "x\n",
+			beforeExists: true,
+			afterExists:  false,
+			expected:     "--- a/f.go\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-x\n",
+		},
+		{
+			name:         "binary",
+			before:       "a\x00",
+			after:        "b\x00",
+			beforeExists: true,
+			afterExists:  true,
+			expected:     "Binary files a/f.go and b/f.go differ\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			diff := unifiedDiff("f.go", []byte(tt.before), []byte(tt.after), tt.beforeExists, tt.afterExists)
+			assert.Equal(t, tt.expected, diff)
+		})
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprint(i + 1)
+	}
+	before := strings.Join(lines, "\n") + "\n"
+	lines[1], lines[17] = "two", "eighteen"
+	after := strings.Join(lines, "\n") + "\n"
+
+	diff := unifiedDiff("f.go", []byte(before), []byte(after), true, true)
+	assert.Contains(t, diff, "@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n")
+	assert.Contains(t, diff, "@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+eighteen\n 19\n 20\n")
+}
+
+func TestDiffLines_ShortestScript(t *testing.T) {
+	t.Parallel()
+
+	ops := diffLines([]string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"})
+	edits := 0
+	for _, op := range ops {
+		if op.kind != ' ' {
+			edits++
+		}
+	}
+	assert.Equal(t, 5, edits, "Myers finds the minimal edit script")
+}
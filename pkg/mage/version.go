@@ -1017,66 +1017,6 @@ func findNextAvailableVersion(initialVersion, bumpType string) (string, []string
 	return "", nil, errMaxAutoIncrementAttempts
 }
 
-// Changelog generates a changelog for the current version
-func (Version) Changelog(args ...string) error {
-	utils.Header("Generating Changelog")
-
-	// Parse command-line parameters
-	params := utils.ParseParams(args)
-
-	// Get version range
-	fromTag := utils.GetParam(params, "from", "")
-	toTag := utils.GetParam(params, "to", "HEAD")
-
-	if fromTag == "" {
-		// Get previous tag
-		fromTag = getPreviousTag()
-		if fromTag == "" {
-			utils.Info("No previous tag found, showing all commits")
-		}
-	}
-
-	// Generate changelog
-
-	var gitArgs []string
-	if fromTag != "" {
-		gitArgs = []string{"log", "--pretty=format:- %s (%h)", fmt.Sprintf("%s..%s", fromTag, toTag)}
-	} else {
-		gitArgs = []string{"log", "--pretty=format:- %s (%h)", toTag}
-	}
-
-	output, err := GetRunner().RunCmdOutput("git", gitArgs...)
-	if err != nil {
-		return fmt.Errorf("failed to generate changelog: %w", err)
-	}
-
-	if fromTag != "" {
-		fmt.Printf("\n## Changes from %s to %s\n\n", fromTag, toTag)
-	} else {
-		fmt.Printf("\n## All Changes\n\n")
-	}
-
-	if strings.TrimSpace(output) == "" {
-		utils.Info("No changes found")
-	} else {
-		utils.Info("%s", output)
-	}
-
-	// Show commit count
-	var countArgs []string
-	if fromTag != "" {
-		countArgs = []string{"rev-list", "--count", fmt.Sprintf("%s..%s", fromTag, toTag)}
-	} else {
-		countArgs = []string{"rev-list", "--count", toTag}
-	}
-
-	if count, err := GetRunner().RunCmdOutput("git", countArgs...); err == nil {
-		fmt.Printf("\n%s commits\n", strings.TrimSpace(count))
-	}
-
-	return nil
-}
-
 // getBuildInfo returns the build information using thread-safe initialization
 // Deprecated: Use BuildInfoProvider.GetBuildInfo() instead
 func getBuildInfo() BuildInfo {
@@ -1399,21 +1339,6 @@ func isVersionBetween(start, middle, end string) bool {
 	return middleV.IsBetween(startV, endV)
 }
 
-// getPreviousTag gets the previous git tag
-func getPreviousTag() string {
-	tags, err := GetRunner().RunCmdOutput("git", "tag", "--sort=-version:refname")
-	if err != nil {
-		return ""
-	}
-
-	tagList := strings.Split(strings.TrimSpace(tags), "\n")
-	if len(tagList) > 1 {
-		return tagList[1]
-	}
-
-	return ""
-}
-
 // bumpVersion bumps the version according to type
 func bumpVersion(current, bumpType string) (string, error) {
 	sv, err := ParseSemanticVersion(current)
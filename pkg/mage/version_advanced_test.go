@@ -316,45 +316,6 @@ func TestBumpMethodWithMocks(t *testing.T) {
 	})
 }
 
-// TestGetPreviousTagWithMocks tests getPreviousTag with mocks
-func TestGetPreviousTagWithMocks(t *testing.T) {
-	// Save original runner
-	originalRunner := GetRunner()
-	defer func() {
-		require.NoError(t, SetRunner(originalRunner))
-	}()
-
-	t.Run("MultipleTags", func(t *testing.T) {
-		mock := NewVersionMockRunner()
-		require.NoError(t, SetRunner(mock))
-
-		mock.SetOutput("git tag --sort=-version:refname", "v2.0.0\nv1.5.0\nv1.0.0\nv0.5.0", nil)
-
-		tag := getPreviousTag()
-		assert.Equal(t, "v1.5.0", tag)
-	})
-
-	t.Run("SingleTag", func(t *testing.T) {
-		mock := NewVersionMockRunner()
-		require.NoError(t, SetRunner(mock))
-
-		mock.SetOutput("git tag --sort=-version:refname", "v1.0.0", nil)
-
-		tag := getPreviousTag()
-		assert.Empty(t, tag)
-	})
-
-	t.Run("NoTags", func(t *testing.T) {
-		mock := NewVersionMockRunner()
-		require.NoError(t, SetRunner(mock))
-
-		mock.SetOutput("git tag --sort=-version:refname", "", errNoTags)
-
-		tag := getPreviousTag()
-		assert.Empty(t, tag)
-	})
-}
-
 // TestVersionShowWithMocks tests Version.Show with mocks
 func TestVersionShowWithMocks(t *testing.T) {
 	// Save original runner
@@ -473,10 +434,9 @@ func TestChangelogEdgeCases(t *testing.T) {
 		require.NoError(t, SetRunner(mock))
 
 		// No previous tag
-		mock.SetOutput("git tag --sort=-version:refname", "", errNoTags)
+		mock.SetOutput("git tag -l v* --sort=-version:refname", "", errNoTags)
 		// No commits
-		mock.SetOutput("git log --pretty=format:- %s (%h) HEAD", "", nil)
-		mock.SetOutput("git rev-list --count HEAD", "0", nil)
+		mock.SetOutput("git log --no-merges "+changelogLogFormat+" HEAD", "", nil)
 
 		version := Version{}
 		err := version.Changelog()
@@ -490,12 +450,12 @@ func TestChangelogEdgeCases(t *testing.T) {
 		// Test uses FROM parameter instead of environment variable
 
 		// Some commits
-		mock.SetOutput("git log --pretty=format:- %s (%h) v1.0.0..HEAD", "- fix: bug (abc123)", nil)
-		mock.SetOutput("git rev-list --count v1.0.0..HEAD", "1", nil)
+		mock.SetOutput("git log --no-merges "+changelogLogFormat+" v1.0.0..HEAD", "abc123\x1fabc123\x1f2024-01-02\x1fdev\x1ffix: bug\x1e", nil)
 
 		version := Version{}
 		err := version.Changelog("from=v1.0.0")
 		require.NoError(t, err)
+		assert.Contains(t, mock.commands, "git log --no-merges "+changelogLogFormat+" v1.0.0..HEAD")
 	})
 }
 
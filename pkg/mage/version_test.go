@@ -244,12 +244,6 @@ func (ts *VersionTestSuite) TestGitOperations() {
 		}
 	})
 
-	ts.Run("GetPreviousTag", func() {
-		// This may return empty string if no previous tags exist
-		tag := getPreviousTag()
-		ts.Require().IsType("", tag)
-	})
-
 	ts.Run("GetTagsOnCurrentCommit", func() {
 		// Test getTagsOnCurrentCommit function
 		tags, err := getTagsOnCurrentCommit()
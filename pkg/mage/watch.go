@@ -0,0 +1,484 @@
+package mage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/mage-x/pkg/common/paths"
+	"github.com/mrz1836/mage-x/pkg/mage/runtimectx"
+	"github.com/mrz1836/mage-x/pkg/utils"
+)
+
+// errInvalidWatchDuration is returned when a watch timing parameter cannot be parsed
+var errInvalidWatchDuration = errors.New("invalid watch duration")
+
+const (
+	// defaultWatchDebounce is how long the tree must stay quiet before a run starts
+	defaultWatchDebounce = 300 * time.Millisecond
+	// defaultWatchInterval is how often the polling watcher scans for changes; it is
+	// only used where native file notifications are unavailable
+	defaultWatchInterval = 250 * time.Millisecond
+	// watchStopGrace is how long an interrupted run may take to exit before it is killed
+	watchStopGrace = 5 * time.Second
+)
+
+// watchEvents are the file system events that trigger a re-run
+const watchEvents = paths.EventCreate | paths.EventWrite | paths.EventRemove | paths.EventRename
+
+// newWatchPathWatcher creates the watcher used by watch mode (replaced in tests)
+//
+//nolint:gochecknoglobals // test seam for the file system watcher
+var newWatchPathWatcher = paths.NewNativePathWatcher
+
+// watchRunFunc performs one watch iteration. changed lists the paths (relative to the
+// watch root) that triggered it and is empty for the initial run. ctx is canceled
+// as soon as newer changes arrive.
+type watchRunFunc func(ctx context.Context, changed []string) error
+
+// watchOptions configures a watch session
+type watchOptions struct {
+	// Root is the directory tree to watch
+	Root string
+
+	// Debounce is how long to wait for a burst of events to settle
+	Debounce time.Duration
+
+	// Ignore holds the .gitignore patterns of Root
+	Ignore *gitignoreMatcher
+
+	// SkipDirs are directory names (or paths relative to Root) that never trigger a run
+	SkipDirs []string
+
+	// Fallback creates the polling watcher used when the native watcher runs out of
+	// kernel watches; nil means no fallback
+	Fallback func() paths.PathWatcher
+}
+
+// Watch re-runs the affected Go tests whenever Go sources change
+func (r Run) Watch() error {
+	return r.WatchWithArgs()
+}
+
+// WatchWithArgs re-runs a chain of commands whenever Go sources change.
+// Arguments without "=" are commands, run in order and stopping at the first failure;
+// quote a command to pass it parameters ("test:run name=TestFoo"). With no commands,
+// only the packages affected by the change are tested. Parameters:
+//   - debounce=<duration>: quiet period before a run starts (default 300ms)
+//   - interval=<duration>: polling interval when the watcher cannot use native
+//     file notifications (default 250ms)
+func (Run) WatchWithArgs(args ...string) error {
+	utils.Header("Watch Mode")
+
+	params, commands := splitWatchArgs(args)
+	debounce, err := parseWatchDuration(params, "debounce", defaultWatchDebounce)
+	if err != nil {
+		return err
+	}
+	interval, err := parseWatchDuration(params, "interval", defaultWatchInterval)
+	if err != nil {
+		return err
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var run watchRunFunc
+	if len(commands) == 0 {
+		utils.Info("Watching %s; testing affected packages on change", root)
+		run = func(ctx context.Context, changed []string) error {
+			return runAffectedTests(ctx, config, changed)
+		}
+	} else {
+		executable, exeErr := os.Executable()
+		if exeErr != nil {
+			return fmt.Errorf("failed to locate executable: %w", exeErr)
+		}
+		utils.Info("Watching %s; running %s on change", root, strings.Join(commands, " → "))
+		run = func(ctx context.Context, _ []string) error {
+			return runWatchCommands(ctx, executable, commands)
+		}
+	}
+
+	// Hidden, vendor and skipped directories never trigger a run, so they are not
+	// watched at all where the watcher supports it
+	configure := func(watcher paths.PathWatcher) paths.PathWatcher {
+		watcher = watcher.SetDebounce(interval)
+		if ignoring, ok := watcher.(paths.IgnoringPathWatcher); ok {
+			ignoring.SetIgnorePatterns(append([]string{".*", "vendor", "node_modules"}, config.Lint.SkipDirs...)...)
+		}
+		return watcher
+	}
+	return watchLoop(runtimectx.Context(), configure(newWatchPathWatcher()), watchOptions{
+		Root:     root,
+		Debounce: debounce,
+		Ignore:   loadGitignore(root),
+		SkipDirs: config.Lint.SkipDirs,
+		Fallback: func() paths.PathWatcher { return configure(paths.NewPathWatcher()) },
+	}, run)
+}
+
+// splitWatchArgs separates key=value parameters from the commands to run
+func splitWatchArgs(args []string) (map[string]string, []string) {
+	var paramArgs, commands []string
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		switch {
+		case arg == "":
+		case strings.Contains(arg, "=") && !strings.Contains(arg, " "):
+			paramArgs = append(paramArgs, arg)
+		default:
+			commands = append(commands, arg)
+		}
+	}
+	return utils.ParseParams(paramArgs), commands
+}
+
+// parseWatchDuration reads a positive duration parameter
+func parseWatchDuration(params map[string]string, key string, defaultValue time.Duration) (time.Duration, error) {
+	value := utils.GetParam(params, key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: %s=%s", errInvalidWatchDuration, key, value)
+	}
+	return d, nil
+}
+
+// startWatcher starts watcher on opts.Root. When the native watcher runs out of
+// kernel watches it is closed and a polling watcher from opts.Fallback takes over.
+// A watcher that fails to start is always closed.
+func startWatcher(watcher paths.PathWatcher, opts watchOptions) (paths.PathWatcher, error) {
+	err := watcher.Watch(opts.Root, watchEvents)
+	if err == nil {
+		return watcher, nil
+	}
+	closeWatcher(watcher)
+	if !errors.Is(err, paths.ErrWatchLimitReached) || opts.Fallback == nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", opts.Root, err)
+	}
+
+	utils.Warn("%v; polling for changes instead", err)
+	fallback := opts.Fallback()
+	if err := fallback.Watch(opts.Root, watchEvents); err != nil {
+		closeWatcher(fallback)
+		return nil, fmt.Errorf("failed to watch %s: %w", opts.Root, err)
+	}
+	return fallback, nil
+}
+
+// closeWatcher closes a watcher, logging a failure at debug level
+func closeWatcher(watcher paths.PathWatcher) {
+	if err := watcher.Close(); err != nil {
+		utils.Debug("failed to close watcher: %v", err)
+	}
+}
+
+// watchLoop runs fn once, then again after every settled burst of relevant changes,
+// canceling a run that is still in flight. It returns when ctx is canceled or the
+// watcher closes its event channel.
+func watchLoop(ctx context.Context, watcher paths.PathWatcher, opts watchOptions, fn watchRunFunc) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultWatchDebounce
+	}
+	watcher, err := startWatcher(watcher, opts)
+	if err != nil {
+		return err
+	}
+	defer closeWatcher(watcher)
+
+	var (
+		cancelRun context.CancelFunc
+		runDone   chan struct{}
+	)
+	stopRun := func() {
+		if cancelRun == nil {
+			return
+		}
+		cancelRun()
+		<-runDone
+		cancelRun = nil
+	}
+	startRun := func(changed []string) {
+		stopRun()
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		cancelRun, runDone = cancel, done
+		go func() {
+			defer close(done)
+			reportWatchRun(runCtx, fn(runCtx, changed))
+		}()
+	}
+	defer stopRun()
+
+	startRun(nil)
+
+	pending := make(map[string]struct{})
+	timer := time.NewTimer(opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	events, errs := watcher.Events(), watcher.Errors()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			rel, relevant := opts.relevantChange(event.Path)
+			if !relevant {
+				continue
+			}
+			pending[rel] = struct{}{}
+			timer.Reset(opts.Debounce)
+
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			utils.Warn("Watcher error: %v", err)
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			changed := make([]string, 0, len(pending))
+			for rel := range pending {
+				changed = append(changed, rel)
+			}
+			slices.Sort(changed)
+			clear(pending)
+
+			utils.Info("Change detected: %s", summarizeChanges(changed))
+			startRun(changed)
+		}
+	}
+}
+
+// reportWatchRun prints the outcome of a watch iteration
+func reportWatchRun(ctx context.Context, err error) {
+	switch {
+	case ctx.Err() != nil:
+		utils.Info("Run interrupted")
+		return
+	case err != nil:
+		utils.Error("Run failed: %v", err)
+	default:
+		utils.Success("Run completed")
+	}
+	utils.Info("Waiting for changes... (Ctrl+C to stop)")
+}
+
+// summarizeChanges formats changed paths for a one-line status message
+func summarizeChanges(changed []string) string {
+	const maxShown = 3
+	if len(changed) <= maxShown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(changed[:maxShown], ", "), len(changed)-maxShown)
+}
+
+// relevantChange reports whether an event path should trigger a run and returns it
+// relative to the watch root. Only Go sources and module files count; hidden, vendor,
+// skipped and git-ignored paths never do.
+func (o watchOptions) relevantChange(path string) (string, bool) {
+	rel, err := filepath.Rel(o.Root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+
+	base := filepath.Base(rel)
+	if filepath.Ext(base) != ".go" && base != "go.mod" && base != "go.sum" && base != "go.work" {
+		return "", false
+	}
+
+	dirs := strings.Split(rel, "/")
+	dirs = dirs[:len(dirs)-1]
+	for i, dir := range dirs {
+		if strings.HasPrefix(dir, ".") || dir == "vendor" || dir == "node_modules" {
+			return "", false
+		}
+		prefix := strings.Join(dirs[:i+1], "/")
+		for _, skip := range o.SkipDirs {
+			skip = strings.Trim(filepath.ToSlash(skip), "/")
+			if skip == dir || skip == prefix {
+				return "", false
+			}
+		}
+	}
+
+	if o.Ignore.Match(rel, false) {
+		return "", false
+	}
+	return rel, true
+}
+
+// runWatchCommands executes each command as a child process of the current executable,
+// stopping at the first failure
+func runWatchCommands(ctx context.Context, executable string, commands []string) error {
+	for _, command := range commands {
+		fields := strings.Fields(command)
+		utils.Info("▶ %s", command)
+		if err := runWatchProcess(ctx, executable, fields...); err != nil {
+			return fmt.Errorf("%s: %w", fields[0], err)
+		}
+	}
+	return nil
+}
+
+// runWatchProcess runs a command attached to the terminal. Cancellation interrupts it
+// first so it can stop its own children, and kills it after watchStopGrace.
+func runWatchProcess(ctx context.Context, name string, args ...string) error {
+	// #nosec G204 -- commands come from the user's own watch invocation
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = watchStopGrace
+	return cmd.Run()
+}
+
+// runAffectedTests runs go test for the packages affected by the changed files, or for
+// every package on the initial run and when module files change
+func runAffectedTests(ctx context.Context, config *Config, changed []string) error {
+	targets := []string{"./..."}
+	if len(changed) > 0 {
+		pkgs, err := listGoPackages(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, ok := affectedPackages(pkgs, changedDirs(changed)); ok {
+			if len(affected) == 0 {
+				utils.Info("No packages affected")
+				return nil
+			}
+			targets = affected
+		}
+	}
+
+	args := []string{"test"}
+	if config.Test.Tags != "" {
+		args = append(args, "-tags", config.Test.Tags)
+	}
+	args = append(args, targets...)
+
+	utils.Info("▶ go %s", strings.Join(args, " "))
+	return runWatchProcess(ctx, "go", args...)
+}
+
+// changedDirs returns the absolute directories of changed Go files; a nil result means
+// a module file changed and everything is affected
+func changedDirs(changed []string) []string {
+	var dirs []string
+	for _, rel := range changed {
+		if filepath.Ext(rel) != ".go" {
+			return nil
+		}
+		dir, err := filepath.Abs(filepath.Dir(filepath.FromSlash(rel)))
+		if err != nil {
+			return nil
+		}
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// goPackage is the subset of go list output used to map changes to packages
+type goPackage struct {
+	ImportPath string
+	Dir        string
+	Deps       []string
+
+	// TestImports includes the imports of both internal and external test files
+	TestImports []string
+}
+
+// listGoPackages lists the packages of the current module with their dependencies
+func listGoPackages(ctx context.Context) ([]goPackage, error) {
+	const format = `{{.ImportPath}}` + "\t" + `{{.Dir}}` + "\t" + `{{join .Deps " "}}` + "\t" + `{{join .TestImports " "}} {{join .XTestImports " "}}`
+
+	// #nosec G204 -- fixed go list invocation
+	out, err := exec.CommandContext(ctx, "go", "list", "-e", "-f", format, "./...").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	var pkgs []goPackage
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		pkgs = append(pkgs, goPackage{
+			ImportPath:  fields[0],
+			Dir:         fields[1],
+			Deps:        strings.Fields(fields[2]),
+			TestImports: strings.Fields(fields[3]),
+		})
+	}
+	return pkgs, nil
+}
+
+// affectedPackages returns the import paths whose tests can observe a change in dirs:
+// the changed packages themselves, packages that depend on them, and packages whose
+// tests import them. ok is false when the changes cannot be mapped to packages.
+func affectedPackages(pkgs []goPackage, dirs []string) (affected []string, ok bool) {
+	if dirs == nil {
+		return nil, false
+	}
+
+	byPath := make(map[string]goPackage, len(pkgs))
+	changed := make(map[string]bool)
+	for _, pkg := range pkgs {
+		byPath[pkg.ImportPath] = pkg
+		if slices.Contains(dirs, pkg.Dir) {
+			changed[pkg.ImportPath] = true
+		}
+	}
+	if len(changed) == 0 {
+		// A new directory or a file outside any listed package
+		return nil, false
+	}
+
+	reaches := func(importPath string) bool {
+		if changed[importPath] {
+			return true
+		}
+		return slices.ContainsFunc(byPath[importPath].Deps, func(dep string) bool { return changed[dep] })
+	}
+
+	for _, pkg := range pkgs {
+		if reaches(pkg.ImportPath) || slices.ContainsFunc(pkg.TestImports, reaches) {
+			affected = append(affected, pkg.ImportPath)
+		}
+	}
+	return affected, true
+}
@@ -0,0 +1,300 @@
+package mage
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/mage-x/pkg/common/paths"
+)
+
+func TestSplitWatchArgs(t *testing.T) {
+	t.Parallel()
+
+	params, commands := splitWatchArgs([]string{"lint", "debounce=1s", "test:run name=TestFoo", " ", "interval=100ms"})
+	assert.Equal(t, []string{"lint", "test:run name=TestFoo"}, commands)
+	assert.Equal(t, map[string]string{"debounce": "1s", "interval": "100ms"}, params)
+}
+
+func TestParseWatchDuration(t *testing.T) {
+	t.Parallel()
+
+	d, err := parseWatchDuration(map[string]string{}, "debounce", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+
+	d, err = parseWatchDuration(map[string]string{"debounce": "50ms"}, "debounce", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, d)
+
+	for _, bad := range []string{"soon", "0s", "-1s"} {
+		_, err = parseWatchDuration(map[string]string{"debounce": bad}, "debounce", time.Second)
+		require.ErrorIs(t, err, errInvalidWatchDuration, bad)
+	}
+}
+
+func TestWatchOptions_RelevantChange(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "project")
+	opts := watchOptions{
+		Root:     root,
+		Ignore:   parseGitignore("generated/\n*_mock.go\n"),
+		SkipDirs: []string{"third_party", "pkg/legacy"},
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "main.go", want: true},
+		{path: "pkg/mage/watch.go", want: true},
+		{path: "go.mod", want: true},
+		{path: "go.sum", want: true},
+		{path: "README.md", want: false},
+		{path: ".git/HEAD", want: false},
+		{path: ".github/tools.go", want: false},
+		{path: "vendor/x/x.go", want: false},
+		{path: "third_party/lib/lib.go", want: false},
+		{path: "pkg/legacy/old.go", want: false},
+		{path: "legacy/ok.go", want: true},
+		{path: "generated/api.go", want: false},
+		{path: "pkg/store_mock.go", want: false},
+	}
+	for _, tt := range tests {
+		rel, ok := opts.relevantChange(filepath.Join(root, filepath.FromSlash(tt.path)))
+		assert.Equal(t, tt.want, ok, tt.path)
+		if ok {
+			assert.Equal(t, tt.path, rel)
+		}
+	}
+
+	_, ok := opts.relevantChange(filepath.Join(filepath.Dir(root), "other", "main.go"))
+	assert.False(t, ok, "paths outside the root are ignored")
+}
+
+func TestAffectedPackages(t *testing.T) {
+	t.Parallel()
+
+	pkgs := []goPackage{
+		{ImportPath: "ex/cmd/app", Dir: "/src/cmd/app", Deps: []string{"ex/pkg/api", "ex/pkg/store", "fmt"}},
+		{ImportPath: "ex/pkg/api", Dir: "/src/pkg/api", Deps: []string{"ex/pkg/store", "fmt"}},
+		{ImportPath: "ex/pkg/store", Dir: "/src/pkg/store", Deps: []string{"fmt"}},
+		{ImportPath: "ex/pkg/testutil", Dir: "/src/pkg/testutil", Deps: []string{"ex/pkg/store"}},
+		{ImportPath: "ex/pkg/util", Dir: "/src/pkg/util", TestImports: []string{"ex/pkg/testutil", "testing"}},
+		{ImportPath: "ex/pkg/other", Dir: "/src/pkg/other", TestImports: []string{"testing"}},
+	}
+
+	affected, ok := affectedPackages(pkgs, []string{"/src/pkg/store"})
+	require.True(t, ok)
+	assert.Equal(t, []string{"ex/cmd/app", "ex/pkg/api", "ex/pkg/store", "ex/pkg/testutil", "ex/pkg/util"}, affected,
+		"dependents and packages whose tests import a dependent are affected")
+
+	affected, ok = affectedPackages(pkgs, []string{"/src/cmd/app"})
+	require.True(t, ok)
+	assert.Equal(t, []string{"ex/cmd/app"}, affected)
+
+	_, ok = affectedPackages(pkgs, nil)
+	assert.False(t, ok, "module file changes cannot be mapped")
+
+	_, ok = affectedPackages(pkgs, []string{"/src/pkg/new"})
+	assert.False(t, ok, "a directory outside the package list cannot be mapped")
+}
+
+func TestChangedDirs(t *testing.T) {
+	t.Parallel()
+
+	dirs := changedDirs([]string{"pkg/a/a.go", "pkg/a/b.go", "main.go"})
+	require.Len(t, dirs, 2)
+	for _, dir := range dirs {
+		assert.True(t, filepath.IsAbs(dir), dir)
+	}
+
+	assert.Nil(t, changedDirs([]string{"pkg/a/a.go", "go.mod"}))
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a.go, b.go", summarizeChanges([]string{"a.go", "b.go"}))
+	assert.Equal(t, "a.go, b.go, c.go and 2 more", summarizeChanges([]string{"a.go", "b.go", "c.go", "d.go", "e.go"}))
+}
+
+// watchRecorder records the changes each watch run was started with
+type watchRecorder struct {
+	mu       sync.Mutex
+	runs     [][]string
+	canceled int
+	started  chan struct{}
+}
+
+func newWatchRecorder() *watchRecorder {
+	return &watchRecorder{started: make(chan struct{}, 10)}
+}
+
+func (w *watchRecorder) run(block bool) watchRunFunc {
+	return func(ctx context.Context, changed []string) error {
+		w.mu.Lock()
+		w.runs = append(w.runs, changed)
+		w.mu.Unlock()
+		w.started <- struct{}{}
+
+		if block {
+			<-ctx.Done()
+			w.mu.Lock()
+			w.canceled++
+			w.mu.Unlock()
+			return ctx.Err()
+		}
+		return nil
+	}
+}
+
+func (w *watchRecorder) waitStart(t *testing.T) {
+	t.Helper()
+	select {
+	case <-w.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a watch run")
+	}
+}
+
+func (w *watchRecorder) snapshot() ([][]string, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([][]string(nil), w.runs...), w.canceled
+}
+
+// startWatchLoop runs watchLoop against a mock watcher until the test ends
+func startWatchLoop(t *testing.T, root string, fn watchRunFunc) *paths.MockPathWatcher {
+	t.Helper()
+
+	watcher := paths.NewMockPathWatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watchLoop(ctx, watcher, watchOptions{Root: root, Debounce: 30 * time.Millisecond}, fn)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Error("watchLoop did not stop after cancellation")
+		}
+	})
+	return watcher
+}
+
+func TestWatchLoop_DebouncesBursts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	rec := newWatchRecorder()
+	watcher := startWatchLoop(t, root, rec.run(false))
+
+	rec.waitStart(t) // initial run
+	for _, name := range []string{"b.go", "a.go", "b.go", "notes.txt"} {
+		watcher.MockEvents <- &paths.PathEvent{Path: filepath.Join(root, name), Op: paths.EventWrite}
+	}
+	rec.waitStart(t)
+
+	time.Sleep(100 * time.Millisecond)
+	runs, _ := rec.snapshot()
+	require.Len(t, runs, 2, "a burst of events triggers a single run")
+	assert.Empty(t, runs[0], "the initial run has no changes")
+	assert.Equal(t, []string{"a.go", "b.go"}, runs[1])
+	require.Len(t, watcher.WatchCalls, 1)
+	assert.Equal(t, root, watcher.WatchCalls[0].Path)
+}
+
+func TestWatchLoop_CancelsInFlightRun(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	rec := newWatchRecorder()
+	watcher := startWatchLoop(t, root, rec.run(true))
+
+	rec.waitStart(t)
+	watcher.MockEvents <- &paths.PathEvent{Path: filepath.Join(root, "main.go"), Op: paths.EventWrite}
+	rec.waitStart(t)
+
+	runs, canceled := rec.snapshot()
+	assert.Len(t, runs, 2)
+	assert.Equal(t, 1, canceled, "the in-flight run is canceled before the next one starts")
+}
+
+func TestWatchLoop_WatchError(t *testing.T) {
+	t.Parallel()
+
+	watcher := paths.NewMockPathWatcher()
+	watcher.ShouldError = true
+	err := watchLoop(context.Background(), watcher, watchOptions{Root: t.TempDir()}, func(context.Context, []string) error {
+		t.Error("run must not start when the watcher fails")
+		return nil
+	})
+	require.ErrorIs(t, err, paths.ErrWatcherMockError)
+}
+
+// failingWatcher is a mock watcher whose Watch fails with err and that records Close
+type failingWatcher struct {
+	*paths.MockPathWatcher
+
+	err    error
+	closed bool
+}
+
+func (w *failingWatcher) Watch(string, paths.EventMask) error { return w.err }
+
+func (w *failingWatcher) Close() error {
+	w.closed = true
+	return w.MockPathWatcher.Close()
+}
+
+func TestWatchLoop_WatchLimitFallsBackToPolling(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	native := &failingWatcher{MockPathWatcher: paths.NewMockPathWatcher(), err: paths.ErrWatchLimitReached}
+	polling := paths.NewMockPathWatcher()
+	rec := newWatchRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watchLoop(ctx, native, watchOptions{
+			Root:     root,
+			Debounce: 30 * time.Millisecond,
+			Fallback: func() paths.PathWatcher { return polling },
+		}, rec.run(false))
+	}()
+
+	rec.waitStart(t)
+	polling.MockEvents <- &paths.PathEvent{Path: filepath.Join(root, "main.go"), Op: paths.EventWrite}
+	rec.waitStart(t)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.True(t, native.closed, "the native watcher is closed before polling starts")
+	require.Len(t, polling.WatchCalls, 1)
+	assert.Equal(t, root, polling.WatchCalls[0].Path)
+}
+
+func TestWatchLoop_ClosesWatcherOnError(t *testing.T) {
+	t.Parallel()
+
+	for _, err := range []error{paths.ErrWatcherMockError, paths.ErrWatchLimitReached} {
+		watcher := &failingWatcher{MockPathWatcher: paths.NewMockPathWatcher(), err: err}
+		loopErr := watchLoop(context.Background(), watcher, watchOptions{Root: t.TempDir()}, func(context.Context, []string) error {
+			t.Error("run must not start when the watcher fails")
+			return nil
+		})
+		require.ErrorIs(t, loopErr, err)
+		assert.True(t, watcher.closed, "the watcher is closed when it fails to start")
+	}
+}